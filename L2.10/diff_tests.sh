@@ -1,5 +1,10 @@
 #!/usr/bin/env bash
 
+# GNU sort collates with the locale tables of the C library and this sort with the
+# Unicode Collation Algorithm, which agree on byte order only in the C locale. Tests
+# run in C unless they name another locale, for input both collations order alike.
+export LC_ALL=C
+
 SORT_OUTPUT="sort_output"
 MY_SORT_OUTPUT="my_sort_output"
 
@@ -495,6 +500,286 @@ run_test() {
 
 }
 
+run_key_test() {
+
+    local file="$1"
+    shift
+
+    sort "$@" "$file" > "$SORT_OUTPUT" 2>&1
+    ./sort "$@" "$file" > "$MY_SORT_OUTPUT" 2>&1
+
+    if diff -u "$SORT_OUTPUT" "$MY_SORT_OUTPUT"; then
+        echo "Test passed: $*"
+    else
+        echo "============================================"
+        echo "Test failed: $*"
+        echo "Expected:"
+        cat "$SORT_OUTPUT"
+        echo "--------------------------------------------"
+        echo "Got:"
+        cat "$MY_SORT_OUTPUT"
+        echo "============================================"
+    fi
+
+    rm -f "$SORT_OUTPUT" "$MY_SORT_OUTPUT"
+
+}
+
+run_locale_test() {
+
+    local locale="$1"
+    local file="$2"
+    shift 2
+
+    if ! locale -a 2>/dev/null | sed 's/utf8$/UTF-8/' | grep -qx "$locale"; then
+        echo "Test skipped: LC_ALL=$locale $* (locale not installed)"
+        return
+    fi
+
+    LC_ALL="$locale" sort "$@" "$file" > "$SORT_OUTPUT" 2>&1
+    LC_ALL="$locale" ./sort "$@" "$file" > "$MY_SORT_OUTPUT" 2>&1
+
+    if diff -u "$SORT_OUTPUT" "$MY_SORT_OUTPUT"; then
+        echo "Test passed: LC_ALL=$locale $*"
+    else
+        echo "============================================"
+        echo "Test failed: LC_ALL=$locale $*"
+        echo "Expected:"
+        cat "$SORT_OUTPUT"
+        echo "--------------------------------------------"
+        echo "Got:"
+        cat "$MY_SORT_OUTPUT"
+        echo "============================================"
+    fi
+
+    rm -f "$SORT_OUTPUT" "$MY_SORT_OUTPUT"
+
+}
+
+run_output_test() {
+
+    local file="$1"
+    shift
+
+    cp "$file" "$SORT_OUTPUT"
+    cp "$file" "$MY_SORT_OUTPUT"
+
+    sort "$@" -o "$SORT_OUTPUT" "$SORT_OUTPUT"
+    ./sort "$@" -o "$MY_SORT_OUTPUT" "$MY_SORT_OUTPUT"
+
+    if diff -u "$SORT_OUTPUT" "$MY_SORT_OUTPUT"; then
+        echo "Test passed: -o onto the input file $*"
+    else
+        echo "============================================"
+        echo "Test failed: -o onto the input file $*"
+        echo "Expected:"
+        cat "$SORT_OUTPUT"
+        echo "--------------------------------------------"
+        echo "Got:"
+        cat "$MY_SORT_OUTPUT"
+        echo "============================================"
+    fi
+
+    rm -f "$SORT_OUTPUT" "$MY_SORT_OUTPUT"
+
+}
+
 go build -o sort ./cmd/sort/main.go
 
 run_test "./assets/test_file_1.txt"
+
+KEYS_FILE="./assets/test_file_3.txt"
+
+run_key_test "$KEYS_FILE" -k 2,2n -k 1,1r
+run_key_test "$KEYS_FILE" -k 3.2,3.5
+run_key_test "$KEYS_FILE" -k 2b,2
+run_key_test "$KEYS_FILE" -k 2.1b,2.2b
+run_key_test "$KEYS_FILE" -k 1.2
+run_key_test "$KEYS_FILE" -k 2,3
+run_key_test "$KEYS_FILE" -k 3,3M -k 1,1
+run_key_test "$KEYS_FILE" -k 3,3Mb -u
+run_key_test "$KEYS_FILE" -k 4,4V
+run_key_test "$KEYS_FILE" -k 4V -r
+run_key_test "$KEYS_FILE" -k 5,5g
+run_key_test "$KEYS_FILE" -k 6,6hr -k 1.3,1.3
+run_key_test "$KEYS_FILE" -k 7.3,7.4n
+run_key_test "$KEYS_FILE" -k 1,1f
+run_key_test "$KEYS_FILE" -k 1,1dfr
+run_key_test "$KEYS_FILE" -k 1,1i
+run_key_test "$KEYS_FILE" -k 2,2nr -k 7
+run_key_test "$KEYS_FILE" -k 2,2 -k 1,1 -n
+run_key_test "$KEYS_FILE" -k 1,1 -u
+run_key_test "$KEYS_FILE" -k 1,1f -u
+run_key_test "$KEYS_FILE" -k 2,2n -u
+run_key_test "$KEYS_FILE" -k 2,2n -c
+run_key_test "$KEYS_FILE" -k 1,1 -cu
+run_key_test "$KEYS_FILE" -k 1,1nM
+run_key_test "$KEYS_FILE" -k 0
+run_key_test "$KEYS_FILE" -k 1.0
+run_key_test "$KEYS_FILE" -k 1,a
+run_key_test "$KEYS_FILE" -k 1x
+
+PASSWD_FILE="./assets/test_file_4.txt"
+
+run_key_test "$PASSWD_FILE" -t : -k 3,3n
+run_key_test "$PASSWD_FILE" -t : -k 3,3n -k 1,1
+run_key_test "$PASSWD_FILE" -t : -k 4,4nr -k 3,3n
+run_key_test "$PASSWD_FILE" -t : -k 7 -k 1,1r
+run_key_test "$PASSWD_FILE" -t : -k 1.2,1.3
+run_key_test "$PASSWD_FILE" -t : -k 6.7b
+run_key_test "$PASSWD_FILE" -t : -k 1,1 -u
+run_key_test "$PASSWD_FILE" -t : -k 3,3n -c
+run_key_test "$PASSWD_FILE" -t : -k 5,5 -s
+run_key_test "$PASSWD_FILE" -t : -k 3,3n -s
+run_key_test "$PASSWD_FILE" -t : -k 2,2 -s -r
+run_key_test "$PASSWD_FILE" -t , -k 2
+run_key_test "$PASSWD_FILE" -s -k 1,1
+run_key_test "$PASSWD_FILE" -t ab
+run_key_test "$PASSWD_FILE" -t : -t ,
+run_key_test "$PASSWD_FILE" -c -o "$MY_SORT_OUTPUT"
+
+run_output_test "$PASSWD_FILE" -t : -k 3,3n
+run_output_test "$KEYS_FILE" -k 2,2n -u
+
+run_key_test "$KEYS_FILE" -S 1K --batch-size=2
+run_key_test "$KEYS_FILE" -S 1K --batch-size=3 -k 2,2n -s
+run_key_test "$KEYS_FILE" -S 2048b --batch-size=2 -k 1,1 -u
+run_key_test "$PASSWD_FILE" -S 1K --batch-size=2 -t : -k 3,3n -k 1,1r
+run_key_test "$PASSWD_FILE" -S 10% -t : -k 7
+run_key_test "$KEYS_FILE" -S 1.5M
+run_key_test "$KEYS_FILE" -S 10x
+run_key_test "$KEYS_FILE" -S 1Y
+run_key_test "$KEYS_FILE" --batch-size=1
+run_key_test "$KEYS_FILE" --batch-size=3k
+
+run_key_test "$KEYS_FILE" -S 1K --batch-size=2 --compress-program=gzip
+run_key_test "$KEYS_FILE" -S 1K -k 2,2n -s --compress-program=gzip -T . -T /tmp
+run_key_test "$KEYS_FILE" -S 1K --batch-size=2 -k 1,1 -u --compress-program=zstd
+run_key_test "$PASSWD_FILE" -S 1K -t : -k 3,3n -T /tmp
+run_key_test "$KEYS_FILE" -S 1K -T /nonexistent
+run_key_test "$KEYS_FILE" --compress-program=gzip --compress-program=xz
+
+NUMBERS_FILE="./assets/test_file_5.txt"
+
+run_key_test "$NUMBERS_FILE" -n
+run_key_test "$NUMBERS_FILE" -nr
+run_key_test "$NUMBERS_FILE" -nu
+run_key_test "$NUMBERS_FILE" -g
+run_key_test "$NUMBERS_FILE" -gr
+run_key_test "$NUMBERS_FILE" -V
+run_key_test "$NUMBERS_FILE" -d
+run_key_test "$NUMBERS_FILE" -f
+run_key_test "$NUMBERS_FILE" -fu
+run_key_test "$NUMBERS_FILE" -i
+run_key_test "$NUMBERS_FILE" -dfr
+run_key_test "$NUMBERS_FILE" -c -g
+run_key_test "$NUMBERS_FILE" -nd
+run_key_test "$NUMBERS_FILE" -gV
+
+NAN_FILE="$(mktemp)"
+
+# NaNs with and without a sign, which strtod(3) reads alike
+printf '%s\n' NaN abc -nan 1 +NaN nan -NAN -1 > "$NAN_FILE"
+
+run_key_test "$NAN_FILE" -g
+run_key_test "$NAN_FILE" -gr
+run_key_test "$NAN_FILE" -g -s
+
+rm -f "$NAN_FILE"
+
+run_key_test "$KEYS_FILE" -k 1,1 -f
+run_key_test "$PASSWD_FILE" -t : -k 5,5 -d -f
+
+# run_merge_test sorts the assets with the given options, then merges the sorted copies with -m.
+run_merge_test() {
+
+    local sorted=()
+    local file
+
+    for file in "$KEYS_FILE" "$PASSWD_FILE" "$NUMBERS_FILE"; do
+        sorted+=("$(mktemp)")
+        sort "$@" "$file" > "${sorted[-1]}"
+    done
+
+    sort -m "$@" "${sorted[@]}" > "$SORT_OUTPUT" 2>&1
+    ./sort -m "$@" "${sorted[@]}" > "$MY_SORT_OUTPUT" 2>&1
+
+    if diff -u "$SORT_OUTPUT" "$MY_SORT_OUTPUT"; then
+        echo "Test passed: -m $*"
+    else
+        echo "============================================"
+        echo "Test failed: -m $*"
+        echo "Expected:"
+        cat "$SORT_OUTPUT"
+        echo "--------------------------------------------"
+        echo "Got:"
+        cat "$MY_SORT_OUTPUT"
+        echo "============================================"
+    fi
+
+    rm -f "$SORT_OUTPUT" "$MY_SORT_OUTPUT" "${sorted[@]}"
+
+}
+
+run_merge_test
+run_merge_test -n
+run_merge_test -r
+run_merge_test -u
+run_merge_test -f -s
+run_merge_test -k 2,2 -s --batch-size=2
+run_merge_test -k 1,1 -u --batch-size=2 --compress-program=gzip
+run_key_test "$KEYS_FILE" -m "$PASSWD_FILE"
+run_key_test "$KEYS_FILE" -cm
+run_key_test "$KEYS_FILE" -m ./assets/nonexistent.txt
+
+RANDOM_SOURCE="./assets/test_file_1.txt"
+
+run_key_test "$NUMBERS_FILE" -R --random-source="$RANDOM_SOURCE"
+run_key_test "$NUMBERS_FILE" -Rf --random-source=/dev/zero
+run_key_test "$NUMBERS_FILE" -Rr -s --random-source="$RANDOM_SOURCE"
+run_key_test "$NUMBERS_FILE" -Ru --random-source="$RANDOM_SOURCE"
+run_key_test "$KEYS_FILE" -S 1K --batch-size=2 -k 1,1R -k 2,2n --random-source="$RANDOM_SOURCE"
+run_key_test "$PASSWD_FILE" -S 1K -t : -k 7,7R -s --random-source=/dev/zero
+run_merge_test -R --random-source="$RANDOM_SOURCE"
+run_key_test "$KEYS_FILE" -k 1,1Rn
+run_key_test "$KEYS_FILE" -RM
+run_key_test "$KEYS_FILE" -R --random-source=/dev/null
+run_key_test "$KEYS_FILE" -R --random-source=./assets/nonexistent.txt
+run_key_test "$KEYS_FILE" --random-source=./assets/nonexistent.txt
+run_key_test "$KEYS_FILE" --random-source=/dev/zero --random-source=/dev/null
+
+ZERO_FILE="$(mktemp)"
+LONG_FILE="$(mktemp)"
+
+# Records ended by NUL that hold newlines, as find -print0 writes them, and lines far longer than 64 KiB
+paste -d '\n' "$KEYS_FILE" "$PASSWD_FILE" | tr ':' ' ' | paste -d '|\n' - - - | tr '\n|' '\0\n' > "$ZERO_FILE"
+for n in 3 1 2; do head -c $((n * 70000)) ./assets/test_file_1.txt | tr '\n' ' '; echo; done > "$LONG_FILE"
+
+run_key_test "$ZERO_FILE" -z
+run_key_test "$ZERO_FILE" -zr -k 2,2
+run_key_test "$ZERO_FILE" -z -k 3,3n -s
+run_key_test "$ZERO_FILE" -zu -k 1,1
+run_key_test "$ZERO_FILE" -z -S 1K --batch-size=2 -k 2
+run_key_test "$ZERO_FILE" -zc
+run_key_test "$LONG_FILE"
+run_key_test "$LONG_FILE" -r -S 1K
+run_merge_test -z
+
+rm -f "$ZERO_FILE" "$LONG_FILE"
+
+LOCALE_FILE="$(mktemp)"
+
+# Words differing in case and accents, without punctuation, which the C library and the
+# Unicode Collation Algorithm weigh differently
+printf '%s\n' "banana 3" "Apple 1" "cherry 2" "apple 4" "Banana 5" "date 1" "Cherry 6" "éclair 2" "Zebra 7" "ёж 8" > "$LOCALE_FILE"
+
+run_locale_test C.UTF-8 "$LOCALE_FILE"
+run_locale_test C.UTF-8 "$LOCALE_FILE" -f
+run_locale_test en_US.UTF-8 "$LOCALE_FILE"
+run_locale_test en_US.UTF-8 "$LOCALE_FILE" -r
+run_locale_test en_US.UTF-8 "$LOCALE_FILE" -k 1,1 -u
+run_locale_test en_US.UTF-8 "$LOCALE_FILE" -k 1,1f -u
+run_locale_test en_US.UTF-8 "$LOCALE_FILE" -k 2,2n -k 1,1 -s
+run_locale_test en_US.UTF-8 "$LOCALE_FILE" -S 1K --batch-size=2 -k 1,1f -s
+
+rm -f "$LOCALE_FILE"
@@ -3,9 +3,11 @@ module L2.10
 go 1.25.1
 
 require (
+	github.com/klauspost/compress v1.18.0
 	github.com/spf13/pflag v1.0.10
 	github.com/spf13/viper v1.21.0
 	golang.org/x/sync v0.17.0
+	golang.org/x/text v0.28.0
 )
 
 require (
@@ -19,5 +21,4 @@ require (
 	github.com/subosito/gotenv v1.6.0 // indirect
 	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/sys v0.29.0 // indirect
-	golang.org/x/text v0.28.0 // indirect
 )
@@ -1,150 +1,242 @@
 // Package comparator provides line comparison functions for the sort utility,
-// supporting numeric, human-readable, month-based, and column/key-based sorting.
+// supporting numeric, general numeric, human-readable, month-based, version,
+// random and key-based sorting, with text collated in the locale of the environment.
 package comparator
 
 import (
-	"bufio"
+	"bytes"
 	"cmp"
+	"crypto/md5"
+	"errors"
 	"fmt"
+	"hash"
+	"math"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"L2.10/internal/flags"
+	"L2.10/internal/linereader"
+	"golang.org/x/text/collate"
 )
 
-// Compare returns a comparison function for two strings based on the provided Flags.
-func Compare(flags *flags.Flags) func(a, b string) int {
+// Comparator compares lines according to the sort keys and options given in Flags.
+type Comparator struct {
+	flags    *flags.Flags      // Options that apply to whole lines, such as -r, -u, -s and -t
+	keys     []flags.Key       // Sort keys, with the global ordering options applied
+	numeric  numericFormat     // Decimal point and thousands separator of numbers for -n
+	collator *collate.Collator // Collation of text in the locale; nil to compare bytes, as in the C locale
+	buf      collate.Buffer    // Buffer the collator makes keys in
+	hasher   hash.Hash         // Hash random keys are compared by; nil without them
+}
 
-	return func(line1, line2 string) int {
+// Line is a line prepared for comparison: its sort keys are extracted and parsed
+// once, so that a line compared many times, like the head of a chunk during the
+// merge, is not parsed again for every comparison.
+type Line struct {
+	Text     string     // The line itself
+	keys     []keyValue // Parsed sort keys, one for each key of the Comparator
+	collated []byte     // Collation key of the whole line; nil if bytes are compared or whole lines never are
+}
 
-		if flags.B {
-			line1 = strings.TrimLeft(line1, " \t")
-			line2 = strings.TrimLeft(line2, " \t")
-		}
+// keyValue is the parsed text of a sort key.
+type keyValue struct {
+	text     string         // Text of the key, without the characters -d and -i ignore and folded by -f
+	collated []byte         // Collation key of text, for keys compared as text; nil if bytes are compared
+	numeric  numericValue   // Value of the key for -n
+	number   int64          // Value of the key for -h and -M
+	float    float64        // Value of the key for -g
+	valid    bool           // Whether the key starts with a number, for -g
+	hash     [md5.Size]byte // Hash of the key seeded with the salt, for -R
+}
 
-		if flags.K {
+// New returns a Comparator for the provided Flags. Text is collated and numbers
+// are read for -n in the locale the environment selects. A Comparator keeps
+// buffers for collation and hashing, so it must not be used by several
+// goroutines at once.
+func New(flags *flags.Flags) *Comparator {
+
+	c := &Comparator{
+		flags:    flags,
+		keys:     flags.SortKeys(),
+		numeric:  localeNumericFormat(),
+		collator: localeCollator(),
+	}
+	if flags.Salt != nil {
+		c.hasher = md5.New()
+	}
 
-			fields1 := strings.Fields(line1)
-			fields2 := strings.Fields(line2)
-			var column1, column2 string
+	return c
 
-			if len(fields1) > flags.ClmnToSort {
-				column1 = fields1[flags.ClmnToSort]
-			}
+}
 
-			if len(fields2) > flags.ClmnToSort {
-				column2 = fields2[flags.ClmnToSort]
-			}
+// Prepare extracts and parses the sort keys of a line. Outside the C locale, the
+// collation keys of its keys compared as text are made here as well, and so is that of
+// the whole line if Compare can fall back to it, as are the hashes of random keys.
+func (c *Comparator) Prepare(text string) Line {
 
-			return CompareLines(column1, column2, flags)
+	line := Line{Text: text}
+	if c.collator != nil && c.comparesLines() {
+		line.collated = collationKey(c.collator, &c.buf, text)
+	}
+	if len(c.keys) == 0 {
+		return line
+	}
 
+	line.keys = make([]keyValue, len(c.keys))
+	for i, key := range c.keys {
+		line.keys[i] = parseValue(keyText(text, key, c.flags.T), key.Order, c.numeric)
+		if c.collator != nil && isTextOrder(key.Order) {
+			line.keys[i].collated = collationKey(c.collator, &c.buf, line.keys[i].text)
+		}
+		if key.Random {
+			c.hashKey(&line.keys[i])
 		}
-
-		return CompareLines(line1, line2, flags)
-
 	}
 
+	return line
+
 }
 
-// CompareLines compares two lines according to the given Flags
-func CompareLines(line1, line2 string, flags *flags.Flags) int {
+// Compare compares two lines prepared by the Comparator. Lines are compared by each
+// sort key in turn. Lines whose keys are all equal are compared byte by byte as a
+// whole, unless -u makes them duplicates of each other or -s keeps them in input order.
+func (c *Comparator) Compare(line1, line2 Line) int {
 
-	if flags.B {
-		line1 = strings.TrimLeft(line1, " \t")
-		line2 = strings.TrimLeft(line2, " \t")
+	if len(c.keys) > 0 {
+		if cmpRes := compareKeys(line1.keys, line2.keys, c.keys); cmpRes != 0 || !c.comparesLines() {
+			return cmpRes
+		}
 	}
 
-	var cmpRes int
-
-	switch {
-	case flags.N:
-		cmpRes = numericComparison(line1, line2)
-	case flags.H:
-		cmpRes = readableComparison(line1, line2)
-	case flags.M:
-		cmpRes = monthsComparison(line1, line2)
-	default:
-		cmpRes = stringComparison(line1, line2)
+	cmpRes := stringComparison(line1.Text, line2.Text)
+	if line1.collated != nil {
+		cmpRes = bytes.Compare(line1.collated, line2.collated)
 	}
 
-	if flags.R {
+	if c.flags.R {
 		return -cmpRes
 	}
 	return cmpRes
 
 }
 
-// numericComparison compares lines based on their numeric value at the start.
-// If a line does not start with a number, it falls back to lexicographic comparison.
-func numericComparison(line1, line2 string) int {
+// comparesLines reports whether Compare falls back to comparing whole lines once their
+// keys are equal, which -u and -s rule out when sort keys are given.
+func (c *Comparator) comparesLines() bool {
+	return len(c.keys) == 0 || !c.flags.U && !c.flags.S
+}
 
-	number1, isNumber1 := getFirstInt(line1)
-	number2, isNumber2 := getFirstInt(line2)
+// Compare returns a comparison function for two lines based on the provided Flags.
+// The function parses both lines on every call; lines compared more than once
+// should be prepared with a Comparator instead.
+func Compare(flags *flags.Flags) func(a, b string) int {
 
-	switch {
-	case isNumber1 && isNumber2:
-		return compareNumbers(number1, number2, line1, line2)
-	case isNumber1:
-		return 1
-	case isNumber2:
-		return -1
-	default:
-		return cmp.Compare(line1, line2)
+	c := New(flags)
+
+	return func(line1, line2 string) int {
+		return c.Compare(c.Prepare(line1), c.Prepare(line2))
 	}
 
 }
 
-// firstIntRegexp matches the first integer at the beginning of a string.
-var firstIntRegexp = regexp.MustCompile(`^\s*([0-9]+)`)
+// hashKey hashes the salt followed by the key, as GNU sort does for -R: the collation key
+// if there is one, and the text otherwise. Equal keys hash alike, whichever chunk they
+// are in, so they stay together while the order of different keys is random.
+func (c *Comparator) hashKey(value *keyValue) {
 
-// getFirstInt extracts the first integer at the start of a line.
-// Returns the number and a boolean indicating whether a number was found.
-func getFirstInt(line string) (int64, bool) {
+	c.hasher.Reset()
+	c.hasher.Write(c.flags.Salt)
+	if value.collated != nil {
+		c.hasher.Write(value.collated)
+	} else {
+		c.hasher.Write([]byte(value.text))
+	}
+	c.hasher.Sum(value.hash[:0])
 
-	firstInt := firstIntRegexp.FindStringSubmatch(line)
-	if len(firstInt) < 2 {
-		return 0, false
+}
+
+// compareKeys compares the parsed keys of two lines in turn and returns the first difference.
+func compareKeys(values1, values2 []keyValue, keys []flags.Key) int {
+
+	for i, key := range keys {
+		if cmpRes := compareKey(values1[i], values2[i], key.Order); cmpRes != 0 {
+			if key.Reverse {
+				return -cmpRes
+			}
+			return cmpRes
+		}
 	}
 
-	number, err := strconv.ParseInt(firstInt[1], 10, 64)
-	return number, err == nil
+	return 0
 
 }
 
-// compareNumbers compares two numbers and falls back to line comparison if equal.
-func compareNumbers(number1, number2 int64, line1, line2 string) int {
+// parseValue parses the text of a key according to its ordering options, reading numbers
+// for -n in the given format. A key that does not start with a number counts as zero for
+// -n and -h, as in GNU sort, and an unknown month name counts as zero for -M, before 'JAN'.
+func parseValue(text string, order flags.Order, format numericFormat) keyValue {
 
-	if res := cmp.Compare(number1, number2); res != 0 {
-		return res
+	if order.Dictionary || order.IgnoreNonprinting || order.FoldCase {
+		text = translate(text, order)
 	}
-	return cmp.Compare(line1, line2)
 
-}
+	value := keyValue{text: text}
 
-// readableComparison compares human-readable numbers.
-func readableComparison(line1 string, line2 string) int {
+	switch {
+	case order.Numeric:
+		value.numeric = parseNumeric(text, format)
+	case order.GeneralNumeric:
+		value.float, value.valid = parseFloatPrefix(text)
+	case order.HumanNumeric:
+		value.number, _ = convertToInt(text)
+	case order.Month:
+		value.number = int64(monthToInt(months, text))
+	}
 
-	number1, isNumber1 := convertToInt(line1)
-	number2, isNumber2 := convertToInt(line2)
+	return value
+
+}
+
+// compareKey compares the parsed key of two lines according to its ordering options.
+func compareKey(value1, value2 keyValue, order flags.Order) int {
 
 	switch {
-	case isNumber1 == nil && isNumber2 == nil:
-		if number1 < number2 {
-			return -1
-		} else if number1 > number2 {
-			return 1
+	case order.Random:
+		if cmpRes := bytes.Compare(value1.hash[:], value2.hash[:]); cmpRes != 0 {
+			return cmpRes
 		}
-		return stringComparison(line1, line2)
-	case isNumber1 == nil && isNumber2 != nil:
-		return 1
-	case isNumber1 != nil && isNumber2 == nil:
-		return -1
+		return textComparison(value1, value2) // Hashes only collide for equal keys, barring bad luck
+	case order.Numeric:
+		return numericComparison(value1.numeric, value2.numeric)
+	case order.GeneralNumeric:
+		return generalNumericComparison(value1, value2)
+	case order.HumanNumeric:
+		return cmp.Compare(value1.number, value2.number)
+	case order.Month:
+		return cmp.Compare(value1.number, value2.number)
+	case order.Version:
+		return versionComparison(value1.text, value2.text)
 	default:
-		return stringComparison(line1, line2)
+		return textComparison(value1, value2)
+	}
+
+}
+
+// textComparison compares keys as text: by their collation keys if they have them,
+// and byte by byte otherwise.
+func textComparison(value1, value2 keyValue) int {
+	if value1.collated != nil {
+		return bytes.Compare(value1.collated, value2.collated)
 	}
+	return stringComparison(value1.text, value2.text)
+}
 
+// isTextOrder reports whether keys with the ordering options are compared as text,
+// which is collated in the locale, rather than as numbers, months or versions.
+func isTextOrder(order flags.Order) bool {
+	return !order.Numeric && !order.GeneralNumeric && !order.HumanNumeric && !order.Month && !order.Version
 }
 
 // IntRe matches a human-readable number at the start of a string, possibly
@@ -190,54 +282,86 @@ func multiplier(number []string) float64 {
 
 }
 
-// monthsComparison compares lines assuming month names.
-func monthsComparison(line1 string, line2 string) int {
+// months maps the lower case abbreviations of month names to their number.
+var months = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
 
-	months := map[string]int{
-		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
-		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
-	}
+// monthToInt converts a month abbreviation to an integer.
+func monthToInt(months map[string]int, line string) int {
 
-	month1 := monthToInt(months, line1)
-	month2 := monthToInt(months, line2)
+	line = strings.TrimLeft(line, " \t\n")
 
-	if month1 == 0 && month2 == 0 {
-		return stringComparison(line1, line2)
+	if len(line) < 3 {
+		return 0
 	}
 
-	if month1 == 0 {
-		return -1
+	if month, found := months[strings.ToLower(line[:3])]; found {
+		return month
 	}
 
-	if month2 == 0 {
-		return 1
-	}
+	return 0
+
+}
 
-	if month1 != month2 {
-		if month1 < month2 {
+// generalNumericComparison compares keys parsed for -g. Keys without a number sort
+// first, followed by NaNs, followed by all other numbers. Negative NaNs sort after
+// positive ones, as GNU sort orders NaNs by their bits.
+func generalNumericComparison(value1, value2 keyValue) int {
+
+	switch {
+	case value1.valid && value2.valid && math.IsNaN(value1.float) && math.IsNaN(value2.float):
+		switch negative1, negative2 := math.Signbit(value1.float), math.Signbit(value2.float); {
+		case negative1 == negative2:
+			return 0
+		case negative1:
+			return 1
+		default:
 			return -1
 		}
+	case value1.valid && value2.valid:
+		return cmp.Compare(value1.float, value2.float) // cmp.Compare orders NaN first and treats -0 and +0 as equal
+	case value1.valid:
 		return 1
+	case value2.valid:
+		return -1
+	default:
+		return 0
 	}
 
-	return stringComparison(line1, line2)
-
 }
 
-// monthToInt converts a month abbreviation to an integer.
-func monthToInt(months map[string]int, line string) int {
+// floatPrefixRe matches the longest prefix of a string that strtod(3) converts in the C locale:
+// a hexadecimal or decimal floating-point number, an infinity or a NaN.
+var floatPrefixRe = regexp.MustCompile(`^[ \t\n\v\f\r]*([+-]?(?:0[xX](?:[0-9a-fA-F]+\.?[0-9a-fA-F]*|\.[0-9a-fA-F]+)([pP][+-]?[0-9]+)?|(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?|(?i:infinity|inf|nan)))`)
 
-	line = strings.TrimLeft(line, " \t")
+// parseFloatPrefix extracts the floating-point number at the start of a line.
+// Returns the number and a boolean indicating whether a number was found.
+func parseFloatPrefix(line string) (float64, bool) {
 
-	if len(line) < 3 {
-		return 0
+	number := floatPrefixRe.FindStringSubmatch(line)
+	if number == nil {
+		return 0, false
 	}
 
-	if month, found := months[strings.ToLower(line[:3])]; found {
-		return month
+	literal := number[1]
+	if strings.EqualFold(strings.TrimLeft(literal, "+-"), "nan") { // strconv.ParseFloat rejects a signed NaN
+		if literal[0] == '-' {
+			return math.Copysign(math.NaN(), -1), true
+		}
+		return math.NaN(), true
+	}
+	if strings.ContainsAny(literal, "xX") && number[2] == "" {
+		literal += "p0" // strconv.ParseFloat requires an exponent in hexadecimal literals
 	}
 
-	return 0
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) { // out of range values are still ordered, as infinities or zeros
+		return 0, false
+	}
+
+	return value, true
 
 }
 
@@ -253,9 +377,9 @@ func stringComparison(line1 string, line2 string) int {
 
 }
 
-// CheckSorted verifies if the input from scanner is sorted according to Flags.
+// CheckSorted verifies if the input from reader is sorted according to Flags.
 // Returns false if a disorder is found, printing a message similar to GNU sort.
-func CheckSorted(scanner *bufio.Scanner, fileName string, flags *flags.Flags) (sorted bool, err error) {
+func CheckSorted(reader *linereader.Reader, fileName string, flags *flags.Flags) (sorted bool, err error) {
 
 	if fileName != "" {
 
@@ -263,7 +387,7 @@ func CheckSorted(scanner *bufio.Scanner, fileName string, flags *flags.Flags) (s
 		if openErr != nil {
 			return false, openErr
 		}
-		scanner = bufio.NewScanner(file)
+		reader = linereader.New(file, flags.LineEnd())
 
 		defer func() {
 			if closeErr := file.Close(); closeErr != nil && err == nil {
@@ -275,13 +399,15 @@ func CheckSorted(scanner *bufio.Scanner, fileName string, flags *flags.Flags) (s
 		fileName = "-"
 	}
 
-	var line string
-	var prev string
+	c := New(flags)
+
+	var line Line
+	var prev Line
 	var lineNum int
 
-	for scanner.Scan() {
+	for reader.Scan() {
 
-		line = scanner.Text()
+		line = c.Prepare(reader.Text())
 		lineNum++
 
 		if lineNum == 1 {
@@ -289,13 +415,9 @@ func CheckSorted(scanner *bufio.Scanner, fileName string, flags *flags.Flags) (s
 			continue
 		}
 
-		if flags.U && line == prev {
-			fmt.Fprintf(os.Stderr, "sort: %s:%d: disorder: %s\n", fileName, lineNum, line)
-			return false, nil
-		}
-
-		if CompareLines(line, prev, flags) < 0 {
-			fmt.Fprintf(os.Stderr, "sort: %s:%d: disorder: %s\n", fileName, lineNum, line)
+		cmpRes := c.Compare(prev, line)
+		if cmpRes > 0 || flags.U && cmpRes == 0 { // With -u, equal lines are a disorder too
+			fmt.Fprintf(os.Stderr, "sort: %s:%d: disorder: %s%c", fileName, lineNum, line.Text, flags.LineEnd()) // Ended like the line itself, as in GNU sort
 			return false, nil
 		}
 
@@ -303,6 +425,6 @@ func CheckSorted(scanner *bufio.Scanner, fileName string, flags *flags.Flags) (s
 
 	}
 
-	return true, scanner.Err()
+	return true, reader.Err()
 
 }
@@ -1,13 +1,19 @@
 package comparator
 
 import (
-	"bufio"
+	"slices"
 	"strings"
 	"testing"
 
 	"L2.10/internal/flags"
+	"L2.10/internal/linereader"
 )
 
+// compareAs compares two keys the way a sort key with the given ordering options does.
+func compareAs(order flags.Order, key1, key2 string) int {
+	return compareKey(parseValue(key1, order, cNumericFormat), parseValue(key2, order, cNumericFormat), order)
+}
+
 func TestStringComparison(t *testing.T) {
 
 	tests := []struct {
@@ -37,12 +43,27 @@ func TestNumericComparison(t *testing.T) {
 		{"1", "2", -1},
 		{"aboba", "1", -1},
 		{"1", "amogus", 1},
-		{"abc", "def", -1},
+		{"abc", "def", 0},
+		{"01", "1", 0},
+		{"-2", "-10", 1},
+		{"-1", "abc", -1},
+		{"-0", "0", 0},
+		{"-", ".", 0},
+		{"+5", "0", 0},
+		{"1.5", "1.25", 1},
+		{"0.50", ".5", 0},
+		{"1.", "1", 0},
+		{"-.5", "-0.25", -1},
+		{" \t7", "7", 0},
+		{"1e3", "2", -1},
+		{"1,000", "2", -1},
+		{"12345678901234567890123", "12345678901234567890122", 1},
+		{"1\x002", "3", 1},
 	}
 
 	for _, tt := range tests {
-		if result := numericComparison(tt.line1, tt.line2); result != tt.expected {
-			t.Errorf("numericComparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
+		if result := compareAs(flags.Order{Numeric: true}, tt.line1, tt.line2); result != tt.expected {
+			t.Errorf("numeric comparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
 		}
 	}
 
@@ -62,8 +83,8 @@ func TestReadableComparison(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		if result := readableComparison(tt.line1, tt.line2); result != tt.expected {
-			t.Errorf("readableComparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
+		if result := compareAs(flags.Order{HumanNumeric: true}, tt.line1, tt.line2); result != tt.expected {
+			t.Errorf("human numeric comparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
 		}
 	}
 
@@ -84,21 +105,22 @@ func TestMonthsComparison(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		if result := monthsComparison(tt.line1, tt.line2); result != tt.expected {
-			t.Errorf("monthsComparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
+		if result := compareAs(flags.Order{Month: true}, tt.line1, tt.line2); result != tt.expected {
+			t.Errorf("month comparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
 		}
 	}
 
 }
+
 func TestCheckSorted(t *testing.T) {
 
 	flags := &flags.Flags{}
 
 	t.Run("sorted", func(t *testing.T) {
 		data := "a\nb\nc\n"
-		scanner := bufio.NewScanner(strings.NewReader(data))
+		reader := linereader.New(strings.NewReader(data), '\n')
 
-		sorted, err := CheckSorted(scanner, "", flags)
+		sorted, err := CheckSorted(reader, "", flags)
 		if err != nil {
 			t.Errorf("CheckSorted returned unexpected error: %v", err)
 		}
@@ -109,9 +131,9 @@ func TestCheckSorted(t *testing.T) {
 
 	t.Run("unsorted", func(t *testing.T) {
 		data := "a\nc\nb\n"
-		scanner := bufio.NewScanner(strings.NewReader(data))
+		reader := linereader.New(strings.NewReader(data), '\n')
 
-		sorted, err := CheckSorted(scanner, "", flags)
+		sorted, err := CheckSorted(reader, "", flags)
 		if err != nil {
 			t.Errorf("CheckSorted returned unexpected error: %v", err)
 		}
@@ -121,3 +143,137 @@ func TestCheckSorted(t *testing.T) {
 	})
 
 }
+
+func TestCompare(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		flags        *flags.Flags
+		line1, line2 string
+		expected     int
+	}{
+		{"bytes", &flags.Flags{}, "abc", "abd", -1},
+		{"reverse", &flags.Flags{R: true}, "abc", "abd", 1},
+		{"numeric", &flags.Flags{N: true}, "10", "9", 1},
+		{"numeric last resort", &flags.Flags{N: true}, "abc", "def", -1},
+		{"numeric last resort reversed", &flags.Flags{N: true, R: true}, "01", "1", 1},
+		{"numeric unique", &flags.Flags{N: true, U: true}, "01", "1", 0},
+		{"blanks", &flags.Flags{B: true}, "  b", "a", 1},
+		{"key", &flags.Flags{Keys: []flags.Key{{StartField: 1, EndField: 1}}}, "a 2", "b 1", 1},
+		{"key inherits global options", &flags.Flags{N: true, R: true, Keys: []flags.Key{{StartField: 1, EndField: 1}}}, "a 2", "b 10", 1},
+		{"key keeps own options", &flags.Flags{N: true, Keys: []flags.Key{{StartField: 1, EndField: 1, Order: flags.Order{Reverse: true}}}}, "a 2", "b 10", -1},
+		{"second key", &flags.Flags{Keys: []flags.Key{
+			{StartField: 1, EndField: 1, Order: flags.Order{Numeric: true}},
+			{StartField: 0, EndField: 0, Order: flags.Order{Reverse: true}},
+		}}, "a 1", "b 01", 1},
+		{"key last resort", &flags.Flags{Keys: []flags.Key{{StartField: 1, EndField: 1}}}, "b 1", "a 1", 1},
+		{"key unique", &flags.Flags{U: true, Keys: []flags.Key{{StartField: 1, EndField: 1}}}, "b 1", "a 1", 0},
+		{"key stable", &flags.Flags{S: true, Keys: []flags.Key{{StartField: 1, EndField: 1}}}, "b 1", "a 1", 0},
+		{"stable without keys", &flags.Flags{S: true}, "b", "a", 1},
+		{"separator", &flags.Flags{T: ",", Keys: []flags.Key{{StartField: 1, EndField: 1, Order: flags.Order{Numeric: true}}}}, "a b,10", "a,9", 1},
+		{"characters", &flags.Flags{Keys: []flags.Key{{StartField: 0, StartChar: 1, EndField: 0, EndChar: 2}}}, "xab", "yac", -1},
+		{"general numeric", &flags.Flags{Keys: []flags.Key{{EndField: -1, Order: flags.Order{GeneralNumeric: true}}}}, "1e3", "999", 1},
+		{"version", &flags.Flags{Keys: []flags.Key{{EndField: -1, Order: flags.Order{Version: true}}}}, "v1.10", "v1.9", 1},
+		{"fold case", &flags.Flags{U: true, Keys: []flags.Key{{EndField: -1, Order: flags.Order{FoldCase: true}}}}, "abc", "ABC", 0},
+		{"dictionary", &flags.Flags{U: true, Keys: []flags.Key{{EndField: -1, Order: flags.Order{Dictionary: true}}}}, "a-b", "ab", 0},
+	}
+
+	for _, tt := range tests {
+		if result := Compare(tt.flags)(tt.line1, tt.line2); result != tt.expected {
+			t.Errorf("Compare (%s)(%q, %q) = %d, expected = %d", tt.name, tt.line1, tt.line2, result, tt.expected)
+		}
+	}
+
+}
+
+func TestGeneralNumericComparison(t *testing.T) {
+
+	tests := []struct {
+		line1, line2 string
+		expected     int
+	}{
+		{"1.5", "1e1", -1},
+		{"-0", "0", 0},
+		{"0x10", "15", 1},
+		{"inf", "1e308", 1},
+		{"-inf", "nan", 1},
+		{"nan", "abc", 1},
+		{"abc", "def", 0},
+		{" 2", "10", -1},
+		{"1e999", "1e308", 1},
+		{"-nan", "abc", 1},
+		{"+NaN", "1", -1},
+		{"-nan", "NaN", 1},
+		{"+NaN", "nan", 0},
+	}
+
+	for _, tt := range tests {
+		if result := compareAs(flags.Order{GeneralNumeric: true}, tt.line1, tt.line2); result != tt.expected {
+			t.Errorf("general numeric comparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
+		}
+	}
+
+}
+
+func TestCheckSortedUnique(t *testing.T) {
+
+	flags := &flags.Flags{U: true, N: true}
+	data := "1\n01\n2\n"
+	reader := linereader.New(strings.NewReader(data), '\n')
+
+	sorted, err := CheckSorted(reader, "", flags)
+	if err != nil {
+		t.Errorf("CheckSorted returned unexpected error: %v", err)
+	}
+	if sorted {
+		t.Errorf("CheckSorted(%q) = true, expected false", data)
+	}
+
+}
+
+func TestComparatorPrepare(t *testing.T) {
+
+	c := New(&flags.Flags{Keys: []flags.Key{
+		{StartField: 1, EndField: 1, Order: flags.Order{Numeric: true}},
+		{EndField: 0, Order: flags.Order{FoldCase: true}},
+	}})
+
+	line := c.Prepare("abc 42 x")
+
+	if line.Text != "abc 42 x" {
+		t.Errorf("Prepare: Text = %q, expected = %q", line.Text, "abc 42 x")
+	}
+	if len(line.keys) != 2 || line.keys[0].numeric.integer != "42" || line.keys[1].text != "ABC" {
+		t.Errorf("Prepare: keys = %+v, expected number 42 and text %q", line.keys, "ABC")
+	}
+
+	if keys := New(new(flags.Flags)).Prepare("abc").keys; keys != nil {
+		t.Errorf("Prepare without keys: keys = %+v, expected none", keys)
+	}
+
+}
+
+func TestRandomCompare(t *testing.T) {
+
+	t.Setenv("LC_ALL", "C") // The expected orders come from GNU sort in the C locale
+
+	salt := make([]byte, 16) // The bytes GNU sort reads from --random-source=/dev/zero
+
+	tests := []struct {
+		flags    flags.Flags
+		expected []string
+	}{
+		{flags.Flags{Random: true, Salt: salt}, []string{"a", "a", "A", "b", "d", "c"}},
+		{flags.Flags{Random: true, F: true, Salt: salt}, []string{"A", "a", "a", "d", "b", "c"}},
+		{flags.Flags{Random: true, R: true, Salt: salt}, []string{"c", "d", "b", "A", "a", "a"}},
+	}
+
+	for _, tt := range tests {
+		lines := []string{"b", "a", "c", "A", "d", "a"}
+		slices.SortFunc(lines, Compare(&tt.flags))
+		if !slices.Equal(lines, tt.expected) {
+			t.Errorf("sort with %+v = %q, expected = %q", tt.flags, lines, tt.expected)
+		}
+	}
+
+}
@@ -0,0 +1,142 @@
+package comparator
+
+import (
+	"strings"
+
+	"L2.10/internal/flags"
+)
+
+// keyText returns the part of a line covered by a sort key. Fields are separated
+// by sep, or, if sep is empty, by the transition from blanks to non-blanks as in
+// GNU sort, so that every field but the first starts with the blanks preceding it
+// unless the key skips them.
+func keyText(line string, key flags.Key, sep string) string {
+
+	start := begField(line, key, sep)
+	end := len(line)
+
+	if key.EndField >= 0 {
+		end = limField(line, key, sep)
+	}
+
+	if end <= start {
+		return ""
+	}
+	return line[start:end]
+
+}
+
+// begField returns the index of the first character of the key in line.
+func begField(line string, key flags.Key, sep string) int {
+
+	pos := skipFields(line, key.StartField, sep, true)
+
+	if key.SkipStartBlanks {
+		pos = skipBlanks(line, pos)
+	}
+
+	return pos + min(len(line)-pos, key.StartChar)
+
+}
+
+// limField returns the index just past the last character of the key in line.
+func limField(line string, key flags.Key, sep string) int {
+
+	if key.EndChar == 0 {
+		return skipFields(line, key.EndField+1, sep, false) // The whole end field belongs to the key
+	}
+
+	pos := skipFields(line, key.EndField, sep, true)
+
+	if key.SkipEndBlanks {
+		pos = skipBlanks(line, pos)
+	}
+
+	return pos + min(len(line)-pos, key.EndChar)
+
+}
+
+// skipFields returns the index just past the first count fields of line. With a
+// separator, the one ending the last of them is skipped as well if pastSep is set.
+func skipFields(line string, count int, sep string, pastSep bool) int {
+
+	pos := 0
+
+	for ; count > 0 && pos < len(line); count-- {
+
+		if sep == "" {
+			pos = skipBlanks(line, pos)
+			for pos < len(line) && !isBlank(line[pos]) {
+				pos++
+			}
+			continue
+		}
+
+		if i := strings.IndexByte(line[pos:], sep[0]); i >= 0 {
+			pos += i
+		} else {
+			pos = len(line)
+		}
+
+		if pos < len(line) && (count > 1 || pastSep) {
+			pos++
+		}
+
+	}
+
+	return pos
+
+}
+
+// skipBlanks returns the index of the first non-blank character of line at or after pos.
+func skipBlanks(line string, pos int) int {
+	for pos < len(line) && isBlank(line[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// isBlank reports whether c is a blank in the C locale. A newline counts as one too,
+// as in GNU sort, so that lines ended by NUL with -z are split into fields at newlines.
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// translate removes the characters a key ignores with -d or -i and folds
+// lower case letters to upper case with -f.
+func translate(text string, order flags.Order) string {
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	for i := range len(text) {
+
+		c := text[i]
+
+		switch {
+		case order.Dictionary:
+			if !isBlank(c) && !isAlnum(c) {
+				continue
+			}
+		case order.IgnoreNonprinting:
+			if c < ' ' || c > '~' {
+				continue
+			}
+		}
+
+		if order.FoldCase && 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+
+		sb.WriteByte(c)
+
+	}
+
+	return sb.String()
+
+}
+
+// isAlnum reports whether c is a letter or a digit in the C locale.
+func isAlnum(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
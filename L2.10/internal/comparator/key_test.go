@@ -0,0 +1,63 @@
+package comparator
+
+import (
+	"testing"
+
+	"L2.10/internal/flags"
+)
+
+func TestKeyText(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		line     string
+		key      flags.Key
+		sep      string
+		expected string
+	}{
+		{"whole line", "  a b", flags.Key{EndField: -1}, "", "  a b"},
+		{"first field", "  a b", flags.Key{EndField: 0}, "", "  a"},
+		{"field keeps leading blanks", "a  b c", flags.Key{StartField: 1, EndField: 1}, "", "  b"},
+		{"field skips leading blanks", "a  b c", flags.Key{StartField: 1, EndField: 1, Order: flags.Order{SkipStartBlanks: true}}, "", "b"},
+		{"to end of line", "a b c", flags.Key{StartField: 1, EndField: -1}, "", " b c"},
+		{"characters", "ab abcdef", flags.Key{StartField: 1, StartChar: 1, EndField: 1, EndChar: 4}, "", "abc"},
+		{"characters skip blanks", "ab abcdef", flags.Key{StartField: 1, StartChar: 1, EndField: 1, EndChar: 4, Order: flags.Order{SkipStartBlanks: true, SkipEndBlanks: true}}, "", "bcd"},
+		{"missing field", "a b", flags.Key{StartField: 3, EndField: 3}, "", ""},
+		{"end before start", "abc def", flags.Key{StartField: 1, EndField: 0}, "", ""},
+		{"tabs", "a\tb\tc", flags.Key{StartField: 2, EndField: 2}, "", "\tc"},
+		{"separator", "root:x:0:0", flags.Key{StartField: 2, EndField: 2}, ":", "0"},
+		{"separator keeps blanks", "a: b :c", flags.Key{StartField: 1, EndField: 1}, ":", " b "},
+		{"separator empty field", "a::c", flags.Key{StartField: 1, EndField: 1}, ":", ""},
+		{"separator to end of line", "a:b:c", flags.Key{StartField: 1, EndField: -1}, ":", "b:c"},
+		{"separator characters", "a:bcd:e", flags.Key{StartField: 1, StartChar: 1, EndField: 2, EndChar: 1}, ":", "cd:e"},
+		{"separator missing field", "a:b", flags.Key{StartField: 2, EndField: 2}, ":", ""},
+	}
+
+	for _, tt := range tests {
+		if result := keyText(tt.line, tt.key, tt.sep); result != tt.expected {
+			t.Errorf("keyText (%s)(%q) = %q, expected = %q", tt.name, tt.line, result, tt.expected)
+		}
+	}
+
+}
+
+func TestTranslate(t *testing.T) {
+
+	tests := []struct {
+		text     string
+		order    flags.Order
+		expected string
+	}{
+		{"a-b c\t!", flags.Order{Dictionary: true}, "ab c\t"},
+		{"a\tb\x01", flags.Order{IgnoreNonprinting: true}, "ab"},
+		{"a\tb", flags.Order{Dictionary: true, IgnoreNonprinting: true}, "a\tb"},
+		{"aBc-1", flags.Order{FoldCase: true}, "ABC-1"},
+	}
+
+	for _, tt := range tests {
+		if result := translate(tt.text, tt.order); result != tt.expected {
+			t.Errorf("translate(%q, %+v) = %q, expected = %q", tt.text, tt.order, result, tt.expected)
+		}
+	}
+
+}
@@ -0,0 +1,68 @@
+package comparator
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// localeName returns the name of the locale in effect for category, such as LC_COLLATE:
+// the one LC_ALL sets, or else the one the category sets, or else the one LANG sets.
+func localeName(category string) string {
+
+	for _, name := range []string{"LC_ALL", category, "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			return locale
+		}
+	}
+
+	return ""
+
+}
+
+// localeParts splits a locale name of the form language[_territory][.codeset][@modifier]
+// into its language with territory, e.g. "ru_RU", and its codeset, e.g. "UTF-8".
+func localeParts(locale string) (lang, codeset string) {
+	locale, _, _ = strings.Cut(locale, "@")
+	lang, codeset, _ = strings.Cut(locale, ".")
+	return lang, codeset
+}
+
+// isCLocale reports whether lang is the C or POSIX locale, or none, which stands for C.
+func isCLocale(lang string) bool {
+	return lang == "" || lang == "C" || lang == "POSIX"
+}
+
+// localeCollator returns a collator ordering text the way the locale LC_ALL, LC_COLLATE
+// or LANG sets does, with the Unicode Collation Algorithm tailored to its language. It
+// returns nil in the C and POSIX locales, where text is ordered byte by byte, and in
+// locales whose codeset is not UTF-8, whose bytes cannot be read as characters.
+func localeCollator() *collate.Collator {
+
+	lang, codeset := localeParts(localeName("LC_COLLATE"))
+	if isCLocale(lang) {
+		return nil
+	}
+
+	if codeset = strings.ToLower(strings.ReplaceAll(codeset, "-", "")); codeset != "" && codeset != "utf8" {
+		return nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(lang, "_", "-"))
+	if err != nil {
+		return nil
+	}
+
+	return collate.New(tag)
+
+}
+
+// collationKey returns the key text sorts by with collator: keys compare byte by byte
+// the way the texts compare in the locale. The key is never nil, even for empty text.
+func collationKey(collator *collate.Collator, buf *collate.Buffer, text string) []byte {
+	key := append([]byte{}, collator.KeyFromString(buf, text)...)
+	buf.Reset()
+	return key
+}
@@ -0,0 +1,94 @@
+package comparator
+
+import (
+	"testing"
+
+	"L2.10/internal/flags"
+)
+
+func TestLocaleCollator(t *testing.T) {
+
+	tests := []struct {
+		lcAll, lcCollate, lang string
+		collated               bool
+	}{
+		{"", "", "", false},
+		{"", "", "C.UTF-8", false},
+		{"", "POSIX", "ru_RU.UTF-8", false},
+		{"", "", "ru_RU.UTF-8", true},
+		{"", "en_US.utf8", "C", true},
+		{"C", "en_US.UTF-8", "en_US.UTF-8", false},
+		{"", "", "ru_RU.KOI8-R", false},
+		{"", "", "de_DE@euro", true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_COLLATE", tt.lcCollate)
+		t.Setenv("LANG", tt.lang)
+		if collated := localeCollator() != nil; collated != tt.collated {
+			t.Errorf("localeCollator() with LC_ALL=%q LC_COLLATE=%q LANG=%q collates = %t, expected = %t", tt.lcAll, tt.lcCollate, tt.lang, collated, tt.collated)
+		}
+	}
+
+}
+
+func TestCollatedCompare(t *testing.T) {
+
+	tests := []struct {
+		locale       string
+		flags        *flags.Flags
+		line1, line2 string
+		expected     int
+	}{
+		{"C", new(flags.Flags), "apple", "Banana", 1},
+		{"en_US.UTF-8", new(flags.Flags), "apple", "Banana", -1},
+		{"en_US.UTF-8", new(flags.Flags), "a", "A", -1},
+		{"en_US.UTF-8", &flags.Flags{R: true}, "apple", "Banana", 1},
+		{"en_US.UTF-8", new(flags.Flags), "äpple", "zebra", -1},
+		{"sv_SE.UTF-8", new(flags.Flags), "äpple", "zebra", 1},
+		{"ru_RU.UTF-8", new(flags.Flags), "ёж", "жук", -1},
+		{"ru_RU.UTF-8", new(flags.Flags), "Яблоко", "арбуз", 1},
+		{"ru_RU.UTF-8", &flags.Flags{Keys: []flags.Key{{StartField: 1, EndField: 1, Order: flags.Order{SkipStartBlanks: true}}}}, "1 Борис", "2 анна", 1},
+		{"ru_RU.UTF-8", &flags.Flags{Keys: []flags.Key{{EndField: 0, Order: flags.Order{Numeric: true}}}}, "10 а", "9 б", 1},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.locale)
+		c := New(tt.flags)
+		if result := c.Compare(c.Prepare(tt.line1), c.Prepare(tt.line2)); result != tt.expected {
+			t.Errorf("Compare(%q, %q) in %s = %d, expected = %d", tt.line1, tt.line2, tt.locale, result, tt.expected)
+		}
+	}
+
+}
+
+func TestPrepareCollatesLinesOnlyWhenCompared(t *testing.T) {
+
+	t.Setenv("LC_ALL", "en_US.UTF-8")
+
+	key := []flags.Key{{StartField: 1, EndField: 1}}
+
+	tests := []struct {
+		flags    *flags.Flags
+		collated bool
+	}{
+		{new(flags.Flags), true},
+		{&flags.Flags{U: true}, true},
+		{&flags.Flags{S: true}, true},
+		{&flags.Flags{Keys: key}, true},
+		{&flags.Flags{Keys: key, U: true}, false},
+		{&flags.Flags{Keys: key, S: true}, false},
+	}
+
+	for _, tt := range tests {
+		line := New(tt.flags).Prepare("b a")
+		if collated := line.collated != nil; collated != tt.collated {
+			t.Errorf("Prepare with %+v: whole line collated = %t, expected = %t", *tt.flags, collated, tt.collated)
+		}
+		if len(tt.flags.Keys) > 0 && line.keys[0].collated == nil {
+			t.Errorf("Prepare with %+v: key not collated", *tt.flags)
+		}
+	}
+
+}
@@ -0,0 +1,135 @@
+package comparator
+
+import (
+	"cmp"
+	"strings"
+)
+
+// noThousandsSep stands for a locale without a thousands separator GNU sort can handle,
+// one that is not a single byte. It matches no byte.
+const noThousandsSep = -1
+
+// numericFormat holds the characters numbers are written with for -n in a locale.
+type numericFormat struct {
+	decimalPoint byte // Character separating the fractional part
+	thousandsSep int  // Character grouping the digits of the integer part, or noThousandsSep
+}
+
+// cNumericFormat is the format of the C and POSIX locales. Their thousands separator is
+// the empty string, which GNU sort takes for NUL, so NUL characters in numbers are skipped.
+var cNumericFormat = numericFormat{decimalPoint: '.', thousandsSep: 0}
+
+// numericFormats holds the format of the locales of some languages, as glibc defines it.
+// Languages grouping digits with a space use a multibyte one, so they have none for sort.
+var numericFormats = map[string]numericFormat{
+	"en": {'.', ','}, "ja": {'.', ','}, "ko": {'.', ','}, "zh": {'.', ','},
+	"da": {',', '.'}, "de": {',', '.'}, "es": {',', '.'}, "id": {',', '.'},
+	"it": {',', '.'}, "nl": {',', '.'}, "pt": {',', '.'}, "tr": {',', '.'},
+	"cs": {',', noThousandsSep}, "fi": {',', noThousandsSep}, "fr": {',', noThousandsSep},
+	"nb": {',', noThousandsSep}, "pl": {',', noThousandsSep}, "ru": {',', noThousandsSep},
+	"sv": {',', noThousandsSep}, "uk": {',', noThousandsSep},
+}
+
+// localeNumericFormat returns the format of numbers in the locale LC_ALL, LC_NUMERIC or LANG
+// names, in this order of precedence. Unknown locales are treated as the C locale.
+func localeNumericFormat() numericFormat {
+
+	lang, _ := localeParts(localeName("LC_NUMERIC"))
+	language, _, _ := strings.Cut(lang, "_")
+
+	if format, ok := numericFormats[language]; ok {
+		return format
+	}
+	return cNumericFormat
+
+}
+
+// numericValue is a number as -n reads it. It keeps the digits rather than converting
+// them, so that numbers of any length and precision compare exactly.
+type numericValue struct {
+	negative bool   // Whether the number is below zero; never set for zero
+	integer  string // Digits of the integer part, without leading zeros and thousands separators
+	fraction string // Digits of the fractional part, without trailing zeros
+}
+
+// parseNumeric reads the number at the start of text the way GNU sort -n does: after
+// leading blanks, an optional '-', digits possibly grouped by thousands separators and
+// an optional fractional part. Text without a number reads as zero.
+func parseNumeric(text string, format numericFormat) numericValue {
+
+	var value numericValue
+
+	pos := skipBlanks(text, 0)
+	if pos < len(text) && text[pos] == '-' {
+		value.negative = true
+		pos++
+	}
+
+	for pos < len(text) && (text[pos] == '0' || int(text[pos]) == format.thousandsSep) {
+		pos++
+	}
+
+	start, end := pos, pos
+	var digits []byte // Digits of the integer part, copied only once a separator is left out
+
+	for pos < len(text) && isDigit(text[pos]) {
+		if digits != nil {
+			digits = append(digits, text[pos])
+		}
+		pos++
+		end = pos
+		for pos < len(text) && int(text[pos]) == format.thousandsSep {
+			if digits == nil {
+				digits = []byte(text[start:end])
+			}
+			pos++
+		}
+	}
+
+	value.integer = text[start:end]
+	if digits != nil {
+		value.integer = string(digits)
+	}
+
+	if pos < len(text) && text[pos] == format.decimalPoint {
+		pos++
+		start = pos
+		for pos < len(text) && isDigit(text[pos]) {
+			pos++
+		}
+		value.fraction = strings.TrimRight(text[start:pos], "0")
+	}
+
+	if value.integer == "" && value.fraction == "" {
+		value.negative = false // "-0" is zero, as is "-" alone
+	}
+
+	return value
+
+}
+
+// numericComparison compares two numbers read by parseNumeric: by sign, then by
+// the number of digits of the integer parts, then digit by digit.
+func numericComparison(value1, value2 numericValue) int {
+
+	if value1.negative != value2.negative {
+		if value1.negative {
+			return -1
+		}
+		return 1
+	}
+
+	cmpRes := cmp.Compare(len(value1.integer), len(value2.integer))
+	if cmpRes == 0 {
+		cmpRes = strings.Compare(value1.integer, value2.integer)
+	}
+	if cmpRes == 0 {
+		cmpRes = strings.Compare(value1.fraction, value2.fraction)
+	}
+
+	if value1.negative {
+		return -cmpRes
+	}
+	return cmpRes
+
+}
@@ -0,0 +1,58 @@
+package comparator
+
+import (
+	"testing"
+)
+
+func TestParseNumeric(t *testing.T) {
+
+	english := numericFormat{decimalPoint: '.', thousandsSep: ','}
+	german := numericFormat{decimalPoint: ',', thousandsSep: '.'}
+
+	tests := []struct {
+		text     string
+		format   numericFormat
+		expected numericValue
+	}{
+		{"  -0012.3400x", cNumericFormat, numericValue{negative: true, integer: "12", fraction: "34"}},
+		{"-0.0", cNumericFormat, numericValue{}},
+		{"1,234", cNumericFormat, numericValue{integer: "1"}},
+		{"1,234,567.5", english, numericValue{integer: "1234567", fraction: "5"}},
+		{",5", english, numericValue{integer: "5"}},
+		{"12,", english, numericValue{integer: "12"}},
+		{"1.234,5", german, numericValue{integer: "1234", fraction: "5"}},
+		{"1 234", numericFormat{decimalPoint: ',', thousandsSep: noThousandsSep}, numericValue{integer: "1"}},
+	}
+
+	for _, tt := range tests {
+		if result := parseNumeric(tt.text, tt.format); result != tt.expected {
+			t.Errorf("parseNumeric(%q, %+v) = %+v, expected = %+v", tt.text, tt.format, result, tt.expected)
+		}
+	}
+
+}
+
+func TestLocaleNumericFormat(t *testing.T) {
+
+	tests := []struct {
+		lcAll, lcNumeric, lang string
+		expected               numericFormat
+	}{
+		{"", "", "", cNumericFormat},
+		{"", "", "C.UTF-8", cNumericFormat},
+		{"", "", "en_US.UTF-8", numericFormat{decimalPoint: '.', thousandsSep: ','}},
+		{"", "de_DE.UTF-8", "en_US.UTF-8", numericFormat{decimalPoint: ',', thousandsSep: '.'}},
+		{"POSIX", "de_DE.UTF-8", "en_US.UTF-8", cNumericFormat},
+		{"", "", "fr_FR@euro", numericFormat{decimalPoint: ',', thousandsSep: noThousandsSep}},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_NUMERIC", tt.lcNumeric)
+		t.Setenv("LANG", tt.lang)
+		if result := localeNumericFormat(); result != tt.expected {
+			t.Errorf("localeNumericFormat() with LC_ALL=%q LC_NUMERIC=%q LANG=%q = %+v, expected = %+v", tt.lcAll, tt.lcNumeric, tt.lang, result, tt.expected)
+		}
+	}
+
+}
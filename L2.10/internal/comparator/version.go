@@ -0,0 +1,136 @@
+package comparator
+
+// versionComparison compares lines as version numbers the way GNU sort -V does,
+// using the algorithm of gnulib's filevercmp: runs of digits compare by numeric
+// value, '~' sorts before anything, even the end of a line, and file suffixes
+// such as ".tar.gz" are only taken into account if the rest of the lines is equal.
+func versionComparison(line1, line2 string) int {
+
+	switch {
+	case line1 == "" || line2 == "":
+		return stringComparison(line1, line2)
+	case line1[0] == '.' && line2[0] != '.':
+		return -1
+	case line1[0] != '.' && line2[0] == '.':
+		return 1
+	case line1[0] == '.': // "." sorts first, then "..", then other names starting with a dot
+		for _, special := range []string{".", ".."} {
+			switch {
+			case line1 == special && line2 == special:
+				return 0
+			case line1 == special:
+				return -1
+			case line2 == special:
+				return 1
+			}
+		}
+	}
+
+	prefix1 := line1[:versionPrefixLen(line1)]
+	prefix2 := line2[:versionPrefixLen(line2)]
+
+	if cmpRes := compareVersions(prefix1, prefix2); cmpRes != 0 || (prefix1 == line1 && prefix2 == line2) {
+		return cmpRes
+	}
+	return compareVersions(line1, line2)
+
+}
+
+// versionPrefixLen returns the length of line without its file suffix, the longest
+// suffix matching the regular expression (\.[A-Za-z~][A-Za-z0-9~]*)*$.
+func versionPrefixLen(line string) int {
+
+	prefixLen := 0
+
+	for i := 0; i < len(line); {
+		i++
+		prefixLen = i
+		for i+1 < len(line) && line[i] == '.' && (isAlpha(line[i+1]) || line[i+1] == '~') {
+			i += 2
+			for i < len(line) && (isAlnum(line[i]) || line[i] == '~') {
+				i++
+			}
+		}
+	}
+
+	return prefixLen
+
+}
+
+// compareVersions compares alternating runs of non-digits and digits of two
+// strings, as the Debian version comparison does.
+func compareVersions(s1, s2 string) int {
+
+	pos1, pos2 := 0, 0
+
+	for pos1 < len(s1) || pos2 < len(s2) {
+
+		for pos1 < len(s1) && !isDigit(s1[pos1]) || pos2 < len(s2) && !isDigit(s2[pos2]) {
+			if order1, order2 := versionOrder(s1, pos1), versionOrder(s2, pos2); order1 != order2 {
+				return order1 - order2
+			}
+			pos1++
+			pos2++
+		}
+
+		for pos1 < len(s1) && s1[pos1] == '0' {
+			pos1++
+		}
+		for pos2 < len(s2) && s2[pos2] == '0' {
+			pos2++
+		}
+
+		firstDiff := 0
+		for pos1 < len(s1) && pos2 < len(s2) && isDigit(s1[pos1]) && isDigit(s2[pos2]) {
+			if firstDiff == 0 {
+				firstDiff = int(s1[pos1]) - int(s2[pos2])
+			}
+			pos1++
+			pos2++
+		}
+
+		switch {
+		case pos1 < len(s1) && isDigit(s1[pos1]):
+			return 1
+		case pos2 < len(s2) && isDigit(s2[pos2]):
+			return -1
+		case firstDiff != 0:
+			return firstDiff
+		}
+
+	}
+
+	return 0
+
+}
+
+// versionOrder returns the weight of the character at pos of a non-digit run:
+// '~' sorts first, then the end of the string, then letters, then everything else.
+func versionOrder(s string, pos int) int {
+
+	if pos >= len(s) {
+		return -1
+	}
+
+	switch c := s[pos]; {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -2
+	default:
+		return int(c) + 256
+	}
+
+}
+
+// isDigit reports whether c is a decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// isAlpha reports whether c is a letter in the C locale.
+func isAlpha(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
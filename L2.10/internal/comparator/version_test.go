@@ -0,0 +1,39 @@
+package comparator
+
+import (
+	"testing"
+)
+
+func TestVersionComparison(t *testing.T) {
+
+	tests := []struct {
+		line1, line2 string
+		expected     int
+	}{
+		{"1.2", "1.10", -1},
+		{"1.010", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"a", "B", 1},
+		{"a1", "a-1", -1},
+		{"foo-1.2.tar.gz", "foo-1.10.tar.gz", -1},
+		{"foo.tar.gz", "foo.tar", 1},
+		{".", "..", -1},
+		{"..", ".a", -1},
+		{".b", "a", -1},
+		{"", "a", -1},
+		{"2.0", "2.0", 0},
+	}
+
+	for _, tt := range tests {
+		result := versionComparison(tt.line1, tt.line2)
+		if result > 0 {
+			result = 1
+		} else if result < 0 {
+			result = -1
+		}
+		if result != tt.expected {
+			t.Errorf("versionComparison(%q, %q) = %d, expected = %d", tt.line1, tt.line2, result, tt.expected)
+		}
+	}
+
+}
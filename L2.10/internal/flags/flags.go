@@ -4,35 +4,72 @@ package flags
 
 import (
 	"fmt"
-	"strconv"
-	"strings"
-	"sync/atomic"
 
 	"github.com/spf13/pflag"
 )
 
 // Flags holds all available command-line options for the sorting utility.
 type Flags struct {
-	K          bool          // Sort via a key (-k)
-	N          bool          // Numeric sort (-n)
-	R          bool          // Reverse order sort (-r)
-	U          bool          // Unique output (-u)
-	M          bool          // Month sort (-M)
-	B          bool          // Ignore leading blanks (-b)
-	C          bool          // Check if input is sorted without sorting (-c)
-	H          bool          // Human-readable numeric sort (-h)
-	kRaw       string        // Raw -k option value, used to determine the key column
-	Blanks     *atomic.Int32 // Counter of blank lines, relevant when -k is active
-	ClmnToSort int           // Index of the column to sort when -k is used
+	N           bool     // Numeric sort (-n)
+	R           bool     // Reverse order sort (-r)
+	U           bool     // Unique output (-u)
+	M           bool     // Month sort (-M)
+	B           bool     // Ignore leading blanks (-b)
+	C           bool     // Check if input is sorted without sorting (-c)
+	H           bool     // Human-readable numeric sort (-h)
+	S           bool     // Stable sort, without the last-resort comparison (-s)
+	G           bool     // General numeric sort (-g)
+	V           bool     // Natural sort of version numbers (-V)
+	D           bool     // Consider only blanks and alphanumeric characters (-d)
+	F           bool     // Fold lower case to upper case characters (-f)
+	I           bool     // Consider only printable characters (-i)
+	Z           bool     // Lines end with NUL rather than newline (-z)
+	Merge       bool     // Merge already sorted inputs without sorting them (-m)
+	Random      bool     // Shuffle, keeping equal keys together (-R)
+	T           string   // Field separator (-t); empty if fields are separated by blanks
+	O           string   // Output file (-o); empty for standard output
+	Keys        []Key    // Sort keys in the order they were given (-k)
+	BufferSize  int64    // Memory budget for chunks in bytes (-S); 0 if chunks are sized in lines
+	BatchSize   int      // Maximum number of chunks merged at once (--batch-size)
+	TempDirs    []string // Directories for temporary files (-T); empty for $TMPDIR or /tmp
+	Compress    string   // Program that compresses temporary files (--compress-program); empty for none
+	Salt        []byte   // Seed of the hash random keys are compared by (--random-source); nil without them
+	keysRaw     []string // Raw -k option values, one per occurrence
+	tRaw        []string // Raw -t option values, one per occurrence
+	oRaw        []string // Raw -o option values, one per occurrence
+	sizeRaw     []string // Raw -S option values, one per occurrence
+	batchRaw    []string // Raw --batch-size option values, one per occurrence
+	compressRaw []string // Raw --compress-program option values, one per occurrence
+	randomRaw   []string // Raw --random-source option values, one per occurrence
+}
+
+// Error describes an invalid command-line option. Its message is worded
+// like the one GNU sort prints for the same mistake.
+type Error struct {
+	msg  string
+	note string
+}
+
+// Error returns the message without the "sort: " prefix.
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Note returns the second line GNU sort prints for some mistakes, such as the
+// limit an option exceeds, or an empty string if there is none.
+func (e *Error) Note() string {
+	return e.note
 }
 
 // Parse parses the command-line flags and returns a Flags struct.
-// Returns an error if parsing or -k key processing fails.
+// Returns an error if a -k key definition, the -t separator, the -S size or
+// the --batch-size is invalid, if options contradict each other, or if the
+// --random-source cannot be read.
 func Parse() (*Flags, error) {
 
-	flags := &Flags{Blanks: new(atomic.Int32)}
+	flags := new(Flags)
 
-	pflag.StringVarP(&flags.kRaw, "key", "k", "", "sort via a key; KEYDEF gives location and type")
+	pflag.StringArrayVarP(&flags.keysRaw, "key", "k", nil, "sort via a key; KEYDEF gives location and type")
 	pflag.BoolVarP(&flags.N, "numeric-sort", "n", false, "compare according to string numerical value")
 	pflag.BoolVarP(&flags.R, "reverse", "r", false, "reverse the result of comparisons")
 	pflag.BoolVarP(&flags.U, "unique", "u", false, "with -c, check for strict ordering;\n  without -c, output only the first of an equal run")
@@ -40,36 +77,163 @@ func Parse() (*Flags, error) {
 	pflag.BoolVarP(&flags.B, "ignore-leading-blanks", "b", false, "ignore leading blanks")
 	pflag.BoolVarP(&flags.C, "check", "c", false, "check for sorted input; do not sort")
 	pflag.BoolVarP(&flags.H, "human-numeric-sort", "h", false, "compare human readable numbers (e.g., 2K 1G)")
+	pflag.BoolVarP(&flags.S, "stable", "s", false, "stabilize sort by disabling last-resort comparison")
+	pflag.BoolVarP(&flags.G, "general-numeric-sort", "g", false, "compare according to general numerical value")
+	pflag.BoolVarP(&flags.V, "version-sort", "V", false, "natural sort of (version) numbers within text")
+	pflag.BoolVarP(&flags.D, "dictionary-order", "d", false, "consider only blanks and alphanumeric characters")
+	pflag.BoolVarP(&flags.F, "ignore-case", "f", false, "fold lower case to upper case characters")
+	pflag.BoolVarP(&flags.I, "ignore-nonprinting", "i", false, "consider only printable characters")
+	pflag.BoolVarP(&flags.Z, "zero-terminated", "z", false, "line delimiter is NUL, not newline")
+	pflag.BoolVarP(&flags.Merge, "merge", "m", false, "merge already sorted files; do not sort")
+	pflag.BoolVarP(&flags.Random, "random-sort", "R", false, "shuffle, but group identical keys")
+	pflag.StringArrayVarP(&flags.tRaw, "field-separator", "t", nil, "use SEP instead of non-blank to blank transition")
+	pflag.StringArrayVarP(&flags.oRaw, "output", "o", nil, "write result to FILE instead of standard output")
+	pflag.StringArrayVarP(&flags.sizeRaw, "buffer-size", "S", nil, "use SIZE for main memory buffer")
+	pflag.StringArrayVarP(&flags.TempDirs, "temporary-directory", "T", nil, "use DIR for temporaries, not $TMPDIR or /tmp;\n  multiple options specify multiple directories")
+	pflag.StringArrayVar(&flags.compressRaw, "compress-program", nil, "compress temporaries with PROG;\n  decompress them with PROG -d; gzip and zstd are built in")
+	pflag.StringArrayVar(&flags.randomRaw, "random-source", nil, "get random bytes from FILE")
+	pflag.StringArrayVar(&flags.batchRaw, "batch-size", nil, fmt.Sprintf("merge at most NMERGE inputs at once (default %d)", DefaultBatchSize))
 
 	pflag.Parse()
 
-	if err := kCheck(&flags.K, flags.kRaw, &flags.ClmnToSort); err != nil {
-		return nil, fmt.Errorf("-k: %w", err)
+	var err error
+
+	if flags.T, err = parseSeparator(flags.tRaw); err != nil {
+		return nil, err
+	}
+
+	if flags.O, err = parseOutput(flags.oRaw); err != nil {
+		return nil, err
+	}
+
+	if flags.Compress, err = parseCompressProgram(flags.compressRaw); err != nil {
+		return nil, err
+	}
+
+	source, err := parseRandomSource(flags.randomRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	if flags.BufferSize, err = parseBufferSize(flags.sizeRaw); err != nil {
+		return nil, err
+	}
+
+	if flags.BatchSize, err = parseBatchSize(flags.batchRaw); err != nil {
+		return nil, err
+	}
+
+	for _, keyDef := range flags.keysRaw {
+		key, err := parseKey(keyDef)
+		if err != nil {
+			return nil, err
+		}
+		flags.Keys = append(flags.Keys, key)
+	}
+
+	if err := checkCompatibility(flags.SortKeys()); err != nil {
+		return nil, err
+	}
+
+	if flags.C && flags.O != "" {
+		return nil, &Error{msg: "options '-co' are incompatible"}
+	}
+
+	if hasRandomKey(flags.SortKeys()) {
+		if flags.Salt, err = readSalt(source); err != nil {
+			return nil, err
+		}
 	}
 
 	return flags, nil
 
 }
 
-// kCheck validates and parses the -k key option.
-// It sets the flagK boolean to true if -k is provided, and extracts the column to sort.
-// Returns an error if the column number cannot be converted to an integer.
-func kCheck(flagK *bool, kString string, clmnToSort *int) error {
-	if kString != "" {
-		*flagK = true
-		fields := strings.FieldsSeq(kString)
-		for number := range fields {
-			if kInt, err := strconv.Atoi(number); err != nil {
-				return fmt.Errorf("unable to convert number using %w", err)
-			} else {
-				if kInt > 0 {
-					*clmnToSort = kInt - 1
-				} else {
-					*clmnToSort = 0
-				}
-				break
-			}
+// parseSeparator validates the -t values and returns the field separator.
+// The value "\0" stands for the NUL character. Repeating -t is only allowed
+// with the same separator.
+func parseSeparator(values []string) (string, error) {
+
+	var sep string
+
+	for _, value := range values {
+
+		switch {
+		case value == "":
+			return "", &Error{msg: "empty tab"}
+		case value == `\0`:
+			value = "\x00"
+		case len(value) > 1:
+			return "", &Error{msg: fmt.Sprintf("multi-character tab '%s'", value)}
+		}
+
+		if sep != "" && sep != value {
+			return "", &Error{msg: "incompatible tabs"}
+		}
+		sep = value
+
+	}
+
+	return sep, nil
+
+}
+
+// parseOutput validates the -o values and returns the output file.
+// Repeating -o is only allowed with the same file.
+func parseOutput(values []string) (string, error) {
+
+	var output string
+
+	for _, value := range values {
+		if output != "" && output != value {
+			return "", &Error{msg: "multiple output files specified"}
+		}
+		output = value
+	}
+
+	return output, nil
+
+}
+
+// parseCompressProgram validates the --compress-program values and returns the program.
+// Repeating --compress-program is only allowed with the same program.
+func parseCompressProgram(values []string) (string, error) {
+
+	var program string
+
+	for _, value := range values {
+		if program != "" && program != value {
+			return "", &Error{msg: "multiple compress programs specified"}
 		}
+		program = value
+	}
+
+	return program, nil
+
+}
+
+// LineEnd returns the character lines end with in the input, the chunks and the output.
+func (f *Flags) LineEnd() byte {
+	if f.Z {
+		return 0
+	}
+	return '\n'
+}
+
+// order returns the ordering options given globally rather than for a single key.
+func (f *Flags) order() Order {
+	return Order{
+		SkipStartBlanks:   f.B,
+		SkipEndBlanks:     f.B,
+		Dictionary:        f.D,
+		FoldCase:          f.F,
+		GeneralNumeric:    f.G,
+		HumanNumeric:      f.H,
+		IgnoreNonprinting: f.I,
+		Month:             f.M,
+		Numeric:           f.N,
+		Random:            f.Random,
+		Reverse:           f.R,
+		Version:           f.V,
 	}
-	return nil
 }
@@ -0,0 +1,60 @@
+package flags
+
+import (
+	"testing"
+)
+
+func TestParseSeparator(t *testing.T) {
+
+	tests := []struct {
+		values   []string
+		expected string
+		err      string
+	}{
+		{nil, "", ""},
+		{[]string{":"}, ":", ""},
+		{[]string{",", ","}, ",", ""},
+		{[]string{`\0`}, "\x00", ""},
+		{[]string{""}, "", "empty tab"},
+		{[]string{"ab"}, "", "multi-character tab 'ab'"},
+		{[]string{":", ","}, "", "incompatible tabs"},
+	}
+
+	for _, tt := range tests {
+		sep, err := parseSeparator(tt.values)
+		var errMsg string
+		if err != nil {
+			errMsg = err.Error()
+		}
+		if sep != tt.expected || errMsg != tt.err {
+			t.Errorf("parseSeparator(%q) = %q, %q, expected = %q, %q", tt.values, sep, errMsg, tt.expected, tt.err)
+		}
+	}
+
+}
+
+func TestParseOutput(t *testing.T) {
+
+	tests := []struct {
+		values   []string
+		expected string
+		err      string
+	}{
+		{nil, "", ""},
+		{[]string{"out"}, "out", ""},
+		{[]string{"out", "out"}, "out", ""},
+		{[]string{"out", "other"}, "", "multiple output files specified"},
+	}
+
+	for _, tt := range tests {
+		output, err := parseOutput(tt.values)
+		var errMsg string
+		if err != nil {
+			errMsg = err.Error()
+		}
+		if output != tt.expected || errMsg != tt.err {
+			t.Errorf("parseOutput(%q) = %q, %q, expected = %q, %q", tt.values, output, errMsg, tt.expected, tt.err)
+		}
+	}
+
+}
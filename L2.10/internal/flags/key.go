@@ -0,0 +1,245 @@
+package flags
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Key is a sort key parsed from a -k KEYDEF of the form F[.C][OPTS][,F[.C][OPTS]].
+// Fields and characters are counted from zero, unlike in the KEYDEF itself.
+type Key struct {
+	StartField int // Field the key starts in
+	StartChar  int // Character of the start field the key starts at
+	EndField   int // Field the key ends in; -1 if the key extends to the end of the line
+	EndChar    int // Number of characters of the end field the key includes; 0 for the whole field
+	Order          // Ordering options that apply to the key
+}
+
+// Order holds the ordering options of a key, given either after a KEYDEF
+// position or globally.
+type Order struct {
+	SkipStartBlanks   bool // Ignore leading blanks of the start field (b)
+	SkipEndBlanks     bool // Ignore leading blanks of the end field (b)
+	Dictionary        bool // Consider only blanks and alphanumeric characters (d)
+	FoldCase          bool // Fold lower case to upper case characters (f)
+	GeneralNumeric    bool // Compare according to general numerical value (g)
+	HumanNumeric      bool // Compare human readable numbers (h)
+	IgnoreNonprinting bool // Consider only printable characters (i)
+	Month             bool // Compare month names (M)
+	Numeric           bool // Compare according to string numerical value (n)
+	Random            bool // Shuffle, keeping equal keys together (R)
+	Reverse           bool // Reverse the result of comparisons (r)
+	Version           bool // Natural sort of version numbers within text (V)
+}
+
+// SortKeys returns the keys lines are compared by, in order. A key given
+// without ordering options inherits the global ones. Without -k, global
+// ordering options other than -r apply to a key spanning the whole line;
+// without either, there are no keys and lines are compared as a whole.
+func (f *Flags) SortKeys() []Key {
+
+	global := f.order()
+
+	if len(f.Keys) == 0 {
+		if inherited := (Order{Reverse: global.Reverse}); global == inherited {
+			return nil
+		}
+		return []Key{{EndField: -1, Order: global}}
+	}
+
+	keys := make([]Key, len(f.Keys))
+	for i, key := range f.Keys {
+		if key.Order == (Order{}) {
+			key.Order = global
+		}
+		keys[i] = key
+	}
+
+	return keys
+
+}
+
+// parseKey parses a KEYDEF. Errors are worded like those of GNU sort.
+func parseKey(def string) (Key, error) {
+
+	key := Key{EndField: -1}
+
+	rest, field, err := parseCount(def, "invalid number at field start")
+	if err != nil {
+		return Key{}, err
+	}
+	if field == 0 {
+		return Key{}, badFieldSpec(def, "field number is zero")
+	}
+	key.StartField = field - 1
+
+	if strings.HasPrefix(rest, ".") {
+		var char int
+		if rest, char, err = parseCount(rest[1:], "invalid number after '.'"); err != nil {
+			return Key{}, err
+		}
+		if char == 0 {
+			return Key{}, badFieldSpec(def, "character offset is zero")
+		}
+		key.StartChar = char - 1
+	}
+
+	rest = parseOrder(rest, &key.Order, false)
+
+	if strings.HasPrefix(rest, ",") {
+
+		if rest, field, err = parseCount(rest[1:], "invalid number after ','"); err != nil {
+			return Key{}, err
+		}
+		if field == 0 {
+			return Key{}, badFieldSpec(def, "field number is zero")
+		}
+		key.EndField = field - 1
+
+		if strings.HasPrefix(rest, ".") {
+			if rest, key.EndChar, err = parseCount(rest[1:], "invalid number after '.'"); err != nil {
+				return Key{}, err
+			}
+		}
+
+		rest = parseOrder(rest, &key.Order, true)
+
+	}
+
+	if rest != "" {
+		return Key{}, badFieldSpec(def, "stray character in field spec")
+	}
+
+	return key, nil
+
+}
+
+// parseCount parses the unsigned decimal number s starts with and returns the rest of s.
+// Numbers too large to be meaningful are clamped to math.MaxInt.
+func parseCount(s string, msg string) (rest string, count int, err error) {
+
+	digits := strings.TrimLeft(s, " \t\n\v\f\r")
+	digits = strings.TrimPrefix(digits, "+")
+
+	end := 0
+	for end < len(digits) && '0' <= digits[end] && digits[end] <= '9' {
+		if count > (math.MaxInt-9)/10 {
+			count = math.MaxInt
+		} else if count != math.MaxInt {
+			count = count*10 + int(digits[end]-'0')
+		}
+		end++
+	}
+
+	if end == 0 {
+		return "", 0, &Error{msg: fmt.Sprintf("%s: invalid count at start of '%s'", msg, s)}
+	}
+
+	return digits[end:], count, nil
+
+}
+
+// parseOrder applies the ordering options s starts with to order and returns the rest of s.
+// A 'b' applies to the end field if the options follow the end position, and to the start field otherwise.
+func parseOrder(s string, order *Order, end bool) string {
+
+	for i := range len(s) {
+		switch s[i] {
+		case 'b':
+			if end {
+				order.SkipEndBlanks = true
+			} else {
+				order.SkipStartBlanks = true
+			}
+		case 'd':
+			order.Dictionary = true
+		case 'f':
+			order.FoldCase = true
+		case 'g':
+			order.GeneralNumeric = true
+		case 'h':
+			order.HumanNumeric = true
+		case 'i':
+			order.IgnoreNonprinting = true
+		case 'M':
+			order.Month = true
+		case 'n':
+			order.Numeric = true
+		case 'R':
+			order.Random = true
+		case 'r':
+			order.Reverse = true
+		case 'V':
+			order.Version = true
+		default:
+			return s[i:]
+		}
+	}
+
+	return ""
+
+}
+
+// badFieldSpec reports an invalid KEYDEF the way GNU sort does.
+func badFieldSpec(def string, msg string) error {
+	return &Error{msg: fmt.Sprintf("%s: invalid field specification '%s'", msg, def)}
+}
+
+// checkCompatibility reports keys that combine ordering options which cannot
+// apply together, such as numeric and month sorting.
+func checkCompatibility(keys []Key) error {
+
+	for _, key := range keys {
+
+		modes := 0
+		for _, set := range []bool{
+			key.Numeric,
+			key.GeneralNumeric,
+			key.HumanNumeric,
+			key.Month,
+			key.Version || key.Random || key.Dictionary || key.IgnoreNonprinting,
+		} {
+			if set {
+				modes++
+			}
+		}
+
+		if modes > 1 {
+			return &Error{msg: fmt.Sprintf("options '-%s' are incompatible", key.Order.letters())}
+		}
+
+	}
+
+	return nil
+
+}
+
+// letters returns the options of an incompatible combination in the order GNU sort lists them.
+// Blank skipping and reversal never conflict and are left out.
+func (o Order) letters() string {
+
+	var sb strings.Builder
+
+	for _, opt := range []struct {
+		set    bool
+		letter byte
+	}{
+		{o.Dictionary, 'd'},
+		{o.FoldCase, 'f'},
+		{o.GeneralNumeric, 'g'},
+		{o.HumanNumeric, 'h'},
+		{o.IgnoreNonprinting && !o.Dictionary, 'i'}, // -d already ignores everything -i does
+		{o.Month, 'M'},
+		{o.Numeric, 'n'},
+		{o.Random, 'R'},
+		{o.Version, 'V'},
+	} {
+		if opt.set {
+			sb.WriteByte(opt.letter)
+		}
+	}
+
+	return sb.String()
+
+}
@@ -0,0 +1,140 @@
+package flags
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+
+	tests := []struct {
+		def      string
+		expected Key
+	}{
+		{"2", Key{StartField: 1, EndField: -1}},
+		{"2,2", Key{StartField: 1, EndField: 1}},
+		{"3.2,3.5", Key{StartField: 2, StartChar: 1, EndField: 2, EndChar: 5}},
+		{"2,3.0", Key{StartField: 1, EndField: 2}},
+		{"2n", Key{StartField: 1, EndField: -1, Order: Order{Numeric: true}}},
+		{"1,1r", Key{EndField: 0, Order: Order{Reverse: true}}},
+		{"2b,2", Key{StartField: 1, EndField: 1, Order: Order{SkipStartBlanks: true}}},
+		{"2,2b", Key{StartField: 1, EndField: 1, Order: Order{SkipEndBlanks: true}}},
+		{"2.3b,3.1nr", Key{StartField: 1, StartChar: 2, EndField: 2, EndChar: 1, Order: Order{SkipStartBlanks: true, Numeric: true, Reverse: true}}},
+		{"1dfgi,1hMV", Key{EndField: 0, Order: Order{Dictionary: true, FoldCase: true, GeneralNumeric: true, IgnoreNonprinting: true, HumanNumeric: true, Month: true, Version: true}}},
+		{"+1", Key{EndField: -1}},
+		{"99999999999999999999999", Key{StartField: math.MaxInt - 1, EndField: -1}},
+	}
+
+	for _, tt := range tests {
+		key, err := parseKey(tt.def)
+		if err != nil {
+			t.Errorf("parseKey(%q) returned error: %v", tt.def, err)
+			continue
+		}
+		if key != tt.expected {
+			t.Errorf("parseKey(%q) = %+v, expected = %+v", tt.def, key, tt.expected)
+		}
+	}
+
+}
+
+func TestParseKeyErrors(t *testing.T) {
+
+	tests := []struct {
+		def      string
+		expected string
+	}{
+		{"0", "field number is zero: invalid field specification '0'"},
+		{"1,0", "field number is zero: invalid field specification '1,0'"},
+		{"1.0", "character offset is zero: invalid field specification '1.0'"},
+		{"a", "invalid number at field start: invalid count at start of 'a'"},
+		{"", "invalid number at field start: invalid count at start of ''"},
+		{"-1", "invalid number at field start: invalid count at start of '-1'"},
+		{"1.a", "invalid number after '.': invalid count at start of 'a'"},
+		{"1,a", "invalid number after ',': invalid count at start of 'a'"},
+		{"1,1.a", "invalid number after '.': invalid count at start of 'a'"},
+		{"1x", "stray character in field spec: invalid field specification '1x'"},
+		{"1,2nx", "stray character in field spec: invalid field specification '1,2nx'"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseKey(tt.def)
+		if err == nil {
+			t.Errorf("parseKey(%q) returned no error, expected %q", tt.def, tt.expected)
+			continue
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("parseKey(%q) error = %q, expected = %q", tt.def, err.Error(), tt.expected)
+		}
+	}
+
+}
+
+func TestSortKeys(t *testing.T) {
+
+	t.Run("no options", func(t *testing.T) {
+		if keys := (&Flags{R: true, U: true}).SortKeys(); keys != nil {
+			t.Errorf("SortKeys() = %+v, expected none", keys)
+		}
+	})
+
+	t.Run("global options", func(t *testing.T) {
+		keys := (&Flags{N: true, R: true}).SortKeys()
+		expected := Key{EndField: -1, Order: Order{Numeric: true, Reverse: true}}
+		if len(keys) != 1 || keys[0] != expected {
+			t.Errorf("SortKeys() = %+v, expected = [%+v]", keys, expected)
+		}
+	})
+
+	t.Run("global modifiers", func(t *testing.T) {
+		keys := (&Flags{D: true, F: true, I: true, V: true}).SortKeys()
+		expected := Key{EndField: -1, Order: Order{Dictionary: true, FoldCase: true, IgnoreNonprinting: true, Version: true}}
+		if len(keys) != 1 || keys[0] != expected {
+			t.Errorf("SortKeys() = %+v, expected = [%+v]", keys, expected)
+		}
+	})
+
+	t.Run("inherited options", func(t *testing.T) {
+		flags := &Flags{B: true, M: true, Keys: []Key{
+			{StartField: 1, EndField: 1},
+			{EndField: 0, Order: Order{Reverse: true}},
+		}}
+		keys := flags.SortKeys()
+		expected := []Key{
+			{StartField: 1, EndField: 1, Order: Order{SkipStartBlanks: true, SkipEndBlanks: true, Month: true}},
+			{EndField: 0, Order: Order{Reverse: true}},
+		}
+		if len(keys) != len(expected) || keys[0] != expected[0] || keys[1] != expected[1] {
+			t.Errorf("SortKeys() = %+v, expected = %+v", keys, expected)
+		}
+	})
+
+}
+
+func TestCheckCompatibility(t *testing.T) {
+
+	tests := []struct {
+		order    Order
+		expected string
+	}{
+		{Order{Numeric: true, Reverse: true, SkipStartBlanks: true}, ""},
+		{Order{Version: true, Dictionary: true, IgnoreNonprinting: true}, ""},
+		{Order{Numeric: true, Month: true}, "options '-Mn' are incompatible"},
+		{Order{Numeric: true, Dictionary: true, FoldCase: true, Reverse: true}, "options '-dfn' are incompatible"},
+		{Order{GeneralNumeric: true, IgnoreNonprinting: true}, "options '-gi' are incompatible"},
+		{Order{Random: true, Version: true, FoldCase: true}, ""},
+		{Order{Numeric: true, Random: true, Reverse: true}, "options '-nR' are incompatible"},
+		{Order{Month: true, Random: true}, "options '-MR' are incompatible"},
+	}
+
+	for _, tt := range tests {
+		var result string
+		if err := checkCompatibility([]Key{{Order: tt.order}}); err != nil {
+			result = err.Error()
+		}
+		if result != tt.expected {
+			t.Errorf("checkCompatibility(%+v) = %q, expected = %q", tt.order, result, tt.expected)
+		}
+	}
+
+}
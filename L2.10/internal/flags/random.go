@@ -0,0 +1,73 @@
+package flags
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"L2.10/internal/syserr"
+)
+
+// saltSize is the number of bytes the hash of random keys is seeded with. GNU sort reads
+// as many from the --random-source, so the same source gives the same order as with it.
+const saltSize = 16
+
+// parseRandomSource validates the --random-source values and returns the file.
+// Repeating --random-source is only allowed with the same file.
+func parseRandomSource(values []string) (string, error) {
+
+	var source string
+
+	for _, value := range values {
+		if source != "" && source != value {
+			return "", &Error{msg: "multiple random sources specified"}
+		}
+		source = value
+	}
+
+	return source, nil
+
+}
+
+// hasRandomKey reports whether any of the keys is compared by -R.
+func hasRandomKey(keys []Key) bool {
+	for _, key := range keys {
+		if key.Random {
+			return true
+		}
+	}
+	return false
+}
+
+// readSalt returns the seed of the hash random keys are compared by: the first saltSize
+// bytes of source, or bytes from the system random generator if there is no source.
+// Errors are worded like those of GNU sort.
+func readSalt(source string) ([]byte, error) {
+
+	salt := make([]byte, saltSize)
+
+	if source == "" {
+		if _, err := rand.Read(salt); err != nil {
+			return nil, &Error{msg: fmt.Sprintf("cannot read random bytes: %v", err)}
+		}
+		return salt, nil
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, &Error{msg: fmt.Sprintf("open failed: %s: %s", source, syserr.Describe(err))}
+	}
+	defer file.Close()
+
+	switch _, err := io.ReadFull(file, salt); {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		return nil, &Error{msg: fmt.Sprintf("'%s': end of file", source)}
+	case err != nil:
+		return nil, &Error{msg: fmt.Sprintf("'%s': read error: %s", source, syserr.Describe(err))}
+	}
+
+	return salt, nil
+
+}
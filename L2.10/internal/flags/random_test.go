@@ -0,0 +1,73 @@
+package flags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRandomSource(t *testing.T) {
+
+	tests := []struct {
+		values   []string
+		expected string
+		err      string
+	}{
+		{nil, "", ""},
+		{[]string{"seed"}, "seed", ""},
+		{[]string{"seed", "seed"}, "seed", ""},
+		{[]string{"seed", "other"}, "", "multiple random sources specified"},
+	}
+
+	for _, tt := range tests {
+		source, err := parseRandomSource(tt.values)
+		var errMsg string
+		if err != nil {
+			errMsg = err.Error()
+		}
+		if source != tt.expected || errMsg != tt.err {
+			t.Errorf("parseRandomSource(%q) = %q, %q, expected = %q, %q", tt.values, source, errMsg, tt.expected, tt.err)
+		}
+	}
+
+}
+
+func TestReadSalt(t *testing.T) {
+
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "seed")
+	short := filepath.Join(dir, "short")
+	missing := filepath.Join(dir, "missing")
+
+	if err := os.WriteFile(seed, []byte("0123456789abcdefXYZ"), 0o600); err != nil {
+		t.Fatalf("failed to write random source: %v", err)
+	}
+	if err := os.WriteFile(short, []byte("0123"), 0o600); err != nil {
+		t.Fatalf("failed to write random source: %v", err)
+	}
+
+	if salt, err := readSalt(seed); err != nil || string(salt) != "0123456789abcdef" {
+		t.Errorf("readSalt(seed) = %q, %v, expected = %q", salt, err, "0123456789abcdef")
+	}
+
+	salt1, err1 := readSalt("")
+	salt2, err2 := readSalt("")
+	if err1 != nil || err2 != nil || len(salt1) != saltSize || bytes.Equal(salt1, salt2) {
+		t.Errorf("readSalt without source = %x, %x, expected two different salts of %d bytes", salt1, salt2, saltSize)
+	}
+
+	for _, tt := range []struct {
+		source   string
+		expected string
+	}{
+		{short, "'" + short + "': end of file"},
+		{missing, "open failed: " + missing + ": No such file or directory"},
+		{dir, "'" + dir + "': read error: Is a directory"},
+	} {
+		if _, err := readSalt(tt.source); err == nil || err.Error() != tt.expected {
+			t.Errorf("readSalt(%q) error = %v, expected = %q", tt.source, err, tt.expected)
+		}
+	}
+
+}
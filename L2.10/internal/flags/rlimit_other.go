@@ -0,0 +1,11 @@
+//go:build !unix
+
+package flags
+
+import "math"
+
+// openFilesLimit returns the number of files the process may have open at once,
+// which is not limited on this platform.
+func openFilesLimit() int {
+	return math.MaxInt
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package flags
+
+import (
+	"math"
+	"syscall"
+)
+
+// openFilesLimit returns the number of files the process may have open at once.
+func openFilesLimit() int {
+
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil || limit.Cur > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(limit.Cur)
+
+}
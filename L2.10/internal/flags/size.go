@@ -0,0 +1,189 @@
+package flags
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultBatchSize = 16       // Number of chunks merged at once unless --batch-size says otherwise
+	minBatchSize     = 2        // Fewest chunks a merge can take
+	minBufferSize    = 16 * 34  // Smallest -S budget; smaller ones are raised to it, as GNU sort does
+	guessedMemory    = 64 << 20 // Physical memory assumed if the real amount is unknown, as GNU sort does
+)
+
+// sizeSuffixes maps the multiplicative suffixes of -S to the power of 1024 they stand for.
+var sizeSuffixes = map[byte]uint{
+	'K': 1, 'k': 1, 'M': 2, 'm': 2, 'G': 3, 'g': 3, 'T': 4, 't': 4,
+	'P': 5, 'E': 6, 'Z': 7, 'Y': 8, 'R': 9, 'Q': 10,
+}
+
+// parseBufferSize validates the -S values and returns the memory budget in bytes, or 0
+// if there is none. If -S is repeated, the largest size is used, so that the order of
+// options does not matter.
+func parseBufferSize(values []string) (int64, error) {
+
+	var size int64
+
+	for _, value := range values {
+		n, err := parseSize(value)
+		if err != nil {
+			return 0, err
+		}
+		size = max(size, n, minBufferSize)
+	}
+
+	return size, nil
+
+}
+
+// parseSize parses a single -S value. A size is a number of KiB, unless it ends with
+// 'b' for bytes, '%' for a percentage of physical memory, or one of the suffixes
+// K, M, G, T, P, E, Z, Y, R and Q. A suffix alone stands for one unit of it.
+func parseSize(value string) (int64, error) {
+
+	suffix, n, overflow, found := parseUnsigned(value)
+	if !found {
+		if _, ok := sizeSuffixes[firstByte(suffix)]; !ok {
+			return 0, &Error{msg: fmt.Sprintf("invalid -S argument '%s'", value)}
+		}
+		n = 1
+	}
+
+	var hi uint64
+
+	switch {
+	case suffix == "":
+		hi, n = bits.Mul64(n, 1024)
+	case suffix == "b" && found:
+	case suffix == "%" && found:
+		mem := float64(physicalMemory()) * float64(n) / 100
+		if mem >= math.MaxInt64 {
+			hi = 1
+		} else {
+			n = uint64(mem)
+		}
+	default:
+		power, ok := sizeSuffixes[suffix[0]]
+		if !ok || len(suffix) > 1 {
+			return 0, &Error{msg: fmt.Sprintf("invalid suffix in -S argument '%s'", value)}
+		}
+		if power*10 >= 64 {
+			hi = 1
+		} else {
+			hi, n = bits.Mul64(n, 1<<(power*10))
+		}
+	}
+
+	if overflow || hi != 0 || n > math.MaxInt64 {
+		return 0, &Error{msg: fmt.Sprintf("-S argument '%s' too large", value)}
+	}
+	return int64(n), nil
+
+}
+
+// parseBatchSize validates the --batch-size values and returns the number of chunks to
+// merge at once. The batch size is limited by the number of files the process may open.
+// If --batch-size is repeated, the last value is used.
+func parseBatchSize(values []string) (int, error) {
+
+	batchSize := DefaultBatchSize
+
+	for _, value := range values {
+
+		suffix, n, overflow, found := parseUnsigned(value)
+		maxSize := maxBatchSize()
+
+		switch {
+		case !found:
+			return 0, &Error{msg: fmt.Sprintf("invalid --batch-size argument '%s'", value)}
+		case suffix != "":
+			return 0, &Error{msg: fmt.Sprintf("invalid suffix in --batch-size argument '%s'", value)}
+		case n < minBatchSize:
+			return 0, &Error{
+				msg:  fmt.Sprintf("invalid --batch-size argument '%s'", value),
+				note: fmt.Sprintf("minimum --batch-size argument is '%d'", minBatchSize),
+			}
+		case overflow || n > uint64(maxSize):
+			return 0, &Error{
+				msg:  fmt.Sprintf("--batch-size argument '%s' too large", value),
+				note: fmt.Sprintf("maximum --batch-size argument with current rlimit is %d", maxSize),
+			}
+		}
+
+		batchSize = int(n)
+
+	}
+
+	return batchSize, nil
+
+}
+
+// maxBatchSize returns the largest number of chunks that can be merged at once: all the files
+// the process may open, except for standard input, standard output and standard error.
+func maxBatchSize() int {
+	return max(openFilesLimit()-3, minBatchSize)
+}
+
+// parseUnsigned parses the decimal number s starts with, after optional blanks and a '+'
+// sign, and returns the rest of s. It reports whether the number overflowed and whether
+// there was a number at all.
+func parseUnsigned(s string) (rest string, n uint64, overflow bool, found bool) {
+
+	digits := strings.TrimLeft(s, " \t\n\v\f\r")
+	digits = strings.TrimPrefix(digits, "+")
+
+	end := 0
+	for end < len(digits) && '0' <= digits[end] && digits[end] <= '9' {
+		hi, lo := bits.Mul64(n, 10)
+		lo, carry := bits.Add64(lo, uint64(digits[end]-'0'), 0)
+		if hi != 0 || carry != 0 {
+			overflow = true
+		}
+		n = lo
+		end++
+	}
+
+	if end == 0 {
+		return s, 0, false, false
+	}
+	return digits[end:], n, overflow, true
+
+}
+
+// firstByte returns the first byte of s, or 0 if s is empty.
+func firstByte(s string) byte {
+	if s == "" {
+		return 0
+	}
+	return s[0]
+}
+
+// physicalMemory returns the amount of physical memory in bytes, read from /proc/meminfo.
+// If it cannot be read, a small amount is assumed.
+func physicalMemory() uint64 {
+
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return guessedMemory
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "MemTotal:" && fields[2] == "kB" {
+			if kib, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+				return kib * 1024
+			}
+		}
+	}
+
+	return guessedMemory
+
+}
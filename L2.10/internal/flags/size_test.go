@@ -0,0 +1,79 @@
+package flags
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseBufferSize(t *testing.T) {
+
+	tests := []struct {
+		values   []string
+		expected int64
+		err      string
+	}{
+		{nil, 0, ""},
+		{[]string{"10"}, 10 << 10, ""},
+		{[]string{"2048b"}, 2048, ""},
+		{[]string{"+1K", " 3M"}, 3 << 20, ""},
+		{[]string{"2G", "1k"}, 2 << 30, ""},
+		{[]string{"M"}, 1 << 20, ""},
+		{[]string{"0"}, minBufferSize, ""},
+		{[]string{"abc"}, 0, "invalid -S argument 'abc'"},
+		{[]string{"-1"}, 0, "invalid -S argument '-1'"},
+		{[]string{"b"}, 0, "invalid -S argument 'b'"},
+		{[]string{"1.5M"}, 0, "invalid suffix in -S argument '1.5M'"},
+		{[]string{"10KB"}, 0, "invalid suffix in -S argument '10KB'"},
+		{[]string{"1Y"}, 0, "-S argument '1Y' too large"},
+		{[]string{"99999999999999999999999"}, 0, "-S argument '99999999999999999999999' too large"},
+	}
+
+	for _, tt := range tests {
+		size, err := parseBufferSize(tt.values)
+		var errMsg string
+		if err != nil {
+			errMsg = err.Error()
+		}
+		if size != tt.expected || errMsg != tt.err {
+			t.Errorf("parseBufferSize(%q) = %d, %q, expected = %d, %q", tt.values, size, errMsg, tt.expected, tt.err)
+		}
+	}
+
+	if size, err := parseBufferSize([]string{"50%"}); err != nil || size != int64(physicalMemory()/2) {
+		t.Errorf("parseBufferSize(50%%) = %d, %v, expected = %d", size, err, physicalMemory()/2)
+	}
+
+}
+
+func TestParseBatchSize(t *testing.T) {
+
+	tooLarge := fmt.Sprint(uint64(maxBatchSize()) + 1)
+
+	tests := []struct {
+		values   []string
+		expected int
+		err      string
+		note     string
+	}{
+		{nil, DefaultBatchSize, "", ""},
+		{[]string{"2"}, 2, "", ""},
+		{[]string{"+8", " 4"}, 4, "", ""},
+		{[]string{"x"}, 0, "invalid --batch-size argument 'x'", ""},
+		{[]string{"3k"}, 0, "invalid suffix in --batch-size argument '3k'", ""},
+		{[]string{"3", "1"}, 0, "invalid --batch-size argument '1'", "minimum --batch-size argument is '2'"},
+		{[]string{tooLarge}, 0, "--batch-size argument '" + tooLarge + "' too large", fmt.Sprintf("maximum --batch-size argument with current rlimit is %d", maxBatchSize())},
+	}
+
+	for _, tt := range tests {
+		batchSize, err := parseBatchSize(tt.values)
+		var errMsg, note string
+		if err != nil {
+			errMsg = err.Error()
+			note = err.(*Error).Note()
+		}
+		if batchSize != tt.expected || errMsg != tt.err || note != tt.note {
+			t.Errorf("parseBatchSize(%q) = %d, %q, %q, expected = %d, %q, %q", tt.values, batchSize, errMsg, note, tt.expected, tt.err, tt.note)
+		}
+	}
+
+}
@@ -0,0 +1,63 @@
+// Package linereader provides reading of the lines of the sort utility's input:
+// records ended by a newline, or by NUL with -z, of any length.
+package linereader
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// Reader reads lines ended by a delimiter, like bufio.Scanner, but without a limit on
+// their length. The delimiter is removed and nothing else, so carriage returns are kept.
+// The last line needs no delimiter.
+type Reader struct {
+	reader *bufio.Reader // Buffered input
+	delim  byte          // Character that ends a line
+	line   string        // Line read by the last call to Scan
+	err    error         // Error that ended reading; io.EOF at the end of the input
+}
+
+// New returns a Reader of the lines of r ended by delim.
+func New(r io.Reader, delim byte) *Reader {
+	return &Reader{reader: bufio.NewReader(r), delim: delim}
+}
+
+// Scan advances to the next line, which is then available through Text. It returns
+// false at the end of the input or on an error, which Err returns afterwards.
+func (r *Reader) Scan() bool {
+
+	if r.err != nil {
+		r.line = ""
+		return false
+	}
+
+	line, err := r.reader.ReadString(r.delim)
+	switch {
+	case err == nil:
+		r.line = line[:len(line)-1]
+		return true
+	case errors.Is(err, io.EOF) && line != "": // The last line has no delimiter
+		r.line = line
+		r.err = err
+		return true
+	default:
+		r.line = ""
+		r.err = err
+		return false
+	}
+
+}
+
+// Text returns the line read by the last call to Scan, without its delimiter.
+func (r *Reader) Text() string {
+	return r.line
+}
+
+// Err returns the error that ended reading, or nil if the end of the input was reached.
+func (r *Reader) Err() error {
+	if errors.Is(r.err, io.EOF) {
+		return nil
+	}
+	return r.err
+}
@@ -0,0 +1,79 @@
+package linereader
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readAll returns the lines New reads from input and the error reading ended with.
+func readAll(input io.Reader, delim byte) ([]string, error) {
+
+	var lines []string
+
+	reader := New(input, delim)
+	for reader.Scan() {
+		lines = append(lines, reader.Text())
+	}
+
+	return lines, reader.Err()
+
+}
+
+func TestReader(t *testing.T) {
+
+	long := strings.Repeat("x", 1<<20) // Far longer than the 64 KiB bufio.Scanner allows
+
+	tests := []struct {
+		name     string
+		input    string
+		delim    byte
+		expected []string
+	}{
+		{"empty", "", '\n', nil},
+		{"lines", "b\na\n", '\n', []string{"b", "a"}},
+		{"no final newline", "b\na", '\n', []string{"b", "a"}},
+		{"empty lines", "\n\nx\n", '\n', []string{"", "", "x"}},
+		{"carriage returns kept", "b\r\na\r\n", '\n', []string{"b\r", "a\r"}},
+		{"long line", long + "\nshort\n", '\n', []string{long, "short"}},
+		{"NUL", "b\nx\x00a\x00", 0, []string{"b\nx", "a"}},
+		{"NUL without final NUL", "b\x00a\n", 0, []string{"b", "a\n"}},
+	}
+
+	for _, tt := range tests {
+		lines, err := readAll(strings.NewReader(tt.input), tt.delim)
+		if err != nil || !slices.Equal(lines, tt.expected) {
+			t.Errorf("%s: lines = %.40q, %v, expected = %.40q", tt.name, lines, err, tt.expected)
+		}
+	}
+
+}
+
+// failingReader returns its data and then an error.
+type failingReader struct {
+	data string
+	err  error
+}
+
+// Read returns the data on the first call and the error on the next ones.
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestReaderError(t *testing.T) {
+
+	errRead := errors.New("read failed")
+
+	lines, err := readAll(&failingReader{data: "a\nb", err: errRead}, '\n')
+	if !errors.Is(err, errRead) || !slices.Equal(lines, []string{"a"}) {
+		t.Errorf("lines = %q, %v, expected = %q, %v", lines, err, []string{"a"}, errRead)
+	}
+
+}
@@ -0,0 +1,131 @@
+package sorter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"L2.10/internal/flags"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The --compress-program values run in-process rather than as separate programs.
+const (
+	builtinGzip = "gzip"
+	builtinZstd = "zstd"
+)
+
+// chunkStream is an open chunk file together with the compression layer on top of it, if any.
+type chunkStream struct {
+	io.Reader                // Decompressed contents of a chunk being read
+	io.Writer                // Compressing writer of a chunk being written
+	closers   []func() error // Called in order by Close, the outermost layer first
+}
+
+// Close closes every layer of the stream and returns the first error.
+func (s *chunkStream) Close() error {
+
+	var err error
+
+	for _, closer := range s.closers {
+		if closeErr := closer(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+
+}
+
+// createChunk creates a new chunk file and returns its name and a writer that compresses
+// what is written with the --compress-program, if any. Chunks are compressed with the
+// built-in gzip or zstd if the program is one of them, and by running the program otherwise.
+func createChunk(flags *flags.Flags) (string, io.WriteCloser, error) {
+
+	file, err := createTempChunk(flags)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch flags.Compress {
+	case "":
+		return file.Name(), file, nil
+	case builtinGzip:
+		gz, err := gzip.NewWriterLevel(file, gzip.BestSpeed) // Chunks are read back soon, so speed matters more than size
+		if err != nil {
+			_ = file.Close()
+			return file.Name(), nil, err
+		}
+		return file.Name(), &chunkStream{Writer: gz, closers: []func() error{gz.Close, file.Close}}, nil
+	case builtinZstd:
+		zw, err := zstd.NewWriter(file, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1)) // Chunks are written by several goroutines already
+		if err != nil {
+			_ = file.Close()
+			return file.Name(), nil, err
+		}
+		return file.Name(), &chunkStream{Writer: zw, closers: []func() error{zw.Close, file.Close}}, nil
+	}
+
+	cmd := exec.Command(flags.Compress)
+	cmd.Stdout = file
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		_ = file.Close()
+		return file.Name(), nil, fmt.Errorf("couldn't execute compress program '%s': %w", flags.Compress, err)
+	}
+
+	return file.Name(), &chunkStream{Writer: stdin, closers: []func() error{stdin.Close, cmd.Wait, file.Close}}, nil
+
+}
+
+// openChunk opens a chunk file for reading, decompressing it with the built-in gzip or
+// zstd, or by running the --compress-program with the -d option.
+func openChunk(name string, flags *flags.Flags) (io.ReadCloser, error) {
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open temporary file %s: %w", name, err)
+	}
+
+	switch flags.Compress {
+	case "":
+		return file, nil
+	case builtinGzip:
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("unable to decompress temporary file %s: %w", name, err)
+		}
+		return &chunkStream{Reader: gz, closers: []func() error{gz.Close, file.Close}}, nil
+	case builtinZstd:
+		zr, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("unable to decompress temporary file %s: %w", name, err)
+		}
+		return &chunkStream{Reader: zr, closers: []func() error{func() error { zr.Close(); return nil }, file.Close}}, nil
+	}
+
+	cmd := exec.Command(flags.Compress, "-d")
+	cmd.Stdin = file
+	cmd.Stderr = os.Stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("couldn't execute compress program '%s' -d: %w", flags.Compress, err)
+	}
+
+	return &chunkStream{Reader: stdout, closers: []func() error{cmd.Wait, file.Close}}, nil
+
+}
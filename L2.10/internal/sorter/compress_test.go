@@ -0,0 +1,66 @@
+package sorter
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"testing"
+
+	"L2.10/internal/flags"
+)
+
+func TestChunkCompression(t *testing.T) {
+
+	programs := []string{"", builtinGzip, builtinZstd}
+	if _, err := exec.LookPath("xz"); err == nil { // Any program taking -d to decompress will do
+		programs = append(programs, "xz")
+	}
+
+	content := "a\nb\nc\n"
+
+	for _, program := range programs {
+
+		opts := &flags.Flags{TempDirs: []string{t.TempDir()}, Compress: program}
+
+		name, writer, err := createChunk(opts)
+		if err != nil {
+			t.Fatalf("createChunk(%q) returned error: %v", program, err)
+		}
+
+		if _, err := io.WriteString(writer, content); err != nil {
+			t.Errorf("failed to write chunk compressed with %q: %v", program, err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Errorf("failed to close chunk compressed with %q: %v", program, err)
+		}
+
+		if raw, err := os.ReadFile(name); err != nil {
+			t.Errorf("failed to read chunk file: %v", err)
+		} else if program != "" && string(raw) == content {
+			t.Errorf("createChunk(%q): chunk was not compressed", program)
+		}
+
+		reader, err := openChunk(name, opts)
+		if err != nil {
+			t.Fatalf("openChunk(%q) returned error: %v", program, err)
+		}
+
+		result, err := io.ReadAll(reader)
+		if err != nil {
+			t.Errorf("failed to read chunk compressed with %q: %v", program, err)
+		}
+		if err := reader.Close(); err != nil {
+			t.Errorf("failed to close chunk compressed with %q: %v", program, err)
+		}
+
+		if string(result) != content {
+			t.Errorf("openChunk(%q): result = %q, expected = %q", program, string(result), content)
+		}
+
+		if err := removeTemp(name); err != nil {
+			t.Errorf("removeTemp returned error: %v", err)
+		}
+
+	}
+
+}
@@ -0,0 +1,49 @@
+package sorter
+
+import (
+	"L2.10/internal/comparator"
+)
+
+// chunkHead is the current line of a chunk during the merge.
+type chunkHead struct {
+	line  comparator.Line // Current line, with its keys parsed once
+	chunk int             // Index of the chunk the line was read from
+}
+
+// mergeHeap is a min-heap of chunk heads for container/heap, so that the next line
+// of the merge is found with O(log K) comparisons for K chunks. Heads that compare
+// equal are ordered by chunk, which keeps equal lines in input order.
+type mergeHeap struct {
+	heads []chunkHead            // Heads of the chunks that are not exhausted yet
+	cmp   *comparator.Comparator // Comparator the heads were prepared by
+}
+
+// Len returns the number of chunk heads.
+func (h *mergeHeap) Len() int {
+	return len(h.heads)
+}
+
+// Less reports whether head i comes before head j in the output.
+func (h *mergeHeap) Less(i, j int) bool {
+	if cmpRes := h.cmp.Compare(h.heads[i].line, h.heads[j].line); cmpRes != 0 {
+		return cmpRes < 0
+	}
+	return h.heads[i].chunk < h.heads[j].chunk
+}
+
+// Swap swaps heads i and j.
+func (h *mergeHeap) Swap(i, j int) {
+	h.heads[i], h.heads[j] = h.heads[j], h.heads[i]
+}
+
+// Push adds a chunkHead.
+func (h *mergeHeap) Push(x any) {
+	h.heads = append(h.heads, x.(chunkHead))
+}
+
+// Pop removes and returns the last head.
+func (h *mergeHeap) Pop() any {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
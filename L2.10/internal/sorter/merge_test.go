@@ -0,0 +1,113 @@
+package sorter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"L2.10/internal/comparator"
+	"L2.10/internal/flags"
+	"L2.10/internal/linereader"
+)
+
+// linearMerge is the merge mergeSort used before the heap, kept as a baseline for
+// BenchmarkMergeSort: it scans the heads of all chunks for every line written and
+// parses the keys of both lines on every comparison.
+func linearMerge(readers []*linereader.Reader, lines []string, alive []bool, flags *flags.Flags, writer *bufio.Writer) error {
+
+	compare := comparator.Compare(flags)
+
+	for {
+
+		minIdx := -1
+		for i := range lines {
+			if alive[i] && (minIdx == -1 || compare(lines[minIdx], lines[i]) > 0) {
+				minIdx = i
+			}
+		}
+
+		if minIdx == -1 {
+			return nil
+		}
+
+		if _, err := writer.WriteString(lines[minIdx] + "\n"); err != nil {
+			return err
+		}
+		updateLines(readers, lines, alive, minIdx)
+
+	}
+
+}
+
+// benchmarkChunks returns k sorted chunks of n lines each, of the form "word number".
+func benchmarkChunks(k int, n int, flags *flags.Flags) []string {
+
+	rnd := rand.New(rand.NewPCG(1, 2))
+	chunks := make([]string, k)
+
+	for i := range chunks {
+		lines := make([]string, n)
+		for j := range lines {
+			lines[j] = fmt.Sprintf("w%05d %d", rnd.IntN(100000), rnd.IntN(1000000))
+		}
+		sortChunk(lines, flags)
+		chunks[i] = strings.Join(lines, "\n") + "\n"
+	}
+
+	return chunks
+
+}
+
+func BenchmarkMergeSort(b *testing.B) {
+
+	merges := []struct {
+		name  string
+		merge func([]*linereader.Reader, []string, []bool, *flags.Flags, *bufio.Writer) error
+	}{
+		{"heap", mergeSort},
+		{"linear", linearMerge},
+	}
+
+	for _, bench := range []struct {
+		name  string
+		flags *flags.Flags
+	}{
+		{"bytes", &flags.Flags{}},
+		{"numeric key", &flags.Flags{Keys: []flags.Key{{StartField: 1, EndField: 1, Order: flags.Order{Numeric: true}}}}},
+	} {
+		for _, k := range []int{10, 100, 1000} {
+
+			chunks := benchmarkChunks(k, 10, bench.flags)
+
+			for _, m := range merges {
+				b.Run(fmt.Sprintf("%s/K=%d/%s", bench.name, k, m.name), func(b *testing.B) {
+					for b.Loop() {
+
+						readers := make([]*linereader.Reader, k)
+						lines := make([]string, k)
+						alive := make([]bool, k)
+
+						for i, chunk := range chunks {
+							readers[i] = linereader.New(strings.NewReader(chunk), '\n')
+							if readers[i].Scan() {
+								lines[i] = readers[i].Text()
+								alive[i] = true
+							}
+						}
+
+						writer := bufio.NewWriter(io.Discard)
+						if err := m.merge(readers, lines, alive, bench.flags, writer); err != nil {
+							b.Fatalf("merge returned error: %v", err)
+						}
+
+					}
+				})
+			}
+
+		}
+	}
+
+}
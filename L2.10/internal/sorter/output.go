@@ -0,0 +1,162 @@
+package sorter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+
+	"L2.10/internal/syserr"
+)
+
+// maxTempAttempts limits how many names createTempOutput tries before giving up.
+const maxTempAttempts = 10000
+
+// destination is where the sorted lines are written.
+type destination struct {
+	file   *os.File // File the lines are written to
+	name   string   // Name of the destination in error messages
+	target string   // File that file replaces once complete; empty if file is written in place
+}
+
+// outputError is a failure to write the sorted lines, reported the way GNU sort reports it.
+type outputError struct {
+	op   string // Operation that failed, e.g. "open failed"
+	path string // Destination the operation was applied to
+	err  error  // Underlying error
+}
+
+// Error returns the message without the "sort: " prefix.
+func (e *outputError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.op, e.path, syserr.Describe(e.err))
+}
+
+// Unwrap returns the underlying error.
+func (e *outputError) Unwrap() error {
+	return e.err
+}
+
+// openOutput opens the destination of the sorted lines: standard output, or the -o file.
+// A regular -o file is not written in place. The lines go to a temporary file in the same
+// directory, which commitOutput renames over it, so that the file may also be one of the
+// inputs and is never seen half-written. Other files, such as devices and pipes, are
+// written in place.
+func openOutput(path string) (*destination, error) {
+
+	if path == "" {
+		return &destination{file: os.Stdout, name: "'standard output'"}, nil
+	}
+
+	target, err := filepath.EvalSymlinks(path) // A symbolic link is written through, not replaced
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		target = path
+	case err != nil:
+		return nil, &outputError{op: "open failed", path: path, err: err}
+	}
+
+	info, err := os.Stat(target)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, &outputError{op: "open failed", path: path, err: err}
+	}
+
+	if info != nil && !info.Mode().IsRegular() {
+		file, err := os.OpenFile(target, os.O_WRONLY|os.O_TRUNC, 0)
+		if err != nil {
+			return nil, &outputError{op: "open failed", path: path, err: err}
+		}
+		return &destination{file: file, name: path}, nil
+	}
+
+	if info != nil { // The file is replaced rather than written to, but only if it could be written to
+		file, err := os.OpenFile(target, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, &outputError{op: "open failed", path: path, err: err}
+		}
+		if err := file.Close(); err != nil {
+			return nil, &outputError{op: "close failed", path: path, err: err}
+		}
+	}
+
+	file, err := createTempOutput(filepath.Dir(target))
+	if err != nil {
+		return nil, &outputError{op: "open failed", path: path, err: err}
+	}
+
+	if info != nil {
+		if err := file.Chmod(info.Mode().Perm()); err != nil { // Keep the permissions of the replaced file
+			abortOutput(&destination{file: file, target: target})
+			return nil, &outputError{op: "open failed", path: path, err: err}
+		}
+	}
+
+	return &destination{file: file, name: path, target: target}, nil
+
+}
+
+// createTempOutput creates a new hidden file in dir and records it for removal. Unlike
+// os.CreateTemp, it leaves the permissions of the file to the umask, as for any other
+// file sort creates.
+func createTempOutput(dir string) (*os.File, error) {
+
+	return trackTemp(func() (*os.File, error) {
+		for attempt := 1; ; attempt++ {
+			name := filepath.Join(dir, fmt.Sprintf(".sort_%d", rand.Uint64()))
+			file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+			if errors.Is(err, os.ErrExist) && attempt < maxTempAttempts {
+				continue
+			}
+			return file, err
+		}
+	})
+
+}
+
+// commitOutput completes the output once all lines are written to writer: it flushes
+// writer and renames the temporary file over the -o file it stands for.
+func commitOutput(dest *destination, writer *bufio.Writer) error {
+
+	if err := writer.Flush(); err != nil {
+		abortOutput(dest)
+		return &outputError{op: "write failed", path: dest.name, err: err}
+	}
+
+	if dest.file == os.Stdout {
+		return nil
+	}
+
+	if err := dest.file.Close(); err != nil {
+		abortOutput(dest)
+		return &outputError{op: "close failed", path: dest.name, err: err}
+	}
+
+	if dest.target == "" {
+		return nil
+	}
+
+	if err := os.Rename(dest.file.Name(), dest.target); err != nil {
+		abortOutput(dest)
+		return &outputError{op: "cannot create", path: dest.name, err: err}
+	}
+	forgetTemp(dest.file.Name())
+
+	return nil
+
+}
+
+// abortOutput closes the destination and removes the temporary file, leaving the -o file untouched.
+func abortOutput(dest *destination) {
+
+	if dest.file == os.Stdout {
+		return
+	}
+
+	_ = dest.file.Close() // The file may already be closed; there is nothing left to report
+
+	if dest.target != "" {
+		_ = removeTemp(dest.file.Name())
+	}
+
+}
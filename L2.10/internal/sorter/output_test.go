@@ -0,0 +1,131 @@
+package sorter
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenOutput(t *testing.T) {
+
+	write := func(t *testing.T, path string, content string) {
+		t.Helper()
+		dest, err := openOutput(path)
+		if err != nil {
+			t.Fatalf("openOutput(%q) returned error: %v", path, err)
+		}
+		writer := bufio.NewWriter(dest.file)
+		if _, err := writer.WriteString(content); err != nil {
+			t.Fatalf("failed to write output: %v", err)
+		}
+		if err := commitOutput(dest, writer); err != nil {
+			t.Fatalf("commitOutput returned error: %v", err)
+		}
+	}
+
+	read := func(t *testing.T, path string) string {
+		t.Helper()
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		return string(content)
+	}
+
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out")
+		write(t, path, "a\n")
+		if result := read(t, path); result != "a\n" {
+			t.Errorf("output = %q, expected = %q", result, "a\n")
+		}
+	})
+
+	t.Run("replaces file and keeps permissions", func(t *testing.T) {
+
+		path := filepath.Join(t.TempDir(), "out")
+		if err := os.WriteFile(path, []byte("old\n"), 0o640); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		dest, err := openOutput(path)
+		if err != nil {
+			t.Fatalf("openOutput returned error: %v", err)
+		}
+		if result := read(t, path); result != "old\n" {
+			t.Errorf("file changed before commit: %q", result)
+		}
+
+		writer := bufio.NewWriter(dest.file)
+		if _, err := writer.WriteString("new\n"); err != nil {
+			t.Fatalf("failed to write output: %v", err)
+		}
+		if err := commitOutput(dest, writer); err != nil {
+			t.Fatalf("commitOutput returned error: %v", err)
+		}
+
+		if result := read(t, path); result != "new\n" {
+			t.Errorf("output = %q, expected = %q", result, "new\n")
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o640 {
+			t.Errorf("output mode = %v (%v), expected = %v", info.Mode().Perm(), err, os.FileMode(0o640))
+		}
+
+	})
+
+	t.Run("symlink is written through", func(t *testing.T) {
+
+		dir := t.TempDir()
+		target := filepath.Join(dir, "target")
+		link := filepath.Join(dir, "link")
+		if err := os.WriteFile(target, []byte("old\n"), 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+
+		write(t, link, "new\n")
+
+		if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("link was replaced: %v, %v", info.Mode(), err)
+		}
+		if result := read(t, target); result != "new\n" {
+			t.Errorf("output = %q, expected = %q", result, "new\n")
+		}
+
+	})
+
+	t.Run("abort leaves file untouched", func(t *testing.T) {
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out")
+		if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		dest, err := openOutput(path)
+		if err != nil {
+			t.Fatalf("openOutput returned error: %v", err)
+		}
+		abortOutput(dest)
+
+		if result := read(t, path); result != "old\n" {
+			t.Errorf("output = %q, expected = %q", result, "old\n")
+		}
+		if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+			t.Errorf("temporary file left behind: %v, %v", entries, err)
+		}
+
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "out")
+		_, err := openOutput(path)
+		expected := "open failed: " + path + ": No such file or directory"
+		if err == nil || err.Error() != expected {
+			t.Errorf("openOutput(%q) error = %v, expected = %q", path, err, expected)
+		}
+	})
+
+}
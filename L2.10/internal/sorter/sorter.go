@@ -5,19 +5,21 @@ package sorter
 
 import (
 	"bufio"
+	"container/heap"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"slices"
-	"strings"
 	"sync"
-	"sync/atomic"
 	"syscall"
 
 	"L2.10/internal/comparator"
 	"L2.10/internal/config"
 	"L2.10/internal/flags"
+	"L2.10/internal/linereader"
 	"github.com/spf13/pflag"
 	"golang.org/x/sync/errgroup"
 )
@@ -26,13 +28,14 @@ const (
 	inputNotSorted   = 1
 	internalError    = 2
 	defaultChunkSize = 1000
+	lineOverhead     = 64 // Memory a line takes besides its text: its string header and its comparator.Line
 )
 
 // Data represents the complete context of a sorting operation,
 // including configuration, command-line flags, file metadata,
 // and intermediate sorting chunks.
 type Data struct {
-	Chunks   []string       // Paths to temporary chunk files
+	Chunks   []string       // Paths to the sorted files to merge: temporary chunk files, or with -m, the inputs
 	Flags    *flags.Flags   // Parsed command-line flags
 	FileName string         // Current input file name, used for concise error message formatting
 	Config   *config.Config // Runtime configuration settings
@@ -43,6 +46,8 @@ type Data struct {
 // It parses input, processes chunks, performs sorting, merging, and cleanup.
 func Sort() {
 
+	handleSignals()
+
 	data, err := processInput()
 	if err != nil {
 		logFatal(err, data.FileName)
@@ -50,20 +55,35 @@ func Sort() {
 
 	if !data.Flags.C {
 
-		scnrs := make([]*bufio.Scanner, len(data.Chunks))
-		files := make([]*os.File, len(data.Chunks))
+		if err := mergeBatches(data); err != nil {
+			logFatal(err, data.FileName)
+		}
+
+		readers := make([]*linereader.Reader, len(data.Chunks))
+		files := make([]io.ReadCloser, len(data.Chunks))
 		lines := make([]string, len(data.Chunks))
 		alive := make([]bool, len(data.Chunks))
 
-		if err := mergePrep(scnrs, files, lines, alive, data.Chunks); err != nil {
+		if err := mergePrep(readers, files, lines, alive, data.Chunks, data.Flags); err != nil {
 			logFatal(err, data.FileName)
 		}
 
-		if err := mergeSort(scnrs, lines, alive, data.Flags); err != nil {
+		dest, err := openOutput(data.Flags.O)
+		if err != nil {
 			logFatal(err, data.FileName)
 		}
+		writer := bufio.NewWriter(dest.file)
+
+		if err := mergeSort(readers, lines, alive, data.Flags, writer); err != nil {
+			abortOutput(dest)
+			logFatal(&outputError{op: "write failed", path: dest.name, err: err}, data.FileName)
+		}
 
-		if err := cleanup(files, data.Chunks); err != nil {
+		if err := commitOutput(dest, writer); err != nil {
+			logFatal(err, data.FileName)
+		}
+
+		if err := cleanup(files, temporary(data.Chunks)); err != nil {
 			logFatal(err, data.FileName)
 		}
 
@@ -72,7 +92,8 @@ func Sort() {
 }
 
 // processInput parses command-line flags and determines the data source.
-// It delegates processing to processFiles or processStdIn depending on input.
+// It delegates processing to processFiles or processStdIn depending on input,
+// or to processMerge with -m.
 func processInput() (*Data, error) {
 
 	data := &Data{Sorted: true}
@@ -86,11 +107,14 @@ func processInput() (*Data, error) {
 
 	flags, err := flags.Parse()
 	if err != nil {
-		return data, fmt.Errorf("failed to parse flags: %w", err)
+		return data, err // intentionally not wrapped: logFatal prints invalid options the way GNU sort does.
 	}
 	data.Flags = flags
 
 	files := pflag.Args()
+	if flags.Merge && !flags.C { // With -c, every input is checked in turn, up to the first one out of order
+		return data, processMerge(data, files)
+	}
 	if len(files) > 0 {
 		return data, processFiles(data, files)
 	}
@@ -111,7 +135,7 @@ func processFiles(data *Data, files []string) error {
 			return err // intentionally not wrapped: the error is handled by logFatal, which formats it in the style of GNU sort using errors.Is checks.
 		}
 
-		if err = processData(data, bufio.NewScanner(file)); err != nil {
+		if err = processData(data, linereader.New(file, data.Flags.LineEnd())); err != nil {
 			return err // intentionally not wrapped
 		}
 
@@ -128,77 +152,159 @@ func processFiles(data *Data, files []string) error {
 // processStdIn processes standard input when no files are provided.
 func processStdIn(data *Data) error {
 
-	if err := processData(data, bufio.NewScanner(os.Stdin)); err != nil {
+	if err := processData(data, linereader.New(os.Stdin, data.Flags.LineEnd())); err != nil {
 		return err // intentionally not wrapped: the error is handled by logFatal, which formats it in the style of GNU sort using errors.Is checks.
 	}
 	return nil
 
 }
 
-// processData sorts or checks sortedness of the provided scanner data
+// processMerge makes the inputs the sources of the merge, for -m. They are already
+// sorted, so they are neither split into chunks nor copied to temporary files, but only
+// checked to be readable. "-" stands for standard input, which is merged if there are
+// no files.
+func processMerge(data *Data, files []string) error {
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	for _, name := range files {
+
+		data.FileName = name
+
+		if name != "-" {
+
+			file, err := os.Open(name)
+			if err != nil {
+				return err // intentionally not wrapped: the error is handled by logFatal, which formats it in the style of GNU sort using errors.Is checks.
+			}
+
+			info, err := file.Stat()
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+
+			switch {
+			case err != nil:
+				return fmt.Errorf("unable to process %s file: %w", name, err)
+			case info.IsDir():
+				return &os.PathError{Op: "read", Path: name, Err: syscall.EISDIR}
+			}
+
+		}
+
+		data.Chunks = append(data.Chunks, name)
+
+	}
+
+	return nil
+
+}
+
+// processData sorts or checks sortedness of the lines of reader
 // depending on flags, and splits input into chunks.
-func processData(data *Data, scanner *bufio.Scanner) error {
+func processData(data *Data, reader *linereader.Reader) error {
 
 	if data.Flags.C {
 
 		var err error
-		data.Sorted, err = comparator.CheckSorted(scanner, data.FileName, data.Flags)
+		data.Sorted, err = comparator.CheckSorted(reader, data.FileName, data.Flags)
 		if err != nil {
 			return err // intentionally not wrapped: the error is handled by logFatal, which formats it in the style of GNU sort using errors.Is checks.
 		}
 
 		if !data.Sorted {
-			os.Exit(inputNotSorted) // Exit with code 1 to mimic GNU sort behavior when input is not sorted (-c flag).
+			exit(inputNotSorted) // Exit with code 1 to mimic GNU sort behavior when input is not sorted (-c flag).
 		}
 
 	}
 
-	return splitToChunks(data, scanner)
+	return splitToChunks(data, reader)
+
+}
 
+// chunk is a portion of the input together with its position among the chunks of the input.
+type chunk struct {
+	seq   int      // Position of the chunk in the input
+	lines []string // Lines of the chunk
 }
 
 // splitToChunks divides input data into chunks and distributes work
-// among concurrent goroutines for processing.
-func splitToChunks(data *Data, scanner *bufio.Scanner) error {
+// among concurrent goroutines for processing. The chunks are appended to
+// data.Chunks in input order, so that merging them keeps equal lines in
+// the order they were read. A chunk holds data.Config.ChunkSize lines, or,
+// with -S, as many lines as its share of the memory budget allows.
+func splitToChunks(data *Data, reader *linereader.Reader) error {
 
 	workers := runtime.GOMAXPROCS(data.Config.Workers)
-	chunksQueue := make(chan []string)
+	budget := chunkBudget(data.Flags.BufferSize, workers)
+	chunksQueue := make(chan chunk)
+	saved := make(map[int]string)
 
 	var mu sync.Mutex
-	var g errgroup.Group
+	g, ctx := errgroup.WithContext(context.Background())
+
+	// Once a worker fails, the others stop too, and no chunk is sent any more
+	send := func(c chunk) bool {
+		select {
+		case chunksQueue <- c:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
 
 	for range workers {
 		g.Go(func() error {
-			return processChunks(data, chunksQueue, &mu)
+			return processChunks(chunksQueue, saved, &mu, data.Flags)
 		})
 	}
 
 	lines := 0
+	var size int64
+	seq := 0
 	chunkBuilder := make([]string, 0, data.Config.ChunkSize)
 
-	for scanner.Scan() {
-		chunkBuilder = append(chunkBuilder, scanner.Text())
+	for reader.Scan() {
+		line := reader.Text()
+		chunkBuilder = append(chunkBuilder, line)
 		lines++
-		if lines == data.Config.ChunkSize {
-			chunk := make([]string, len(chunkBuilder))
-			copy(chunk, chunkBuilder)
-			chunksQueue <- chunk
+		size += int64(len(line)) + lineOverhead
+		if budget > 0 && size >= budget || budget == 0 && lines == data.Config.ChunkSize {
+			chunkLines := make([]string, len(chunkBuilder))
+			copy(chunkLines, chunkBuilder)
+			if !send(chunk{seq: seq, lines: chunkLines}) {
+				break
+			}
+			seq++
 			chunkBuilder = chunkBuilder[:0] // Reset chunkBuilder to reuse it for the next chunk without reallocating
 			lines = 0
+			size = 0
 		}
 	}
 
-	if len(chunkBuilder) > 0 {
-		chunksQueue <- chunkBuilder // Send any remaining lines in chunkBuilder as the final chunk
+	if len(chunkBuilder) > 0 && ctx.Err() == nil {
+		if send(chunk{seq: seq, lines: chunkBuilder}) { // Send any remaining lines in chunkBuilder as the final chunk
+			seq++
+		}
 	}
 
 	close(chunksQueue)
 
-	if err := g.Wait(); err != nil {
+	err := g.Wait()
+
+	for i := range seq {
+		if fileName, ok := saved[i]; ok { // Chunks a failed worker did not save are missing
+			data.Chunks = append(data.Chunks, fileName)
+		}
+	}
+
+	if err != nil {
 		return fmt.Errorf("worker malfunction: %w", err)
 	}
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		return err // intentionally not wrapped: the error is handled by logFatal, which formats it in the style of GNU sort using errors.Is checks.
 	}
 
@@ -206,43 +312,58 @@ func splitToChunks(data *Data, scanner *bufio.Scanner) error {
 
 }
 
-// processChunks sorts individual chunks of data and saves them into temporary files.
-func processChunks(data *Data, chunksQueue <-chan []string, mu *sync.Mutex) error {
+// chunkBudget returns how many bytes of lines a chunk may hold within the -S memory
+// budget, or 0 if there is none. Besides the chunk being read, every worker may hold
+// a chunk it is sorting, so the budget is shared by workers+1 chunks.
+func chunkBudget(bufferSize int64, workers int) int64 {
+	if bufferSize == 0 {
+		return 0
+	}
+	return max(bufferSize/int64(workers+1), 1)
+}
+
+// processChunks sorts individual chunks of data and saves them into temporary files,
+// recording the file name of each chunk under its position in saved.
+func processChunks(chunksQueue <-chan chunk, saved map[int]string, mu *sync.Mutex, flags *flags.Flags) error {
 	for chunk := range chunksQueue {
-		sortChunk(chunk, data.Flags)
-		filename, err := saveChunk(chunk)
+		sortChunk(chunk.lines, flags)
+		filename, err := saveChunk(chunk.lines, flags)
 		if err != nil {
 			return fmt.Errorf("saving error: %w", err)
 		}
 		mu.Lock()
-		data.Chunks = append(data.Chunks, filename)
+		saved[chunk.seq] = filename
 		mu.Unlock()
 	}
 	return nil
 }
 
 // sortChunk sorts a single chunk of lines using comparator and given flags.
+// The keys of every line are parsed once, before sorting.
 func sortChunk(lines []string, flags *flags.Flags) {
 
-	slices.SortStableFunc(lines, comparator.Compare(flags))
+	c := comparator.New(flags)
 
-	if flags.K {
-		for _, line := range lines {
-			if line == "" {
-				flags.Blanks.Add(1)
-				continue
-			}
-		}
+	prepared := make([]comparator.Line, len(lines))
+	for i, line := range lines {
+		prepared[i] = c.Prepare(line)
+	}
+
+	slices.SortStableFunc(prepared, c.Compare)
+
+	for i := range prepared {
+		lines[i] = prepared[i].Text
 	}
 
 }
 
 // saveChunk writes a sorted chunk into a temporary file and returns its name.
-func saveChunk(chunk []string) (fileName string, err error) {
+// Lines are ended like in the input, so that lines containing newlines survive -z.
+func saveChunk(chunk []string, flags *flags.Flags) (fileName string, err error) {
 
-	tempFile, err := os.CreateTemp(".", "chunk_")
+	fileName, tempFile, err := createChunk(flags)
 	if err != nil {
-		return "", fmt.Errorf("unable to create temporary file: %w", err)
+		return "", err // intentionally not wrapped: logFatal reports a temporary file that cannot be created the way GNU sort does.
 	}
 
 	defer func() {
@@ -253,7 +374,11 @@ func saveChunk(chunk []string) (fileName string, err error) {
 
 	writer := bufio.NewWriter(tempFile)
 	for _, line := range chunk {
-		if _, writeErr := writer.WriteString(line + "\n"); writeErr != nil {
+		_, writeErr := writer.WriteString(line)
+		if writeErr == nil {
+			writeErr = writer.WriteByte(flags.LineEnd())
+		}
+		if writeErr != nil {
 			return "", fmt.Errorf("unable to write string to temporary file: %w", writeErr)
 		}
 	}
@@ -262,159 +387,206 @@ func saveChunk(chunk []string) (fileName string, err error) {
 		return "", fmt.Errorf("unable to flush buffered data: %w", flushErr)
 	}
 
-	return tempFile.Name(), nil
+	return fileName, nil
 
 }
 
-// mergePrep prepares scanners and files for the merging stage.
-func mergePrep(scnrs []*bufio.Scanner, files []*os.File, lines []string, alive []bool, chunks []string) error {
+// mergeBatches merges the chunks in batches of at most --batch-size consecutive chunks,
+// each into a single larger chunk, until no more chunks are left than the final merge
+// may take. No merge thus has more files open than the batch size. Since batches are
+// made of consecutive chunks, equal lines stay in the order they were read.
+func mergeBatches(data *Data) error {
 
-	for i, file := range chunks {
+	batchSize := data.Flags.BatchSize
+	if batchSize < 2 {
+		batchSize = flags.DefaultBatchSize
+	}
 
-		currentFile, err := os.Open(file)
-		if err != nil {
-			return fmt.Errorf("unable to open temporary file %s: %w", file, err)
-		}
+	for len(data.Chunks) > batchSize {
 
-		files[i] = currentFile
-		scnrs[i] = bufio.NewScanner(currentFile)
+		var merged []string
+
+		for start := 0; start < len(data.Chunks); start += batchSize {
+
+			batch := data.Chunks[start:min(start+batchSize, len(data.Chunks))]
+			if len(batch) == 1 {
+				merged = append(merged, batch[0])
+				continue
+			}
+
+			fileName, err := mergeChunks(batch, data.Flags)
+			if err != nil {
+				data.Chunks = append(merged, data.Chunks[start:]...) // Chunks that may be left, for cleanup
+				return err
+			}
+			merged = append(merged, fileName)
 
-		if scnrs[i].Scan() {
-			lines[i] = scnrs[i].Text()
-			alive[i] = true
 		}
 
+		data.Chunks = merged
+
 	}
 
 	return nil
 
 }
 
-// mergeSort merges sorted chunks into final sorted output.
-func mergeSort(scnrs []*bufio.Scanner, lines []string, alive []bool, flags *flags.Flags) error {
+// mergeChunks merges sorted chunks into a new temporary file, removes the chunks
+// and returns the name of the file. Inputs merged with -m are not removed.
+func mergeChunks(chunks []string, flags *flags.Flags) (string, error) {
+
+	readers := make([]*linereader.Reader, len(chunks))
+	files := make([]io.ReadCloser, len(chunks))
+	lines := make([]string, len(chunks))
+	alive := make([]bool, len(chunks))
+
+	if err := mergePrep(readers, files, lines, alive, chunks, flags); err != nil {
+		return "", err
+	}
+
+	fileName, tempFile, err := createChunk(flags)
+	if err != nil {
+		return "", err // intentionally not wrapped: logFatal reports a temporary file that cannot be created the way GNU sort does.
+	}
+
+	writer := bufio.NewWriter(tempFile)
+	err = mergeSort(readers, lines, alive, flags, writer)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = removeTemp(fileName) // The merge is incomplete, and its chunks are still there
+		return "", fmt.Errorf("unable to write merged chunks to temporary file: %w", err)
+	}
 
-	if flags.K && !flags.R {
-		printBlanks(flags.Blanks)
+	if err := cleanup(files, temporary(chunks)); err != nil {
+		return "", err
 	}
 
-	var prevLine string
-	var printedEmpty bool
-	var lesserLineIdx int
+	return fileName, nil
 
-	for {
+}
 
-		lesserLineIdx = -1
+// mergePrep prepares readers and files for the merging stage.
+func mergePrep(readers []*linereader.Reader, files []io.ReadCloser, lines []string, alive []bool, chunks []string, flags *flags.Flags) error {
 
-		for currentIdx := range lines {
-			if !alive[currentIdx] {
-				continue
-			}
-			lesserLineIdx = getLesserLineIdx(lesserLineIdx, currentIdx, lines, flags)
-		}
+	for i, file := range chunks {
 
-		if lesserLineIdx == -1 { // EOF reached for all files.
-			break
+		currentFile, err := openSource(file, flags)
+		if err != nil {
+			return err
 		}
 
-		output(lines[lesserLineIdx], &prevLine, flags, &printedEmpty)
-		updateLines(scnrs, lines, alive, lesserLineIdx)
+		files[i] = currentFile
+		readers[i] = linereader.New(currentFile, flags.LineEnd())
 
-	}
+		if readers[i].Scan() {
+			lines[i] = readers[i].Text()
+			alive[i] = true
+		}
 
-	if flags.K && flags.R {
-		printBlanks(flags.Blanks)
 	}
 
 	return nil
 
 }
 
-// printBlanks prints the specified number of blank lines.
-func printBlanks(amount *atomic.Int32) {
-	blanks := amount.Load()
-	for range blanks {
-		fmt.Println()
+// openSource opens a sorted file to merge: a chunk, or, with -m, an input file
+// or standard input if the name is "-".
+func openSource(name string, flags *flags.Flags) (io.ReadCloser, error) {
+
+	switch {
+	case isTemp(name):
+		return openChunk(name, flags)
+	case name == "-":
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %w", name, err)
 	}
+	return file, nil
+
 }
 
-// output prints the current line to stdout according to sorting flags.
-func output(outputLine string, prevLine *string, flags *flags.Flags, printedEmpty *bool) {
+// mergeSort merges sorted chunks into final sorted output written to writer.
+// The chunk heads are kept in a heap, and the keys of every line are parsed
+// once, when the line becomes the head of its chunk.
+func mergeSort(readers []*linereader.Reader, lines []string, alive []bool, flags *flags.Flags, writer *bufio.Writer) error {
 
-	shouldPrint := true
+	c := comparator.New(flags)
+	h := &mergeHeap{cmp: c}
 
-	if flags.U {
-		if outputLine == "" {
-			shouldPrint = !*printedEmpty
-			*printedEmpty = true
-		} else {
-			shouldPrint = (outputLine != *prevLine)
-			*prevLine = outputLine
+	for i := range lines {
+		if alive[i] {
+			h.heads = append(h.heads, chunkHead{line: c.Prepare(lines[i]), chunk: i})
 		}
-
-	} else if flags.K {
-		shouldPrint = (strings.TrimSpace(outputLine) != "")
 	}
+	heap.Init(h)
 
-	if shouldPrint {
-		fmt.Println(outputLine)
-	}
+	var prevLine comparator.Line
+	var printed bool
 
-}
+	for h.Len() > 0 {
 
-// updateLines updates scanner state after reading one line from a file.
-func updateLines(scanners []*bufio.Scanner, lines []string, alive []bool, lesserLineIdx int) {
+		head := h.heads[0]
 
-	if scanners[lesserLineIdx].Scan() {
-		lines[lesserLineIdx] = scanners[lesserLineIdx].Text()
-		alive[lesserLineIdx] = true
-	} else {
-		lines[lesserLineIdx] = ""
-		alive[lesserLineIdx] = false
-	}
+		if err := output(writer, head.line, &prevLine, &printed, flags, c); err != nil {
+			return err
+		}
 
-}
+		updateLines(readers, lines, alive, head.chunk)
 
-// getLesserLineIdx compares two lines and returns the index of the lesser one.
-func getLesserLineIdx(minIdx int, curIdx int, lines []string, flags *flags.Flags) int {
+		if alive[head.chunk] {
+			h.heads[0].line = c.Prepare(lines[head.chunk])
+			heap.Fix(h, 0)
+		} else { // EOF reached for this chunk
+			heap.Pop(h)
+		}
 
-	if minIdx == -1 {
-		return curIdx
 	}
 
-	line1 := prepareLine(lines[minIdx], flags)
-	line2 := prepareLine(lines[curIdx], flags)
+	return nil
+
+}
 
-	if flags.K {
-		switch {
-		case line1 == "" && line2 != "":
-			return curIdx
-		case line1 != "" && line2 == "":
-			return minIdx
-		}
+// output writes the current line to writer according to sorting flags.
+// With -u, a line equal to the previously written one is skipped.
+func output(writer *bufio.Writer, outputLine comparator.Line, prevLine *comparator.Line, printed *bool, flags *flags.Flags, c *comparator.Comparator) error {
+
+	if flags.U && *printed && c.Compare(*prevLine, outputLine) == 0 {
+		return nil
 	}
 
-	if comparator.CompareLines(line1, line2, flags) > 0 {
-		return curIdx
+	*prevLine = outputLine
+	*printed = true
+
+	if _, err := writer.WriteString(outputLine.Text); err != nil {
+		return err
 	}
-	return minIdx
+	return writer.WriteByte(flags.LineEnd())
 
 }
 
-// prepareLine extracts the key field from a line for comparison.
-func prepareLine(line string, flags *flags.Flags) string {
-	if !flags.K {
-		return line
-	}
-	fields := strings.Fields(line)
-	if len(fields) > flags.ClmnToSort {
-		return fields[flags.ClmnToSort]
+// updateLines updates reader state after reading one line from a file.
+func updateLines(readers []*linereader.Reader, lines []string, alive []bool, lesserLineIdx int) {
 
+	if readers[lesserLineIdx].Scan() {
+		lines[lesserLineIdx] = readers[lesserLineIdx].Text()
+		alive[lesserLineIdx] = true
+	} else {
+		lines[lesserLineIdx] = ""
+		alive[lesserLineIdx] = false
 	}
-	return ""
+
 }
 
 // cleanup closes all open files and removes temporary chunk files.
-func cleanup(files []*os.File, chunks []string) error {
+func cleanup(files []io.ReadCloser, chunks []string) error {
 
 	if err := closeFiles(files); err != nil {
 		return fmt.Errorf("failed to close files: %w", err)
@@ -428,8 +600,11 @@ func cleanup(files []*os.File, chunks []string) error {
 }
 
 // closeFiles safely closes all opened files.
-func closeFiles(files []*os.File) error {
+func closeFiles(files []io.ReadCloser) error {
 	for _, file := range files {
+		if file == nil { // Never opened
+			continue
+		}
 		if err := file.Close(); err != nil {
 			return fmt.Errorf("file.Close() returned an error: %w", err)
 		}
@@ -440,7 +615,7 @@ func closeFiles(files []*os.File) error {
 // deleteChunks removes temporary chunk files created during sorting.
 func deleteChunks(chunks []string) error {
 	for _, chunk := range chunks {
-		if err := os.Remove(chunk); err != nil {
+		if err := removeTemp(chunk); err != nil {
 			return fmt.Errorf("unable to remove file: %w", err)
 		}
 	}
@@ -448,7 +623,15 @@ func deleteChunks(chunks []string) error {
 }
 
 // logFatal prints formatted error messages consistent with GNU sort behavior
+// and exits, removing the temporary files. A write to a closed pipe ends sort
+// silently, as the SIGPIPE it comes with would.
 func logFatal(err error, fileName string) {
+	var flagsErr *flags.Error
+	var outputErr *outputError
+	var tempErr *tempError
+	if errors.Is(err, syscall.EPIPE) {
+		die(syscall.SIGPIPE)
+	}
 	if pathErr, ok := err.(*os.PathError); ok {
 		switch {
 		case errors.Is(pathErr.Err, syscall.EISDIR):
@@ -458,8 +641,17 @@ func logFatal(err error, fileName string) {
 		case errors.Is(pathErr.Err, os.ErrPermission):
 			fmt.Fprintf(os.Stderr, "sort: cannot read: %s: Permission denied\n", fileName)
 		}
+	} else if errors.As(err, &flagsErr) {
+		fmt.Fprintf(os.Stderr, "sort: %v\n", err)
+		if note := flagsErr.Note(); note != "" {
+			fmt.Fprintf(os.Stderr, "sort: %s\n", note)
+		}
+	} else if errors.As(err, &outputErr) {
+		fmt.Fprintf(os.Stderr, "sort: %v\n", err)
+	} else if errors.As(err, &tempErr) {
+		fmt.Fprintf(os.Stderr, "sort: %v\n", tempErr)
 	} else {
 		fmt.Fprintf(os.Stderr, "sort: fatal error: %v\n", err)
 	}
-	os.Exit(internalError) // Exit with code 2 for internal errors, matching GNU sort behavior
+	exit(internalError) // Exit with code 2 for internal errors, matching GNU sort behavior
 }
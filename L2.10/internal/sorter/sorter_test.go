@@ -2,13 +2,19 @@ package sorter
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
-	"sync/atomic"
 	"testing"
 
+	"L2.10/internal/comparator"
 	"L2.10/internal/config"
 	"L2.10/internal/flags"
+	"L2.10/internal/linereader"
 )
 
 func TestSortChunkAndSaveChunk(t *testing.T) {
@@ -24,7 +30,7 @@ func TestSortChunkAndSaveChunk(t *testing.T) {
 		}
 	}
 
-	fileName, err := saveChunk(lines)
+	fileName, err := saveChunk(lines, new(flags.Flags))
 	if err != nil {
 		t.Fatalf("saveChunk returned error: %v", err)
 	}
@@ -55,14 +61,23 @@ func TestSplitToChunks(t *testing.T) {
 	}
 
 	lines := []string{"q", "w", "e"}
-	scanner := bufio.NewScanner(strings.NewReader(strings.Join(lines, "\n")))
+	reader := linereader.New(strings.NewReader(strings.Join(lines, "\n")), '\n')
 
-	if err := splitToChunks(data, scanner); err != nil {
+	if err := splitToChunks(data, reader); err != nil {
 		t.Fatalf("splitToChunks returned error: %v", err)
 	}
 
-	if len(data.Chunks) == 0 {
-		t.Errorf("splitToChunks: no chunks created, expected > 0")
+	expected := []string{"q\nw\n", "e\n"}
+	if len(data.Chunks) != len(expected) {
+		t.Errorf("splitToChunks: %d chunks created, expected = %d", len(data.Chunks), len(expected))
+	}
+
+	for i, chunkFile := range data.Chunks { // Chunks must stay in input order
+		if result, err := os.ReadFile(chunkFile); err != nil {
+			t.Errorf("failed to read chunk file: %v", err)
+		} else if i < len(expected) && string(result) != expected[i] {
+			t.Errorf("splitToChunks: chunk %d = %q, expected = %q", i, string(result), expected[i])
+		}
 	}
 
 	for _, chunkFile := range data.Chunks {
@@ -73,14 +88,278 @@ func TestSplitToChunks(t *testing.T) {
 
 }
 
-func TestGetLesserLineIdx(t *testing.T) {
+func TestSplitToChunksBufferSize(t *testing.T) {
 
-	flags := &flags.Flags{}
-	lines := []string{"b", "a", "c"}
+	data := &Data{
+		Flags:  &flags.Flags{BufferSize: 4 * (1 + lineOverhead)}, // Two chunks in memory at once, two lines each
+		Config: &config.Config{ChunkSize: 1000, Workers: 1},
+	}
+
+	reader := linereader.New(strings.NewReader("q\nw\ne\nr\nt"), '\n')
+
+	if err := splitToChunks(data, reader); err != nil {
+		t.Fatalf("splitToChunks returned error: %v", err)
+	}
+
+	expected := []string{"q\nw\n", "e\nr\n", "t\n"}
+	if len(data.Chunks) != len(expected) {
+		t.Errorf("splitToChunks: %d chunks created, expected = %d", len(data.Chunks), len(expected))
+	}
+
+	for i, chunkFile := range data.Chunks {
+		if result, err := os.ReadFile(chunkFile); err != nil {
+			t.Errorf("failed to read chunk file: %v", err)
+		} else if i < len(expected) && string(result) != expected[i] {
+			t.Errorf("splitToChunks: chunk %d = %q, expected = %q", i, string(result), expected[i])
+		}
+	}
+
+	if err := deleteChunks(data.Chunks); err != nil {
+		t.Logf("failed to remove chunk files: %v", err)
+	}
+
+}
+
+func TestMergeBatches(t *testing.T) {
+
+	data := &Data{Flags: &flags.Flags{S: true, BatchSize: 2, Keys: []flags.Key{{EndField: 0}}}}
+
+	for _, chunk := range [][]string{{"a 1"}, {"a 2", "b 1"}, {"a 3"}, {"b 2"}, {"a 4"}} {
+		fileName, err := saveChunk(chunk, data.Flags)
+		if err != nil {
+			t.Fatalf("saveChunk returned error: %v", err)
+		}
+		data.Chunks = append(data.Chunks, fileName)
+	}
+
+	if err := mergeBatches(data); err != nil {
+		t.Fatalf("mergeBatches returned error: %v", err)
+	}
+
+	if len(data.Chunks) > data.Flags.BatchSize {
+		t.Errorf("mergeBatches: %d chunks left, expected at most %d", len(data.Chunks), data.Flags.BatchSize)
+	}
+
+	fileName, err := mergeChunks(data.Chunks, data.Flags)
+	if err != nil {
+		t.Fatalf("mergeChunks returned error: %v", err)
+	}
+	defer func() {
+		if rmErr := os.Remove(fileName); rmErr != nil {
+			t.Logf("failed to remove chunk file: %v", rmErr)
+		}
+	}()
+
+	result, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read merged chunk: %v", err)
+	}
+
+	expected := "a 1\na 2\na 3\na 4\nb 1\nb 2\n" // Equal keys stay in input order
+	if string(result) != expected {
+		t.Errorf("mergeBatches: merged = %q, expected = %q", string(result), expected)
+	}
+
+}
+
+func TestProcessMerge(t *testing.T) {
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input")
+	if err := os.WriteFile(input, []byte("a\n"), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	data := &Data{Flags: &flags.Flags{Merge: true}}
+	if err := processMerge(data, []string{input, "-"}); err != nil {
+		t.Fatalf("processMerge returned error: %v", err)
+	}
+	if len(data.Chunks) != 2 || data.Chunks[0] != input || data.Chunks[1] != "-" {
+		t.Errorf("processMerge: chunks = %q, expected = %q", data.Chunks, []string{input, "-"})
+	}
+
+	data = &Data{Flags: &flags.Flags{Merge: true}}
+	if err := processMerge(data, nil); err != nil || len(data.Chunks) != 1 || data.Chunks[0] != "-" {
+		t.Errorf("processMerge without files: chunks = %q, %v, expected standard input", data.Chunks, err)
+	}
+
+	for _, name := range []string{filepath.Join(dir, "missing"), dir} {
+		data = &Data{Flags: &flags.Flags{Merge: true}}
+		var pathErr *os.PathError
+		if err := processMerge(data, []string{input, name}); !errors.As(err, &pathErr) || data.FileName != name {
+			t.Errorf("processMerge(%q) error = %v, file name = %q, expected a path error for it", name, err, data.FileName)
+		}
+	}
+
+}
+
+func TestMergeBatchesKeepsInputs(t *testing.T) {
+
+	dir := t.TempDir()
+	data := &Data{Flags: &flags.Flags{Merge: true, BatchSize: 2}}
+
+	for i, content := range []string{"a\nd\n", "b\ne\n", "c\nf\n"} {
+		input := filepath.Join(dir, fmt.Sprint("input", i))
+		if err := os.WriteFile(input, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write input: %v", err)
+		}
+		data.Chunks = append(data.Chunks, input)
+	}
+	inputs := slices.Clone(data.Chunks)
+
+	if err := mergeBatches(data); err != nil {
+		t.Fatalf("mergeBatches returned error: %v", err)
+	}
+
+	fileName, err := mergeChunks(data.Chunks, data.Flags)
+	if err != nil {
+		t.Fatalf("mergeChunks returned error: %v", err)
+	}
+	defer func() {
+		if rmErr := removeTemp(fileName); rmErr != nil {
+			t.Logf("failed to remove chunk file: %v", rmErr)
+		}
+	}()
+
+	if result, err := os.ReadFile(fileName); err != nil {
+		t.Errorf("failed to read merged chunk: %v", err)
+	} else if expected := "a\nb\nc\nd\ne\nf\n"; string(result) != expected {
+		t.Errorf("mergeBatches: merged = %q, expected = %q", string(result), expected)
+	}
+
+	for _, input := range inputs { // Only the temporary chunks of the intermediate merges are removed
+		if _, err := os.Stat(input); err != nil {
+			t.Errorf("mergeBatches: input %q removed: %v", input, err)
+		}
+	}
+
+}
+
+func TestMergeBatchesRandom(t *testing.T) {
+
+	data := &Data{Flags: &flags.Flags{Random: true, S: true, BatchSize: 2, Salt: []byte("0123456789abcdef"), Keys: []flags.Key{{EndField: 0, Order: flags.Order{Random: true}}}}}
+
+	for _, chunk := range [][]string{{"a 1", "b 1", "c 1"}, {"c 2", "a 2"}, {"b 2", "a 3", "d 1"}} {
+		sortChunk(chunk, data.Flags)
+		fileName, err := saveChunk(chunk, data.Flags)
+		if err != nil {
+			t.Fatalf("saveChunk returned error: %v", err)
+		}
+		data.Chunks = append(data.Chunks, fileName)
+	}
+
+	if err := mergeBatches(data); err != nil {
+		t.Fatalf("mergeBatches returned error: %v", err)
+	}
+
+	fileName, err := mergeChunks(data.Chunks, data.Flags)
+	if err != nil {
+		t.Fatalf("mergeChunks returned error: %v", err)
+	}
+	defer func() {
+		if rmErr := removeTemp(fileName); rmErr != nil {
+			t.Logf("failed to remove chunk file: %v", rmErr)
+		}
+	}()
+
+	result, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read merged chunk: %v", err)
+	}
+
+	var keys []string // Keys in the order their groups come in
+	for line := range strings.Lines(string(result)) {
+		key, _, _ := strings.Cut(line, " ")
+		if len(keys) == 0 || keys[len(keys)-1] != key {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) != 4 { // Each of a, b, c and d once: equal keys from different chunks are adjacent
+		t.Errorf("mergeBatches: merged = %q, expected equal keys together", string(result))
+	}
+
+}
+
+func TestMergeChunksZeroTerminated(t *testing.T) {
+
+	opts := &flags.Flags{Z: true, Keys: []flags.Key{{StartField: 1, EndField: 1}}}
+
+	var chunks []string
+	for _, chunk := range [][]string{{"b\nz 1", "a\nc 2"}, {"d\nm", "e" + strings.Repeat("x", 1<<17)}} {
+		sortChunk(chunk, opts)
+		fileName, err := saveChunk(chunk, opts)
+		if err != nil {
+			t.Fatalf("saveChunk returned error: %v", err)
+		}
+		chunks = append(chunks, fileName)
+	}
+
+	fileName, err := mergeChunks(chunks, opts)
+	if err != nil {
+		t.Fatalf("mergeChunks returned error: %v", err)
+	}
+	defer func() {
+		if rmErr := removeTemp(fileName); rmErr != nil {
+			t.Logf("failed to remove chunk file: %v", rmErr)
+		}
+	}()
+
+	result, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read merged chunk: %v", err)
+	}
+
+	// Newlines separate fields: the keys are "", "\nc", "\nm" and "\nz"
+	expected := "e" + strings.Repeat("x", 1<<17) + "\x00a\nc 2\x00d\nm\x00b\nz 1\x00"
+	if string(result) != expected {
+		t.Errorf("mergeChunks with -z: merged = %.60q, expected = %.60q", string(result), expected)
+	}
+
+}
+
+func TestMergeSort(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		flags    *flags.Flags
+		chunks   []string
+		expected string
+	}{
+		{"bytes", &flags.Flags{}, []string{"a\nd\n", "b\nc\ne\n", "", "a\nf\n"}, "a\na\nb\nc\nd\ne\nf\n"},
+		{"reverse", &flags.Flags{R: true}, []string{"d\na\n", "c\nb\n"}, "d\nc\nb\na\n"},
+		{"ties keep chunk order", &flags.Flags{S: true, Keys: []flags.Key{{EndField: 0}}}, []string{"a 2\nb 1\n", "a 1\nb 2\n", "a 3\n"}, "a 2\na 1\na 3\nb 1\nb 2\n"},
+		{"unique keeps first", &flags.Flags{U: true, N: true}, []string{"1 x\n2 x\n", "01 y\n3 y\n"}, "1 x\n2 x\n3 y\n"},
+	}
+
+	for _, tt := range tests {
+
+		readers := make([]*linereader.Reader, len(tt.chunks))
+		lines := make([]string, len(tt.chunks))
+		alive := make([]bool, len(tt.chunks))
+
+		for i, chunk := range tt.chunks {
+			readers[i] = linereader.New(strings.NewReader(chunk), '\n')
+			if readers[i].Scan() {
+				lines[i] = readers[i].Text()
+				alive[i] = true
+			}
+		}
+
+		var sb strings.Builder
+		writer := bufio.NewWriter(&sb)
+
+		if err := mergeSort(readers, lines, alive, tt.flags, writer); err != nil {
+			t.Fatalf("mergeSort (%s) returned error: %v", tt.name, err)
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatalf("failed to flush output: %v", err)
+		}
+
+		if sb.String() != tt.expected {
+			t.Errorf("mergeSort (%s): result = %q, expected = %q", tt.name, sb.String(), tt.expected)
+		}
 
-	idx := getLesserLineIdx(-1, 1, lines, flags)
-	if idx != 1 {
-		t.Errorf("getLesserLineIdx: result = %d, expected = %d", idx, 1)
 	}
 
 }
@@ -106,24 +385,18 @@ func TestUpdateLines(t *testing.T) {
 		t.Fatalf("failed to seek temp file: %v", err)
 	}
 
-	scanner := bufio.NewScanner(file)
-	scanners := []*bufio.Scanner{scanner}
+	reader := linereader.New(file, '\n')
+	readers := []*linereader.Reader{reader}
 	lines := []string{""}
 	alive := []bool{true}
 
-	updateLines(scanners, lines, alive, 0)
+	updateLines(readers, lines, alive, 0)
 	if lines[0] != "q" || !alive[0] {
 		t.Errorf("updateLines: lines[0] = %q, alive[0] = %v, expected = %q, %v", lines[0], alive[0], "q", true)
 	}
 
 }
 
-func TestPrintBlanks(t *testing.T) {
-	amount := &atomic.Int32{}
-	amount.Store(3)
-	printBlanks(amount)
-}
-
 func TestMergePrep(t *testing.T) {
 
 	result := []string{"a\n", "b\n"}
@@ -154,12 +427,12 @@ func TestMergePrep(t *testing.T) {
 
 	}
 
-	scnrs := make([]*bufio.Scanner, len(chunkFiles))
+	readers := make([]*linereader.Reader, len(chunkFiles))
 	lines := make([]string, len(chunkFiles))
 	alive := make([]bool, len(chunkFiles))
-	files := make([]*os.File, len(chunkFiles))
+	files := make([]io.ReadCloser, len(chunkFiles))
 
-	if err := mergePrep(scnrs, files, lines, alive, chunkFiles); err != nil {
+	if err := mergePrep(readers, files, lines, alive, chunkFiles, new(flags.Flags)); err != nil {
 		t.Fatalf("mergePrep returned error: %v", err)
 	}
 
@@ -183,7 +456,7 @@ func TestCleanup(t *testing.T) {
 	}
 
 	chunks := []string{tmpFile.Name()}
-	if err := cleanup([]*os.File{}, chunks); err != nil {
+	if err := cleanup([]io.ReadCloser{}, chunks); err != nil {
 		t.Errorf("cleanup returned error: %v", err)
 	}
 
@@ -193,29 +466,28 @@ func TestCleanup(t *testing.T) {
 
 }
 
-func TestPrepareLine(t *testing.T) {
+func TestOutput(t *testing.T) {
 
-	flags := &flags.Flags{K: true, ClmnToSort: 1}
-	line := "Why are you reading this"
+	var sb strings.Builder
+	var prevLine comparator.Line
+	var printed bool
+	flags := &flags.Flags{U: true}
+	writer := bufio.NewWriter(&sb)
 
-	result := prepareLine(line, flags)
-	expected := "are"
+	c := comparator.New(flags)
 
-	if result != expected {
-		t.Errorf("prepareLine(%q) = %q, expected = %q", line, result, expected)
+	for _, line := range []string{"test", "test", ""} {
+		if err := output(writer, c.Prepare(line), &prevLine, &printed, flags, c); err != nil {
+			t.Fatalf("output returned error: %v", err)
+		}
 	}
 
-}
-
-func TestOutput(t *testing.T) {
-
-	var prevLine string
-	var printedEmpty bool
-	flags := &flags.Flags{U: true}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("failed to flush output: %v", err)
+	}
 
-	output("test", &prevLine, flags, &printedEmpty)
-	if prevLine != "test" {
-		t.Errorf("output: prevLine = %q, expected = %q", prevLine, "test")
+	if expected := "test\n\n"; sb.String() != expected {
+		t.Errorf("output: result = %q, expected = %q", sb.String(), expected)
 	}
 
 }
@@ -0,0 +1,161 @@
+package sorter
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"L2.10/internal/flags"
+	"L2.10/internal/syserr"
+)
+
+// temps keeps track of the temporary files that exist, so that they are removed on
+// every way out of the sort, including fatal errors and signals.
+var temps = struct {
+	sync.Mutex
+	names map[string]struct{} // Temporary files created and not yet removed or renamed
+	next  int                 // Index of the -T directory the next chunk goes to
+}{names: make(map[string]struct{})}
+
+// tempError is a failure to create a temporary file, reported the way GNU sort reports it.
+type tempError struct {
+	dir string // Directory the file was to be created in
+	err error  // Underlying error
+}
+
+// Error returns the message without the "sort: " prefix.
+func (e *tempError) Error() string {
+	return fmt.Sprintf("cannot create temporary file in '%s': %s", e.dir, syserr.Describe(e.err))
+}
+
+// Unwrap returns the underlying error.
+func (e *tempError) Unwrap() error {
+	return e.err
+}
+
+// createTempChunk creates a new chunk file in the temporary directory. With several
+// -T directories, chunks are spread across them in turn; without any, $TMPDIR or
+// /tmp is used.
+func createTempChunk(flags *flags.Flags) (*os.File, error) {
+
+	temps.Lock()
+	dir := os.TempDir()
+	if len(flags.TempDirs) > 0 {
+		dir = flags.TempDirs[temps.next%len(flags.TempDirs)]
+		temps.next++
+	}
+	temps.Unlock()
+
+	file, err := trackTemp(func() (*os.File, error) {
+		return os.CreateTemp(dir, "sort")
+	})
+	if err != nil {
+		return nil, &tempError{dir: dir, err: err}
+	}
+	return file, nil
+
+}
+
+// trackTemp creates a temporary file with create and records it for removal. A signal
+// cannot come in between, since removeTempFiles waits for the lock held meanwhile.
+func trackTemp(create func() (*os.File, error)) (*os.File, error) {
+
+	temps.Lock()
+	defer temps.Unlock()
+
+	file, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	temps.names[file.Name()] = struct{}{}
+	return file, nil
+
+}
+
+// removeTemp removes a temporary file and stops tracking it.
+func removeTemp(name string) error {
+	temps.Lock()
+	defer temps.Unlock()
+	delete(temps.names, name)
+	return os.Remove(name)
+}
+
+// temporary returns the names of temporary files among names, leaving out the input
+// files -m merges directly, which must not be removed.
+func temporary(names []string) []string {
+
+	temps.Lock()
+	defer temps.Unlock()
+
+	var tempNames []string
+	for _, name := range names {
+		if _, ok := temps.names[name]; ok {
+			tempNames = append(tempNames, name)
+		}
+	}
+
+	return tempNames
+
+}
+
+// isTemp reports whether name is a temporary file.
+func isTemp(name string) bool {
+	temps.Lock()
+	defer temps.Unlock()
+	_, ok := temps.names[name]
+	return ok
+}
+
+// forgetTemp stops tracking a temporary file that has been renamed to a permanent one.
+func forgetTemp(name string) {
+	temps.Lock()
+	defer temps.Unlock()
+	delete(temps.names, name)
+}
+
+// removeTempFiles removes every temporary file that is left. It is only called on the
+// way out, so it keeps the lock: no temporary file can be created afterwards.
+func removeTempFiles() {
+
+	temps.Lock()
+
+	for name := range temps.names {
+		_ = os.Remove(name) // Nothing is left to report the failure to
+	}
+	clear(temps.names)
+
+}
+
+// exit removes the temporary files and terminates the process with code.
+func exit(code int) {
+	removeTempFiles()
+	os.Exit(code)
+}
+
+// handleSignals makes the signals that terminate sort remove its temporary files first.
+func handleSignals() {
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT, syscall.SIGPIPE)
+
+	go func() {
+		die(<-signals)
+	}()
+
+}
+
+// die removes the temporary files and terminates the process the way a shell reports
+// a process killed by sig: with the exit code 128 plus the signal number.
+func die(sig os.Signal) {
+
+	removeTempFiles()
+
+	if signum, ok := sig.(syscall.Signal); ok {
+		os.Exit(128 + int(signum))
+	}
+	os.Exit(internalError)
+
+}
@@ -0,0 +1,81 @@
+package sorter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"L2.10/internal/flags"
+)
+
+func TestCreateTempChunk(t *testing.T) {
+
+	dirs := []string{t.TempDir(), t.TempDir()}
+	opts := &flags.Flags{TempDirs: dirs}
+
+	temps.Lock()
+	temps.next = 0 // Other tests may have created chunks already
+	temps.Unlock()
+
+	for i := range 4 { // Chunks go to the -T directories in turn
+
+		file, err := createTempChunk(opts)
+		if err != nil {
+			t.Fatalf("createTempChunk returned error: %v", err)
+		}
+
+		if err := file.Close(); err != nil {
+			t.Errorf("failed to close chunk file: %v", err)
+		}
+
+		if dir := filepath.Dir(file.Name()); dir != dirs[i%len(dirs)] {
+			t.Errorf("createTempChunk: chunk %d created in %q, expected = %q", i, dir, dirs[i%len(dirs)])
+		}
+
+		if err := removeTemp(file.Name()); err != nil {
+			t.Errorf("removeTemp returned error: %v", err)
+		}
+
+	}
+
+	missing := filepath.Join(dirs[0], "missing")
+	_, err := createTempChunk(&flags.Flags{TempDirs: []string{missing}})
+	expected := "cannot create temporary file in '" + missing + "': No such file or directory"
+	if err == nil || err.Error() != expected {
+		t.Errorf("createTempChunk(%q) error = %v, expected = %q", missing, err, expected)
+	}
+
+}
+
+func TestRemoveTempFiles(t *testing.T) {
+
+	opts := &flags.Flags{TempDirs: []string{t.TempDir()}}
+
+	var names []string
+	for range 3 {
+		file, err := createTempChunk(opts)
+		if err != nil {
+			t.Fatalf("createTempChunk returned error: %v", err)
+		}
+		if err := file.Close(); err != nil {
+			t.Errorf("failed to close chunk file: %v", err)
+		}
+		names = append(names, file.Name())
+	}
+
+	forgetTemp(names[0]) // As if renamed over the -o file
+
+	removeTempFiles()
+	temps.Unlock() // removeTempFiles keeps the lock, since it is meant to be followed by an exit
+
+	if _, err := os.Stat(names[0]); err != nil {
+		t.Errorf("removeTempFiles: forgotten file %q removed: %v", names[0], err)
+	}
+
+	for _, name := range names[1:] {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("removeTempFiles: file %q was not removed", name)
+		}
+	}
+
+}
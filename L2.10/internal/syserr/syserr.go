@@ -0,0 +1,24 @@
+// Package syserr words system errors the way the C library does, so that the messages
+// of the sort utility match those of GNU sort.
+package syserr
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+)
+
+// Describe returns the description of the system error behind err, capitalized like
+// strerror(3) words it, e.g. "No such file or directory", or the message of err if it
+// is not a system error.
+func Describe(err error) string {
+
+	var errno syscall.Errno
+	if !errors.As(err, &errno) || errno.Error() == "" {
+		return err.Error()
+	}
+
+	msg := errno.Error()
+	return strings.ToUpper(msg[:1]) + msg[1:]
+
+}
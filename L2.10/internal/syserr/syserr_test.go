@@ -0,0 +1,31 @@
+package syserr
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{syscall.ENOENT, "No such file or directory"},
+		{&fs.PathError{Op: "open", Path: "x", Err: syscall.EACCES}, "Permission denied"},
+		{fmt.Errorf("write: %w", syscall.ENOSPC), "No space left on device"},
+		{os.ErrNotExist, "file does not exist"},
+		{errors.New("short write"), "short write"},
+	}
+
+	for _, tt := range tests {
+		if result := Describe(tt.err); result != tt.expected {
+			t.Errorf("Describe(%v) = %q, expected = %q", tt.err, result, tt.expected)
+		}
+	}
+
+}
@@ -16,9 +16,60 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/create_event": {
+        "/admin/compact": {
             "post": {
-                "description": "Creates an event for a user",
+                "description": "Makes the storage backend reclaim unused space, if the backend supports it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Force a storage compaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant whose data is managed, used when tenancy is enabled",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.CompactResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse401"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse500"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse501"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/delete_user_events": {
+            "post": {
+                "description": "Removes every event owned by the user and returns how many were removed",
                 "consumes": [
                     "application/json"
                 ],
@@ -26,17 +77,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "admin"
                 ],
-                "summary": "Create a new event",
+                "summary": "Delete all events of a user",
                 "parameters": [
                     {
-                        "description": "Event data",
+                        "type": "string",
+                        "description": "Tenant whose data is managed, used when tenancy is enabled",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User to purge",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateRequestV1"
+                            "$ref": "#/definitions/admin.DeleteUserEventsRequest"
                         }
                     }
                 ],
@@ -44,73 +101,179 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateResponseV1"
+                            "$ref": "#/definitions/admin.DeleteUserEventsResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse400"
+                            "$ref": "#/definitions/admin.ErrorResponse400"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse401"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse500"
+                            "$ref": "#/definitions/admin.ErrorResponse500"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
             }
         },
-        "/api/v1/delete_event": {
+        "/admin/snapshot": {
             "post": {
-                "description": "Deletes an event for a user by ID",
-                "consumes": [
+                "description": "Makes the storage backend persist a snapshot of its data, if the backend supports it",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Force a storage snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant whose data is managed, used when tenancy is enabled",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.SnapshotResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse401"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse500"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse501"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/stats": {
+            "get": {
+                "description": "Returns user, event and upcoming event totals, the events per day histogram and the approximate storage size",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "admin"
                 ],
-                "summary": "Delete an event",
+                "summary": "Storage statistics",
                 "parameters": [
                     {
-                        "description": "Event delete data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Tenant whose data is managed, used when tenancy is enabled",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.DeleteRequestV1"
+                            "$ref": "#/definitions/admin.StatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse401"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ErrorResponse500"
                         }
                     }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "description": "Returns every user owning at least one event together with their event count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant whose data is managed, used when tenancy is enabled",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.DeleteResponseV1"
+                            "$ref": "#/definitions/admin.UsersResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse400"
+                            "$ref": "#/definitions/admin.ErrorResponse401"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse500"
+                            "$ref": "#/definitions/admin.ErrorResponse500"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ]
             }
         },
-        "/api/v1/events_for_day": {
+        "/api/v1/attachments": {
             "get": {
-                "description": "Returns all events for a given day for a user",
+                "description": "Returns the files attached to an event of the user, in upload order",
                 "consumes": [
                     "application/json"
                 ],
@@ -118,10 +281,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "attachments"
                 ],
-                "summary": "Get events for a day",
+                "summary": "List event attachments",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
                     {
                         "type": "integer",
                         "description": "User ID",
@@ -131,8 +300,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Date in YYYY-MM-DD format",
-                        "name": "date",
+                        "description": "Event ID",
+                        "name": "event_id",
                         "in": "query",
                         "required": true
                     }
@@ -141,7 +310,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.ListOfEventsResponseV1"
+                            "$ref": "#/definitions/v1.ListOfAttachmentsResponseV1"
                         }
                     },
                     "400": {
@@ -159,9 +328,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/events_for_month": {
-            "get": {
-                "description": "Returns all events for a given month for a user",
+        "/api/v1/create_event": {
+            "post": {
+                "description": "Creates an event for a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -171,28 +340,29 @@ const docTemplate = `{
                 "tags": [
                     "events"
                 ],
-                "summary": "Get events for a month",
+                "summary": "Create a new event",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "Date in YYYY-MM-DD format",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
+                        "description": "Event data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateRequestV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.ListOfEventsResponseV1"
+                            "$ref": "#/definitions/v1.CreateResponseV1"
                         }
                     },
                     "400": {
@@ -210,9 +380,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/events_for_week": {
-            "get": {
-                "description": "Returns all events for a given week for a user",
+        "/api/v1/create_webhook": {
+            "post": {
+                "description": "Registers a URL that receives a signed JSON notification whenever an event of the user is created, updated or deleted. The signing secret is only returned in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -220,30 +390,83 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "webhooks"
                 ],
-                "summary": "Get events for a week",
+                "summary": "Register a webhook",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Webhook data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateWebhookRequestV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateWebhookResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/delete_attachment": {
+            "post": {
+                "description": "Removes a file attached to an event of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Delete an event attachment",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Date in YYYY-MM-DD format",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Attachment delete data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeleteAttachmentRequestV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.ListOfEventsResponseV1"
+                            "$ref": "#/definitions/v1.DeleteAttachmentResponseV1"
                         }
                     },
                     "400": {
@@ -261,9 +484,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/update_event": {
+        "/api/v1/delete_event": {
             "post": {
-                "description": "Updates an event's text or date",
+                "description": "Deletes an event for a user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -273,15 +496,21 @@ const docTemplate = `{
                 "tags": [
                     "events"
                 ],
-                "summary": "Update an existing event",
+                "summary": "Delete an event",
                 "parameters": [
                     {
-                        "description": "Event update data",
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Event delete data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateRequestV1"
+                            "$ref": "#/definitions/v1.DeleteRequestV1"
                         }
                     }
                 ],
@@ -289,7 +518,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateResponseV1"
+                            "$ref": "#/definitions/v1.DeleteResponseV1"
                         }
                     },
                     "400": {
@@ -306,21 +535,1059 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "v1.CreateRequestV1": {
-            "type": "object",
-            "properties": {
-                "date": {
-                    "description": "EventDate is the date of the event in YYYY-MM-DD format.",
-                    "type": "string",
+        },
+        "/api/v1/delete_webhook": {
+            "post": {
+                "description": "Removes a webhook of the user together with its pending deliveries and delivery log",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Webhook delete data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeleteWebhookRequestV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeleteWebhookResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/download_attachment": {
+            "get": {
+                "description": "Returns the content of a file attached to an event of the user",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Download an event attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events_for_day": {
+            "get": {
+                "description": "Returns all events for a given day for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events for a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list the holidays of the period",
+                        "name": "holidays",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListOfEventsResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events_for_month": {
+            "get": {
+                "description": "Returns all events for a given month for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events for a month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list the holidays of the period",
+                        "name": "holidays",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListOfEventsResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events_for_week": {
+            "get": {
+                "description": "Returns all events for a given week for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events for a week",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list the holidays of the period",
+                        "name": "holidays",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListOfEventsResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/quick_add": {
+            "post": {
+                "description": "Reads an English or Russian phrase such as \"lunch with Ann next friday\", \"dentist 2028-12-04\" or \"каждый понедельник standup\" as an event date, text and optional recurrence, resolving relative dates against reference_date. The interpretation is returned for confirmation; with confirm set the event is created. Recurring phrases create their first occurrence and list the upcoming dates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Quick-add an event from a phrase",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Phrase and options",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.QuickAddRequestV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.QuickAddResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/retry_delivery": {
+            "post": {
+                "description": "Moves a delivery from the dead-letter list of a webhook back into the queue with a fresh attempt budget",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Retry a dead-lettered delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Delivery retry data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RetryDeliveryRequestV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RetryDeliveryResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/settings": {
+            "get": {
+                "description": "Returns the user's holiday calendar country and working week, or the defaults",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get working calendar settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SettingsResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/update_event": {
+            "post": {
+                "description": "Updates an event's text or date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update an existing event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Event update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateRequestV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/update_settings": {
+            "post": {
+                "description": "Sets the user's holiday calendar country and working week",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update working calendar settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Working calendar settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSettingsRequestV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSettingsResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload_attachment": {
+            "post": {
+                "description": "Uploads a file as multipart/form-data and attaches it to an event of the user. The file type is detected from its content.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Attach a file to an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UploadAttachmentResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse413"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse415"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhook_deliveries": {
+            "get": {
+                "description": "Returns the notifications sent to a webhook of the user, newest first. Filter by status=dead to get the dead-letter list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook delivery log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListOfDeliveriesResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "Returns the webhooks of the user, in registration order. Secrets are not included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID, used when tenancy is enabled with the header source",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ListOfWebhooksResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse400"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse500"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.CompactResponse": {
+            "type": "object",
+            "properties": {
+                "storage_compacted": {
+                    "description": "Compacted indicates whether the storage was compacted.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "admin.DayCountDto": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Date is the day in YYYY-MM-DD format.",
+                    "type": "string",
+                    "example": "2028-12-04"
+                },
+                "events": {
+                    "description": "Events is the number of events on that day across all users.",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "admin.DeleteUserEventsRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "description": "UserID is the ID of the user whose events are deleted.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "admin.DeleteUserEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events_deleted": {
+                    "description": "Deleted is the number of removed events.",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "admin.ErrorResponse400": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the HTTP status code.",
+                    "type": "integer",
+                    "example": 400
+                },
+                "message": {
+                    "description": "Message is a human-readable description of the error.",
+                    "type": "string",
+                    "example": "missing or invalid user ID"
+                }
+            }
+        },
+        "admin.ErrorResponse401": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the HTTP status code.",
+                    "type": "integer",
+                    "example": 401
+                },
+                "message": {
+                    "description": "Message is a human-readable description of the error.",
+                    "type": "string",
+                    "example": "unauthorized: missing or invalid admin token"
+                }
+            }
+        },
+        "admin.ErrorResponse500": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the HTTP status code.",
+                    "type": "integer",
+                    "example": 500
+                },
+                "message": {
+                    "description": "Message is a human-readable description of the error.",
+                    "type": "string",
+                    "example": "internal server error"
+                }
+            }
+        },
+        "admin.ErrorResponse501": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the HTTP status code.",
+                    "type": "integer",
+                    "example": 501
+                },
+                "message": {
+                    "description": "Message is a human-readable description of the error.",
+                    "type": "string",
+                    "example": "operation not supported by the storage backend"
+                }
+            }
+        },
+        "admin.SnapshotResponse": {
+            "type": "object",
+            "properties": {
+                "snapshot_taken": {
+                    "description": "Snapshot indicates whether the snapshot was taken.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "admin.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Events is the total number of events.",
+                    "type": "integer",
+                    "example": 5
+                },
+                "events_per_day": {
+                    "description": "EventsPerDay is the events per day histogram ordered by date.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.DayCountDto"
+                    }
+                },
+                "size_bytes": {
+                    "description": "SizeBytes is the approximate size of the stored events.",
+                    "type": "integer",
+                    "example": 1024
+                },
+                "upcoming_events": {
+                    "description": "Upcoming is the number of events dated today or later.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "users": {
+                    "description": "Users is the number of users with at least one event.",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "admin.UserDto": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Events is the number of events owned by the user.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "admin.UsersResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "description": "Users is the list of users ordered by ID.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.UserDto"
+                    }
+                }
+            }
+        },
+        "v1.AttachmentDtoV1": {
+            "type": "object",
+            "properties": {
+                "attachment_id": {
+                    "description": "AttachmentID is the unique identifier of the attachment.",
+                    "type": "string",
+                    "example": "9b2f0c1e-4d7a-4f3e-8a51-6c2d9e0b7a13"
+                },
+                "content_type": {
+                    "description": "ContentType is the MIME type detected from the file content.",
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "name": {
+                    "description": "Name is the original file name.",
+                    "type": "string",
+                    "example": "agenda.pdf"
+                },
+                "size": {
+                    "description": "Size is the file size in bytes.",
+                    "type": "integer",
+                    "example": 48213
+                },
+                "uploaded_at": {
+                    "description": "UploadedAt is the upload time in RFC 3339 format.",
+                    "type": "string",
+                    "example": "2028-12-01T10:00:00Z"
+                }
+            }
+        },
+        "v1.CreateRequestV1": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "EventDate is the date of the event in YYYY-MM-DD format.",
+                    "type": "string",
                     "example": "2028-12-04"
                 },
-                "text": {
-                    "description": "Text is the optional description of the event.",
+                "text": {
+                    "description": "Text is the optional description of the event.",
+                    "type": "string",
+                    "example": "Touch grass"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user who owns the event.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.CreateResponseV1": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "description": "EventID is the unique identifier of the newly created event.",
+                    "type": "string",
+                    "example": "3383503d-fb71-4b8c-85bd-a914c84252a9"
+                },
+                "warning": {
+                    "description": "Warning is set when the event was created on a non-working day.",
+                    "type": "string",
+                    "example": "event date falls on a non-working day: 2028-12-03 (Sunday)"
+                }
+            }
+        },
+        "v1.CreateWebhookRequestV1": {
+            "type": "object",
+            "required": [
+                "url",
+                "user_id"
+            ],
+            "properties": {
+                "url": {
+                    "description": "URL is the http or https address notifications are posted to.",
+                    "type": "string",
+                    "example": "https://bot.example.com/calendar"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user whose events are reported.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.CreateWebhookResponseV1": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "Secret is the key notifications are signed with. It is only returned once.",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "webhook": {
+                    "description": "Webhook describes the registered webhook.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1.WebhookDtoV1"
+                        }
+                    ]
+                }
+            }
+        },
+        "v1.DeleteAttachmentRequestV1": {
+            "type": "object",
+            "required": [
+                "attachment_id",
+                "event_id",
+                "user_id"
+            ],
+            "properties": {
+                "attachment_id": {
+                    "description": "AttachmentID is the unique identifier of the attachment to remove.",
+                    "type": "string",
+                    "example": "9b2f0c1e-4d7a-4f3e-8a51-6c2d9e0b7a13"
+                },
+                "event_id": {
+                    "description": "EventID is the unique identifier of the event.",
                     "type": "string",
-                    "example": "Touch grass"
+                    "example": "3383503d-fb71-4b8c-85bd-a914c84252a9"
                 },
                 "user_id": {
                     "description": "UserID is the ID of the user who owns the event.",
@@ -329,13 +1596,13 @@ const docTemplate = `{
                 }
             }
         },
-        "v1.CreateResponseV1": {
+        "v1.DeleteAttachmentResponseV1": {
             "type": "object",
             "properties": {
-                "event_id": {
-                    "description": "EventID is the unique identifier of the newly created event.",
-                    "type": "string",
-                    "example": "3383503d-fb71-4b8c-85bd-a914c84252a9"
+                "attachment_deleted": {
+                    "description": "Deleted indicates whether the attachment was successfully removed.",
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
@@ -368,6 +1635,90 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.DeleteWebhookRequestV1": {
+            "type": "object",
+            "required": [
+                "user_id",
+                "webhook_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "description": "UserID is the ID of the user who owns the webhook.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "webhook_id": {
+                    "description": "WebhookID is the unique identifier of the webhook to remove.",
+                    "type": "string",
+                    "example": "5f0c2a8e-3b1d-4e6f-9a7c-2d8b1e4f6a90"
+                }
+            }
+        },
+        "v1.DeleteWebhookResponseV1": {
+            "type": "object",
+            "properties": {
+                "webhook_deleted": {
+                    "description": "Deleted indicates whether the webhook was successfully removed.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "v1.DeliveryDtoV1": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts is the number of attempts made so far.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "description": "CreatedAt is the time of the change in RFC 3339 format.",
+                    "type": "string",
+                    "example": "2028-12-01T10:00:00Z"
+                },
+                "delivery_id": {
+                    "description": "DeliveryID is the unique identifier of the notification, sent in every attempt.",
+                    "type": "string",
+                    "example": "c1a4e7b2-8d3f-4a6e-b5c9-0f2d7e1a3b48"
+                },
+                "last_error": {
+                    "description": "LastError describes why the last attempt failed.",
+                    "type": "string",
+                    "example": "webhook responded with 502 Bad Gateway"
+                },
+                "last_status_code": {
+                    "description": "LastStatus is the HTTP status code of the last response, if there was one.",
+                    "type": "integer",
+                    "example": 204
+                },
+                "next_attempt": {
+                    "description": "NextAttempt is the time of the next attempt of a pending delivery in RFC 3339 format.",
+                    "type": "string",
+                    "example": "2028-12-01T10:00:10Z"
+                },
+                "status": {
+                    "description": "Status is the delivery state; dead deliveries form the dead-letter list.",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "delivered",
+                        "dead"
+                    ],
+                    "example": "delivered"
+                },
+                "type": {
+                    "description": "Type is the kind of change reported.",
+                    "type": "string",
+                    "example": "event.created"
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is the time of the last attempt in RFC 3339 format.",
+                    "type": "string",
+                    "example": "2028-12-01T10:00:01Z"
+                }
+            }
+        },
         "v1.ErrorResponse400": {
             "type": "object",
             "properties": {
@@ -383,6 +1734,36 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.ErrorResponse413": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the HTTP status code.",
+                    "type": "integer",
+                    "example": 413
+                },
+                "message": {
+                    "description": "Message is a human-readable description of the error.",
+                    "type": "string",
+                    "example": "attachment exceeds the maximum allowed size"
+                }
+            }
+        },
+        "v1.ErrorResponse415": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the HTTP status code.",
+                    "type": "integer",
+                    "example": 415
+                },
+                "message": {
+                    "description": "Message is a human-readable description of the error.",
+                    "type": "string",
+                    "example": "attachment type is not allowed: application/x-msdownload"
+                }
+            }
+        },
         "v1.ErrorResponse500": {
             "type": "object",
             "properties": {
@@ -411,6 +1792,16 @@ const docTemplate = `{
                     "type": "string",
                     "example": "3383503d-fb71-4b8c-85bd-a914c84252a9"
                 },
+                "holiday": {
+                    "description": "Holiday is the name of the holiday on the event date, if any.",
+                    "type": "string",
+                    "example": "Christmas Day"
+                },
+                "non_working_day": {
+                    "description": "NonWorkingDay indicates that the event date is not a working day for the user.",
+                    "type": "boolean",
+                    "example": true
+                },
                 "text": {
                     "description": "Text is the description of the event.",
                     "type": "string",
@@ -418,6 +1809,45 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.HolidayDtoV1": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Date is the day of the holiday in YYYY-MM-DD format.",
+                    "type": "string",
+                    "example": "2028-12-25"
+                },
+                "name": {
+                    "description": "Name is the name of the holiday.",
+                    "type": "string",
+                    "example": "Christmas Day"
+                }
+            }
+        },
+        "v1.ListOfAttachmentsResponseV1": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "description": "Attachments lists the files in upload order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AttachmentDtoV1"
+                    }
+                }
+            }
+        },
+        "v1.ListOfDeliveriesResponseV1": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "description": "Deliveries lists the notifications, newest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.DeliveryDtoV1"
+                    }
+                }
+            }
+        },
         "v1.ListOfEventsResponseV1": {
             "type": "object",
             "properties": {
@@ -427,6 +1857,202 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/v1.EventDtoV1"
                     }
+                },
+                "holidays": {
+                    "description": "Holidays lists the holidays of the period when requested with holidays=true.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.HolidayDtoV1"
+                    }
+                }
+            }
+        },
+        "v1.ListOfWebhooksResponseV1": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "description": "Webhooks lists the webhooks in registration order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WebhookDtoV1"
+                    }
+                }
+            }
+        },
+        "v1.QuickAddRequestV1": {
+            "type": "object",
+            "required": [
+                "text",
+                "user_id"
+            ],
+            "properties": {
+                "confirm": {
+                    "description": "Confirm creates the event; otherwise only the interpretation is returned.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "reference_date": {
+                    "description": "ReferenceDate is the client's today in YYYY-MM-DD format that relative dates are resolved against; defaults to today in UTC.",
+                    "type": "string",
+                    "example": "2028-11-29"
+                },
+                "text": {
+                    "description": "Text is the phrase in English or Russian naming the event and its date.",
+                    "type": "string",
+                    "example": "lunch with Ann next friday"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user who owns the event.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.QuickAddResponseV1": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Date is the event date, or the first occurrence of a recurring event, in YYYY-MM-DD format.",
+                    "type": "string",
+                    "example": "2028-12-08"
+                },
+                "event_created": {
+                    "description": "Created indicates whether the event was created.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "event_id": {
+                    "description": "EventID is the unique identifier of the created event.",
+                    "type": "string",
+                    "example": "3383503d-fb71-4b8c-85bd-a914c84252a9"
+                },
+                "language": {
+                    "description": "Language is the language the date was read in.",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "ru"
+                    ],
+                    "example": "en"
+                },
+                "matched": {
+                    "description": "Matched is the part of the phrase read as the date and recurrence.",
+                    "type": "string",
+                    "example": "next friday"
+                },
+                "recurrence": {
+                    "description": "Recurrence is set when the phrase names a repeating event.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1.RecurrenceDtoV1"
+                        }
+                    ]
+                },
+                "text": {
+                    "description": "Text is the phrase without the words naming the date.",
+                    "type": "string",
+                    "example": "lunch with Ann"
+                },
+                "upcoming": {
+                    "description": "Upcoming lists the next dates of a recurring event, starting with Date.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2028-12-08",
+                        "2028-12-15"
+                    ]
+                },
+                "warning": {
+                    "description": "Warning is set when the date is a non-working day.",
+                    "type": "string",
+                    "example": "event date falls on a non-working day: 2028-12-03 (Sunday)"
+                }
+            }
+        },
+        "v1.RecurrenceDtoV1": {
+            "type": "object",
+            "properties": {
+                "frequency": {
+                    "description": "Frequency is how often the event repeats.",
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ],
+                    "example": "weekly"
+                },
+                "weekday": {
+                    "description": "Weekday is the day of the week of weekly events.",
+                    "type": "string",
+                    "example": "fri"
+                }
+            }
+        },
+        "v1.RetryDeliveryRequestV1": {
+            "type": "object",
+            "required": [
+                "delivery_id",
+                "user_id",
+                "webhook_id"
+            ],
+            "properties": {
+                "delivery_id": {
+                    "description": "DeliveryID is the unique identifier of the delivery to retry.",
+                    "type": "string",
+                    "example": "c1a4e7b2-8d3f-4a6e-b5c9-0f2d7e1a3b48"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user who owns the webhook.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "webhook_id": {
+                    "description": "WebhookID is the unique identifier of the webhook.",
+                    "type": "string",
+                    "example": "5f0c2a8e-3b1d-4e6f-9a7c-2d8b1e4f6a90"
+                }
+            }
+        },
+        "v1.RetryDeliveryResponseV1": {
+            "type": "object",
+            "properties": {
+                "delivery_queued": {
+                    "description": "Queued indicates whether the delivery was moved back into the queue.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "v1.SettingsResponseV1": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "description": "Country is the code of the holiday calendar, empty for none.",
+                    "type": "string",
+                    "example": "RU"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "working_days": {
+                    "description": "WorkingDays lists the user's working days.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri"
+                    ]
                 }
             }
         },
@@ -462,9 +2088,94 @@ const docTemplate = `{
                     "description": "Updated indicates whether the event was successfully updated.",
                     "type": "boolean",
                     "example": true
+                },
+                "warning": {
+                    "description": "Warning is set when the event was moved to a non-working day.",
+                    "type": "string",
+                    "example": "event date falls on a non-working day: 2028-12-03 (Sunday)"
+                }
+            }
+        },
+        "v1.UpdateSettingsRequestV1": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "description": "Country is the code of the holiday calendar, empty for none.",
+                    "type": "string",
+                    "example": "RU"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "working_days": {
+                    "description": "WorkingDays lists the user's working days, e.g. mon..sun.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri"
+                    ]
+                }
+            }
+        },
+        "v1.UpdateSettingsResponseV1": {
+            "type": "object",
+            "properties": {
+                "settings_updated": {
+                    "description": "Updated indicates whether the settings were saved.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "v1.UploadAttachmentResponseV1": {
+            "type": "object",
+            "properties": {
+                "attachment": {
+                    "description": "Attachment describes the stored file.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1.AttachmentDtoV1"
+                        }
+                    ]
+                }
+            }
+        },
+        "v1.WebhookDtoV1": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt is the registration time in RFC 3339 format.",
+                    "type": "string",
+                    "example": "2028-12-01T10:00:00Z"
+                },
+                "url": {
+                    "description": "URL is the address notifications are posted to.",
+                    "type": "string",
+                    "example": "https://bot.example.com/calendar"
+                },
+                "webhook_id": {
+                    "description": "WebhookID is the unique identifier of the webhook.",
+                    "type": "string",
+                    "example": "5f0c2a8e-3b1d-4e6f-9a7c-2d8b1e4f6a90"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Admin API token, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -478,6 +2189,8 @@ var SwaggerInfo = &swag.Spec{
 	Description:      "",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
 }
 
 func init() {
@@ -0,0 +1,21 @@
+// Package main provides the entry point for calctl, the calendar API command-line client.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"L2.18/internal/calctl"
+)
+
+// main runs calctl and exits with its exit code. An interrupt cancels the request in flight.
+func main() {
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := calctl.Run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+
+	os.Exit(code)
+
+}
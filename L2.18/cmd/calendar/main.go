@@ -1,16 +1,30 @@
 // Package main provides the entry point for the calendar application.
 //
-// It initializes the application by booting the App and starting the HTTP server.
+// Without arguments it boots the App and starts the HTTP server; with arguments it
+// runs one of the offline maintenance commands (backup, restore, migrate) and exits.
 package main
 
 import (
+	"context"
+	"os"
+
 	_ "L2.18/api/openapi-spec/docs"
 	"L2.18/internal/app"
+	"L2.18/internal/maintenance"
 )
 
 // main is the entry point of the application.
+//
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Admin API token, sent as "Bearer <token>"
 func main() {
 
+	if len(os.Args) > 1 {
+		os.Exit(maintenance.Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	app.Boot().Run()
 
 }
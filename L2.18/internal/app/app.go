@@ -1,7 +1,7 @@
 // Package app defines the main application structure and lifecycle management.
 //
 // It handles application initialization, context and signal management, server startup,
-// graceful shutdown, and resource cleanup. The App struct encapsulates all components
+// configuration hot reload, ordered graceful shutdown, and resource cleanup. The App struct encapsulates all components
 // required to run the calendar service, including logger, server, storage, and context.
 package app
 
@@ -12,35 +12,63 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
+	"time"
 
 	"L2.18/internal/config"
+	"L2.18/internal/errs"
 	"L2.18/internal/handler"
 	"L2.18/internal/repository"
 	"L2.18/internal/server"
 	"L2.18/internal/service"
+	"L2.18/internal/webhook"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/egress"
+	"L2.18/pkg/holidays"
 	"L2.18/pkg/logger"
+	"L2.18/pkg/shutdown"
+	"L2.18/pkg/tracing"
 )
 
+// Shutdown phases, in the order they run.
+const (
+	phaseServer     = "server"     // drains in-flight requests
+	phaseBackground = "background" // stops the config watcher and the webhook dispatcher
+	phaseFlush      = "flush"      // flushes trace spans and snapshots the storage
+	phaseClose      = "close"      // closes the storage and the webhook store
+)
+
+// shutdownGrace is how long a shutdown hook still running at the deadline of its phase
+// is waited for, e.g. while the server closes the connections it could not drain.
+const shutdownGrace = time.Second
+
 // App represents the main application instance, managing its components and lifecycle.
 type App struct {
-	logger  logger.Logger      // Structured logger used throughout the application for info, warning, error, and debug logs
-	server  server.Server      // HTTP server instance that handles incoming requests
-	storage repository.Storage // Persistent storage layer for events and application data
-	ctx     context.Context    // Context used for cancellation and graceful shutdown
-	cancel  context.CancelFunc // Function to cancel the application context and trigger shutdown
-	wg      *sync.WaitGroup    // WaitGroup to synchronize goroutines during server run and shutdown
+	config     config.App              // Configuration currently in effect, updated on successful reloads
+	logger     logger.Logger           // Structured logger used throughout the application for info, warning, error, and debug logs
+	server     server.Server           // HTTP server instance that handles incoming requests
+	storage    repository.Storage      // Persistent storage layer for events and application data
+	webhooks   repository.WebhookStore // Registered webhooks and their persistent delivery queue
+	dispatcher *webhook.Dispatcher     // Background worker delivering queued webhook notifications
+	service    service.Service         // Business logic layer, reconfigured on reloads
+	limiter    *handler.Limiter        // Per-client rate limiter, reconfigured on reloads
+	tracing    tracing.Shutdown        // Flushes buffered spans and stops the span exporter
+	ctx        context.Context         // Context used for cancellation and graceful shutdown
+	cancel     context.CancelFunc      // Function to cancel the application context and trigger shutdown
+	shutdown   *shutdown.Manager       // Runs the shutdown phases and reports what had to be force-terminated
 }
 
 // Boot initializes the application and returns an App instance.
 //
 // This function performs the following tasks:
 //  1. Loads configuration from files or environment variables.
-//  2. Initializes the structured logger.
-//  3. Wires together storage, service, handler, and HTTP server components.
+//  2. Initializes the structured logger and distributed tracing, loads holiday calendars,
+//     opens the attachment storage and the webhook store, and sets up the egress policy
+//     restricting the hosts webhooks are delivered to.
+//  3. Wires together storage, service, handler, and HTTP server components, and restores
+//     the storage from its last snapshot.
 //  4. Sets up a cancellable context that listens to OS signals for graceful shutdown.
-//  5. Creates a wait group for managing goroutines.
+//  5. Declares the shutdown phases and registers the hooks stopping each component.
 //
 // If any critical error occurs during initialization (e.g., configuration load failure),
 // the function logs the error and terminates the application.
@@ -52,32 +80,60 @@ func Boot() *App {
 	}
 
 	logger := logger.NewLogger(config.Logger)
-	server, storage := wireApp(nil, config, logger)
-
-	ctx, cancel := newContext(logger)
-	wg := new(sync.WaitGroup)
-
-	return &App{
-		logger:  logger,
-		server:  server,
-		storage: storage,
-		ctx:     ctx,
-		cancel:  cancel,
-		wg:      wg,
+
+	shutdownTracing, err := tracing.Init(config.Tracing)
+	if err != nil {
+		logger.LogFatal("app — failed to initialize tracing", err, "layer", "app")
+	}
+
+	calendar, err := holidays.Load(config.Calendar)
+	if err != nil {
+		logger.LogFatal("app — failed to load holiday calendars", err, "layer", "app")
+	}
+
+	blobs, err := repository.NewBlobStore(config.Storage, logger)
+	if err != nil {
+		logger.LogFatal("app — failed to open attachment storage", err, "layer", "app")
 	}
 
+	webhooks, err := repository.NewWebhookStore(config.Storage, config.Webhooks, logger)
+	if err != nil {
+		logger.LogFatal("app — failed to open webhook store", err, "layer", "app")
+	}
+
+	policy, err := egress.NewPolicy(config.Webhooks.AllowedNetworks)
+	if err != nil {
+		logger.LogFatal("app — failed to set up the webhook egress policy", err, "layer", "app")
+	}
+
+	app := wireApp(nil, blobs, webhooks, policy, config, calendar, clock.System(), logger)
+	app.tracing = shutdownTracing
+
+	if err := app.loadSnapshot(); err != nil {
+		logger.LogFatal("app — failed to load storage snapshot", err, "layer", "app")
+	}
+
+	app.ctx, app.cancel = newContext(logger)
+	app.shutdown = app.newShutdown()
+
+	return app
+
 }
 
-// wireApp initializes repository, service, handler, and server components.
+// wireApp initializes repository, service, webhook dispatcher, handler, and server components.
 //
-// It returns the fully configured HTTP server and storage instance.
+// It returns an App holding the fully configured components; context and shutdown
+// manager are left for the caller to set up.
 // This function allows optional dependency injection for the database (db parameter).
-func wireApp(db any, config config.App, logger logger.Logger) (server.Server, repository.Storage) {
-	storage := repository.NewStorage(db, config.Storage, logger)
-	service := service.NewService(config.Service, storage, logger)
-	handler := handler.NewHandler(service, logger)
+func wireApp(db any, blobs repository.BlobStore, webhooks repository.WebhookStore, policy *egress.Policy, config config.App, calendar *holidays.Registry, clock clock.Clock, logger logger.Logger) *App {
+	storage := repository.NewStorage(db, config.Storage, clock, logger)
+	admin := service.NewAdmin(storage, blobs, logger)
+	service := service.NewService(config.Service, storage, blobs, webhooks, policy, calendar, clock, logger)
+	dispatcher := webhook.NewDispatcher(config.Webhooks, policy, webhooks, clock, logger)
+	limiter := handler.NewLimiter(config.RateLimit)
+	handler := handler.NewHandler(service, admin, limiter, config, logger)
 	server := server.NewServer(config.Server, handler, logger)
-	return server, storage
+	return &App{config: config, logger: logger, server: server, storage: storage, webhooks: webhooks, dispatcher: dispatcher, service: service, limiter: limiter}
 }
 
 // newContext creates a cancellable context and listens to OS signals for graceful shutdown.
@@ -101,39 +157,184 @@ func newContext(logger logger.Logger) (context.Context, context.CancelFunc) {
 
 }
 
+// newShutdown declares the shutdown phases with their configured timeouts and registers
+// the hooks of the components stopped in them. The hooks of background work are
+// registered when it is started, by Run.
+func (a *App) newShutdown() *shutdown.Manager {
+
+	m := shutdown.New(shutdownGrace)
+
+	m.Phase(phaseServer, a.config.Server.ShutdownTimeout)
+	m.Phase(phaseBackground, a.config.Shutdown.BackgroundTimeout)
+	m.Phase(phaseFlush, a.config.Shutdown.FlushTimeout)
+	m.Phase(phaseClose, a.config.Shutdown.CloseTimeout)
+
+	m.Register(phaseServer, "http server", a.server.Shutdown)
+	m.Register(phaseFlush, "trace exporter", a.stopTracing)
+	m.Register(phaseFlush, "storage snapshot", a.saveSnapshot)
+	m.Register(phaseClose, "storage", shutdown.Func(a.storage.Close))
+	m.Register(phaseClose, "webhook store", shutdown.Func(a.webhooks.Close))
+
+	return m
+
+}
+
 // Run starts the HTTP server and waits for the application context to be cancelled (e.g., SIGINT).
 //
 // It performs the following steps:
-// 1. Starts the HTTP server in a separate goroutine managed by the wait group.
-// 2. Blocks until the application context is cancelled.
-// 3. Logs the shutdown initiation.
-// 4. Calls App.Stop() to gracefully shut down the server and release resources.
-// 5. Waits for all goroutines in the wait group to finish.
+// 1. Starts the HTTP server in a separate goroutine.
+// 2. Starts watching for configuration changes (file edits and SIGHUP) and delivering webhook notifications.
+// 3. Blocks until the application context is cancelled.
+// 4. Logs the shutdown initiation.
+// 5. Calls App.Stop() to run the shutdown phases and release resources.
 func (a *App) Run() {
 
-	a.wg.Go(func() {
+	go func() {
 		if err := a.server.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			a.logger.LogFatal("server run failed", err, "layer", "app")
 		}
-	})
+	}()
+
+	a.background("config watcher", a.watchConfig)
+	a.background("webhook dispatcher", func() { a.dispatcher.Run(a.ctx) })
 
 	<-a.ctx.Done()
 
 	a.logger.LogInfo("app — shutting down...", "layer", "app")
 	a.Stop()
 
-	a.wg.Wait()
+}
+
+// background runs work, which must return once the application context is cancelled,
+// in a separate goroutine, and registers a hook in the background shutdown phase that
+// cancels the context and waits for work to return.
+func (a *App) background(name string, work func()) {
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		work()
+	}()
+
+	a.shutdown.Register(phaseBackground, name, func(ctx context.Context) error {
+		a.cancel()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
 
 }
 
-// Stop gracefully shuts down the HTTP server and releases all application resources.
+// Stop gracefully shuts down the application by running the shutdown phases in order,
+// each bounded by its timeout, then reports their outcome and closes the logger last,
+// so every component can log until it has stopped.
 //
-// It performs the following:
-// 1. Calls server.Shutdown() with a timeout context to stop accepting new requests and finish ongoing ones.
-// 2. Clears all in-memory data in the storage and logs the shutdown.
-// 3. Closes the logger and its underlying resources (e.g., log file).
+// The phases are:
+// 1. server: stops accepting requests and lets in-flight ones finish within server.shutdown_timeout;
+// connections still open then are closed, cutting off their requests.
+// 2. background: stops the config watcher and the webhook dispatcher.
+// 3. flush: flushes buffered trace spans and snapshots the storage, if it supports snapshots.
+// 4. close: clears and closes the storage, which refuses writes from then on, and closes the
+// webhook store, whose queue is already on disk.
+//
+// A hook still running when its phase times out is abandoned, so a stuck component cannot
+// keep the process alive, and the shutdown moves on to the next phase.
 func (a *App) Stop() {
-	a.server.Shutdown()
-	a.storage.Close()
+	report := a.shutdown.Shutdown()
+	a.logShutdown(report)
 	a.logger.Close()
 }
+
+// logShutdown logs every shutdown hook that failed or did not finish in time, followed
+// by a summary; hooks that finished cleanly are logged at debug level.
+func (a *App) logShutdown(report shutdown.Report) {
+
+	for _, hook := range report.Hooks {
+
+		args := []any{"phase", hook.Phase, "hook", hook.Hook, "duration", hook.Duration.String(), "layer", "app"}
+
+		switch {
+
+		case hook.Abandoned:
+			a.logger.LogError("app — shutdown hook abandoned at its deadline", hook.Err, args...)
+
+		case hook.TimedOut:
+			a.logger.LogWarn("app — shutdown hook cut short by its deadline", append([]any{"err", errString(hook.Err)}, args...)...)
+
+		case hook.Err != nil:
+			a.logger.LogError("app — shutdown hook failed", hook.Err, args...)
+
+		default:
+			a.logger.Debug("app — shutdown hook finished", args...)
+
+		}
+
+	}
+
+	a.logger.LogInfo("app — shutdown complete",
+		"duration", report.Duration.String(),
+		"hooks", len(report.Hooks),
+		"force_terminated", len(report.ForceTerminated()),
+		"failed", len(report.Failed()),
+		"layer", "app",
+	)
+
+}
+
+// errString returns the message of err, or an empty string if err is nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// stopTracing flushes pending spans within the deadline of ctx.
+func (a *App) stopTracing(ctx context.Context) error {
+	if a.tracing == nil {
+		return nil
+	}
+	return a.tracing(ctx)
+}
+
+// loadSnapshot restores the storage from its last snapshot, if the storage keeps snapshots.
+func (a *App) loadSnapshot() error {
+
+	snapshotter, ok := a.storage.(repository.Snapshotter)
+	if !ok {
+		return nil
+	}
+
+	loaded, err := snapshotter.LoadSnapshot(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if loaded > 0 {
+		a.logger.LogInfo("app — storage restored from snapshot", "records", loaded, "layer", "app")
+	}
+
+	return nil
+
+}
+
+// saveSnapshot snapshots the storage before its data is cleared, if the storage keeps snapshots.
+func (a *App) saveSnapshot(ctx context.Context) error {
+
+	snapshotter, ok := a.storage.(repository.Snapshotter)
+	if !ok {
+		return nil
+	}
+
+	err := snapshotter.Snapshot(ctx)
+	if err != nil && !errors.Is(err, errs.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+
+}
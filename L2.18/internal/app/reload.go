@@ -0,0 +1,139 @@
+package app
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+
+	"L2.18/internal/config"
+)
+
+// watchConfig reloads the configuration whenever the config file changes or the
+// process receives SIGHUP, until the application context is cancelled.
+//
+// If the config file cannot be watched, SIGHUP remains the only reload trigger.
+func (a *App) watchConfig() {
+
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	changes, err := config.Watch(a.ctx)
+	if err != nil {
+		a.logger.LogWarn("app — config file watching disabled, reload with SIGHUP only", "err", err.Error(), "layer", "app")
+	}
+
+	for {
+		select {
+
+		case <-a.ctx.Done():
+			return
+
+		case <-hupCh:
+			a.reload("SIGHUP")
+
+		case _, ok := <-changes:
+			if !ok {
+				changes = nil // stop selecting on the closed channel
+				continue
+			}
+			a.reload("file change")
+
+		}
+	}
+
+}
+
+// reload reads and validates the configuration and applies its reloadable settings.
+//
+// Reloadable settings are the service limits and policies, including per-tenant limits, the debug log level and the rate limits;
+// each of them is swapped atomically, and only after the whole configuration has been
+// validated, so an invalid file never leaves the application half-reconfigured.
+// Changes to any other setting are reported as requiring a restart and are not applied.
+func (a *App) reload(trigger string) {
+
+	a.logger.LogInfo("app — reloading configuration", "trigger", trigger, "layer", "app")
+
+	loaded, err := config.Reload()
+	if err != nil {
+		a.logger.LogError("app — configuration reload rejected, keeping current settings", err, "trigger", trigger, "layer", "app")
+		return
+	}
+
+	if restart := restartRequired(a.config, loaded); len(restart) > 0 {
+		a.logger.LogWarn("app — changed settings require a restart and were not applied", "settings", strings.Join(restart, ", "), "layer", "app")
+	}
+
+	a.service.Reconfigure(loaded.Service)
+	a.limiter.Reconfigure(loaded.RateLimit)
+	a.logger.SetDebug(loaded.Logger.Debug)
+
+	a.config.Service = loaded.Service
+	a.config.RateLimit = loaded.RateLimit
+	a.config.Logger.Debug = loaded.Logger.Debug
+
+	a.logger.LogInfo("app — configuration reloaded",
+		"trigger", trigger,
+		"max_events_per_user", loaded.Service.MaxEventsPerUser,
+		"max_webhooks_per_user", loaded.Service.MaxWebhooksPerUser,
+		"non_working_days", loaded.Service.NonWorkingDays,
+		"tenant_limits", len(loaded.Service.Tenants),
+		"attachment_max_size_mb", loaded.Service.Attachments.MaxSizeMB,
+		"attachment_max_per_event", loaded.Service.Attachments.MaxPerEvent,
+		"debug_mode", loaded.Logger.Debug,
+		"rate_limit_enabled", loaded.RateLimit.Enabled,
+		"rate_limit_rps", loaded.RateLimit.RequestsPerSecond,
+		"rate_limit_burst", loaded.RateLimit.Burst,
+		"layer", "app",
+	)
+
+}
+
+// restartRequired returns the names of the configuration sections that differ between
+// current and loaded but cannot be applied without restarting the application.
+func restartRequired(current, loaded config.App) []string {
+
+	var res []string
+
+	currentLogger, loadedLogger := current.Logger, loaded.Logger
+	currentLogger.Debug, loadedLogger.Debug = false, false
+
+	if currentLogger != loadedLogger {
+		res = append(res, "logger")
+	}
+	if !reflect.DeepEqual(current.Server, loaded.Server) {
+		res = append(res, "server")
+	}
+	if !reflect.DeepEqual(current.CORS, loaded.CORS) {
+		res = append(res, "cors")
+	}
+	if current.Headers != loaded.Headers {
+		res = append(res, "security_headers")
+	}
+	if !reflect.DeepEqual(current.Calendar, loaded.Calendar) {
+		res = append(res, "calendar")
+	}
+	if current.Storage != loaded.Storage {
+		res = append(res, "storage")
+	}
+	if current.Tracing != loaded.Tracing {
+		res = append(res, "tracing")
+	}
+	if current.Admin != loaded.Admin {
+		res = append(res, "admin")
+	}
+	if current.Tenancy != loaded.Tenancy {
+		res = append(res, "tenancy")
+	}
+	if !reflect.DeepEqual(current.Webhooks, loaded.Webhooks) {
+		res = append(res, "webhooks")
+	}
+	if current.Shutdown != loaded.Shutdown {
+		res = append(res, "shutdown")
+	}
+
+	return res
+
+}
@@ -0,0 +1,289 @@
+// Package backup defines the versioned JSON-lines format used to back up, restore and
+// migrate calendar data, and to keep snapshots of the in-memory storage between runs.
+//
+// A backup is a text file of JSON objects, one per line:
+//
+//	{"format":"l2.18-backup","version":1,"created_at":"2030-01-07T12:00:00Z"}
+//	{"kind":"settings","tenant":"default","settings":{"user_id":1,"country":"RU","working_days":[1,2,3,4,5]}}
+//	{"kind":"event","tenant":"default","event":{"event_id":"…","user_id":1,"date":"2030-01-08","text":"standup"}}
+//	{"kind":"attachment","tenant":"default","attachment":{"attachment_id":"…","event_id":"…","user_id":1,…}}
+//	{"kind":"end","records":3}
+//
+// The header names the format and its version; the end line counts the records, so that
+// a truncated file is told apart from a complete one. Attachments follow the event they
+// belong to. Every record is validated when read, and errors name the offending line.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"L2.18/internal/models"
+	"L2.18/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+// Format is the name of the format written in the header of every backup.
+const Format = "l2.18-backup"
+
+// Version is the version of the format written by this package. Readers accept
+// backups of this version and older ones.
+const Version = 1
+
+// maxTextLength is the longest event text accepted, the same limit the service enforces.
+const maxTextLength = 500
+
+// dateLayout is the layout of event dates in a backup.
+const dateLayout = "2006-01-02"
+
+// Record kinds, the value of the "kind" field of each line after the header.
+const (
+	kindSettings   = "settings"   // working calendar settings of a user
+	kindEvent      = "event"      // an event
+	kindAttachment = "attachment" // metadata of a file attached to an event
+	kindEnd        = "end"        // last line, counting the records
+)
+
+var (
+	ErrNotBackup     = errors.New("not an L2.18 backup")                 // the header is missing or names another format
+	ErrVersion       = errors.New("unsupported backup version")          // the backup was written by a newer version
+	ErrInvalidRecord = errors.New("invalid backup record")               // a record is malformed or breaks a rule of the format
+	ErrTruncated     = errors.New("backup is truncated")                 // the end line is missing
+	ErrRecordCount   = errors.New("backup record count does not match")  // the end line counts another number of records
+	ErrTrailingData  = errors.New("unexpected data after the end line")  // the end line is not the last one
+	ErrDuplicate     = errors.New("record already exists in the target") // a restored record collides with stored data
+)
+
+// Header is the first line of a backup.
+type Header struct {
+	Format    string    `json:"format"`     // always Format
+	Version   int       `json:"version"`    // version of the format
+	CreatedAt time.Time `json:"created_at"` // time the backup was written
+}
+
+// Record is a single piece of calendar data of a tenant. Exactly one of Settings,
+// Event and Attachment is set.
+type Record struct {
+	Tenant     string             // tenant owning the data
+	Settings   *models.Settings   // working calendar settings of a user
+	Event      *models.Event      // an event; only its ID, user, date and text are kept
+	Attachment *models.Attachment // metadata of a file attached to an event
+}
+
+// Sink receives the records of a dump, e.g. a Writer or an SQLWriter.
+type Sink interface {
+	// Write adds a record.
+	Write(rec Record) error
+}
+
+// line is the JSON form of a record line.
+type line struct {
+	Kind       string          `json:"kind"`
+	Tenant     string          `json:"tenant,omitempty"`
+	Settings   *settingsLine   `json:"settings,omitempty"`
+	Event      *eventLine      `json:"event,omitempty"`
+	Attachment *attachmentLine `json:"attachment,omitempty"`
+	Records    *int            `json:"records,omitempty"`
+}
+
+// settingsLine is the JSON form of working calendar settings.
+type settingsLine struct {
+	UserID      int            `json:"user_id"`
+	Country     string         `json:"country,omitempty"`
+	WorkingDays []time.Weekday `json:"working_days"`
+}
+
+// eventLine is the JSON form of an event.
+type eventLine struct {
+	EventID string `json:"event_id"`
+	UserID  int    `json:"user_id"`
+	Date    string `json:"date"`
+	Text    string `json:"text"`
+}
+
+// attachmentLine is the JSON form of attachment metadata.
+type attachmentLine struct {
+	AttachmentID string    `json:"attachment_id"`
+	EventID      string    `json:"event_id"`
+	UserID       int       `json:"user_id"`
+	Name         string    `json:"name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// toLine converts a validated record to its JSON form.
+func toLine(rec Record) line {
+
+	switch {
+
+	case rec.Settings != nil:
+		s := rec.Settings
+		return line{Kind: kindSettings, Tenant: rec.Tenant, Settings: &settingsLine{UserID: s.UserID, Country: s.Country, WorkingDays: s.WorkingDays}}
+
+	case rec.Event != nil:
+		e := rec.Event
+		return line{Kind: kindEvent, Tenant: rec.Tenant, Event: &eventLine{EventID: e.Meta.EventID, UserID: e.Meta.UserID, Date: e.Meta.EventDate.Format(dateLayout), Text: e.Data.Text}}
+
+	default:
+		a := rec.Attachment
+		return line{Kind: kindAttachment, Tenant: rec.Tenant, Attachment: &attachmentLine{AttachmentID: a.AttachmentID, EventID: a.EventID, UserID: a.UserID, Name: a.Name, ContentType: a.ContentType, Size: a.Size, CreatedAt: a.CreatedAt}}
+
+	}
+
+}
+
+// toRecord converts a record line to a record, checking that it has the payload of its kind.
+func toRecord(l line) (Record, error) {
+
+	if l.Kind != kindSettings && l.Kind != kindEvent && l.Kind != kindAttachment {
+		return Record{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidRecord, l.Kind)
+	}
+
+	rec := Record{Tenant: l.Tenant}
+	payloads := 0
+
+	if l.Settings != nil {
+		payloads++
+		rec.Settings = &models.Settings{UserID: l.Settings.UserID, Country: l.Settings.Country, WorkingDays: l.Settings.WorkingDays}
+	}
+
+	if l.Event != nil {
+		payloads++
+		date, err := time.Parse(dateLayout, l.Event.Date)
+		if err != nil {
+			return Record{}, fmt.Errorf("%w: event date %q, expected YYYY-MM-DD", ErrInvalidRecord, l.Event.Date)
+		}
+		rec.Event = &models.Event{Meta: models.Meta{EventID: l.Event.EventID, UserID: l.Event.UserID, EventDate: date}, Data: models.Data{Text: l.Event.Text}}
+	}
+
+	if l.Attachment != nil {
+		payloads++
+		a := l.Attachment
+		rec.Attachment = &models.Attachment{AttachmentID: a.AttachmentID, EventID: a.EventID, UserID: a.UserID, Name: a.Name, ContentType: a.ContentType, Size: a.Size, CreatedAt: a.CreatedAt}
+	}
+
+	wantPayload := (l.Kind == kindSettings && rec.Settings != nil) || (l.Kind == kindEvent && rec.Event != nil) || (l.Kind == kindAttachment && rec.Attachment != nil)
+	if payloads != 1 || !wantPayload {
+		return Record{}, fmt.Errorf("%w: a %q record must hold only its %s", ErrInvalidRecord, l.Kind, l.Kind)
+	}
+
+	return rec, nil
+
+}
+
+// validator checks records one by one, remembering what it has seen to reject
+// duplicates and attachments of unknown events.
+type validator struct {
+	events   map[string]eventOwner // event ID -> tenant and user of the event
+	settings map[string]bool       // tenant/user -> settings seen
+}
+
+// eventOwner identifies the tenant and user an event belongs to.
+type eventOwner struct {
+	tenant string
+	userID int
+}
+
+// newValidator creates a validator that has seen no records.
+func newValidator() *validator {
+	return &validator{events: make(map[string]eventOwner), settings: make(map[string]bool)}
+}
+
+// check validates rec against the rules of the format and the records seen before it.
+func (v *validator) check(rec Record) error {
+
+	if !tenant.Valid(rec.Tenant) {
+		return fmt.Errorf("%w: tenant %q", ErrInvalidRecord, rec.Tenant)
+	}
+
+	switch {
+
+	case rec.Settings != nil && rec.Event == nil && rec.Attachment == nil:
+		return v.checkSettings(rec.Tenant, rec.Settings)
+
+	case rec.Event != nil && rec.Settings == nil && rec.Attachment == nil:
+		return v.checkEvent(rec.Tenant, rec.Event)
+
+	case rec.Attachment != nil && rec.Settings == nil && rec.Event == nil:
+		return v.checkAttachment(rec.Tenant, rec.Attachment)
+
+	default:
+		return fmt.Errorf("%w: a record holds exactly one of settings, event or attachment", ErrInvalidRecord)
+
+	}
+
+}
+
+// checkSettings validates the working calendar settings of a user.
+func (v *validator) checkSettings(tenantID string, settings *models.Settings) error {
+
+	if settings.UserID <= 0 {
+		return fmt.Errorf("%w: settings of invalid user ID %d", ErrInvalidRecord, settings.UserID)
+	}
+
+	for _, day := range settings.WorkingDays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("%w: settings of user %d: invalid working day %d", ErrInvalidRecord, settings.UserID, day)
+		}
+	}
+
+	if !slices.IsSorted(settings.WorkingDays) || len(slices.Compact(slices.Clone(settings.WorkingDays))) != len(settings.WorkingDays) {
+		return fmt.Errorf("%w: settings of user %d: working days must be ordered from Sunday without repeats", ErrInvalidRecord, settings.UserID)
+	}
+
+	key := fmt.Sprintf("%s/%d", tenantID, settings.UserID)
+	if v.settings[key] {
+		return fmt.Errorf("%w: settings of user %d repeated", ErrInvalidRecord, settings.UserID)
+	}
+	v.settings[key] = true
+
+	return nil
+
+}
+
+// checkEvent validates an event.
+func (v *validator) checkEvent(tenantID string, event *models.Event) error {
+
+	if _, err := uuid.Parse(event.Meta.EventID); err != nil {
+		return fmt.Errorf("%w: invalid event ID %q", ErrInvalidRecord, event.Meta.EventID)
+	}
+
+	if event.Meta.UserID <= 0 {
+		return fmt.Errorf("%w: event %s of invalid user ID %d", ErrInvalidRecord, event.Meta.EventID, event.Meta.UserID)
+	}
+
+	if len(event.Data.Text) > maxTextLength {
+		return fmt.Errorf("%w: event %s: text exceeds %d characters", ErrInvalidRecord, event.Meta.EventID, maxTextLength)
+	}
+
+	if _, seen := v.events[event.Meta.EventID]; seen {
+		return fmt.Errorf("%w: event %s repeated", ErrInvalidRecord, event.Meta.EventID)
+	}
+	v.events[event.Meta.EventID] = eventOwner{tenant: tenantID, userID: event.Meta.UserID}
+
+	return nil
+
+}
+
+// checkAttachment validates the metadata of an attachment, which must follow its event.
+func (v *validator) checkAttachment(tenantID string, attachment *models.Attachment) error {
+
+	if attachment.AttachmentID == "" || attachment.Name == "" || attachment.Size < 0 {
+		return fmt.Errorf("%w: attachment %q of event %s needs an ID, a name and a size", ErrInvalidRecord, attachment.AttachmentID, attachment.EventID)
+	}
+
+	owner, found := v.events[attachment.EventID]
+	if !found || owner.tenant != tenantID {
+		return fmt.Errorf("%w: attachment %s of unknown event %s", ErrInvalidRecord, attachment.AttachmentID, attachment.EventID)
+	}
+
+	if owner.userID != attachment.UserID {
+		return fmt.Errorf("%w: attachment %s of user %d on event %s of user %d", ErrInvalidRecord, attachment.AttachmentID, attachment.UserID, attachment.EventID, owner.userID)
+	}
+
+	return nil
+
+}
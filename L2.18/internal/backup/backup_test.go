@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"L2.18/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	eventA = "3383503d-fb71-4b8c-85bd-a914c84252a9"
+	eventB = "9c1d6a3e-2f4b-4e8a-9d7c-5b6a1e2f3d4c"
+)
+
+var createdAt = time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+
+// sample returns records covering every kind across two tenants.
+func sample() []Record {
+	return []Record{
+		{Tenant: "default", Settings: &models.Settings{UserID: 1, Country: "RU", WorkingDays: []time.Weekday{time.Monday, time.Tuesday}}},
+		{Tenant: "default", Event: &models.Event{Meta: models.Meta{EventID: eventA, UserID: 1, EventDate: time.Date(2030, 1, 8, 0, 0, 0, 0, time.UTC)}, Data: models.Data{Text: `it's "quoted"`}}},
+		{Tenant: "default", Attachment: &models.Attachment{AttachmentID: "a1", EventID: eventA, UserID: 1, Name: "agenda.pdf", ContentType: "application/pdf", Size: 42, CreatedAt: createdAt}},
+		{Tenant: "team-b", Event: &models.Event{Meta: models.Meta{EventID: eventB, UserID: 2, EventDate: time.Date(2030, 2, 28, 0, 0, 0, 0, time.UTC)}, Data: models.Data{Text: "<b>standup</b>"}}},
+	}
+}
+
+// encode writes records as a complete backup.
+func encode(t *testing.T, records []Record) string {
+
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, createdAt)
+	require.NoError(t, err)
+	for _, rec := range records {
+		require.NoError(t, w.Write(rec))
+	}
+	require.Equal(t, len(records), w.Records())
+	require.NoError(t, w.Close())
+
+	return buf.String()
+
+}
+
+// decode reads every record of a backup.
+func decode(input string) ([]Record, error) {
+
+	r, err := NewReader(strings.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+
+}
+
+func TestRoundTrip(t *testing.T) {
+
+	data := encode(t, sample())
+
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+	require.Len(t, lines, 6)
+	require.Equal(t, `{"format":"l2.18-backup","version":1,"created_at":"2030-01-07T12:00:00Z"}`, lines[0])
+	require.Contains(t, lines[2], `"date":"2030-01-08"`)
+	require.Contains(t, lines[4], `<b>standup</b>`)
+	require.Equal(t, `{"kind":"end","records":4}`, lines[5])
+
+	r, err := NewReader(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, Header{Format: Format, Version: Version, CreatedAt: createdAt}, r.Header())
+
+	records, err := decode(data)
+	require.NoError(t, err)
+	require.Equal(t, sample(), records)
+
+}
+
+func TestReader_Errors(t *testing.T) {
+
+	data := encode(t, sample())
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+
+	// replace returns the backup with line n (1-based) replaced.
+	replace := func(n int, with string) string {
+		changed := append([]string(nil), lines...)
+		changed[n-1] = with
+		return strings.Join(changed, "\n")
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		err   error
+		msg   string
+	}{
+		{"empty", "", ErrNotBackup, "empty input"},
+		{"not a backup", "hello\n", ErrNotBackup, "line 1"},
+		{"other format", `{"format":"other","version":1}`, ErrNotBackup, "line 1"},
+		{"newer version", `{"format":"l2.18-backup","version":2}`, ErrVersion, "reads versions up to 1"},
+		{"truncated", strings.Join(lines[:4], "\n"), ErrTruncated, "after line 4"},
+		{"wrong count", replace(6, `{"kind":"end","records":5}`), ErrRecordCount, "line 6"},
+		{"trailing data", data + lines[2] + "\n", ErrTrailingData, "line 7"},
+		{"unknown kind", replace(2, `{"kind":"user","tenant":"default"}`), ErrInvalidRecord, "line 2"},
+		{"unknown field", replace(2, `{"kind":"settings","tenant":"default","settings":{"user_id":1,"working_days":[],"extra":1}}`), ErrInvalidRecord, "line 2"},
+		{"missing payload", replace(2, `{"kind":"event","tenant":"default"}`), ErrInvalidRecord, "line 2"},
+		{"bad tenant", replace(2, `{"kind":"settings","tenant":"no tenant","settings":{"user_id":1,"working_days":[]}}`), ErrInvalidRecord, "line 2"},
+		{"bad working day", replace(2, `{"kind":"settings","tenant":"default","settings":{"user_id":1,"working_days":[7]}}`), ErrInvalidRecord, "line 2"},
+		{"unordered working days", replace(2, `{"kind":"settings","tenant":"default","settings":{"user_id":1,"working_days":[2,1]}}`), ErrInvalidRecord, "line 2"},
+		{"bad event ID", replace(3, `{"kind":"event","tenant":"default","event":{"event_id":"x","user_id":1,"date":"2030-01-08","text":""}}`), ErrInvalidRecord, "line 3"},
+		{"bad date", replace(3, `{"kind":"event","tenant":"default","event":{"event_id":"`+eventA+`","user_id":1,"date":"08.01.2030","text":""}}`), ErrInvalidRecord, "line 3"},
+		{"bad user", replace(3, `{"kind":"event","tenant":"default","event":{"event_id":"`+eventA+`","user_id":0,"date":"2030-01-08","text":""}}`), ErrInvalidRecord, "line 3"},
+		{"long text", replace(3, `{"kind":"event","tenant":"default","event":{"event_id":"`+eventA+`","user_id":1,"date":"2030-01-08","text":"`+strings.Repeat("x", 501)+`"}}`), ErrInvalidRecord, "line 3"},
+		{"repeated event", replace(5, lines[2]), ErrInvalidRecord, "line 5"},
+		{"orphan attachment", replace(4, strings.Replace(lines[3], eventA, eventB, 1)), ErrInvalidRecord, "line 4"},
+		{"attachment of another user", replace(4, strings.Replace(lines[3], `"user_id":1`, `"user_id":2`, 1)), ErrInvalidRecord, "line 4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decode(tt.input)
+			require.ErrorIs(t, err, tt.err)
+			require.Contains(t, err.Error(), tt.msg)
+		})
+	}
+
+}
+
+func TestReader_BlankLines(t *testing.T) {
+
+	records, err := decode("\n" + strings.ReplaceAll(encode(t, sample()), "\n", "\n\n"))
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+
+}
+
+func TestWriter_RejectsInvalidRecords(t *testing.T) {
+
+	w, err := NewWriter(io.Discard, createdAt)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, w.Write(Record{Tenant: "default"}), ErrInvalidRecord)
+	require.ErrorIs(t, w.Write(Record{Tenant: "default", Attachment: sample()[2].Attachment}), ErrInvalidRecord)
+	require.ErrorIs(t, w.Write(Record{Tenant: "default", Settings: sample()[0].Settings, Event: sample()[1].Event}), ErrInvalidRecord)
+	require.Zero(t, w.Records())
+
+}
+
+func TestSQLWriter(t *testing.T) {
+
+	var buf bytes.Buffer
+	w, err := NewSQLWriter(&buf, createdAt)
+	require.NoError(t, err)
+	for _, rec := range sample() {
+		require.NoError(t, w.Write(rec))
+	}
+	require.Equal(t, 4, w.Records())
+	require.NoError(t, w.Close())
+
+	script := buf.String()
+	require.True(t, strings.HasPrefix(script, "-- L2.18 calendar data, l2.18-backup format version 1, migrated at 2030-01-07T12:00:00Z\n\nBEGIN;"))
+	require.Contains(t, script, "CREATE TABLE IF NOT EXISTS events")
+	require.Contains(t, script, "VALUES ('default', 1, 'RU', '{1,2}');")
+	require.Contains(t, script, `VALUES ('`+eventA+`', 'default', 1, '2030-01-08', 'it''s "quoted"');`)
+	require.Contains(t, script, `VALUES ('`+eventA+`', 'a1', 1, 'default', 1, 'agenda.pdf', 'application/pdf', 42, '2030-01-07T12:00:00Z');`)
+	require.True(t, strings.HasSuffix(script, "\n-- 4 records\nCOMMIT;\n"))
+
+	require.ErrorIs(t, w.Write(Record{Tenant: "default", Event: sample()[1].Event}), ErrInvalidRecord)
+
+}
@@ -0,0 +1,177 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxLineSize is the longest line accepted, well above the largest valid record.
+const maxLineSize = 1 << 20
+
+// Reader reads and validates a backup record by record.
+type Reader struct {
+	scanner   *bufio.Scanner // splits the backup into lines
+	header    Header         // header of the backup
+	validator *validator     // rules every record must follow
+	line      int            // number of the last line read
+	records   int            // number of records read
+	done      bool           // whether the end line was read
+}
+
+// NewReader starts reading a backup from r by reading and checking its header.
+// Returns ErrNotBackup if r does not start with a backup header and ErrVersion
+// if the backup is of a newer version than this package supports.
+func NewReader(r io.Reader) (*Reader, error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	rd := &Reader{scanner: scanner, validator: newValidator()}
+
+	data, err := rd.next()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("backup: %w: empty input", ErrNotBackup)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &rd.header); err != nil || rd.header.Format != Format {
+		return nil, fmt.Errorf("backup: line %d: %w", rd.line, ErrNotBackup)
+	}
+
+	if rd.header.Version < 1 || rd.header.Version > Version {
+		return nil, fmt.Errorf("backup: line %d: %w %d, this build reads versions up to %d", rd.line, ErrVersion, rd.header.Version, Version)
+	}
+
+	return rd, nil
+
+}
+
+// Header returns the header of the backup.
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Read returns the next record. It returns io.EOF after the end line, once the
+// record count has been checked. Errors name the line of the offending record.
+func (r *Reader) Read() (Record, error) {
+
+	if r.done {
+		return Record{}, io.EOF
+	}
+
+	data, err := r.next()
+	if errors.Is(err, io.EOF) {
+		return Record{}, fmt.Errorf("backup: after line %d: %w", r.line, ErrTruncated)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	var l line
+	if err := strictUnmarshal(data, &l); err != nil {
+		return Record{}, fmt.Errorf("backup: line %d: %w: %v", r.line, ErrInvalidRecord, err)
+	}
+
+	if l.Kind == kindEnd {
+		return Record{}, r.end(l)
+	}
+
+	if l.Records != nil {
+		return Record{}, fmt.Errorf("backup: line %d: %w: only the end line counts records", r.line, ErrInvalidRecord)
+	}
+
+	rec, err := toRecord(l)
+	if err == nil {
+		err = r.validator.check(rec)
+	}
+	if err != nil {
+		return Record{}, fmt.Errorf("backup: line %d: %w", r.line, err)
+	}
+
+	r.records++
+
+	return rec, nil
+
+}
+
+// end checks the end line: its record count and that nothing follows it.
+func (r *Reader) end(l line) error {
+
+	if l.Records == nil || *l.Records != r.records {
+		return fmt.Errorf("backup: line %d: %w: read %d", r.line, ErrRecordCount, r.records)
+	}
+
+	if _, err := r.next(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("backup: line %d: %w", r.line, ErrTrailingData)
+	}
+
+	r.done = true
+
+	return io.EOF
+
+}
+
+// next returns the next non-blank line, or io.EOF at the end of the input.
+func (r *Reader) next() ([]byte, error) {
+
+	for r.scanner.Scan() {
+		r.line++
+		if data := bytes.TrimSpace(r.scanner.Bytes()); len(data) > 0 {
+			return data, nil
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("backup: after line %d: %w", r.line, err)
+	}
+
+	return nil, io.EOF
+
+}
+
+// strictUnmarshal decodes a single JSON value, rejecting unknown fields.
+func strictUnmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("more than one value on the line")
+	}
+	return nil
+}
+
+// Copy reads every record from r and writes it to sink, returning the number of records copied.
+func Copy(sink Sink, r *Reader) (int, error) {
+
+	copied := 0
+
+	for {
+
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return copied, nil
+		}
+		if err != nil {
+			return copied, err
+		}
+
+		if err := sink.Write(rec); err != nil {
+			return copied, err
+		}
+
+		copied++
+
+	}
+
+}
@@ -0,0 +1,131 @@
+package backup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schema creates the tables an SQL migration fills. Attachments keep their position
+// to preserve upload order; they are removed together with their event.
+const schema = `CREATE TABLE IF NOT EXISTS settings (
+    tenant       TEXT       NOT NULL,
+    user_id      INTEGER    NOT NULL,
+    country      TEXT       NOT NULL DEFAULT '',
+    working_days SMALLINT[] NOT NULL,
+    PRIMARY KEY (tenant, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS events (
+    event_id   UUID    PRIMARY KEY,
+    tenant     TEXT    NOT NULL,
+    user_id    INTEGER NOT NULL,
+    event_date DATE    NOT NULL,
+    text       TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS events_tenant_user_date ON events (tenant, user_id, event_date);
+
+CREATE TABLE IF NOT EXISTS attachments (
+    event_id      UUID        NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    attachment_id TEXT        NOT NULL,
+    position      INTEGER     NOT NULL,
+    tenant        TEXT        NOT NULL,
+    user_id       INTEGER     NOT NULL,
+    name          TEXT        NOT NULL,
+    content_type  TEXT        NOT NULL,
+    size          BIGINT      NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (event_id, attachment_id)
+);
+`
+
+// SQLWriter writes records as a PostgreSQL script that creates the calendar tables, if
+// missing, and inserts the records within a single transaction. Records are validated
+// like in a backup. Close must be called to commit the transaction.
+type SQLWriter struct {
+	buf       *bufio.Writer  // buffered destination
+	validator *validator     // rules every record must follow
+	positions map[string]int // event ID -> attachments written for the event
+	records   int            // number of records written
+}
+
+// NewSQLWriter starts an SQL script on w, noting createdAt as the time of the migration.
+func NewSQLWriter(w io.Writer, createdAt time.Time) (*SQLWriter, error) {
+
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintf(buf, "-- L2.18 calendar data, %s format version %d, migrated at %s\n\n", Format, Version, createdAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(buf, "BEGIN;\n\n%s\n", schema)
+
+	if err := buf.Flush(); err != nil {
+		return nil, fmt.Errorf("backup: write sql schema: %w", err)
+	}
+
+	return &SQLWriter{buf: buf, validator: newValidator(), positions: make(map[string]int)}, nil
+
+}
+
+// Write validates rec and appends the statement inserting it.
+func (w *SQLWriter) Write(rec Record) error {
+
+	if err := w.validator.check(rec); err != nil {
+		return fmt.Errorf("backup: record %d: %w", w.records+1, err)
+	}
+
+	switch {
+
+	case rec.Settings != nil:
+		s := rec.Settings
+		days := make([]string, len(s.WorkingDays))
+		for i, day := range s.WorkingDays {
+			days[i] = strconv.Itoa(int(day))
+		}
+		fmt.Fprintf(w.buf, "INSERT INTO settings (tenant, user_id, country, working_days) VALUES (%s, %d, %s, '{%s}');\n",
+			quote(rec.Tenant), s.UserID, quote(s.Country), strings.Join(days, ","))
+
+	case rec.Event != nil:
+		e := rec.Event
+		fmt.Fprintf(w.buf, "INSERT INTO events (event_id, tenant, user_id, event_date, text) VALUES (%s, %s, %d, %s, %s);\n",
+			quote(e.Meta.EventID), quote(rec.Tenant), e.Meta.UserID, quote(e.Meta.EventDate.Format(dateLayout)), quote(e.Data.Text))
+
+	default:
+		a := rec.Attachment
+		w.positions[a.EventID]++
+		fmt.Fprintf(w.buf, "INSERT INTO attachments (event_id, attachment_id, position, tenant, user_id, name, content_type, size, created_at) VALUES (%s, %s, %d, %s, %d, %s, %s, %d, %s);\n",
+			quote(a.EventID), quote(a.AttachmentID), w.positions[a.EventID], quote(rec.Tenant), a.UserID, quote(a.Name), quote(a.ContentType), a.Size, quote(a.CreatedAt.UTC().Format(time.RFC3339Nano)))
+
+	}
+
+	w.records++
+
+	return nil
+
+}
+
+// Records returns the number of records written so far.
+func (w *SQLWriter) Records() int {
+	return w.records
+}
+
+// Close commits the transaction at the end of the script and flushes buffered data.
+// It does not close the underlying writer.
+func (w *SQLWriter) Close() error {
+
+	fmt.Fprintf(w.buf, "\n-- %d records\nCOMMIT;\n", w.records)
+
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("backup: flush sql: %w", err)
+	}
+
+	return nil
+
+}
+
+// quote returns s as an SQL string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
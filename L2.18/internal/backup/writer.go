@@ -0,0 +1,72 @@
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Writer writes a backup. Records are validated as they are written, so a backup that
+// could not be read back is never produced. Close must be called to complete it.
+type Writer struct {
+	buf       *bufio.Writer // buffered destination
+	enc       *json.Encoder // encodes a line per value
+	validator *validator    // rules every record must follow
+	records   int           // number of records written
+}
+
+// NewWriter starts a backup on w by writing its header, stamped with createdAt.
+func NewWriter(w io.Writer, createdAt time.Time) (*Writer, error) {
+
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(Header{Format: Format, Version: Version, CreatedAt: createdAt.UTC()}); err != nil {
+		return nil, fmt.Errorf("backup: write header: %w", err)
+	}
+
+	return &Writer{buf: buf, enc: enc, validator: newValidator()}, nil
+
+}
+
+// Write validates rec and appends it to the backup.
+func (w *Writer) Write(rec Record) error {
+
+	if err := w.validator.check(rec); err != nil {
+		return fmt.Errorf("backup: record %d: %w", w.records+1, err)
+	}
+
+	if err := w.enc.Encode(toLine(rec)); err != nil {
+		return fmt.Errorf("backup: write record %d: %w", w.records+1, err)
+	}
+
+	w.records++
+
+	return nil
+
+}
+
+// Records returns the number of records written so far.
+func (w *Writer) Records() int {
+	return w.records
+}
+
+// Close completes the backup by writing the end line and flushing buffered data.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+
+	records := w.records
+	if err := w.enc.Encode(line{Kind: kindEnd, Records: &records}); err != nil {
+		return fmt.Errorf("backup: write end: %w", err)
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("backup: flush: %w", err)
+	}
+
+	return nil
+
+}
@@ -0,0 +1,100 @@
+package calctl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"L2.18/internal/handler/admin"
+)
+
+// admin runs the "admin" subcommands.
+func (s *session) admin(ctx context.Context, command string, args []string) error {
+
+	var opts options
+
+	flags := s.flagSet("admin "+command, &opts)
+	if err := s.parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	switch command {
+
+	case "users":
+		return s.adminUsers(ctx)
+
+	case "stats":
+		return s.adminStats(ctx)
+
+	case "purge":
+		if opts.userID <= 0 {
+			return usageErrorf("admin purge requires --user")
+		}
+		deleted, err := s.client.DeleteUserEvents(ctx, opts.userID)
+		if err != nil {
+			return err
+		}
+		return s.result(admin.DeleteUserEventsResponse{Deleted: deleted}, fmt.Sprintf("deleted %d events of user %d", deleted, opts.userID))
+
+	case "snapshot":
+		if err := s.client.Snapshot(ctx); err != nil {
+			return err
+		}
+		return s.result(admin.SnapshotResponse{Snapshot: true}, "storage snapshot taken")
+
+	case "compact":
+		if err := s.client.Compact(ctx); err != nil {
+			return err
+		}
+		return s.result(admin.CompactResponse{Compacted: true}, "storage compacted")
+
+	default:
+		return usageErrorf("unknown admin command %q, expected users, stats, purge, snapshot or compact", command)
+
+	}
+
+}
+
+// adminUsers prints the users with their event counts.
+func (s *session) adminUsers(ctx context.Context) error {
+
+	users, err := s.client.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	if s.settings.Output == formatJSON {
+		return writeJSON(s.stdout, admin.UsersResponse{Users: users})
+	}
+
+	rows := make([][]string, len(users))
+	for i, user := range users {
+		rows[i] = []string{strconv.Itoa(user.UserID), strconv.Itoa(user.Events)}
+	}
+
+	return writeTable(s.stdout, []string{"USER ID", "EVENTS"}, rows)
+
+}
+
+// adminStats prints the storage statistics followed by the events per day histogram.
+func (s *session) adminStats(ctx context.Context) error {
+
+	stats, err := s.client.Stats(ctx)
+	if err != nil {
+		return err
+	}
+
+	if s.settings.Output == formatJSON {
+		return writeJSON(s.stdout, stats)
+	}
+
+	fmt.Fprintf(s.stdout, "users: %d\nevents: %d\nupcoming: %d\nsize: %d bytes\n\n", stats.Users, stats.Events, stats.Upcoming, stats.SizeBytes)
+
+	rows := make([][]string, len(stats.EventsPerDay))
+	for i, day := range stats.EventsPerDay {
+		rows[i] = []string{day.Date, strconv.Itoa(day.Events)}
+	}
+
+	return writeTable(s.stdout, []string{"DATE", "EVENTS"}, rows)
+
+}
@@ -0,0 +1,461 @@
+// Package calctl implements calctl, the command-line client of the calendar API.
+//
+// Every API endpoint is available as a subcommand. Defaults such as the server URL,
+// user ID, output format and admin token are read from a YAML config file and can be
+// overridden with flags. Agendas are printed as tables, JSON or iCalendar, and API
+// errors are turned into friendly messages and distinct exit codes.
+package calctl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "L2.18/internal/handler/v1"
+	"L2.18/internal/models"
+)
+
+// Exit codes returned by Run.
+const (
+	exitOK           = 0 // command succeeded
+	exitFailure      = 1 // unexpected failure, e.g. the service is unreachable
+	exitUsage        = 2 // invalid command line
+	exitInvalid      = 3 // the API rejected the request as invalid (400)
+	exitRefused      = 4 // the API refused the request (404, 429, 501, 503)
+	exitUnauthorized = 5 // the admin token is missing or wrong (401)
+	exitServer       = 6 // the API failed internally (500)
+)
+
+// usage is printed by "calctl help" and on invalid invocations.
+const usage = `calctl — command-line client for the calendar API
+
+Usage:
+  calctl <command> [flags]
+
+Events:
+  create  --date YYYY-MM-DD [--text TEXT]             create an event
+  update  --id ID [--text TEXT] [--new-date DATE]     change an event
+  delete  --id ID                                     delete an event
+  day     [--date YYYY-MM-DD]                         agenda for a day (default today)
+  week    [--date YYYY-MM-DD]                         agenda for the ISO week of a date
+  month   [--date YYYY-MM-DD]                         agenda for the month of a date
+          (day, week and month accept --holidays to list the holidays of the period)
+  settings [--country CODE] [--working-days mon,...]  show or change the working calendar
+
+Administration (requires admin_token):
+  admin users                                         list users with event counts
+  admin stats                                         storage statistics
+  admin purge --user ID                               delete all events of a user
+  admin snapshot                                      force a storage snapshot
+  admin compact                                       force a storage compaction
+
+Common flags:
+  --config PATH     config file (default $CALCTL_CONFIG or <user config dir>/calctl/config.yaml)
+  --server URL      calendar service URL
+  --output FORMAT   table, json or ical (ical applies to agendas only)
+  --user ID         user ID for event commands
+  --token TOKEN     admin token for admin commands
+  --tenant ID       tenant to work in, when the server is multi-tenant
+
+Exit codes:
+  0 success, 1 unexpected failure, 2 invalid usage, 3 invalid request,
+  4 request refused, 5 not authorized, 6 server error
+`
+
+// usageError is an invalid command line detected by calctl itself.
+type usageError struct {
+	msg string
+}
+
+// Error returns the usage error message.
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usageErrorf creates a usageError with a formatted message.
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// options holds the flags shared by all commands.
+type options struct {
+	config string // path to the config file
+	server string // service URL override
+	output string // output format override
+	token  string // admin token override
+	tenant string // tenant override
+	userID int    // user ID override
+}
+
+// session carries the state of a single calctl invocation.
+type session struct {
+	stdout   io.Writer        // destination of command output
+	stderr   io.Writer        // destination of errors and usage
+	settings Settings         // effective settings after flag overrides
+	client   *Client          // API client built from settings
+	now      func() time.Time // clock used for default dates and iCal stamps
+}
+
+// Run executes calctl with the given arguments and returns the process exit code.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+
+	s := &session{stdout: stdout, stderr: stderr, now: time.Now}
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	err := s.dispatch(ctx, args[0], args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	fmt.Fprintln(stderr, "calctl:", err)
+
+	return exitCode(err)
+
+}
+
+// dispatch runs the named command.
+func (s *session) dispatch(ctx context.Context, command string, args []string) error {
+
+	switch command {
+
+	case "help", "-h", "--help":
+		fmt.Fprint(s.stdout, usage)
+		return nil
+
+	case "create":
+		return s.create(ctx, args)
+
+	case "update":
+		return s.update(ctx, args)
+
+	case "delete":
+		return s.delete(ctx, args)
+
+	case "day", "week", "month":
+		return s.agenda(ctx, models.Period(command), args)
+
+	case "settings":
+		return s.userSettings(ctx, args)
+
+	case "admin":
+		if len(args) == 0 {
+			return usageErrorf("admin requires a subcommand: users, stats, purge, snapshot or compact")
+		}
+		return s.admin(ctx, args[0], args[1:])
+
+	default:
+		return usageErrorf("unknown command %q, run 'calctl help' for usage", command)
+
+	}
+
+}
+
+// flagSet creates a flag set for a command with the common flags registered.
+func (s *session) flagSet(name string, opts *options) *flag.FlagSet {
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(s.stderr)
+
+	flags.StringVar(&opts.config, "config", "", "config file")
+	flags.StringVar(&opts.server, "server", "", "calendar service URL")
+	flags.StringVar(&opts.output, "output", "", "output format: table, json or ical")
+	flags.StringVar(&opts.token, "token", "", "admin token")
+	flags.StringVar(&opts.tenant, "tenant", "", "tenant ID")
+	flags.IntVar(&opts.userID, "user", 0, "user ID")
+
+	return flags
+
+}
+
+// parse parses the command flags, loads the settings, applies the flag overrides
+// and prepares the API client.
+func (s *session) parse(flags *flag.FlagSet, opts *options, args []string) error {
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+
+	if flags.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	settings, err := LoadSettings(opts.config)
+	if err != nil {
+		return err
+	}
+
+	if opts.server != "" {
+		settings.Server = opts.server
+	}
+	if opts.output != "" {
+		settings.Output = opts.output
+	}
+	if opts.token != "" {
+		settings.AdminToken = opts.token
+	}
+	if opts.tenant != "" {
+		settings.Tenant = opts.tenant
+	}
+	if opts.userID != 0 {
+		settings.UserID = opts.userID
+	}
+
+	if !validFormat(settings.Output) {
+		return usageErrorf("unknown output format %q, expected table, json or ical", settings.Output)
+	}
+
+	s.settings = settings
+	s.client = NewClient(settings)
+
+	return nil
+
+}
+
+// userID returns the effective user ID or a usage error if none is configured.
+func (s *session) userID() (int, error) {
+	if s.settings.UserID <= 0 {
+		return 0, usageErrorf("user ID required: pass --user or set user_id in the config file")
+	}
+	return s.settings.UserID, nil
+}
+
+// create runs the "create" command.
+func (s *session) create(ctx context.Context, args []string) error {
+
+	var opts options
+	var date, text string
+
+	flags := s.flagSet("create", &opts)
+	flags.StringVar(&date, "date", "", "event date (YYYY-MM-DD)")
+	flags.StringVar(&text, "text", "", "event text")
+
+	if err := s.parse(flags, &opts, args); err != nil {
+		return err
+	}
+	if date == "" {
+		return usageErrorf("create requires --date")
+	}
+
+	userID, err := s.userID()
+	if err != nil {
+		return err
+	}
+
+	response, err := s.client.CreateEvent(ctx, v1.CreateRequestV1{UserID: userID, EventDate: date, Text: text})
+	if err != nil {
+		return err
+	}
+
+	return s.result(response, withWarning("event created: "+response.EventID, response.Warning))
+
+}
+
+// update runs the "update" command.
+func (s *session) update(ctx context.Context, args []string) error {
+
+	var opts options
+	var eventID, text, newDate string
+
+	flags := s.flagSet("update", &opts)
+	flags.StringVar(&eventID, "id", "", "event ID")
+	flags.StringVar(&text, "text", "", "new event text")
+	flags.StringVar(&newDate, "new-date", "", "new event date (YYYY-MM-DD)")
+
+	if err := s.parse(flags, &opts, args); err != nil {
+		return err
+	}
+	if eventID == "" {
+		return usageErrorf("update requires --id")
+	}
+	if text == "" && newDate == "" {
+		return usageErrorf("update requires --text and/or --new-date")
+	}
+
+	userID, err := s.userID()
+	if err != nil {
+		return err
+	}
+
+	response, err := s.client.UpdateEvent(ctx, v1.UpdateRequestV1{UserID: userID, EventID: eventID, Text: text, NewDate: newDate})
+	if err != nil {
+		return err
+	}
+
+	return s.result(response, withWarning("event updated: "+eventID, response.Warning))
+
+}
+
+// delete runs the "delete" command.
+func (s *session) delete(ctx context.Context, args []string) error {
+
+	var opts options
+	var eventID string
+
+	flags := s.flagSet("delete", &opts)
+	flags.StringVar(&eventID, "id", "", "event ID")
+
+	if err := s.parse(flags, &opts, args); err != nil {
+		return err
+	}
+	if eventID == "" {
+		return usageErrorf("delete requires --id")
+	}
+
+	userID, err := s.userID()
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.DeleteEvent(ctx, v1.DeleteRequestV1{UserID: userID, EventID: eventID}); err != nil {
+		return err
+	}
+
+	return s.result(v1.DeleteResponseV1{Deleted: true}, "event deleted: "+eventID)
+
+}
+
+// agenda runs the "day", "week" and "month" commands.
+func (s *session) agenda(ctx context.Context, period models.Period, args []string) error {
+
+	var opts options
+	var date string
+	var withHolidays bool
+
+	flags := s.flagSet(string(period), &opts)
+	flags.StringVar(&date, "date", "", "date within the period (YYYY-MM-DD, default today)")
+	flags.BoolVar(&withHolidays, "holidays", false, "also list the holidays of the period")
+
+	if err := s.parse(flags, &opts, args); err != nil {
+		return err
+	}
+	if date == "" {
+		date = s.now().Format("2006-01-02")
+	}
+
+	userID, err := s.userID()
+	if err != nil {
+		return err
+	}
+
+	agenda, err := s.client.Events(ctx, period, userID, date, withHolidays)
+	if err != nil {
+		return err
+	}
+
+	return writeAgenda(s.stdout, s.settings.Output, agenda, s.now())
+
+}
+
+// userSettings runs the "settings" command: it shows the user's working calendar,
+// or changes it when --country or --working-days is given.
+func (s *session) userSettings(ctx context.Context, args []string) error {
+
+	var opts options
+	var country, workingDays string
+
+	flags := s.flagSet("settings", &opts)
+	flags.StringVar(&country, "country", "", "holiday calendar country code, \"none\" to clear")
+	flags.StringVar(&workingDays, "working-days", "", "comma-separated working days, e.g. mon,tue,wed,thu,fri")
+
+	if err := s.parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	userID, err := s.userID()
+	if err != nil {
+		return err
+	}
+
+	current, err := s.client.Settings(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if country == "" && workingDays == "" {
+		if s.settings.Output == formatJSON {
+			return writeJSON(s.stdout, current)
+		}
+		return writeTable(s.stdout, []string{"USER ID", "COUNTRY", "WORKING DAYS"}, [][]string{
+			{strconv.Itoa(current.UserID), current.Country, strings.Join(current.WorkingDays, ",")},
+		})
+	}
+
+	request := v1.UpdateSettingsRequestV1{UserID: userID, Country: current.Country, WorkingDays: current.WorkingDays}
+	if country == "none" {
+		request.Country = ""
+	} else if country != "" {
+		request.Country = country
+	}
+	if workingDays != "" {
+		request.WorkingDays = strings.Split(workingDays, ",")
+	}
+
+	if err := s.client.UpdateSettings(ctx, request); err != nil {
+		return err
+	}
+
+	return s.result(v1.UpdateSettingsResponseV1{Updated: true}, "settings updated")
+
+}
+
+// result prints the outcome of a command that does not return an agenda:
+// the API result as JSON, or a one-line message otherwise.
+func (s *session) result(response any, message string) error {
+	if s.settings.Output == formatJSON {
+		return writeJSON(s.stdout, response)
+	}
+	_, err := fmt.Fprintln(s.stdout, message)
+	return err
+}
+
+// withWarning appends a server warning to a result message on its own line.
+func withWarning(message, warning string) string {
+	if warning == "" {
+		return message
+	}
+	return message + "\nwarning: " + warning
+}
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return exitFailure
+	}
+
+	switch apiErr.Status {
+
+	case http.StatusBadRequest:
+		return exitInvalid
+
+	case http.StatusUnauthorized:
+		return exitUnauthorized
+
+	case http.StatusNotFound, http.StatusTooManyRequests, http.StatusNotImplemented, http.StatusServiceUnavailable:
+		return exitRefused
+
+	default:
+		return exitServer
+
+	}
+
+}
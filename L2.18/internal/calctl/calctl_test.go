@@ -0,0 +1,226 @@
+package calctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// run executes calctl against server with a config file pointing at it.
+func run(t *testing.T, server *httptest.Server, args ...string) (int, string, string) {
+
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server: "+server.URL+"\nuser_id: 7\nadmin_token: secret\n"), 0o600))
+	t.Setenv("CALCTL_CONFIG", path)
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+
+}
+
+// respond creates a test server answering every request with status and body.
+func respond(t *testing.T, status int, body string, check func(r *http.Request)) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			check(r)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+const agendaBody = `{"result":{"events":[{"text":"Touch grass","date":"2028-12-04","event_id":"3383503d-fb71-4b8c-85bd-a914c84252a9"}]}}`
+
+func TestRun_AgendaFormats(t *testing.T) {
+
+	server := respond(t, http.StatusOK, agendaBody, func(r *http.Request) {
+		require.Equal(t, "/api/v1/events_for_week", r.URL.Path)
+		require.Equal(t, "7", r.URL.Query().Get("user_id"))
+		require.Equal(t, "2028-12-04", r.URL.Query().Get("date"))
+	})
+
+	code, stdout, _ := run(t, server, "week", "--date", "2028-12-04")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "DATE")
+	require.Contains(t, stdout, "Touch grass")
+
+	code, stdout, _ = run(t, server, "week", "--date", "2028-12-04", "--output", "json")
+	require.Equal(t, exitOK, code)
+	var decoded map[string][]map[string]string
+	require.NoError(t, json.Unmarshal([]byte(stdout), &decoded))
+	require.Equal(t, "Touch grass", decoded["events"][0]["text"])
+
+	code, stdout, _ = run(t, server, "week", "--date", "2028-12-04", "--output", "ical")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "BEGIN:VCALENDAR\r\n")
+	require.Contains(t, stdout, "DTSTART;VALUE=DATE:20281204\r\n")
+	require.Contains(t, stdout, "DTEND;VALUE=DATE:20281205\r\n")
+	require.Contains(t, stdout, "SUMMARY:Touch grass\r\n")
+
+}
+
+func TestRun_CreateSendsDefaults(t *testing.T) {
+
+	server := respond(t, http.StatusOK, `{"result":{"event_id":"abc"}}`, func(r *http.Request) {
+		require.Equal(t, "/api/v1/create_event", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, float64(7), body["user_id"])
+		require.Equal(t, "2028-12-04", body["date"])
+	})
+
+	code, stdout, _ := run(t, server, "create", "--date", "2028-12-04", "--text", "Touch grass")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "event created: abc\n", stdout)
+
+}
+
+func TestRun_SettingsUpdateKeepsCurrentValues(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/settings":
+			_, _ = w.Write([]byte(`{"result":{"user_id":7,"country":"RU","working_days":["mon","tue"]}}`))
+		case "/api/v1/update_settings":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "RU", body["country"])
+			require.Equal(t, []any{"sun", "mon"}, body["working_days"])
+			_, _ = w.Write([]byte(`{"result":{"settings_updated":true}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	code, stdout, _ := run(t, server, "settings", "--working-days", "sun,mon")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "settings updated\n", stdout)
+
+}
+
+func TestRun_AgendaHolidays(t *testing.T) {
+
+	server := respond(t, http.StatusOK, `{"result":{"events":[{"text":"Dinner","date":"2030-12-25","event_id":"x","holiday":"Christmas Day","non_working_day":true}],"holidays":[{"date":"2030-12-25","name":"Christmas Day"}]}}`, func(r *http.Request) {
+		require.Equal(t, "true", r.URL.Query().Get("holidays"))
+	})
+
+	code, stdout, _ := run(t, server, "month", "--date", "2030-12-01", "--holidays")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "NOTE")
+	require.Contains(t, stdout, "HOLIDAY")
+	require.Equal(t, 2, strings.Count(stdout, "Christmas Day"))
+
+}
+
+func TestRun_AdminSendsToken(t *testing.T) {
+
+	server := respond(t, http.StatusOK, `{"result":{"users":[{"user_id":1,"events":2}]}}`, func(r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+	})
+
+	code, stdout, _ := run(t, server, "admin", "users")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "USER ID")
+
+}
+
+func TestRun_SendsTenant(t *testing.T) {
+
+	server := respond(t, http.StatusOK, agendaBody, func(r *http.Request) {
+		require.Equal(t, "team-a", r.Header.Get("X-Tenant-ID"))
+	})
+
+	code, _, _ := run(t, server, "day", "--date", "2028-12-04", "--tenant", "team-a")
+	require.Equal(t, exitOK, code)
+
+}
+
+func TestRun_ErrorExitCodes(t *testing.T) {
+
+	cases := []struct {
+		status  int
+		body    string
+		code    int
+		message string
+	}{
+		{http.StatusBadRequest, `{"error":"invalid date format, expected YYYY-MM-DD"}`, exitInvalid, "calctl: invalid request: invalid date format, expected YYYY-MM-DD\n"},
+		{http.StatusServiceUnavailable, `{"error":"maximum number of events reached"}`, exitRefused, "calctl: request refused: maximum number of events reached\n"},
+		{http.StatusUnauthorized, `{"error":"unauthorized: missing or invalid admin token"}`, exitUnauthorized, "calctl: not authorized"},
+		{http.StatusInternalServerError, `{"error":"internal server error"}`, exitServer, "calctl: server error (500): internal server error, try again later\n"},
+	}
+
+	for _, tc := range cases {
+		code, _, stderr := run(t, respond(t, tc.status, tc.body, nil), "day")
+		require.Equal(t, tc.code, code)
+		require.True(t, strings.HasPrefix(stderr, tc.message), stderr)
+	}
+
+}
+
+func TestRun_UsageAndConnectionErrors(t *testing.T) {
+
+	server := respond(t, http.StatusOK, agendaBody, nil)
+
+	code, _, stderr := run(t, server, "bogus")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "unknown command")
+
+	code, _, _ = run(t, server, "day", "--output", "xml")
+	require.Equal(t, exitUsage, code)
+
+	code, _, _ = run(t, server, "create")
+	require.Equal(t, exitUsage, code)
+
+	server.Close()
+	code, _, stderr = run(t, server, "day")
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "cannot reach the calendar service")
+
+}
+
+func TestLoadSettings_Defaults(t *testing.T) {
+
+	settings, err := LoadSettings(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	require.Zero(t, settings)
+
+	t.Setenv("CALCTL_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	settings, err = LoadSettings("")
+	require.NoError(t, err)
+	require.Equal(t, Settings{Server: "http://localhost:8080", Output: "table", TenantHeader: "X-Tenant-ID", Timeout: 10 * time.Second}, settings)
+
+}
+
+func TestFoldICal(t *testing.T) {
+
+	line := "SUMMARY:" + strings.Repeat("я", 60)
+	folded := foldICal(line)
+
+	for _, part := range strings.Split(folded, "\r\n") {
+		require.LessOrEqual(t, len(part), icalLineLimit)
+	}
+	require.Equal(t, line, strings.ReplaceAll(folded, "\r\n ", ""))
+
+	require.Equal(t, `a\, b\; c\\d\ne`, escapeICal("a, b; c\\d\ne"))
+
+}
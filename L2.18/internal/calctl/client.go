@@ -0,0 +1,211 @@
+package calctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"L2.18/internal/handler/admin"
+	v1 "L2.18/internal/handler/v1"
+	"L2.18/internal/models"
+)
+
+// Client is an HTTP client of the calendar API.
+//
+// It speaks the same request and response types as the server handlers, so the
+// client and the API cannot drift apart silently.
+type Client struct {
+	baseURL      string       // base URL of the calendar service, without a trailing slash
+	token        string       // admin bearer token, sent with admin requests only
+	tenant       string       // tenant sent with every request, if set
+	tenantHeader string       // request header carrying the tenant
+	http         *http.Client // underlying HTTP client
+}
+
+// APIError is an error response returned by the calendar API.
+type APIError struct {
+	Status  int    // HTTP status code
+	Message string // error message from the response body
+}
+
+// Error returns a human-friendly description of the API error.
+func (e *APIError) Error() string {
+
+	switch e.Status {
+
+	case http.StatusBadRequest:
+		return "invalid request: " + e.Message
+
+	case http.StatusUnauthorized:
+		return "not authorized: " + e.Message + " (check admin_token in the config file)"
+
+	case http.StatusNotFound:
+		return "endpoint not found (is the admin API enabled on the server?)"
+
+	case http.StatusTooManyRequests:
+		return "too many requests, slow down and try again later"
+
+	case http.StatusNotImplemented:
+		return "not supported: " + e.Message
+
+	case http.StatusServiceUnavailable:
+		return "request refused: " + e.Message
+
+	default:
+		return fmt.Sprintf("server error (%d): %s, try again later", e.Status, e.Message)
+
+	}
+
+}
+
+// NewClient creates a new Client for the service described by the settings.
+func NewClient(settings Settings) *Client {
+	return &Client{
+		baseURL:      strings.TrimRight(settings.Server, "/"),
+		token:        settings.AdminToken,
+		tenant:       settings.Tenant,
+		tenantHeader: settings.TenantHeader,
+		http:         &http.Client{Timeout: settings.Timeout},
+	}
+}
+
+// CreateEvent creates an event and returns its ID, with a warning if the day is not a working one.
+func (c *Client) CreateEvent(ctx context.Context, request v1.CreateRequestV1) (v1.CreateResponseV1, error) {
+	var response v1.CreateResponseV1
+	err := c.do(ctx, http.MethodPost, "/api/v1/create_event", nil, request, &response)
+	return response, err
+}
+
+// UpdateEvent changes the text and/or date of an event.
+func (c *Client) UpdateEvent(ctx context.Context, request v1.UpdateRequestV1) (v1.UpdateResponseV1, error) {
+	var response v1.UpdateResponseV1
+	err := c.do(ctx, http.MethodPost, "/api/v1/update_event", nil, request, &response)
+	return response, err
+}
+
+// DeleteEvent deletes an event.
+func (c *Client) DeleteEvent(ctx context.Context, request v1.DeleteRequestV1) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/delete_event", nil, request, &v1.DeleteResponseV1{})
+}
+
+// Events returns the events of a user for the day, week or month containing date (YYYY-MM-DD),
+// together with the holidays of the period if withHolidays is set.
+func (c *Client) Events(ctx context.Context, period models.Period, userID int, date string, withHolidays bool) (v1.ListOfEventsResponseV1, error) {
+	var response v1.ListOfEventsResponseV1
+	query := url.Values{"user_id": {strconv.Itoa(userID)}, "date": {date}}
+	if withHolidays {
+		query.Set("holidays", "true")
+	}
+	err := c.do(ctx, http.MethodGet, "/api/v1/events_for_"+string(period), query, nil, &response)
+	return response, err
+}
+
+// Settings returns the effective working calendar settings of a user.
+func (c *Client) Settings(ctx context.Context, userID int) (v1.SettingsResponseV1, error) {
+	var response v1.SettingsResponseV1
+	err := c.do(ctx, http.MethodGet, "/api/v1/settings", url.Values{"user_id": {strconv.Itoa(userID)}}, nil, &response)
+	return response, err
+}
+
+// UpdateSettings changes the working calendar settings of a user.
+func (c *Client) UpdateSettings(ctx context.Context, request v1.UpdateSettingsRequestV1) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/update_settings", nil, request, &v1.UpdateSettingsResponseV1{})
+}
+
+// ListUsers returns every user owning events with their event counts.
+func (c *Client) ListUsers(ctx context.Context) ([]admin.UserDto, error) {
+	var response admin.UsersResponse
+	err := c.do(ctx, http.MethodGet, "/admin/users", nil, nil, &response)
+	return response.Users, err
+}
+
+// DeleteUserEvents deletes all events of a user and returns how many were removed.
+func (c *Client) DeleteUserEvents(ctx context.Context, userID int) (int, error) {
+	var response admin.DeleteUserEventsResponse
+	err := c.do(ctx, http.MethodPost, "/admin/delete_user_events", nil, admin.DeleteUserEventsRequest{UserID: userID}, &response)
+	return response.Deleted, err
+}
+
+// Stats returns global storage statistics.
+func (c *Client) Stats(ctx context.Context) (admin.StatsResponse, error) {
+	var response admin.StatsResponse
+	err := c.do(ctx, http.MethodGet, "/admin/stats", nil, nil, &response)
+	return response, err
+}
+
+// Snapshot forces a storage snapshot.
+func (c *Client) Snapshot(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/snapshot", nil, nil, &admin.SnapshotResponse{})
+}
+
+// Compact forces a storage compaction.
+func (c *Client) Compact(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/compact", nil, nil, &admin.CompactResponse{})
+}
+
+// do sends a request to the API and decodes the "result" field of a successful
+// response into result. Error responses are returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, result any) error {
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, target, payload)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" && strings.HasPrefix(path, "/admin/") {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.tenant != "" {
+		request.Header.Set(c.tenantHeader, c.tenant)
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("cannot reach the calendar service at %s: %w", c.baseURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&failure); err != nil || failure.Error == "" {
+			failure.Error = http.StatusText(response.StatusCode)
+		}
+		return &APIError{Status: response.StatusCode, Message: failure.Error}
+	}
+
+	envelope := struct {
+		Result any `json:"result"`
+	}{Result: result}
+
+	if err := json.NewDecoder(response.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("unexpected response from %s: %w", path, err)
+	}
+
+	return nil
+
+}
@@ -0,0 +1,177 @@
+package calctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	v1 "L2.18/internal/handler/v1"
+)
+
+// Output formats supported by the --output flag.
+const (
+	formatTable = "table" // aligned human-readable columns
+	formatJSON  = "json"  // indented JSON of the API result
+	formatICal  = "ical"  // iCalendar (RFC 5545), agendas only
+)
+
+// icalLineLimit is the maximum length of an iCalendar content line in octets.
+const icalLineLimit = 75
+
+// validFormat reports whether format is a supported output format.
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatICal
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeTable writes a header and rows as tab-aligned columns.
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+
+}
+
+// writeAgenda writes a list of events, and the holidays of the period if any, in the
+// requested format.
+func writeAgenda(w io.Writer, format string, agenda v1.ListOfEventsResponseV1, now time.Time) error {
+
+	switch format {
+
+	case formatJSON:
+		return writeJSON(w, agenda)
+
+	case formatICal:
+		return writeICal(w, agenda.Events, now)
+
+	default:
+
+		if len(agenda.Events) == 0 {
+			fmt.Fprintln(w, "no events")
+		} else {
+			rows := make([][]string, len(agenda.Events))
+			for i, event := range agenda.Events {
+				rows[i] = []string{event.EventDate, event.EventID, event.Text, note(event)}
+			}
+			if err := writeTable(w, []string{"DATE", "EVENT ID", "TEXT", "NOTE"}, rows); err != nil {
+				return err
+			}
+		}
+
+		if len(agenda.Holidays) == 0 {
+			return nil
+		}
+
+		fmt.Fprintln(w)
+
+		rows := make([][]string, len(agenda.Holidays))
+		for i, holiday := range agenda.Holidays {
+			rows[i] = []string{holiday.Date, holiday.Name}
+		}
+
+		return writeTable(w, []string{"HOLIDAY", "NAME"}, rows)
+
+	}
+
+}
+
+// note describes why an event's day is not a working day, if it is not.
+func note(event v1.EventDtoV1) string {
+	switch {
+	case event.Holiday != "":
+		return event.Holiday
+	case event.NonWorkingDay:
+		return "non-working day"
+	default:
+		return ""
+	}
+}
+
+// writeICal writes events as an iCalendar stream of all-day VEVENTs.
+// now is used as the DTSTAMP of every event.
+func writeICal(w io.Writer, events []v1.EventDtoV1, now time.Time) error {
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//L2.18//calctl//EN",
+		"CALSCALE:GREGORIAN",
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	for _, event := range events {
+
+		date, err := time.Parse("2006-01-02", event.EventDate)
+		if err != nil {
+			return fmt.Errorf("event %s has invalid date %q", event.EventID, event.EventDate)
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.EventID+"@calendar",
+			"DTSTAMP:"+stamp,
+			"DTSTART;VALUE=DATE:"+date.Format("20060102"),
+			"DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+escapeICal(event.Text),
+			"END:VEVENT",
+		)
+
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICal(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+// escapeICal escapes a TEXT property value as required by RFC 5545.
+func escapeICal(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldICal splits a content line longer than 75 octets into continuation lines,
+// never breaking inside a UTF-8 sequence.
+func foldICal(line string) string {
+
+	var b strings.Builder
+	limit := icalLineLimit
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icalLineLimit - 1 // the leading space counts towards the limit
+	}
+
+	b.WriteString(line)
+
+	return b.String()
+
+}
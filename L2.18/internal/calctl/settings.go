@@ -0,0 +1,76 @@
+package calctl
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Settings holds the calctl defaults read from the config file.
+// Command-line flags take precedence over every value.
+type Settings struct {
+	Server       string        // Base URL of the calendar service
+	UserID       int           // User ID used when a command is given no --user flag
+	Output       string        // Default output format: "table", "json" or "ical"
+	AdminToken   string        // Bearer token sent with the admin commands
+	Tenant       string        // Tenant sent with every request (empty for the server's default tenant)
+	TenantHeader string        // Request header carrying the tenant, as configured on the server
+	Timeout      time.Duration // Timeout of a single HTTP request
+}
+
+// defaultConfigPath returns the config file used when neither --config nor
+// CALCTL_CONFIG is set: calctl/config.yaml in the user's config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "calctl", "config.yaml")
+}
+
+// LoadSettings reads the calctl config file and returns its settings with defaults
+// applied for missing values.
+//
+// An explicitly given path must exist. When path is empty, CALCTL_CONFIG and then the
+// default location are tried, and a missing file simply yields the defaults.
+func LoadSettings(path string) (Settings, error) {
+
+	v := viper.New()
+
+	v.SetDefault("server", "http://localhost:8080")
+	v.SetDefault("output", "table")
+	v.SetDefault("tenant_header", "X-Tenant-ID")
+	v.SetDefault("timeout", 10*time.Second)
+
+	explicit := path != ""
+	if !explicit {
+		if path = os.Getenv("CALCTL_CONFIG"); path != "" {
+			explicit = true
+		} else {
+			path = defaultConfigPath()
+		}
+	}
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+			return Settings{}, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
+	return Settings{
+		Server:       v.GetString("server"),
+		UserID:       v.GetInt("user_id"),
+		Output:       v.GetString("output"),
+		AdminToken:   v.GetString("admin_token"),
+		Tenant:       v.GetString("tenant"),
+		TenantHeader: v.GetString("tenant_header"),
+		Timeout:      v.GetDuration("timeout"),
+	}, nil
+
+}
@@ -5,24 +5,66 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/netip"
+	"slices"
 	"time"
 
+	"L2.18/pkg/egress"
+	"L2.18/pkg/tenant"
 	"github.com/spf13/viper"
 )
 
 // App holds all configuration sections for the application.
 type App struct {
-	Logger  Logger  // Logger configuration
-	Server  Server  // HTTP server configuration
-	Service Service // Business logic / service configuration
-	Storage Storage // Persistent storage configuration
+	Logger    Logger    // Logger configuration
+	Server    Server    // HTTP server configuration
+	CORS      CORS      // Cross-origin requests allowed from browsers
+	Headers   Headers   // Security headers added to every response
+	RateLimit RateLimit // Per-client request rate limiting configuration
+	Service   Service   // Business logic / service configuration
+	Calendar  Calendar  // Holiday calendars and default working week
+	Storage   Storage   // Persistent storage configuration
+	Tracing   Tracing   // Distributed tracing configuration
+	Admin     Admin     // Administrative API configuration
+	Tenancy   Tenancy   // Multi-tenant request isolation
+	Webhooks  Webhooks  // Outbound webhook delivery
+	Shutdown  Shutdown  // Timeouts of the shutdown phases following the server drain
 }
 
 // Logger contains configuration for the structured logger.
 type Logger struct {
-	LogDir string // Directory where logs are stored
-	Debug  bool   // Enables debug logging if true
+	LogDir   string   // Directory where logs are stored
+	Debug    bool     // Enables debug logging if true
+	Backend  string   // Logger implementation: "slog" (single append-only file) or "rotating"
+	Format   string   // Output format: "json" or "text"
+	Rotation Rotation // Log file rotation settings, used by the rotating backend
+	Sampling Sampling // Per-level sampling settings, applied on top of any backend
+}
+
+// Rotation contains configuration for size- and time-based log file rotation.
+type Rotation struct {
+	MaxSizeMB  int           // Rotate once the active file grows beyond this size in megabytes (0 disables)
+	Interval   time.Duration // Rotate once the active file is older than this duration (0 disables)
+	MaxBackups int           // Maximum number of rotated files to keep (0 keeps all)
+	MaxAge     time.Duration // Maximum age of rotated files before removal (0 keeps all)
+	Compress   bool          // Compresses rotated files with gzip if true
+}
+
+// Sampling contains configuration for per-level log sampling.
+type Sampling struct {
+	Enabled  bool          // Enables sampling if true
+	Interval time.Duration // Window after which sampling counters are reset
+	Debug    SamplingRule  // Sampling rule for debug messages
+	Info     SamplingRule  // Sampling rule for info messages
+	Warn     SamplingRule  // Sampling rule for warning messages
+}
+
+// SamplingRule describes how many identical messages of one level pass per interval.
+type SamplingRule struct {
+	First      int // Number of identical messages logged unconditionally per interval (0 disables sampling for the level)
+	Thereafter int // After First, only every Thereafter-th message is logged (0 drops the rest)
 }
 
 // Server contains configuration parameters for the HTTP server.
@@ -31,26 +73,135 @@ type Server struct {
 	ReadTimeout     time.Duration // Maximum duration for reading a request
 	WriteTimeout    time.Duration // Maximum duration before timing out writes
 	MaxHeaderBytes  int           // Maximum size of request headers in bytes
-	ShutdownTimeout time.Duration // Timeout for graceful server shutdown
+	ShutdownTimeout time.Duration // Time in-flight requests get to finish on shutdown before their connections are closed
+	TLS             TLS           // TLS termination settings
+	TrustedProxies  []string      // Proxies (IPs or CIDRs) whose X-Forwarded-For and X-Real-IP headers name the client; none if empty
+}
+
+// TLS contains configuration for serving HTTPS.
+type TLS struct {
+	Enabled  bool   // Serves HTTPS instead of plain HTTP if true
+	CertFile string // PEM certificate chain, reloaded whenever the file changes
+	KeyFile  string // PEM private key of the certificate, reloaded whenever the file changes
+	DevCert  bool   // Generates a self-signed localhost certificate into CertFile and KeyFile if they are missing; for development only
+}
+
+// CORS contains configuration for cross-origin resource sharing.
+type CORS struct {
+	Enabled          bool          // Answers preflight requests and adds CORS headers if true
+	AllowedOrigins   []string      // Origins allowed to call the API, e.g. https://calendar.example.com; "*" allows any
+	AllowedMethods   []string      // Methods allowed in cross-origin requests
+	AllowedHeaders   []string      // Request headers allowed in cross-origin requests
+	ExposedHeaders   []string      // Response headers readable by cross-origin callers
+	AllowCredentials bool          // Allows cookies and Authorization headers in cross-origin requests
+	MaxAge           time.Duration // How long browsers may cache a preflight response
+}
+
+// Headers contains configuration for the security headers added to responses.
+type Headers struct {
+	Enabled               bool          // Adds the security headers if true
+	ContentSecurityPolicy string        // Content-Security-Policy of API responses (empty omits it); not sent with the Swagger UI
+	FrameOptions          string        // X-Frame-Options value (empty omits it)
+	ReferrerPolicy        string        // Referrer-Policy value (empty omits it)
+	HSTSMaxAge            time.Duration // Strict-Transport-Security max-age, sent over TLS only (0 omits it)
+}
+
+// RateLimit contains configuration for per-client request rate limiting.
+type RateLimit struct {
+	Enabled           bool    // Enables rate limiting if true
+	RequestsPerSecond float64 // Sustained number of requests allowed per client per second
+	Burst             int     // Maximum number of requests a client can send at once
 }
 
 // Service contains configuration for the business logic layer.
 type Service struct {
-	MaxEventsPerUser int // Maximum number of events a user can create
+	MaxEventsPerUser   int                     // Maximum number of events a user can create
+	MaxWebhooksPerUser int                     // Maximum number of webhooks a user can register
+	NonWorkingDays     string                  // Policy for events on non-working days: "allow", "warn" or "reject"
+	Attachments        Attachments             // Limits of files attached to events
+	Tenants            map[string]TenantLimits // Per-tenant overrides of the limits above, keyed by tenant ID
+}
+
+// Attachments contains the limits of files attached to events.
+type Attachments struct {
+	MaxSizeMB    int      // Maximum size of a single file in megabytes
+	MaxPerEvent  int      // Maximum number of files attached to one event
+	AllowedTypes []string // MIME types accepted for upload, matched against the type detected from the content
+}
+
+// TenantLimits contains the limits of a single tenant. Zero values inherit the service-wide limits.
+type TenantLimits struct {
+	MaxEventsPerUser int // Maximum number of events a user of the tenant can create
+}
+
+// Calendar contains configuration for holiday calendars and working weeks.
+type Calendar struct {
+	HolidaysDir    string   // Directory with one <COUNTRY>.txt holiday file per country
+	DefaultCountry string   // Holiday calendar of users without their own setting (empty for none)
+	WorkingWeek    []string // Working days of users without their own setting, e.g. mon, tue, ...
 }
 
 // Storage contains configuration for the storage layer.
 type Storage struct {
-	ExpectedUsers    int // Expected number of users for preallocation / sizing
-	MaxEventsPerUser int // Maximum events per user in storage
-	MaxEventsPerDay  int // Maximum events per day in storage
+	ExpectedUsers    int    // Expected number of users for preallocation / sizing
+	MaxEventsPerUser int    // Maximum events per user in storage
+	MaxEventsPerDay  int    // Maximum events per day in storage
+	AttachmentsDir   string // Directory where the files attached to events are stored
+	WebhooksFile     string // File keeping registered webhooks and their delivery queue across restarts
+	SnapshotFile     string // File the in-memory storage is snapshotted to and restored from, empty to disable snapshots
+}
+
+// Tracing contains configuration for OpenTelemetry distributed tracing.
+type Tracing struct {
+	Enabled     bool    // Enables span export if true; trace context is propagated either way
+	Exporter    string  // Span exporter: "stdout" or "otlp"
+	Endpoint    string  // OTLP/HTTP collector endpoint (host:port), used by the otlp exporter
+	Insecure    bool    // Uses plain HTTP instead of HTTPS for the OTLP endpoint if true
+	ServiceName string  // Service name reported with every span
+	SampleRatio float64 // Fraction of new traces to sample, from 0 to 1; sampled parents are always followed
+}
+
+// Admin contains configuration for the administrative API.
+type Admin struct {
+	Token string // Bearer token required by the /admin endpoints; the admin API is disabled if empty
+}
+
+// Tenancy contains configuration for resolving the tenant of each request.
+type Tenancy struct {
+	Enabled bool   // Resolves a tenant for every request if true; otherwise all requests share the default tenant
+	Source  string // Where the tenant ID is read from: "header" or "claim"
+	Header  string // Request header carrying the tenant ID, used by the header source
+	Claim   string // Bearer token claim carrying the tenant ID, used by the claim source
+	Secret  string // HMAC secret verifying HS256 bearer tokens, used by the claim source
+	Default string // Tenant of requests that do not name one; such requests are rejected if empty
+}
+
+// Webhooks contains configuration for delivering webhook notifications.
+type Webhooks struct {
+	Timeout         time.Duration // Maximum duration of a single delivery attempt
+	PollInterval    time.Duration // How often the queue is checked for deliveries that are due
+	MaxAttempts     int           // Attempts made before a delivery is moved to the dead-letter list
+	InitialBackoff  time.Duration // Delay before the first retry; doubled after every further failure
+	MaxBackoff      time.Duration // Upper bound of the delay between retries
+	LogSize         int           // Finished deliveries kept per webhook in the delivery log, counted separately for delivered and dead ones
+	AllowedNetworks []string      // Loopback, private or link-local networks (CIDRs or IPs) webhooks may nonetheless be delivered to
+}
+
+// Shutdown contains the timeouts of the shutdown phases run after the HTTP server has
+// drained within Server.ShutdownTimeout. Work still running when its phase times out
+// is abandoned and reported.
+type Shutdown struct {
+	BackgroundTimeout time.Duration // Time background workers (config watcher, webhook dispatcher) get to stop
+	FlushTimeout      time.Duration // Time trace spans and the storage snapshot get to be written
+	CloseTimeout      time.Duration // Time the storage and the webhook store get to close
 }
 
 // Load reads the configuration from a file and returns an App instance.
 //
 // The configuration file must exist; if it cannot be read, an error is returned.
 // For any fields missing or empty within the file, default values are applied
-// to ensure the application has all required settings.
+// to ensure the application has all required settings. The result is validated
+// like on Reload, so the service never starts with values a reload would reject.
 func Load() (App, error) {
 
 	viper.AddConfigPath(".")
@@ -60,27 +211,229 @@ func Load() (App, error) {
 		return App{}, fmt.Errorf("viper: %v", err)
 	}
 
+	config := build()
+	if err := Validate(config); err != nil {
+		return App{}, err
+	}
+
+	return config, nil
+
+}
+
+// Reload re-reads the configuration file used by Load and returns the new configuration.
+//
+// As with Load, the result is validated, so callers can swap to it safely. On any error
+// the caller is expected to keep its current configuration.
+func Reload() (App, error) {
+
+	if err := viper.ReadInConfig(); err != nil {
+		return App{}, fmt.Errorf("viper: %v", err)
+	}
+
+	config := build()
+	if err := Validate(config); err != nil {
+		return App{}, err
+	}
+
+	return config, nil
+
+}
+
+// build assembles the App configuration from the values currently held by Viper,
+// applying defaults for missing fields.
+func build() App {
+
 	logger := loggerConfig()
 	server := serverConfig()
+	cors := corsConfig()
+	headers := headersConfig()
+	rateLimit := rateLimitConfig()
 	service := serviceConfig()
+	calendar := calendarConfig()
 	storage := storageConfig()
+	tracing := tracingConfig()
+	admin := adminConfig()
+	tenancy := tenancyConfig()
+	webhooks := webhooksConfig()
+	shutdown := shutdownConfig()
 
-	failsafe(&logger, &server, &service, &storage)
+	failsafe(&logger, &server, &cors, &headers, &rateLimit, &service, &calendar, &storage, &tracing, &tenancy, &webhooks, &shutdown)
 
 	return App{
-		Logger:  logger,
-		Server:  server,
-		Service: service,
-		Storage: storage,
-	}, nil
+		Logger:    logger,
+		Server:    server,
+		CORS:      cors,
+		Headers:   headers,
+		RateLimit: rateLimit,
+		Service:   service,
+		Calendar:  calendar,
+		Storage:   storage,
+		Tracing:   tracing,
+		Admin:     admin,
+		Tenancy:   tenancy,
+		Webhooks:  webhooks,
+		Shutdown:  shutdown,
+	}
+
+}
+
+// Validate checks that the configuration values are usable.
+// It returns an error describing every invalid field, or nil if the configuration is valid.
+func Validate(config App) error {
+
+	var errs []error
+
+	if config.Logger.Backend != "slog" && config.Logger.Backend != "rotating" {
+		errs = append(errs, fmt.Errorf("logger.backend must be 'slog' or 'rotating', got %q", config.Logger.Backend))
+	}
+	if config.Logger.Format != "json" && config.Logger.Format != "text" {
+		errs = append(errs, fmt.Errorf("logger.format must be 'json' or 'text', got %q", config.Logger.Format))
+	}
+
+	if config.Server.Port == "" {
+		errs = append(errs, errors.New("server.port must not be empty"))
+	}
+	if config.Server.ReadTimeout <= 0 || config.Server.WriteTimeout <= 0 || config.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server timeouts must be positive"))
+	}
+	if config.Server.TLS.Enabled && (config.Server.TLS.CertFile == "" || config.Server.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("server.tls.cert_file and server.tls.key_file must be set when TLS is enabled"))
+	}
+	for _, proxy := range config.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				errs = append(errs, fmt.Errorf("server.trusted_proxies: %q is neither an IP address nor a CIDR", proxy))
+			}
+		}
+	}
+
+	if config.CORS.Enabled {
+		if len(config.CORS.AllowedOrigins) == 0 {
+			errs = append(errs, errors.New("cors.allowed_origins must list at least one origin when CORS is enabled"))
+		}
+		if config.CORS.AllowCredentials && slices.Contains(config.CORS.AllowedOrigins, "*") {
+			errs = append(errs, errors.New("cors.allowed_origins must name the origins explicitly when cors.allow_credentials is set"))
+		}
+		if config.CORS.MaxAge < 0 {
+			errs = append(errs, fmt.Errorf("cors.max_age must not be negative, got %v", config.CORS.MaxAge))
+		}
+	}
+
+	if config.Headers.HSTSMaxAge < 0 {
+		errs = append(errs, fmt.Errorf("security_headers.hsts_max_age must not be negative, got %v", config.Headers.HSTSMaxAge))
+	}
+
+	if config.RateLimit.Enabled && (config.RateLimit.RequestsPerSecond <= 0 || config.RateLimit.Burst <= 0) {
+		errs = append(errs, errors.New("rate_limit.requests_per_second and rate_limit.burst must be positive when rate limiting is enabled"))
+	}
+
+	if config.Service.MaxEventsPerUser <= 0 {
+		errs = append(errs, fmt.Errorf("service.max_events_per_user must be positive, got %d", config.Service.MaxEventsPerUser))
+	}
+	if config.Service.MaxWebhooksPerUser < 0 {
+		errs = append(errs, fmt.Errorf("service.max_webhooks_per_user must not be negative, got %d", config.Service.MaxWebhooksPerUser))
+	}
+	if config.Service.NonWorkingDays != "allow" && config.Service.NonWorkingDays != "warn" && config.Service.NonWorkingDays != "reject" {
+		errs = append(errs, fmt.Errorf("service.non_working_days must be 'allow', 'warn' or 'reject', got %q", config.Service.NonWorkingDays))
+	}
+	if config.Service.Attachments.MaxSizeMB <= 0 || config.Service.Attachments.MaxPerEvent <= 0 {
+		errs = append(errs, errors.New("service.attachments.max_size_mb and service.attachments.max_per_event must be positive"))
+	}
+	if len(config.Service.Attachments.AllowedTypes) == 0 {
+		errs = append(errs, errors.New("service.attachments.allowed_types must list at least one MIME type"))
+	}
+	for id, limits := range config.Service.Tenants {
+		if !tenant.Valid(id) {
+			errs = append(errs, fmt.Errorf("service.tenants: invalid tenant ID %q", id))
+		}
+		if limits.MaxEventsPerUser < 0 {
+			errs = append(errs, fmt.Errorf("service.tenants.%s.max_events_per_user must not be negative, got %d", id, limits.MaxEventsPerUser))
+		}
+	}
+
+	if len(config.Calendar.WorkingWeek) == 0 {
+		errs = append(errs, errors.New("calendar.working_week must list at least one day"))
+	}
+
+	if config.Storage.ExpectedUsers < 0 || config.Storage.MaxEventsPerDay < 0 {
+		errs = append(errs, errors.New("storage values must not be negative"))
+	}
+	if config.Storage.AttachmentsDir == "" {
+		errs = append(errs, errors.New("storage.attachments_directory must not be empty"))
+	}
+	if config.Storage.WebhooksFile == "" {
+		errs = append(errs, errors.New("storage.webhooks_file must not be empty"))
+	}
+
+	if config.Tracing.Enabled && config.Tracing.Exporter != "stdout" && config.Tracing.Exporter != "otlp" {
+		errs = append(errs, fmt.Errorf("tracing.exporter must be 'stdout' or 'otlp', got %q", config.Tracing.Exporter))
+	}
+	if config.Tracing.SampleRatio < 0 || config.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio must be between 0 and 1, got %v", config.Tracing.SampleRatio))
+	}
+
+	if config.Tenancy.Enabled {
+		if config.Tenancy.Source != "header" && config.Tenancy.Source != "claim" {
+			errs = append(errs, fmt.Errorf("tenancy.source must be 'header' or 'claim', got %q", config.Tenancy.Source))
+		}
+		if config.Tenancy.Source == "claim" && config.Tenancy.Secret == "" {
+			errs = append(errs, errors.New("tenancy.secret must be set when tenancy.source is 'claim'"))
+		}
+		if config.Tenancy.Default != "" && !tenant.Valid(config.Tenancy.Default) {
+			errs = append(errs, fmt.Errorf("tenancy.default: invalid tenant ID %q", config.Tenancy.Default))
+		}
+	}
+
+	if config.Webhooks.Timeout <= 0 || config.Webhooks.PollInterval <= 0 || config.Webhooks.InitialBackoff <= 0 {
+		errs = append(errs, errors.New("webhooks.timeout, webhooks.poll_interval and webhooks.initial_backoff must be positive"))
+	}
+	if config.Webhooks.MaxBackoff < config.Webhooks.InitialBackoff {
+		errs = append(errs, errors.New("webhooks.max_backoff must not be shorter than webhooks.initial_backoff"))
+	}
+	if config.Webhooks.MaxAttempts <= 0 || config.Webhooks.LogSize <= 0 {
+		errs = append(errs, errors.New("webhooks.max_attempts and webhooks.log_size must be positive"))
+	}
+	if _, err := egress.NewPolicy(config.Webhooks.AllowedNetworks); err != nil {
+		errs = append(errs, fmt.Errorf("webhooks.allowed_networks: %w", err))
+	}
+
+	if config.Shutdown.BackgroundTimeout <= 0 || config.Shutdown.FlushTimeout <= 0 || config.Shutdown.CloseTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown timeouts must be positive"))
+	}
+
+	return errors.Join(errs...)
 
 }
 
 // loggerConfig reads logger configuration from Viper.
 func loggerConfig() Logger {
 	return Logger{
-		LogDir: viper.GetString("app.logger.log_directory"),
-		Debug:  viper.GetBool("app.logger.debug_mode"),
+		LogDir:  viper.GetString("app.logger.log_directory"),
+		Debug:   viper.GetBool("app.logger.debug_mode"),
+		Backend: viper.GetString("app.logger.backend"),
+		Format:  viper.GetString("app.logger.format"),
+		Rotation: Rotation{
+			MaxSizeMB:  viper.GetInt("app.logger.rotation.max_size_mb"),
+			Interval:   viper.GetDuration("app.logger.rotation.interval"),
+			MaxBackups: viper.GetInt("app.logger.rotation.max_backups"),
+			MaxAge:     viper.GetDuration("app.logger.rotation.max_age"),
+			Compress:   viper.GetBool("app.logger.rotation.compress"),
+		},
+		Sampling: Sampling{
+			Enabled:  viper.GetBool("app.logger.sampling.enabled"),
+			Interval: viper.GetDuration("app.logger.sampling.interval"),
+			Debug:    samplingRule("app.logger.sampling.debug"),
+			Info:     samplingRule("app.logger.sampling.info"),
+			Warn:     samplingRule("app.logger.sampling.warn"),
+		},
+	}
+}
+
+// samplingRule reads a single per-level sampling rule from Viper.
+func samplingRule(key string) SamplingRule {
+	return SamplingRule{
+		First:      viper.GetInt(key + ".first"),
+		Thereafter: viper.GetInt(key + ".thereafter"),
 	}
 }
 
@@ -92,13 +445,82 @@ func serverConfig() Server {
 		WriteTimeout:    viper.GetDuration("app.server.write_timeout"),
 		MaxHeaderBytes:  viper.GetInt("app.server.max_header_bytes"),
 		ShutdownTimeout: viper.GetDuration("app.server.shutdown_timeout"),
+		TLS: TLS{
+			Enabled:  viper.GetBool("app.server.tls.enabled"),
+			CertFile: viper.GetString("app.server.tls.cert_file"),
+			KeyFile:  viper.GetString("app.server.tls.key_file"),
+			DevCert:  viper.GetBool("app.server.tls.dev_cert"),
+		},
+		TrustedProxies: viper.GetStringSlice("app.server.trusted_proxies"),
+	}
+}
+
+// corsConfig reads CORS configuration from Viper.
+func corsConfig() CORS {
+	return CORS{
+		Enabled:          viper.GetBool("app.cors.enabled"),
+		AllowedOrigins:   viper.GetStringSlice("app.cors.allowed_origins"),
+		AllowedMethods:   viper.GetStringSlice("app.cors.allowed_methods"),
+		AllowedHeaders:   viper.GetStringSlice("app.cors.allowed_headers"),
+		ExposedHeaders:   viper.GetStringSlice("app.cors.exposed_headers"),
+		AllowCredentials: viper.GetBool("app.cors.allow_credentials"),
+		MaxAge:           viper.GetDuration("app.cors.max_age"),
+	}
+}
+
+// headersConfig reads security headers configuration from Viper.
+func headersConfig() Headers {
+	return Headers{
+		Enabled:               viper.GetBool("app.security_headers.enabled"),
+		ContentSecurityPolicy: viper.GetString("app.security_headers.content_security_policy"),
+		FrameOptions:          viper.GetString("app.security_headers.frame_options"),
+		ReferrerPolicy:        viper.GetString("app.security_headers.referrer_policy"),
+		HSTSMaxAge:            viper.GetDuration("app.security_headers.hsts_max_age"),
+	}
+}
+
+// rateLimitConfig reads rate limiting configuration from Viper.
+func rateLimitConfig() RateLimit {
+	return RateLimit{
+		Enabled:           viper.GetBool("app.rate_limit.enabled"),
+		RequestsPerSecond: viper.GetFloat64("app.rate_limit.requests_per_second"),
+		Burst:             viper.GetInt("app.rate_limit.burst"),
 	}
 }
 
 // serviceConfig reads service configuration from Viper.
 func serviceConfig() Service {
 	return Service{
-		MaxEventsPerUser: viper.GetInt("app.service.max_events_per_user"),
+		MaxEventsPerUser:   viper.GetInt("app.service.max_events_per_user"),
+		MaxWebhooksPerUser: viper.GetInt("app.service.max_webhooks_per_user"),
+		NonWorkingDays:     viper.GetString("app.service.non_working_days"),
+		Attachments: Attachments{
+			MaxSizeMB:    viper.GetInt("app.service.attachments.max_size_mb"),
+			MaxPerEvent:  viper.GetInt("app.service.attachments.max_per_event"),
+			AllowedTypes: viper.GetStringSlice("app.service.attachments.allowed_types"),
+		},
+		Tenants: tenantLimits("app.service.tenants"),
+	}
+}
+
+// tenantLimits reads the per-tenant limits listed under key from Viper.
+// Viper lowercases keys, so tenant IDs are normalized the same way as in requests.
+func tenantLimits(key string) map[string]TenantLimits {
+	res := make(map[string]TenantLimits)
+	for id := range viper.GetStringMap(key) {
+		res[tenant.Normalize(id)] = TenantLimits{
+			MaxEventsPerUser: viper.GetInt(key + "." + id + ".max_events_per_user"),
+		}
+	}
+	return res
+}
+
+// calendarConfig reads holiday calendar configuration from Viper.
+func calendarConfig() Calendar {
+	return Calendar{
+		HolidaysDir:    viper.GetString("app.calendar.holidays_directory"),
+		DefaultCountry: viper.GetString("app.calendar.default_country"),
+		WorkingWeek:    viper.GetStringSlice("app.calendar.working_week"),
 	}
 }
 
@@ -107,6 +529,62 @@ func storageConfig() Storage {
 	return Storage{
 		ExpectedUsers:   viper.GetInt("app.storage.expected_users"),
 		MaxEventsPerDay: viper.GetInt("app.storage.max_events_per_day"),
+		AttachmentsDir:  viper.GetString("app.storage.attachments_directory"),
+		WebhooksFile:    viper.GetString("app.storage.webhooks_file"),
+		SnapshotFile:    viper.GetString("app.storage.snapshot_file"),
+	}
+}
+
+// tracingConfig reads tracing configuration from Viper.
+func tracingConfig() Tracing {
+	return Tracing{
+		Enabled:     viper.GetBool("app.tracing.enabled"),
+		Exporter:    viper.GetString("app.tracing.exporter"),
+		Endpoint:    viper.GetString("app.tracing.endpoint"),
+		Insecure:    viper.GetBool("app.tracing.insecure"),
+		ServiceName: viper.GetString("app.tracing.service_name"),
+		SampleRatio: viper.GetFloat64("app.tracing.sample_ratio"),
+	}
+}
+
+// adminConfig reads administrative API configuration from Viper.
+func adminConfig() Admin {
+	return Admin{
+		Token: viper.GetString("app.admin.token"),
+	}
+}
+
+// tenancyConfig reads tenancy configuration from Viper.
+func tenancyConfig() Tenancy {
+	return Tenancy{
+		Enabled: viper.GetBool("app.tenancy.enabled"),
+		Source:  viper.GetString("app.tenancy.source"),
+		Header:  viper.GetString("app.tenancy.header"),
+		Claim:   viper.GetString("app.tenancy.claim"),
+		Secret:  viper.GetString("app.tenancy.secret"),
+		Default: tenant.Normalize(viper.GetString("app.tenancy.default")),
+	}
+}
+
+// webhooksConfig reads webhook delivery configuration from Viper.
+func webhooksConfig() Webhooks {
+	return Webhooks{
+		Timeout:         viper.GetDuration("app.webhooks.timeout"),
+		PollInterval:    viper.GetDuration("app.webhooks.poll_interval"),
+		MaxAttempts:     viper.GetInt("app.webhooks.max_attempts"),
+		InitialBackoff:  viper.GetDuration("app.webhooks.initial_backoff"),
+		MaxBackoff:      viper.GetDuration("app.webhooks.max_backoff"),
+		LogSize:         viper.GetInt("app.webhooks.log_size"),
+		AllowedNetworks: viper.GetStringSlice("app.webhooks.allowed_networks"),
+	}
+}
+
+// shutdownConfig reads shutdown phase timeouts from Viper.
+func shutdownConfig() Shutdown {
+	return Shutdown{
+		BackgroundTimeout: viper.GetDuration("app.shutdown.background_timeout"),
+		FlushTimeout:      viper.GetDuration("app.shutdown.flush_timeout"),
+		CloseTimeout:      viper.GetDuration("app.shutdown.close_timeout"),
 	}
 }
 
@@ -115,16 +593,24 @@ func storageConfig() Storage {
 // This ensures the application can still run even if parts of the config file
 // are missing or empty. It prints informative messages for any field that
 // is using a default value.
-func failsafe(logger *Logger, server *Server, service *Service, storage *Storage) {
+func failsafe(logger *Logger, server *Server, cors *CORS, headers *Headers, rateLimit *RateLimit, service *Service, calendar *Calendar, storage *Storage, tracing *Tracing, tenancy *Tenancy, webhooks *Webhooks, shutdown *Shutdown) {
 
 	if len(viper.AllSettings()) == 0 {
 
 		fmt.Println("config file is empty, switching to default values")
 
-		*logger = Logger{Debug: true}
+		*logger = Logger{Debug: true, Backend: "slog", Format: "json"}
 		*server = Server{Port: "8080", ReadTimeout: 5 * time.Second, WriteTimeout: 10 * time.Second, MaxHeaderBytes: 1048576, ShutdownTimeout: 15 * time.Second}
-		*service = Service{MaxEventsPerUser: 100}
-		*storage = Storage{ExpectedUsers: 100, MaxEventsPerDay: 100, MaxEventsPerUser: 100}
+		*cors = defaultCORS()
+		*headers = defaultHeaders()
+		*rateLimit = RateLimit{}
+		*service = Service{MaxEventsPerUser: 100, MaxWebhooksPerUser: 5, NonWorkingDays: "allow", Attachments: Attachments{MaxSizeMB: 10, MaxPerEvent: 10, AllowedTypes: defaultAttachmentTypes()}}
+		*calendar = Calendar{HolidaysDir: "holidays", WorkingWeek: defaultWorkingWeek()}
+		*storage = Storage{ExpectedUsers: 100, MaxEventsPerDay: 100, MaxEventsPerUser: 100, AttachmentsDir: "attachments", WebhooksFile: "webhooks.json", SnapshotFile: "snapshot.jsonl"}
+		*tracing = Tracing{ServiceName: "calendar", SampleRatio: 1}
+		*tenancy = Tenancy{Source: "header", Header: "X-Tenant-ID", Claim: "tenant", Default: tenant.Default}
+		*webhooks = defaultWebhooks()
+		*shutdown = defaultShutdown()
 
 		return
 
@@ -134,6 +620,18 @@ func failsafe(logger *Logger, server *Server, service *Service, storage *Storage
 		fmt.Println("logger.debug_mode missing, switching to default 'true'")
 		logger.Debug = true
 	}
+	if !viper.IsSet("app.logger.backend") {
+		fmt.Println("logger.backend missing, switching to default 'slog'")
+		logger.Backend = "slog"
+	}
+	if !viper.IsSet("app.logger.format") {
+		fmt.Println("logger.format missing, switching to default 'json'")
+		logger.Format = "json"
+	}
+	if logger.Sampling.Enabled && !viper.IsSet("app.logger.sampling.interval") {
+		fmt.Println("logger.sampling.interval missing, switching to default 1s")
+		logger.Sampling.Interval = time.Second
+	}
 
 	if !viper.IsSet("app.server.port") {
 		fmt.Println("server.port missing, switching to default '8080'")
@@ -155,11 +653,95 @@ func failsafe(logger *Logger, server *Server, service *Service, storage *Storage
 		fmt.Println("server.shutdown_timeout missing, switching to default 15s")
 		server.ShutdownTimeout = 15 * time.Second
 	}
+	if server.TLS.Enabled && !viper.IsSet("app.server.tls.cert_file") {
+		fmt.Println("server.tls.cert_file missing, switching to default 'certs/server.crt'")
+		server.TLS.CertFile = "certs/server.crt"
+	}
+	if server.TLS.Enabled && !viper.IsSet("app.server.tls.key_file") {
+		fmt.Println("server.tls.key_file missing, switching to default 'certs/server.key'")
+		server.TLS.KeyFile = "certs/server.key"
+	}
+
+	corsDefaults := defaultCORS()
+
+	if cors.Enabled && !viper.IsSet("app.cors.allowed_methods") {
+		fmt.Println("cors.allowed_methods missing, switching to default GET, POST, OPTIONS")
+		cors.AllowedMethods = corsDefaults.AllowedMethods
+	}
+	if cors.Enabled && !viper.IsSet("app.cors.allowed_headers") {
+		fmt.Println("cors.allowed_headers missing, switching to default Content-Type, Authorization, X-Tenant-ID, traceparent")
+		cors.AllowedHeaders = corsDefaults.AllowedHeaders
+	}
+	if cors.Enabled && !viper.IsSet("app.cors.max_age") {
+		fmt.Println("cors.max_age missing, switching to default 10m")
+		cors.MaxAge = corsDefaults.MaxAge
+	}
+
+	headersDefaults := defaultHeaders()
+
+	if !viper.IsSet("app.security_headers.enabled") {
+		fmt.Println("security_headers.enabled missing, switching to default 'true'")
+		headers.Enabled = true
+	}
+	if !viper.IsSet("app.security_headers.content_security_policy") {
+		fmt.Println("security_headers.content_security_policy missing, switching to default \"default-src 'none'; frame-ancestors 'none'\"")
+		headers.ContentSecurityPolicy = headersDefaults.ContentSecurityPolicy
+	}
+	if !viper.IsSet("app.security_headers.frame_options") {
+		fmt.Println("security_headers.frame_options missing, switching to default 'DENY'")
+		headers.FrameOptions = headersDefaults.FrameOptions
+	}
+	if !viper.IsSet("app.security_headers.referrer_policy") {
+		fmt.Println("security_headers.referrer_policy missing, switching to default 'no-referrer'")
+		headers.ReferrerPolicy = headersDefaults.ReferrerPolicy
+	}
+	if !viper.IsSet("app.security_headers.hsts_max_age") {
+		fmt.Println("security_headers.hsts_max_age missing, switching to default 8760h")
+		headers.HSTSMaxAge = headersDefaults.HSTSMaxAge
+	}
+
+	if rateLimit.Enabled && !viper.IsSet("app.rate_limit.requests_per_second") {
+		fmt.Println("rate_limit.requests_per_second missing, switching to default 10")
+		rateLimit.RequestsPerSecond = 10
+	}
+	if rateLimit.Enabled && !viper.IsSet("app.rate_limit.burst") {
+		fmt.Println("rate_limit.burst missing, switching to default 20")
+		rateLimit.Burst = 20
+	}
 
 	if !viper.IsSet("app.service.max_events_per_user") {
 		fmt.Println("service.max_events_per_user missing, switching to default 100")
 		service.MaxEventsPerUser = 100
 	}
+	if !viper.IsSet("app.service.max_webhooks_per_user") {
+		fmt.Println("service.max_webhooks_per_user missing, switching to default 5")
+		service.MaxWebhooksPerUser = 5
+	}
+	if !viper.IsSet("app.service.non_working_days") {
+		fmt.Println("service.non_working_days missing, switching to default 'allow'")
+		service.NonWorkingDays = "allow"
+	}
+	if !viper.IsSet("app.service.attachments.max_size_mb") {
+		fmt.Println("service.attachments.max_size_mb missing, switching to default 10")
+		service.Attachments.MaxSizeMB = 10
+	}
+	if !viper.IsSet("app.service.attachments.max_per_event") {
+		fmt.Println("service.attachments.max_per_event missing, switching to default 10")
+		service.Attachments.MaxPerEvent = 10
+	}
+	if !viper.IsSet("app.service.attachments.allowed_types") {
+		fmt.Println("service.attachments.allowed_types missing, switching to default documents and images")
+		service.Attachments.AllowedTypes = defaultAttachmentTypes()
+	}
+
+	if !viper.IsSet("app.calendar.holidays_directory") {
+		fmt.Println("calendar.holidays_directory missing, switching to default 'holidays'")
+		calendar.HolidaysDir = "holidays"
+	}
+	if !viper.IsSet("app.calendar.working_week") {
+		fmt.Println("calendar.working_week missing, switching to default mon-fri")
+		calendar.WorkingWeek = defaultWorkingWeek()
+	}
 
 	if !viper.IsSet("app.storage.expected_users") {
 		fmt.Println("storage.expected_users missing, switching to default 100")
@@ -169,5 +751,156 @@ func failsafe(logger *Logger, server *Server, service *Service, storage *Storage
 		fmt.Println("storage.max_events_per_day missing, switching to default 100")
 		storage.MaxEventsPerDay = 100
 	}
+	if !viper.IsSet("app.storage.attachments_directory") {
+		fmt.Println("storage.attachments_directory missing, switching to default 'attachments'")
+		storage.AttachmentsDir = "attachments"
+	}
+	if !viper.IsSet("app.storage.webhooks_file") {
+		fmt.Println("storage.webhooks_file missing, switching to default 'webhooks.json'")
+		storage.WebhooksFile = "webhooks.json"
+	}
+	if !viper.IsSet("app.storage.snapshot_file") {
+		fmt.Println("storage.snapshot_file missing, switching to default 'snapshot.jsonl'")
+		storage.SnapshotFile = "snapshot.jsonl"
+	}
 
+	if !viper.IsSet("app.tracing.service_name") {
+		fmt.Println("tracing.service_name missing, switching to default 'calendar'")
+		tracing.ServiceName = "calendar"
+	}
+	if !viper.IsSet("app.tracing.sample_ratio") {
+		fmt.Println("tracing.sample_ratio missing, switching to default 1")
+		tracing.SampleRatio = 1
+	}
+	if tracing.Enabled && !viper.IsSet("app.tracing.exporter") {
+		fmt.Println("tracing.exporter missing, switching to default 'stdout'")
+		tracing.Exporter = "stdout"
+	}
+	if tracing.Exporter == "otlp" && !viper.IsSet("app.tracing.endpoint") {
+		fmt.Println("tracing.endpoint missing, switching to default 'localhost:4318'")
+		tracing.Endpoint = "localhost:4318"
+	}
+
+	if !viper.IsSet("app.tenancy.source") {
+		fmt.Println("tenancy.source missing, switching to default 'header'")
+		tenancy.Source = "header"
+	}
+	if !viper.IsSet("app.tenancy.header") {
+		fmt.Println("tenancy.header missing, switching to default 'X-Tenant-ID'")
+		tenancy.Header = "X-Tenant-ID"
+	}
+	if !viper.IsSet("app.tenancy.claim") {
+		fmt.Println("tenancy.claim missing, switching to default 'tenant'")
+		tenancy.Claim = "tenant"
+	}
+	if !viper.IsSet("app.tenancy.default") {
+		fmt.Println("tenancy.default missing, switching to default 'default'")
+		tenancy.Default = tenant.Default
+	}
+
+	defaults := defaultWebhooks()
+
+	if !viper.IsSet("app.webhooks.timeout") {
+		fmt.Println("webhooks.timeout missing, switching to default 5s")
+		webhooks.Timeout = defaults.Timeout
+	}
+	if !viper.IsSet("app.webhooks.poll_interval") {
+		fmt.Println("webhooks.poll_interval missing, switching to default 1s")
+		webhooks.PollInterval = defaults.PollInterval
+	}
+	if !viper.IsSet("app.webhooks.max_attempts") {
+		fmt.Println("webhooks.max_attempts missing, switching to default 8")
+		webhooks.MaxAttempts = defaults.MaxAttempts
+	}
+	if !viper.IsSet("app.webhooks.initial_backoff") {
+		fmt.Println("webhooks.initial_backoff missing, switching to default 10s")
+		webhooks.InitialBackoff = defaults.InitialBackoff
+	}
+	if !viper.IsSet("app.webhooks.max_backoff") {
+		fmt.Println("webhooks.max_backoff missing, switching to default 1h")
+		webhooks.MaxBackoff = defaults.MaxBackoff
+	}
+	if !viper.IsSet("app.webhooks.log_size") {
+		fmt.Println("webhooks.log_size missing, switching to default 50")
+		webhooks.LogSize = defaults.LogSize
+	}
+
+	shutdownDefaults := defaultShutdown()
+
+	if !viper.IsSet("app.shutdown.background_timeout") {
+		fmt.Println("shutdown.background_timeout missing, switching to default 10s")
+		shutdown.BackgroundTimeout = shutdownDefaults.BackgroundTimeout
+	}
+	if !viper.IsSet("app.shutdown.flush_timeout") {
+		fmt.Println("shutdown.flush_timeout missing, switching to default 10s")
+		shutdown.FlushTimeout = shutdownDefaults.FlushTimeout
+	}
+	if !viper.IsSet("app.shutdown.close_timeout") {
+		fmt.Println("shutdown.close_timeout missing, switching to default 5s")
+		shutdown.CloseTimeout = shutdownDefaults.CloseTimeout
+	}
+
+}
+
+// defaultWorkingWeek returns the default working days, Monday to Friday.
+func defaultWorkingWeek() []string {
+	return []string{"mon", "tue", "wed", "thu", "fri"}
+}
+
+// defaultAttachmentTypes returns the MIME types accepted for attachments by default:
+// PDF, plain text, iCalendar, Word documents and common images.
+func defaultAttachmentTypes() []string {
+	return []string{
+		"application/pdf",
+		"text/plain",
+		"text/calendar",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"image/png",
+		"image/jpeg",
+	}
+}
+
+// defaultWebhooks returns the default webhook delivery settings: eight attempts over
+// roughly twenty minutes, backing off from 10 seconds up to an hour.
+func defaultWebhooks() Webhooks {
+	return Webhooks{
+		Timeout:        5 * time.Second,
+		PollInterval:   time.Second,
+		MaxAttempts:    8,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Hour,
+		LogSize:        50,
+	}
+}
+
+// defaultCORS returns the default CORS settings: disabled, and once enabled, simple API
+// calls with JSON bodies, admin and tenant headers and trace context, cached for 10 minutes.
+func defaultCORS() CORS {
+	return CORS{
+		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization", "X-Tenant-ID", "traceparent"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// defaultHeaders returns the default security headers: a policy allowing no content,
+// no framing, no referrers and HTTPS for a year once it has been used.
+func defaultHeaders() Headers {
+	return Headers{
+		Enabled:               true,
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+		HSTSMaxAge:            365 * 24 * time.Hour,
+	}
+}
+
+// defaultShutdown returns the default shutdown phase timeouts: enough for the webhook
+// dispatcher to finish a delivery attempt and for a large snapshot to be written.
+func defaultShutdown() Shutdown {
+	return Shutdown{
+		BackgroundTimeout: 10 * time.Second,
+		FlushTimeout:      10 * time.Second,
+		CloseTimeout:      5 * time.Second,
+	}
 }
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Watch notifies about changes of the configuration file read by Load.
+//
+// The directory containing the file is watched rather than the file itself, so
+// editors that replace the file on save are handled as well. Bursts of file system
+// events are coalesced: the returned channel holds at most one pending notification.
+// Watching stops and the channel is closed once ctx is cancelled.
+func Watch(ctx context.Context) (<-chan struct{}, error) {
+
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		return nil, errors.New("no configuration file in use")
+	}
+
+	configFile, err := filepath.Abs(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve configuration file path: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("fsnotify: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("fsnotify: %w", err)
+	}
+
+	changes := make(chan struct{}, 1)
+
+	go func() {
+
+		defer close(changes)
+		defer watcher.Close()
+
+		for {
+			select {
+
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != configFile || !(event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
+					continue
+				}
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+			}
+		}
+
+	}()
+
+	return changes, nil
+
+}
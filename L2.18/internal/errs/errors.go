@@ -10,20 +10,48 @@ package errs
 import "errors"
 
 var (
-	ErrInvalidJSON       = errors.New("invalid JSON format")                                 // invalid JSON format
-	ErrEmptyEventText    = errors.New("event text cannot be empty")                          // event text cannot be empty
-	ErrMissingDate       = errors.New("event date is required")                              // event date is required
-	ErrInvalidDateFormat = errors.New("invalid date format, expected YYYY-MM-DD")            // invalid date format, expected YYYY-MM-DD
-	ErrEventTextTooLong  = errors.New("event text exceeds maximum length of 500 characters") // event text exceeds maximum length of 500 characters
-	ErrInvalidUserID     = errors.New("missing or invalid user ID")                          // missing or invalid user ID
-	ErrEventInPast       = errors.New("event date cannot be in the past")                    // event date cannot be in the past
-	ErrEventTooFar       = errors.New("event date cannot be more than 10 years ahead")       // event date cannot be more than 10 years ahead
-	ErrMaxEvents         = errors.New("maximum number of events reached")                    // maximum number of events reached
-	ErrNothingToUpdate   = errors.New("no changes detected to update")                       // no changes detected to update
-	ErrEventNotFound     = errors.New("event not found")                                     // event not found
-	ErrInvalidEventID    = errors.New("invalid event ID format")                             // invalid event ID format
-	ErrUnauthorized      = errors.New("unauthorized: you cannot modify this event")          // unauthorized: you cannot modify this event
-	ErrMissingParams     = errors.New("missing required parameters: user_id or date")        // missing required parameters: user_id or date
-	ErrMissingEventID    = errors.New("event ID is required")                                // event ID is required
-	ErrInternal          = errors.New("internal server error")                               // internal server error
+	ErrInvalidJSON           = errors.New("invalid JSON format")                                          // invalid JSON format
+	ErrEmptyEventText        = errors.New("event text cannot be empty")                                   // event text cannot be empty
+	ErrMissingDate           = errors.New("event date is required")                                       // event date is required
+	ErrInvalidDateFormat     = errors.New("invalid date format, expected YYYY-MM-DD")                     // invalid date format, expected YYYY-MM-DD
+	ErrEventTextTooLong      = errors.New("event text exceeds maximum length of 500 characters")          // event text exceeds maximum length of 500 characters
+	ErrInvalidUserID         = errors.New("missing or invalid user ID")                                   // missing or invalid user ID
+	ErrEventInPast           = errors.New("event date cannot be in the past")                             // event date cannot be in the past
+	ErrEventTooFar           = errors.New("event date cannot be more than 10 years ahead")                // event date cannot be more than 10 years ahead
+	ErrMaxEvents             = errors.New("maximum number of events reached")                             // maximum number of events reached
+	ErrNothingToUpdate       = errors.New("no changes detected to update")                                // no changes detected to update
+	ErrEventNotFound         = errors.New("event not found")                                              // event not found
+	ErrInvalidEventID        = errors.New("invalid event ID format")                                      // invalid event ID format
+	ErrUnauthorized          = errors.New("unauthorized: you cannot modify this event")                   // unauthorized: you cannot modify this event
+	ErrMissingParams         = errors.New("missing required parameters: user_id or date")                 // missing required parameters: user_id or date
+	ErrMissingEventID        = errors.New("event ID is required")                                         // event ID is required
+	ErrInternal              = errors.New("internal server error")                                        // internal server error
+	ErrRateLimited           = errors.New("rate limit exceeded, try again later")                         // rate limit exceeded, try again later
+	ErrAdminUnauthorized     = errors.New("unauthorized: missing or invalid admin token")                 // unauthorized: missing or invalid admin token
+	ErrNotSupported          = errors.New("operation not supported by the storage backend")               // operation not supported by the storage backend
+	ErrNonWorkingDay         = errors.New("event date falls on a non-working day")                        // event date falls on a non-working day
+	ErrUnknownCountry        = errors.New("no holiday calendar for the country")                          // no holiday calendar for the country
+	ErrInvalidWorkingDay     = errors.New("invalid working days, expected names like mon..sun")           // invalid working days, expected names like mon..sun
+	ErrMissingTenant         = errors.New("tenant is required")                                           // tenant is required
+	ErrInvalidTenant         = errors.New("invalid tenant ID")                                            // invalid tenant ID
+	ErrInvalidToken          = errors.New("unauthorized: missing or invalid bearer token")                // unauthorized: missing or invalid bearer token
+	ErrMissingFile           = errors.New("attachment file is missing or empty")                          // attachment file is missing or empty
+	ErrAttachmentSize        = errors.New("attachment exceeds the maximum allowed size")                  // attachment exceeds the maximum allowed size
+	ErrAttachmentType        = errors.New("attachment type is not allowed")                               // attachment type is not allowed
+	ErrMaxAttachments        = errors.New("maximum number of attachments reached")                        // maximum number of attachments reached
+	ErrAttachmentNotFound    = errors.New("attachment not found")                                         // attachment not found
+	ErrInvalidAttachmentID   = errors.New("invalid attachment ID format")                                 // invalid attachment ID format
+	ErrInvalidWebhookURL     = errors.New("webhook URL must be an absolute http or https URL")            // webhook URL must be an absolute http or https URL
+	ErrWebhookHost           = errors.New("webhook URL must not point to a private or local host")        // webhook URL must not point to a private or local host
+	ErrInvalidWebhookID      = errors.New("invalid webhook ID format")                                    // invalid webhook ID format
+	ErrMaxWebhooks           = errors.New("maximum number of webhooks reached")                           // maximum number of webhooks reached
+	ErrWebhookNotFound       = errors.New("webhook not found")                                            // webhook not found
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")                                   // webhook delivery not found
+	ErrInvalidDeliveryStatus = errors.New("invalid delivery status, expected pending, delivered or dead") // invalid delivery status, expected pending, delivered or dead
+	ErrNotDeadLetter         = errors.New("only dead-lettered deliveries can be retried")                 // only dead-lettered deliveries can be retried
+	ErrEmptyPhrase           = errors.New("quick-add phrase cannot be empty")                             // quick-add phrase cannot be empty
+	ErrNoDateInPhrase        = errors.New("no date or recurrence found in the phrase")                    // no date or recurrence found in the phrase
+	ErrManyDatesInPhrase     = errors.New("the phrase names more than one date or recurrence")            // the phrase names more than one date or recurrence
+	ErrNoSuchDate            = errors.New("the phrase names a date that does not exist")                  // the phrase names a date that does not exist
+	ErrStorageClosed         = errors.New("storage is closed")                                            // storage is closed
 )
@@ -0,0 +1,71 @@
+package admin
+
+// UserDto represents a user and the number of events they own.
+type UserDto struct {
+	UserID int `json:"user_id" example:"1"` // UserID is the ID of the user.
+	Events int `json:"events" example:"3"`  // Events is the number of events owned by the user.
+}
+
+// UsersResponse represents the response containing the list of users.
+type UsersResponse struct {
+	Users []UserDto `json:"users"` // Users is the list of users ordered by ID.
+}
+
+// DeleteUserEventsRequest represents the request body for deleting all events of a user.
+type DeleteUserEventsRequest struct {
+	UserID int `json:"user_id" binding:"required" example:"1"` // UserID is the ID of the user whose events are deleted.
+}
+
+// DeleteUserEventsResponse represents the response returned after deleting a user's events.
+type DeleteUserEventsResponse struct {
+	Deleted int `json:"events_deleted" example:"3"` // Deleted is the number of removed events.
+}
+
+// DayCountDto represents a single bucket of the events per day histogram.
+type DayCountDto struct {
+	Date   string `json:"date" example:"2028-12-04"` // Date is the day in YYYY-MM-DD format.
+	Events int    `json:"events" example:"5"`        // Events is the number of events on that day across all users.
+}
+
+// StatsResponse represents the response containing global storage statistics.
+type StatsResponse struct {
+	Users        int           `json:"users" example:"2"`           // Users is the number of users with at least one event.
+	Events       int           `json:"events" example:"5"`          // Events is the total number of events.
+	Upcoming     int           `json:"upcoming_events" example:"3"` // Upcoming is the number of events dated today or later.
+	SizeBytes    int64         `json:"size_bytes" example:"1024"`   // SizeBytes is the approximate size of the stored events.
+	EventsPerDay []DayCountDto `json:"events_per_day"`              // EventsPerDay is the events per day histogram ordered by date.
+}
+
+// SnapshotResponse represents the response returned after taking a storage snapshot.
+type SnapshotResponse struct {
+	Snapshot bool `json:"snapshot_taken" example:"true"` // Snapshot indicates whether the snapshot was taken.
+}
+
+// CompactResponse represents the response returned after compacting the storage.
+type CompactResponse struct {
+	Compacted bool `json:"storage_compacted" example:"true"` // Compacted indicates whether the storage was compacted.
+}
+
+// ErrorResponse400 represents a standard bad request response.
+type ErrorResponse400 struct {
+	Code    int    `json:"code" example:"400"`                           // Code is the HTTP status code.
+	Message string `json:"message" example:"missing or invalid user ID"` // Message is a human-readable description of the error.
+}
+
+// ErrorResponse401 represents a response to a request without a valid admin token.
+type ErrorResponse401 struct {
+	Code    int    `json:"code" example:"401"`                                             // Code is the HTTP status code.
+	Message string `json:"message" example:"unauthorized: missing or invalid admin token"` // Message is a human-readable description of the error.
+}
+
+// ErrorResponse500 represents a standard internal error response.
+type ErrorResponse500 struct {
+	Code    int    `json:"code" example:"500"`                      // Code is the HTTP status code.
+	Message string `json:"message" example:"internal server error"` // Message is a human-readable description of the error.
+}
+
+// ErrorResponse501 represents a response to an operation the storage backend does not support.
+type ErrorResponse501 struct {
+	Code    int    `json:"code" example:"501"`                                               // Code is the HTTP status code.
+	Message string `json:"message" example:"operation not supported by the storage backend"` // Message is a human-readable description of the error.
+}
@@ -0,0 +1,180 @@
+// Package admin provides the administrative API handlers of the event management system.
+//
+// It defines a Handler struct that wraps the admin service and logger, and exposes
+// HTTP endpoints to inspect users and storage statistics, bulk delete a user's events,
+// and trigger storage maintenance. The endpoints are mounted under /admin and are
+// protected by a bearer token; each method is annotated for Swagger documentation.
+package admin
+
+import (
+	"L2.18/internal/errs"
+	"L2.18/internal/service"
+	"L2.18/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler represents the administrative API handler.
+//
+// It holds references to the admin service and logger.
+type Handler struct {
+	service service.Admin // service performs the storage-wide administrative operations
+	logger  logger.Logger // logger is used to log request processing and errors
+}
+
+// NewHandler creates a new Handler instance with the given admin service and logger.
+//
+// service: the administrative service that the handler will call.
+// logger: structured logger to log request and error information.
+func NewHandler(service service.Admin, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListUsers handles HTTP GET requests to list all users with their event counts.
+//
+// @Summary List users
+// @Description Returns every user owning at least one event together with their event count
+// @Tags admin
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant whose data is managed, used when tenancy is enabled"
+// @Security AdminToken
+// @Success 200 {object} UsersResponse
+// @Failure 401 {object} ErrorResponse401
+// @Failure 500 {object} ErrorResponse500
+// @Router /admin/users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+
+	users, err := h.service.ListUsers(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := UsersResponse{Users: make([]UserDto, len(users))}
+	for i, user := range users {
+		response.Users[i] = UserDto{UserID: user.UserID, Events: user.Events}
+	}
+
+	respondOK(c, response)
+
+}
+
+// DeleteUserEvents handles HTTP POST requests to delete all events of a user.
+//
+// @Summary Delete all events of a user
+// @Description Removes every event owned by the user and returns how many were removed
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant whose data is managed, used when tenancy is enabled"
+// @Security AdminToken
+// @Param request body DeleteUserEventsRequest true "User to purge"
+// @Success 200 {object} DeleteUserEventsResponse
+// @Failure 400 {object} ErrorResponse400
+// @Failure 401 {object} ErrorResponse401
+// @Failure 500 {object} ErrorResponse500
+// @Router /admin/delete_user_events [post]
+func (h *Handler) DeleteUserEvents(c *gin.Context) {
+
+	var request DeleteUserEventsRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	deleted, err := h.service.DeleteUserEvents(c.Request.Context(), request.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, DeleteUserEventsResponse{Deleted: deleted})
+
+}
+
+// Stats handles HTTP GET requests for global storage statistics.
+//
+// @Summary Storage statistics
+// @Description Returns user, event and upcoming event totals, the events per day histogram and the approximate storage size
+// @Tags admin
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant whose data is managed, used when tenancy is enabled"
+// @Security AdminToken
+// @Success 200 {object} StatsResponse
+// @Failure 401 {object} ErrorResponse401
+// @Failure 500 {object} ErrorResponse500
+// @Router /admin/stats [get]
+func (h *Handler) Stats(c *gin.Context) {
+
+	stats, err := h.service.Stats(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := StatsResponse{
+		Users:        stats.Users,
+		Events:       stats.Events,
+		Upcoming:     stats.Upcoming,
+		SizeBytes:    stats.SizeBytes,
+		EventsPerDay: make([]DayCountDto, len(stats.EventsPerDay)),
+	}
+
+	for i, day := range stats.EventsPerDay {
+		response.EventsPerDay[i] = DayCountDto{Date: day.Date.Format("2006-01-02"), Events: day.Events}
+	}
+
+	respondOK(c, response)
+
+}
+
+// Snapshot handles HTTP POST requests to force a storage snapshot.
+//
+// @Summary Force a storage snapshot
+// @Description Makes the storage backend persist a snapshot of its data, if the backend supports it
+// @Tags admin
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant whose data is managed, used when tenancy is enabled"
+// @Security AdminToken
+// @Success 200 {object} SnapshotResponse
+// @Failure 401 {object} ErrorResponse401
+// @Failure 501 {object} ErrorResponse501
+// @Failure 500 {object} ErrorResponse500
+// @Router /admin/snapshot [post]
+func (h *Handler) Snapshot(c *gin.Context) {
+
+	if err := h.service.Snapshot(c.Request.Context()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, SnapshotResponse{Snapshot: true})
+
+}
+
+// Compact handles HTTP POST requests to force a storage compaction.
+//
+// @Summary Force a storage compaction
+// @Description Makes the storage backend reclaim unused space, if the backend supports it
+// @Tags admin
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant whose data is managed, used when tenancy is enabled"
+// @Security AdminToken
+// @Success 200 {object} CompactResponse
+// @Failure 401 {object} ErrorResponse401
+// @Failure 501 {object} ErrorResponse501
+// @Failure 500 {object} ErrorResponse500
+// @Router /admin/compact [post]
+func (h *Handler) Compact(c *gin.Context) {
+
+	if err := h.service.Compact(c.Request.Context()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, CompactResponse{Compacted: true})
+
+}
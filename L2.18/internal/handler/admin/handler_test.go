@@ -0,0 +1,181 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	serviceMock "L2.18/internal/service/mocks"
+	loggerMock "L2.18/pkg/logger/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ListUsers_Success(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockAdmin(controller)
+	testHandler := NewHandler(mockService, loggerMock.NewMockLogger(controller))
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	mockService.EXPECT().ListUsers(gomock.Any()).Return([]models.UserStats{{UserID: 1, Events: 2}, {UserID: 5, Events: 1}}, nil)
+
+	testHandler.ListUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result UsersResponse }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []UserDto{{UserID: 1, Events: 2}, {UserID: 5, Events: 1}}, resp.Result.Users)
+
+}
+
+func TestHandler_DeleteUserEvents_Success(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockAdmin(controller)
+	testHandler := NewHandler(mockService, loggerMock.NewMockLogger(controller))
+
+	gin.SetMode(gin.TestMode)
+
+	body, _ := json.Marshal(DeleteUserEventsRequest{UserID: 3})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockService.EXPECT().DeleteUserEvents(gomock.Any(), 3).Return(4, nil)
+
+	testHandler.DeleteUserEvents(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result DeleteUserEventsResponse }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 4, resp.Result.Deleted)
+
+}
+
+func TestHandler_DeleteUserEvents_ErrInvalidJSON(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	testHandler := NewHandler(serviceMock.NewMockAdmin(controller), loggerMock.NewMockLogger(controller))
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{invalid json}")))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	testHandler.DeleteUserEvents(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrInvalidJSON.Error())
+
+}
+
+func TestHandler_Stats_Success(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockAdmin(controller)
+	testHandler := NewHandler(mockService, loggerMock.NewMockLogger(controller))
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	day := time.Date(2028, 12, 4, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().Stats(gomock.Any()).Return(models.Stats{
+		Users:        1,
+		Events:       2,
+		SizeBytes:    300,
+		EventsPerDay: []models.DayCount{{Date: day, Events: 2}},
+	}, nil)
+
+	testHandler.Stats(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result StatsResponse }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, StatsResponse{Users: 1, Events: 2, SizeBytes: 300, EventsPerDay: []DayCountDto{{Date: "2028-12-04", Events: 2}}}, resp.Result)
+
+}
+
+func TestHandler_Maintenance(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockAdmin(controller)
+	testHandler := NewHandler(mockService, loggerMock.NewMockLogger(controller))
+
+	gin.SetMode(gin.TestMode)
+
+	mockService.EXPECT().Snapshot(gomock.Any()).Return(errs.ErrNotSupported)
+	mockService.EXPECT().Compact(gomock.Any()).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+
+	testHandler.Snapshot(c)
+	assertErrorResponse(t, w, http.StatusNotImplemented, errs.ErrNotSupported.Error())
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+
+	testHandler.Compact(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+}
+
+func TestMapErrorToStatus(t *testing.T) {
+
+	status, msg := mapErrorToStatus(errs.ErrInvalidUserID)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errs.ErrInvalidUserID.Error(), msg)
+
+	status, msg = mapErrorToStatus(errs.ErrStorageClosed)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, errs.ErrStorageClosed.Error(), msg)
+
+	status, msg = mapErrorToStatus(errors.New("boom"))
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, errs.ErrInternal.Error(), msg)
+
+}
+
+func assertErrorResponse(t *testing.T, w *httptest.ResponseRecorder, wantStatus int, wantMsg string) {
+	t.Helper()
+	assert.Equal(t, wantStatus, w.Code)
+	var resp map[string]string
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, wantMsg, resp["error"])
+}
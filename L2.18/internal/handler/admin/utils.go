@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"L2.18/internal/errs"
+	"github.com/gin-gonic/gin"
+)
+
+// respondOK sends a successful JSON response to the client.
+//
+// c: Gin context
+// response: the response payload to send
+func respondOK(c *gin.Context, response any) {
+	c.JSON(http.StatusOK, gin.H{"result": response})
+}
+
+// respondError sends an error JSON response to the client based on the error type.
+//
+// c: Gin context
+// err: the error to map and send
+func respondError(c *gin.Context, err error) {
+	if err != nil {
+		status, msg := mapErrorToStatus(err)
+		c.AbortWithStatusJSON(status, gin.H{"error": msg})
+	}
+}
+
+// mapErrorToStatus maps application errors to HTTP status codes and messages.
+//
+// err: the error to map
+//
+// Returns:
+// - HTTP status code
+// - error message string to send in response
+func mapErrorToStatus(err error) (int, string) {
+
+	switch {
+
+	case errors.Is(err, errs.ErrInvalidJSON),
+		errors.Is(err, errs.ErrInvalidUserID):
+		return http.StatusBadRequest, err.Error()
+
+	case errors.Is(err, errs.ErrNotSupported):
+		return http.StatusNotImplemented, err.Error()
+
+	case errors.Is(err, errs.ErrStorageClosed):
+		return http.StatusServiceUnavailable, err.Error()
+
+	default:
+		return http.StatusInternalServerError, errs.ErrInternal.Error()
+
+	}
+
+}
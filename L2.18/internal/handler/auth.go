@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"L2.18/internal/errs"
+	"github.com/gin-gonic/gin"
+)
+
+// adminAuth creates a Gin middleware that admits only requests carrying the admin
+// token as "Authorization: Bearer <token>" and rejects the rest with 401 Unauthorized.
+//
+// Tokens are compared in constant time so response timing reveals nothing about them.
+func adminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": errs.ErrAdminUnauthorized.Error()})
+			return
+		}
+		c.Next()
+	}
+}
@@ -7,58 +7,126 @@ import (
 	"net/http"
 	"time"
 
+	"L2.18/internal/config"
+	adminapi "L2.18/internal/handler/admin"
 	v1 "L2.18/internal/handler/v1"
 	"L2.18/internal/service"
 	"L2.18/pkg/logger"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer creates the handler layer spans.
+var tracer = tracing.Tracer("L2.18/internal/handler")
+
 // NewHandler creates and configures the HTTP handler for the application.
 //
-// It sets up the Gin engine, registers middleware, API v1 routes, and the
-// Swagger documentation endpoint.
+// It sets up the Gin engine, registers middleware (including security headers and CORS,
+// which answers browser preflight requests before rate limiting), API v1 routes, the
+// token-protected admin routes, and the Swagger documentation endpoint. The admin routes
+// are registered only when an admin token is configured.
+//
+// API v1 requests are assigned a tenant from the configured source. Admin requests carry
+// the admin token in the Authorization header, so their tenant is always read from the
+// tenant header.
 //
 // Parameters:
 // - service: the service layer instance that provides business logic
+// - admin: the administrative service backing the /admin endpoints
+// - limiter: per-client rate limiter applied to every request
+// - config: application configuration; the admin, tenancy, CORS and security headers sections and the trusted proxies are used
 // - logger: logger instance to log requests and errors
 //
 // Returns:
 // - http.Handler instance ready to be served by a HTTP server
-func NewHandler(service service.Service, logger logger.Logger) http.Handler {
+func NewHandler(service service.Service, admin service.Admin, limiter *Limiter, config config.App, logger logger.Logger) http.Handler {
 
 	handler := gin.New()
 
+	// Clients could otherwise pick the IP they are rate limited and logged by with X-Forwarded-For
+	if err := handler.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		logger.LogError("handler — invalid trusted proxies, trusting none", err, "layer", "handler")
+		_ = handler.SetTrustedProxies(nil)
+	}
+
 	handler.Use(gin.Recovery())
 	handler.Use(middleware(logger))
+	handler.Use(securityHeaders(config.Headers))
+	handler.Use(cors(config.CORS))
+	handler.Use(rateLimit(limiter))
 
-	apiV1 := handler.Group("/api/v1")
+	apiV1 := handler.Group("/api/v1", resolveTenant(config.Tenancy))
 	handlerV1 := v1.NewHandler(service, logger)
 
 	apiV1.POST("/create_event", handlerV1.CreateEvent)
 	apiV1.POST("/update_event", handlerV1.UpdateEvent)
 	apiV1.POST("/delete_event", handlerV1.DeleteEvent)
+	apiV1.POST("/quick_add", handlerV1.QuickAdd)
 
 	apiV1.GET("/events_for_day", handlerV1.GetEventsDay)
 	apiV1.GET("/events_for_week", handlerV1.GetEventsWeek)
 	apiV1.GET("/events_for_month", handlerV1.GetEventsMonth)
 
+	apiV1.POST("/update_settings", handlerV1.UpdateSettings)
+	apiV1.GET("/settings", handlerV1.GetSettings)
+
+	apiV1.POST("/upload_attachment", handlerV1.UploadAttachment)
+	apiV1.GET("/attachments", handlerV1.GetAttachments)
+	apiV1.GET("/download_attachment", handlerV1.DownloadAttachment)
+	apiV1.POST("/delete_attachment", handlerV1.DeleteAttachment)
+
+	apiV1.POST("/create_webhook", handlerV1.CreateWebhook)
+	apiV1.GET("/webhooks", handlerV1.GetWebhooks)
+	apiV1.POST("/delete_webhook", handlerV1.DeleteWebhook)
+	apiV1.GET("/webhook_deliveries", handlerV1.GetDeliveries)
+	apiV1.POST("/retry_delivery", handlerV1.RetryDelivery)
+
+	if config.Admin.Token != "" {
+
+		adminTenancy := config.Tenancy
+		adminTenancy.Source = "header"
+
+		adminAPI := handler.Group("/admin", adminAuth(config.Admin.Token), resolveTenant(adminTenancy))
+		handlerAdmin := adminapi.NewHandler(admin, logger)
+
+		adminAPI.GET("/users", handlerAdmin.ListUsers)
+		adminAPI.GET("/stats", handlerAdmin.Stats)
+
+		adminAPI.POST("/delete_user_events", handlerAdmin.DeleteUserEvents)
+		adminAPI.POST("/snapshot", handlerAdmin.Snapshot)
+		adminAPI.POST("/compact", handlerAdmin.Compact)
+
+	} else {
+		logger.LogInfo("handler — admin token not set, admin API disabled", "layer", "handler")
+	}
+
 	handler.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	return handler
 
 }
 
-// middleware creates a Gin middleware that logs incoming HTTP requests and their outcomes.
+// middleware creates a Gin middleware that traces and logs incoming HTTP requests and their outcomes.
 //
-// It generates a request ID, measures request latency, and logs request details
-// including method, path, query string, client IP, HTTP status, user agent, and Gin errors.
+// It generates a request ID, continues the trace from an incoming W3C traceparent header
+// (or starts a new one), and opens a server span whose context, together with the request ID,
+// is passed down to the service and storage layers through the request context. The trace
+// context is written back in the response headers. It measures request latency and logs
+// request details including method, path, query string, client IP, HTTP status, user agent,
+// tenant, trace ID, and Gin errors.
 //
 // Logging behavior based on HTTP status:
 // - 500: LogError
-// - 400, 503: LogWarn
+// - 400, 401, 403, 413, 415, 429, 501, 503: LogWarn
 // - others: LogInfo
 //
 // Parameters:
@@ -78,11 +146,38 @@ func middleware(logger logger.Logger) gin.HandlerFunc {
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		spanName := c.FullPath()
+		if spanName == "" {
+			spanName = "unmatched route"
+		}
+
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("url.path", path),
+				attribute.String("client.address", c.ClientIP()),
+			),
+		)
+		defer span.End()
+
+		ctx = tracing.WithRequestID(ctx, requestID)
+		c.Request = c.Request.WithContext(ctx)
+		propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
+
 		c.Next()
 
 		latency := time.Since(start)
 		status := c.Writer.Status()
 
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
 		fields := []any{
 			"request_id", requestID,
 			"method", c.Request.Method,
@@ -93,16 +188,21 @@ func middleware(logger logger.Logger) gin.HandlerFunc {
 			"query", query,
 			"proto", c.Request.Proto,
 			"user_agent", c.Request.UserAgent(),
+			"tenant", tenant.ID(c.Request.Context()),
 			"gin_errors", c.Errors.ByType(gin.ErrorTypePrivate).String(),
 			"layer", "handler",
 		}
 
+		if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+			fields = append(fields, "trace_id", spanContext.TraceID().String())
+		}
+
 		msg := fmt.Sprintf("handler — received %s request to %s", c.Request.Method, path)
 
 		switch status {
 		case 500:
 			logger.LogError(msg, nil, fields...)
-		case 400, 503:
+		case 400, 401, 403, 413, 415, 429, 501, 503:
 			logger.LogWarn(msg, fields...)
 		default:
 			logger.LogInfo(msg, fields...)
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/models"
+	serviceMock "L2.18/internal/service/mocks"
+	loggerMock "L2.18/pkg/logger/mocks"
+	"L2.18/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestMiddleware_PropagatesTraceContext(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	_, err := tracing.Init(config.Tracing{})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	gin.SetMode(gin.TestMode)
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockLogger.EXPECT().LogInfo("handler — admin token not set, admin API disabled", "layer", "handler").Times(1)
+	mockLogger.EXPECT().LogInfo("handler — received GET request to /api/v1/events_for_day", gomock.Any()).Times(1)
+
+	var serviceCtx context.Context
+	mockService.EXPECT().GetEvents(gomock.Any(), gomock.Any(), models.Day).DoAndReturn(
+		func(ctx context.Context, _ *models.Meta, _ models.Period) ([]models.Event, error) {
+			serviceCtx = ctx
+			return nil, nil
+		})
+
+	handler := NewHandler(mockService, serviceMock.NewMockAdmin(controller), NewLimiter(config.RateLimit{}), config.App{}, mockLogger)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	date := time.Now().UTC().Add(24 * time.Hour).Format("2006-01-02")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/events_for_day?user_id=1&date="+date, nil)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	handler.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Header().Get("traceparent"), traceID)
+
+	require.NotNil(t, serviceCtx)
+	require.NotEmpty(t, tracing.RequestID(serviceCtx))
+	require.Equal(t, traceID, trace.SpanContextFromContext(serviceCtx).TraceID().String())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /api/v1/events_for_day", spans[0].Name())
+	require.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+
+}
+
+func TestAdminAuth(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	mockAdmin := serviceMock.NewMockAdmin(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockLogger.EXPECT().LogWarn("handler — received GET request to /admin/users", gomock.Any()).Times(2)
+	mockLogger.EXPECT().LogInfo("handler — received GET request to /admin/users", gomock.Any()).Times(1)
+
+	mockAdmin.EXPECT().ListUsers(gomock.Any()).Return(nil, nil).Times(1)
+
+	handler := NewHandler(serviceMock.NewMockService(controller), mockAdmin, NewLimiter(config.RateLimit{}), config.App{Admin: config.Admin{Token: "secret"}}, mockLogger)
+
+	for header, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		handler.ServeHTTP(w, r)
+		require.Equal(t, want, w.Code, "Authorization: %q", header)
+	}
+
+}
+
+func TestAdminDisabledWithoutToken(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockLogger.EXPECT().LogInfo("handler — admin token not set, admin API disabled", "layer", "handler").Times(1)
+	mockLogger.EXPECT().LogInfo("handler — received GET request to /admin/users", gomock.Any()).Times(1)
+
+	handler := NewHandler(serviceMock.NewMockService(controller), serviceMock.NewMockAdmin(controller), NewLimiter(config.RateLimit{}), config.App{}, mockLogger)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+}
+
+func TestRateLimit_ForwardedFor(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	// send makes a request from the proxy or client at 203.0.113.7 on behalf of forwardedFor.
+	send := func(handler http.Handler, forwardedFor string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		r.RemoteAddr = "203.0.113.7:40000"
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	newHandler := func(trustedProxies []string) http.Handler {
+		controller := gomock.NewController(t)
+		mockLogger := loggerMock.NewMockLogger(controller)
+		mockLogger.EXPECT().LogInfo(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().LogWarn(gomock.Any(), gomock.Any()).AnyTimes()
+		limiter := NewLimiter(config.RateLimit{Enabled: true, RequestsPerSecond: 0.001, Burst: 1})
+		return NewHandler(serviceMock.NewMockService(controller), serviceMock.NewMockAdmin(controller), limiter, config.App{Server: config.Server{TrustedProxies: trustedProxies}}, mockLogger)
+	}
+
+	untrusted := newHandler(nil)
+	require.Equal(t, http.StatusNotFound, send(untrusted, "198.51.100.1"))
+	require.Equal(t, http.StatusTooManyRequests, send(untrusted, "198.51.100.2"), "a spoofed X-Forwarded-For must not get a fresh bucket")
+	require.Equal(t, http.StatusTooManyRequests, send(untrusted, "198.51.100.3"))
+
+	trusted := newHandler([]string{"203.0.113.0/24"})
+	require.Equal(t, http.StatusNotFound, send(trusted, "198.51.100.1"))
+	require.Equal(t, http.StatusNotFound, send(trusted, "198.51.100.2"), "clients behind a trusted proxy are limited separately")
+	require.Equal(t, http.StatusTooManyRequests, send(trusted, "198.51.100.1"))
+
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"github.com/gin-gonic/gin"
+)
+
+// idleBucketTTL is how long a client bucket may stay unused before it is evicted.
+const idleBucketTTL = 10 * time.Minute
+
+// Limiter is a per-client token bucket rate limiter.
+//
+// Each client IP gets a bucket refilled at RequestsPerSecond up to Burst tokens; every
+// request consumes one token. The settings can be replaced at runtime with Reconfigure,
+// which applies them atomically to all subsequent requests.
+type Limiter struct {
+	settings  atomic.Pointer[config.RateLimit] // current rate limit settings
+	buckets   map[string]*bucket               // client IP -> token bucket
+	lastSweep time.Time                        // last eviction of idle buckets
+	now       func() time.Time                 // clock used for refills
+	mu        sync.Mutex                       // protects buckets and lastSweep
+}
+
+// bucket holds the token state of a single client.
+type bucket struct {
+	tokens   float64   // tokens currently available
+	lastSeen time.Time // time of the last refill
+}
+
+// NewLimiter creates a new Limiter with the provided settings.
+func NewLimiter(config config.RateLimit) *Limiter {
+	limiter := &Limiter{buckets: make(map[string]*bucket), now: time.Now}
+	limiter.lastSweep = limiter.now()
+	limiter.Reconfigure(config)
+	return limiter
+}
+
+// Reconfigure atomically replaces the rate limit settings.
+func (l *Limiter) Reconfigure(config config.RateLimit) {
+	l.settings.Store(&config)
+}
+
+// Allow reports whether a request from the given client may proceed, consuming a token if so.
+func (l *Limiter) Allow(client string) bool {
+
+	settings := l.settings.Load()
+	if !settings.Enabled {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, found := l.buckets[client]
+	if !found {
+		b = &bucket{tokens: float64(settings.Burst), lastSeen: now}
+		l.buckets[client] = b
+	}
+
+	b.tokens = min(float64(settings.Burst), b.tokens+now.Sub(b.lastSeen).Seconds()*settings.RequestsPerSecond)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+
+}
+
+// sweep evicts buckets that have not been used for idleBucketTTL, at most once per TTL.
+// Must be called with mu held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleBucketTTL {
+		return
+	}
+	for client, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= idleBucketTTL {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimit creates a Gin middleware that rejects requests exceeding the client's
+// rate limit with 429 Too Many Requests.
+func rateLimit(limiter *Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.Allow(c.ClientIP()) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": errs.ErrRateLimited.Error()})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+
+	limiter := NewLimiter(config.RateLimit{Enabled: true, RequestsPerSecond: 1, Burst: 2})
+
+	clock := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return clock }
+	limiter.lastSweep = clock
+
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.False(t, limiter.Allow("10.0.0.1"))
+	require.True(t, limiter.Allow("10.0.0.2"))
+
+	clock = clock.Add(time.Second)
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.False(t, limiter.Allow("10.0.0.1"))
+
+	clock = clock.Add(idleBucketTTL)
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.Len(t, limiter.buckets, 1)
+
+}
+
+func TestLimiter_Reconfigure(t *testing.T) {
+
+	limiter := NewLimiter(config.RateLimit{})
+
+	for range 100 {
+		require.True(t, limiter.Allow("10.0.0.1"))
+	}
+
+	limiter.Reconfigure(config.RateLimit{Enabled: true, RequestsPerSecond: 1, Burst: 1})
+
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.False(t, limiter.Allow("10.0.0.1"))
+
+}
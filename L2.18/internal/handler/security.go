@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"L2.18/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerPrefix is the path of the Swagger UI, whose pages need scripts and styles and
+// so are served without the Content-Security-Policy of the API.
+const swaggerPrefix = "/swagger/"
+
+// cors creates a Gin middleware implementing cross-origin resource sharing for the
+// configured origins.
+//
+// Requests from an allowed origin get Access-Control-Allow-Origin and the related
+// headers; preflight requests (OPTIONS with Access-Control-Request-Method) are answered
+// with 204 No Content and the allowed methods and headers, or with 403 Forbidden if the
+// origin or method is not allowed. Requests without an Origin header, and all requests
+// when CORS is disabled, pass through untouched.
+func cors(config config.CORS) gin.HandlerFunc {
+
+	if !config.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	anyOrigin := slices.Contains(config.AllowedOrigins, "*")
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	exposed := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+
+		requested := c.GetHeader("Access-Control-Request-Method")
+		preflight := c.Request.Method == http.MethodOptions && requested != ""
+		allowed := anyOrigin || containsFold(config.AllowedOrigins, origin)
+
+		if preflight && !(allowed && containsFold(config.AllowedMethods, requested)) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		if !allowed {
+			c.Next()
+			return
+		}
+
+		if anyOrigin && !config.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposed != "" {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		c.Header("Access-Control-Allow-Methods", methods)
+		if headers != "" {
+			c.Header("Access-Control-Allow-Headers", headers)
+		}
+		c.Header("Access-Control-Max-Age", maxAge)
+		c.AbortWithStatus(http.StatusNoContent)
+
+	}
+
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(item string) bool { return strings.EqualFold(item, s) })
+}
+
+// securityHeaders creates a Gin middleware adding the configured security headers to
+// every response: X-Content-Type-Options, X-Frame-Options, Referrer-Policy, a
+// Content-Security-Policy (except for the Swagger UI) and, over TLS only,
+// Strict-Transport-Security. Empty values are omitted.
+func securityHeaders(config config.Headers) gin.HandlerFunc {
+
+	if !config.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	hsts := ""
+	if config.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(config.HSTSMaxAge.Seconds()))
+	}
+
+	return func(c *gin.Context) {
+
+		header := c.Writer.Header()
+
+		header.Set("X-Content-Type-Options", "nosniff")
+		if config.FrameOptions != "" {
+			header.Set("X-Frame-Options", config.FrameOptions)
+		}
+		if config.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", config.ReferrerPolicy)
+		}
+		if config.ContentSecurityPolicy != "" && !strings.HasPrefix(c.Request.URL.Path, swaggerPrefix) {
+			header.Set("Content-Security-Policy", config.ContentSecurityPolicy)
+		}
+		if hsts != "" && c.Request.TLS != nil {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+
+		c.Next()
+
+	}
+
+}
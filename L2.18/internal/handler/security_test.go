@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// securityEngine returns a Gin engine applying the security middleware to a POST route
+// and the Swagger path, answering both with 200 OK.
+func securityEngine(corsConfig config.CORS, headers config.Headers) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(securityHeaders(headers), cors(corsConfig))
+	engine.POST("/api/v1/create_event", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/swagger/*any", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return engine
+}
+
+var testCORS = config.CORS{
+	Enabled:        true,
+	AllowedOrigins: []string{"https://calendar.example.com"},
+	AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+	AllowedHeaders: []string{"Content-Type", "X-Tenant-ID"},
+	ExposedHeaders: []string{"traceparent"},
+	MaxAge:         10 * time.Minute,
+}
+
+func TestCORS(t *testing.T) {
+
+	anyOrigin := testCORS
+	anyOrigin.AllowedOrigins = []string{"*"}
+
+	withCredentials := testCORS
+	withCredentials.AllowCredentials = true
+
+	testCases := []struct {
+		name        string
+		config      config.CORS
+		method      string
+		origin      string
+		preflight   string
+		wantStatus  int
+		wantOrigin  string
+		wantMethods string
+	}{
+		{"disabled", config.CORS{}, http.MethodPost, "https://calendar.example.com", "", http.StatusOK, "", ""},
+		{"same origin", testCORS, http.MethodPost, "", "", http.StatusOK, "", ""},
+		{"allowed origin", testCORS, http.MethodPost, "https://Calendar.example.com", "", http.StatusOK, "https://Calendar.example.com", ""},
+		{"foreign origin", testCORS, http.MethodPost, "https://evil.example.com", "", http.StatusOK, "", ""},
+		{"any origin", anyOrigin, http.MethodPost, "https://evil.example.com", "", http.StatusOK, "*", ""},
+		{"credentials", withCredentials, http.MethodPost, "https://calendar.example.com", "", http.StatusOK, "https://calendar.example.com", ""},
+		{"preflight", testCORS, http.MethodOptions, "https://calendar.example.com", "POST", http.StatusNoContent, "https://calendar.example.com", "GET, POST, OPTIONS"},
+		{"preflight of foreign origin", testCORS, http.MethodOptions, "https://evil.example.com", "POST", http.StatusForbidden, "", ""},
+		{"preflight of disallowed method", testCORS, http.MethodOptions, "https://calendar.example.com", "DELETE", http.StatusForbidden, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			r := httptest.NewRequest(tc.method, "/api/v1/create_event", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+			if tc.preflight != "" {
+				r.Header.Set("Access-Control-Request-Method", tc.preflight)
+			}
+
+			w := httptest.NewRecorder()
+			securityEngine(tc.config, config.Headers{}).ServeHTTP(w, r)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			require.Equal(t, tc.wantOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			require.Equal(t, tc.wantMethods, w.Header().Get("Access-Control-Allow-Methods"))
+
+			if tc.wantMethods != "" {
+				require.Equal(t, "Content-Type, X-Tenant-ID", w.Header().Get("Access-Control-Allow-Headers"))
+				require.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+			} else if tc.wantOrigin != "" {
+				require.Equal(t, "traceparent", w.Header().Get("Access-Control-Expose-Headers"))
+			}
+
+			require.Equal(t, tc.config.AllowCredentials && tc.wantOrigin != "", w.Header().Get("Access-Control-Allow-Credentials") == "true")
+			if tc.config.Enabled && tc.origin != "" {
+				require.Contains(t, w.Header().Values("Vary"), "Origin")
+			}
+
+		})
+	}
+
+}
+
+func TestSecurityHeaders(t *testing.T) {
+
+	headers := config.Headers{
+		Enabled:               true,
+		ContentSecurityPolicy: "default-src 'none'",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+		HSTSMaxAge:            time.Hour,
+	}
+
+	serve := func(headers config.Headers, path string, overTLS bool) http.Header {
+		method := http.MethodPost
+		if path != "/api/v1/create_event" {
+			method = http.MethodGet
+		}
+		r := httptest.NewRequest(method, path, nil)
+		if overTLS {
+			r.TLS = &tls.ConnectionState{}
+		}
+		w := httptest.NewRecorder()
+		securityEngine(config.CORS{}, headers).ServeHTTP(w, r)
+		return w.Header()
+	}
+
+	plain := serve(headers, "/api/v1/create_event", false)
+	require.Equal(t, "nosniff", plain.Get("X-Content-Type-Options"))
+	require.Equal(t, "DENY", plain.Get("X-Frame-Options"))
+	require.Equal(t, "no-referrer", plain.Get("Referrer-Policy"))
+	require.Equal(t, "default-src 'none'", plain.Get("Content-Security-Policy"))
+	require.Empty(t, plain.Get("Strict-Transport-Security"), "HSTS is only sent over TLS")
+
+	secure := serve(headers, "/api/v1/create_event", true)
+	require.Equal(t, "max-age=3600", secure.Get("Strict-Transport-Security"))
+
+	swagger := serve(headers, "/swagger/index.html", false)
+	require.Equal(t, "nosniff", swagger.Get("X-Content-Type-Options"))
+	require.Empty(t, swagger.Get("Content-Security-Policy"))
+
+	disabled := serve(config.Headers{ContentSecurityPolicy: "default-src 'none'"}, "/api/v1/create_event", true)
+	require.Empty(t, disabled.Get("X-Content-Type-Options"))
+	require.Empty(t, disabled.Get("Content-Security-Policy"))
+
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"L2.18/pkg/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// resolveTenant creates a Gin middleware that determines the tenant of each request and
+// stores it in the request context, where the service and storage layers pick it up.
+//
+// Depending on the configured source, the tenant ID is read from a request header, which
+// must be set by a trusted gateway, or from a claim of an HS256-signed bearer token.
+// Requests that do not name a tenant belong to the default tenant; if there is none they
+// are rejected with 400 Bad Request, as are malformed tenant IDs. Missing, invalid or
+// expired tokens are rejected with 401 Unauthorized.
+//
+// When tenancy is disabled, every request belongs to tenant.Default.
+func resolveTenant(config config.Tenancy) gin.HandlerFunc {
+
+	if !config.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	secret := []byte(config.Secret)
+
+	return func(c *gin.Context) {
+
+		var id string
+
+		if config.Source == "claim" {
+			token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+			claim, err := tokenClaim(token, secret, config.Claim, time.Now())
+			if !found || err != nil {
+				c.Header("WWW-Authenticate", `Bearer realm="calendar"`)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": errs.ErrInvalidToken.Error()})
+				return
+			}
+			id = claim
+		} else {
+			id = c.GetHeader(config.Header)
+		}
+
+		id = tenant.Normalize(id)
+		if id == "" {
+			id = config.Default
+		}
+
+		if id == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": errs.ErrMissingTenant.Error()})
+			return
+		}
+		if !tenant.Valid(id) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": errs.ErrInvalidTenant.Error()})
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), id))
+		c.Next()
+
+	}
+
+}
+
+// tokenClaim verifies an HS256-signed JWT and returns the value of its string claim.
+//
+// Only the HS256 algorithm is accepted, so unsigned ("alg": "none") tokens are rejected.
+// The registered exp and nbf claims are honoured when present. An empty string is returned,
+// without an error, if the token is valid but lacks the claim.
+func tokenClaim(token string, secret []byte, claim string, now time.Time) (string, error) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", err
+	}
+	if header.Alg != "HS256" {
+		return "", errors.New("unsupported signing algorithm " + header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("invalid token signature")
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", err
+	}
+
+	if exp, ok := claims["exp"].(float64); ok && !now.Before(time.Unix(int64(exp), 0)) {
+		return "", errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0)) {
+		return "", errors.New("token not valid yet")
+	}
+
+	value, _ := claims[claim].(string)
+
+	return value, nil
+
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a JWT into v.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
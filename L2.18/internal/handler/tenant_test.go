@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// signToken builds an HS256 JWT with the given header and payload JSON.
+func signToken(header, payload string, secret []byte) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(payload))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// tenantEngine returns a Gin engine that resolves the tenant and echoes it back.
+func tenantEngine(config config.Tenancy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", resolveTenant(config), func(c *gin.Context) {
+		c.String(http.StatusOK, tenant.ID(c.Request.Context()))
+	})
+	return engine
+}
+
+func TestResolveTenant_Header(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		config     config.Tenancy
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"disabled", config.Tenancy{Header: "X-Tenant-ID"}, "team-a", http.StatusOK, tenant.Default},
+		{"from header", config.Tenancy{Enabled: true, Source: "header", Header: "X-Tenant-ID"}, "Team-A", http.StatusOK, "team-a"},
+		{"default tenant", config.Tenancy{Enabled: true, Source: "header", Header: "X-Tenant-ID", Default: "shared"}, "", http.StatusOK, "shared"},
+		{"missing tenant", config.Tenancy{Enabled: true, Source: "header", Header: "X-Tenant-ID"}, "", http.StatusBadRequest, ""},
+		{"invalid tenant", config.Tenancy{Enabled: true, Source: "header", Header: "X-Tenant-ID"}, "team/a", http.StatusBadRequest, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set("X-Tenant-ID", tc.header)
+			}
+
+			w := httptest.NewRecorder()
+			tenantEngine(tc.config).ServeHTTP(w, r)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			if tc.wantStatus == http.StatusOK {
+				require.Equal(t, tc.wantTenant, w.Body.String())
+			}
+
+		})
+	}
+
+}
+
+func TestResolveTenant_Claim(t *testing.T) {
+
+	secret := []byte("s3cr3t")
+	header := `{"alg":"HS256","typ":"JWT"}`
+	expired := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	testCases := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantTenant string
+	}{
+		{"valid token", signToken(header, `{"sub":"1","tenant":"team-b"}`, secret), http.StatusOK, "team-b"},
+		{"token without claim", signToken(header, `{"sub":"1"}`, secret), http.StatusOK, "shared"},
+		{"wrong secret", signToken(header, `{"tenant":"team-b"}`, []byte("other")), http.StatusUnauthorized, ""},
+		{"expired token", signToken(header, `{"tenant":"team-b","exp":`+expired+`}`, secret), http.StatusUnauthorized, ""},
+		{"unsigned token", signToken(`{"alg":"none"}`, `{"tenant":"team-b"}`, secret), http.StatusUnauthorized, ""},
+		{"malformed token", "not-a-token", http.StatusUnauthorized, ""},
+		{"missing token", "", http.StatusUnauthorized, ""},
+	}
+
+	engine := tenantEngine(config.Tenancy{Enabled: true, Source: "claim", Header: "X-Tenant-ID", Claim: "tenant", Secret: string(secret), Default: "shared"})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-Tenant-ID", "team-a")
+			if tc.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, r)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			if tc.wantStatus == http.StatusOK {
+				require.Equal(t, tc.wantTenant, w.Body.String())
+			}
+
+		})
+	}
+
+}
@@ -9,7 +9,8 @@ type CreateRequestV1 struct {
 
 // CreateResponseV1 represents the response returned after creating an event.
 type CreateResponseV1 struct {
-	EventID string `json:"event_id" example:"3383503d-fb71-4b8c-85bd-a914c84252a9"` // EventID is the unique identifier of the newly created event.
+	EventID string `json:"event_id" example:"3383503d-fb71-4b8c-85bd-a914c84252a9"`                                // EventID is the unique identifier of the newly created event.
+	Warning string `json:"warning,omitempty" example:"event date falls on a non-working day: 2028-12-03 (Sunday)"` // Warning is set when the event was created on a non-working day.
 }
 
 // UpdateRequestV1 represents the request body for updating an existing event.
@@ -22,7 +23,8 @@ type UpdateRequestV1 struct {
 
 // UpdateResponseV1 represents the response returned after updating an event.
 type UpdateResponseV1 struct {
-	Updated bool `json:"event_updated" example:"true"` // Updated indicates whether the event was successfully updated.
+	Updated bool   `json:"event_updated" example:"true"`                                                           // Updated indicates whether the event was successfully updated.
+	Warning string `json:"warning,omitempty" example:"event date falls on a non-working day: 2028-12-03 (Sunday)"` // Warning is set when the event was moved to a non-working day.
 }
 
 // DeleteRequestV1 represents the request body for deleting an existing event.
@@ -36,16 +38,167 @@ type DeleteResponseV1 struct {
 	Deleted bool `json:"event_deleted" example:"true"` // Deleted indicates whether the event was successfully deleted.
 }
 
+// QuickAddRequestV1 represents the request body for adding an event from a free-form phrase.
+type QuickAddRequestV1 struct {
+	UserID        int    `json:"user_id" binding:"required" example:"1"`                       // UserID is the ID of the user who owns the event.
+	Text          string `json:"text" binding:"required" example:"lunch with Ann next friday"` // Text is the phrase in English or Russian naming the event and its date.
+	ReferenceDate string `json:"reference_date,omitempty" example:"2028-11-29"`                // ReferenceDate is the client's today in YYYY-MM-DD format that relative dates are resolved against; defaults to today in UTC.
+	Confirm       bool   `json:"confirm" example:"false"`                                      // Confirm creates the event; otherwise only the interpretation is returned.
+}
+
+// RecurrenceDtoV1 represents how an event read from a phrase repeats.
+type RecurrenceDtoV1 struct {
+	Frequency string `json:"frequency" example:"weekly" enums:"daily,weekly,monthly,yearly"` // Frequency is how often the event repeats.
+	Weekday   string `json:"weekday,omitempty" example:"fri"`                                // Weekday is the day of the week of weekly events.
+}
+
+// QuickAddResponseV1 represents the interpretation of a phrase, and the event created from it if confirmed.
+type QuickAddResponseV1 struct {
+	Date       string           `json:"date" example:"2028-12-08"`                                                              // Date is the event date, or the first occurrence of a recurring event, in YYYY-MM-DD format.
+	Text       string           `json:"text" example:"lunch with Ann"`                                                          // Text is the phrase without the words naming the date.
+	Recurrence *RecurrenceDtoV1 `json:"recurrence,omitempty"`                                                                   // Recurrence is set when the phrase names a repeating event.
+	Upcoming   []string         `json:"upcoming,omitempty" example:"2028-12-08,2028-12-15" swaggertype:"array,string"`          // Upcoming lists the next dates of a recurring event, starting with Date.
+	Language   string           `json:"language" example:"en" enums:"en,ru"`                                                    // Language is the language the date was read in.
+	Matched    string           `json:"matched" example:"next friday"`                                                          // Matched is the part of the phrase read as the date and recurrence.
+	Created    bool             `json:"event_created" example:"false"`                                                          // Created indicates whether the event was created.
+	EventID    string           `json:"event_id,omitempty" example:"3383503d-fb71-4b8c-85bd-a914c84252a9"`                      // EventID is the unique identifier of the created event.
+	Warning    string           `json:"warning,omitempty" example:"event date falls on a non-working day: 2028-12-03 (Sunday)"` // Warning is set when the date is a non-working day.
+}
+
 // EventDtoV1 represents an event in responses containing event info.
 type EventDtoV1 struct {
-	Text      string `json:"text" example:"Touch grass"`                              // Text is the description of the event.
-	EventDate string `json:"date" example:"2028-12-04"`                               // EventDate is the date of the event in YYYY-MM-DD format.
-	EventID   string `json:"event_id" example:"3383503d-fb71-4b8c-85bd-a914c84252a9"` // EventID is the unique identifier of the event.
+	Text          string `json:"text" example:"Touch grass"`                              // Text is the description of the event.
+	EventDate     string `json:"date" example:"2028-12-04"`                               // EventDate is the date of the event in YYYY-MM-DD format.
+	EventID       string `json:"event_id" example:"3383503d-fb71-4b8c-85bd-a914c84252a9"` // EventID is the unique identifier of the event.
+	Holiday       string `json:"holiday,omitempty" example:"Christmas Day"`               // Holiday is the name of the holiday on the event date, if any.
+	NonWorkingDay bool   `json:"non_working_day,omitempty" example:"true"`                // NonWorkingDay indicates that the event date is not a working day for the user.
+}
+
+// HolidayDtoV1 represents a holiday in responses listing the holidays of a period.
+type HolidayDtoV1 struct {
+	Date string `json:"date" example:"2028-12-25"`    // Date is the day of the holiday in YYYY-MM-DD format.
+	Name string `json:"name" example:"Christmas Day"` // Name is the name of the holiday.
 }
 
 // ListOfEventsResponseV1 represents a response containing a list of events.
 type ListOfEventsResponseV1 struct {
-	Events []EventDtoV1 `json:"events"` // Events is the list of events returned by the API.
+	Events   []EventDtoV1   `json:"events"`             // Events is the list of events returned by the API.
+	Holidays []HolidayDtoV1 `json:"holidays,omitempty"` // Holidays lists the holidays of the period when requested with holidays=true.
+}
+
+// UpdateSettingsRequestV1 represents the request body for changing a user's working calendar settings.
+type UpdateSettingsRequestV1 struct {
+	UserID      int      `json:"user_id" example:"1"`                                                   // UserID is the ID of the user.
+	Country     string   `json:"country,omitempty" example:"RU"`                                        // Country is the code of the holiday calendar, empty for none.
+	WorkingDays []string `json:"working_days" example:"mon,tue,wed,thu,fri" swaggertype:"array,string"` // WorkingDays lists the user's working days, e.g. mon..sun.
+}
+
+// UpdateSettingsResponseV1 represents the response returned after changing settings.
+type UpdateSettingsResponseV1 struct {
+	Updated bool `json:"settings_updated" example:"true"` // Updated indicates whether the settings were saved.
+}
+
+// SettingsResponseV1 represents a user's effective working calendar settings.
+type SettingsResponseV1 struct {
+	UserID      int      `json:"user_id" example:"1"`                                                   // UserID is the ID of the user.
+	Country     string   `json:"country" example:"RU"`                                                  // Country is the code of the holiday calendar, empty for none.
+	WorkingDays []string `json:"working_days" example:"mon,tue,wed,thu,fri" swaggertype:"array,string"` // WorkingDays lists the user's working days.
+}
+
+// AttachmentDtoV1 represents a file attached to an event.
+type AttachmentDtoV1 struct {
+	AttachmentID string `json:"attachment_id" example:"9b2f0c1e-4d7a-4f3e-8a51-6c2d9e0b7a13"` // AttachmentID is the unique identifier of the attachment.
+	Name         string `json:"name" example:"agenda.pdf"`                                    // Name is the original file name.
+	ContentType  string `json:"content_type" example:"application/pdf"`                       // ContentType is the MIME type detected from the file content.
+	Size         int64  `json:"size" example:"48213"`                                         // Size is the file size in bytes.
+	UploadedAt   string `json:"uploaded_at" example:"2028-12-01T10:00:00Z"`                   // UploadedAt is the upload time in RFC 3339 format.
+}
+
+// UploadAttachmentResponseV1 represents the response returned after attaching a file to an event.
+type UploadAttachmentResponseV1 struct {
+	Attachment AttachmentDtoV1 `json:"attachment"` // Attachment describes the stored file.
+}
+
+// ListOfAttachmentsResponseV1 represents a response containing the files attached to an event.
+type ListOfAttachmentsResponseV1 struct {
+	Attachments []AttachmentDtoV1 `json:"attachments"` // Attachments lists the files in upload order.
+}
+
+// DeleteAttachmentRequestV1 represents the request body for removing a file attached to an event.
+type DeleteAttachmentRequestV1 struct {
+	UserID       int    `json:"user_id" binding:"required" example:"1"`                                          // UserID is the ID of the user who owns the event.
+	EventID      string `json:"event_id" binding:"required" example:"3383503d-fb71-4b8c-85bd-a914c84252a9"`      // EventID is the unique identifier of the event.
+	AttachmentID string `json:"attachment_id" binding:"required" example:"9b2f0c1e-4d7a-4f3e-8a51-6c2d9e0b7a13"` // AttachmentID is the unique identifier of the attachment to remove.
+}
+
+// DeleteAttachmentResponseV1 represents the response returned after removing an attachment.
+type DeleteAttachmentResponseV1 struct {
+	Deleted bool `json:"attachment_deleted" example:"true"` // Deleted indicates whether the attachment was successfully removed.
+}
+
+// CreateWebhookRequestV1 represents the request body for registering a webhook.
+type CreateWebhookRequestV1 struct {
+	UserID int    `json:"user_id" binding:"required" example:"1"`                            // UserID is the ID of the user whose events are reported.
+	URL    string `json:"url" binding:"required" example:"https://bot.example.com/calendar"` // URL is the http or https address notifications are posted to.
+}
+
+// WebhookDtoV1 represents a registered webhook.
+type WebhookDtoV1 struct {
+	WebhookID string `json:"webhook_id" example:"5f0c2a8e-3b1d-4e6f-9a7c-2d8b1e4f6a90"` // WebhookID is the unique identifier of the webhook.
+	URL       string `json:"url" example:"https://bot.example.com/calendar"`            // URL is the address notifications are posted to.
+	CreatedAt string `json:"created_at" example:"2028-12-01T10:00:00Z"`                 // CreatedAt is the registration time in RFC 3339 format.
+}
+
+// CreateWebhookResponseV1 represents the response returned after registering a webhook.
+type CreateWebhookResponseV1 struct {
+	Webhook WebhookDtoV1 `json:"webhook"`                                                                           // Webhook describes the registered webhook.
+	Secret  string       `json:"secret" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // Secret is the key notifications are signed with. It is only returned once.
+}
+
+// ListOfWebhooksResponseV1 represents a response containing the webhooks of a user.
+type ListOfWebhooksResponseV1 struct {
+	Webhooks []WebhookDtoV1 `json:"webhooks"` // Webhooks lists the webhooks in registration order.
+}
+
+// DeleteWebhookRequestV1 represents the request body for removing a webhook.
+type DeleteWebhookRequestV1 struct {
+	UserID    int    `json:"user_id" binding:"required" example:"1"`                                       // UserID is the ID of the user who owns the webhook.
+	WebhookID string `json:"webhook_id" binding:"required" example:"5f0c2a8e-3b1d-4e6f-9a7c-2d8b1e4f6a90"` // WebhookID is the unique identifier of the webhook to remove.
+}
+
+// DeleteWebhookResponseV1 represents the response returned after removing a webhook.
+type DeleteWebhookResponseV1 struct {
+	Deleted bool `json:"webhook_deleted" example:"true"` // Deleted indicates whether the webhook was successfully removed.
+}
+
+// DeliveryDtoV1 represents one notification in the delivery log of a webhook.
+type DeliveryDtoV1 struct {
+	DeliveryID  string `json:"delivery_id" example:"c1a4e7b2-8d3f-4a6e-b5c9-0f2d7e1a3b48"`            // DeliveryID is the unique identifier of the notification, sent in every attempt.
+	Type        string `json:"type" example:"event.created"`                                          // Type is the kind of change reported.
+	Status      string `json:"status" example:"delivered" enums:"pending,delivered,dead"`             // Status is the delivery state; dead deliveries form the dead-letter list.
+	Attempts    int    `json:"attempts" example:"1"`                                                  // Attempts is the number of attempts made so far.
+	LastStatus  int    `json:"last_status_code,omitempty" example:"204"`                              // LastStatus is the HTTP status code of the last response, if there was one.
+	LastError   string `json:"last_error,omitempty" example:"webhook responded with 502 Bad Gateway"` // LastError describes why the last attempt failed.
+	NextAttempt string `json:"next_attempt,omitempty" example:"2028-12-01T10:00:10Z"`                 // NextAttempt is the time of the next attempt of a pending delivery in RFC 3339 format.
+	CreatedAt   string `json:"created_at" example:"2028-12-01T10:00:00Z"`                             // CreatedAt is the time of the change in RFC 3339 format.
+	UpdatedAt   string `json:"updated_at" example:"2028-12-01T10:00:01Z"`                             // UpdatedAt is the time of the last attempt in RFC 3339 format.
+}
+
+// ListOfDeliveriesResponseV1 represents a response containing the delivery log of a webhook.
+type ListOfDeliveriesResponseV1 struct {
+	Deliveries []DeliveryDtoV1 `json:"deliveries"` // Deliveries lists the notifications, newest first.
+}
+
+// RetryDeliveryRequestV1 represents the request body for retrying a dead-lettered delivery.
+type RetryDeliveryRequestV1 struct {
+	UserID     int    `json:"user_id" binding:"required" example:"1"`                                        // UserID is the ID of the user who owns the webhook.
+	WebhookID  string `json:"webhook_id" binding:"required" example:"5f0c2a8e-3b1d-4e6f-9a7c-2d8b1e4f6a90"`  // WebhookID is the unique identifier of the webhook.
+	DeliveryID string `json:"delivery_id" binding:"required" example:"c1a4e7b2-8d3f-4a6e-b5c9-0f2d7e1a3b48"` // DeliveryID is the unique identifier of the delivery to retry.
+}
+
+// RetryDeliveryResponseV1 represents the response returned after queueing a delivery again.
+type RetryDeliveryResponseV1 struct {
+	Queued bool `json:"delivery_queued" example:"true"` // Queued indicates whether the delivery was moved back into the queue.
 }
 
 // ErrorResponse represents a standard bad request response.
@@ -59,3 +212,15 @@ type ErrorResponse500 struct {
 	Code    int    `json:"code" example:"500"`                      // Code is the HTTP status code.
 	Message string `json:"message" example:"internal server error"` // Message is a human-readable description of the error.
 }
+
+// ErrorResponse413 represents a response to an attachment exceeding the size limit.
+type ErrorResponse413 struct {
+	Code    int    `json:"code" example:"413"`                                            // Code is the HTTP status code.
+	Message string `json:"message" example:"attachment exceeds the maximum allowed size"` // Message is a human-readable description of the error.
+}
+
+// ErrorResponse415 represents a response to an attachment of a disallowed type.
+type ErrorResponse415 struct {
+	Code    int    `json:"code" example:"415"`                                                         // Code is the HTTP status code.
+	Message string `json:"message" example:"attachment type is not allowed: application/x-msdownload"` // Message is a human-readable description of the error.
+}
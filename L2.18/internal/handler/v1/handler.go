@@ -1,17 +1,21 @@
 // Package v1 provides version 1 of the API handlers for the event management system.
 //
 // It defines a Handler struct that wraps the service layer and logger, and exposes
-// HTTP endpoints to create, update, delete, and retrieve user events. Each method
+// HTTP endpoints to create, update, delete, and retrieve user events, the files
+// attached to them, and the webhooks notified about their changes. Each method
 // is annotated for Swagger documentation generation and uses JSON for request
 // and response payloads.
 package v1
 
 import (
+	"mime"
+	"net/http"
 	"time"
 
 	"L2.18/internal/errs"
 	"L2.18/internal/models"
 	"L2.18/internal/service"
+	"L2.18/pkg/holidays"
 	"L2.18/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
@@ -44,6 +48,7 @@ func NewHandler(service service.Service, logger logger.Logger) *Handler {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
 // @Param request body CreateRequestV1 true "Event data"
 // @Success 200 {object} CreateResponseV1
 // @Failure 400 {object} ErrorResponse400
@@ -69,13 +74,13 @@ func (h *Handler) CreateEvent(c *gin.Context) {
 		Data: models.Data{Text: request.Text},
 	}
 
-	eventID, err := h.service.CreateEvent(&event)
+	eventID, err := h.service.CreateEvent(c.Request.Context(), &event)
 	if err != nil {
 		respondError(c, err)
 		return
 	}
 
-	respondOK(c, CreateResponseV1{EventID: eventID})
+	respondOK(c, CreateResponseV1{EventID: eventID, Warning: event.Meta.Warning})
 
 }
 
@@ -87,6 +92,7 @@ func (h *Handler) CreateEvent(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
 // @Param request body UpdateRequestV1 true "Event update data"
 // @Success 200 {object} UpdateResponseV1
 // @Failure 400 {object} ErrorResponse400
@@ -117,12 +123,12 @@ func (h *Handler) UpdateEvent(c *gin.Context) {
 		Meta: models.Meta{UserID: request.UserID, EventID: request.EventID, NewDate: date},
 		Data: models.Data{Text: request.Text}}
 
-	if err := h.service.UpdateEvent(&event); err != nil {
+	if err := h.service.UpdateEvent(c.Request.Context(), &event); err != nil {
 		respondError(c, err)
 		return
 	}
 
-	respondOK(c, UpdateResponseV1{Updated: true})
+	respondOK(c, UpdateResponseV1{Updated: true, Warning: event.Meta.Warning})
 
 }
 
@@ -133,6 +139,7 @@ func (h *Handler) UpdateEvent(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
 // @Param request body DeleteRequestV1 true "Event delete data"
 // @Success 200 {object} DeleteResponseV1
 // @Failure 400 {object} ErrorResponse400
@@ -149,7 +156,7 @@ func (h *Handler) DeleteEvent(c *gin.Context) {
 
 	meta := models.Meta{UserID: request.UserID, EventID: request.EventID}
 
-	if err := h.service.DeleteEvent(&meta); err != nil {
+	if err := h.service.DeleteEvent(c.Request.Context(), &meta); err != nil {
 		respondError(c, err)
 		return
 	}
@@ -158,6 +165,50 @@ func (h *Handler) DeleteEvent(c *gin.Context) {
 
 }
 
+// QuickAdd handles HTTP POST requests to add an event from a free-form phrase.
+// Without confirm it only returns how the phrase was read, so the client can show it for confirmation.
+//
+// @Summary Quick-add an event from a phrase
+// @Description Reads an English or Russian phrase such as "lunch with Ann next friday", "dentist 2028-12-04" or "каждый понедельник standup" as an event date, text and optional recurrence, resolving relative dates against reference_date. The interpretation is returned for confirmation; with confirm set the event is created. Recurring phrases create their first occurrence and list the upcoming dates.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param request body QuickAddRequestV1 true "Phrase and options"
+// @Success 200 {object} QuickAddResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/quick_add [post]
+func (h *Handler) QuickAdd(c *gin.Context) {
+
+	var request QuickAddRequestV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	var reference time.Time
+
+	if request.ReferenceDate != "" {
+		date, err := parseDate(request.ReferenceDate)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		reference = date
+	}
+
+	result, err := h.service.QuickAdd(c.Request.Context(), request.UserID, request.Text, reference, request.Confirm)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, toQuickAddResponse(result))
+
+}
+
 // GetEventsDay handles HTTP GET requests to retrieve all events for a specific day.
 //
 // @Summary Get events for a day
@@ -165,8 +216,10 @@ func (h *Handler) DeleteEvent(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
 // @Param user_id query int true "User ID"
 // @Param date query string true "Date in YYYY-MM-DD format"
+// @Param holidays query bool false "Also list the holidays of the period"
 // @Success 200 {object} ListOfEventsResponseV1
 // @Failure 400 {object} ErrorResponse400
 // @Failure 500 {object} ErrorResponse500
@@ -182,8 +235,10 @@ func (h *Handler) GetEventsDay(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
 // @Param user_id query int true "User ID"
 // @Param date query string true "Date in YYYY-MM-DD format"
+// @Param holidays query bool false "Also list the holidays of the period"
 // @Success 200 {object} ListOfEventsResponseV1
 // @Failure 400 {object} ErrorResponse400
 // @Failure 500 {object} ErrorResponse500
@@ -199,8 +254,10 @@ func (h *Handler) GetEventsWeek(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
 // @Param user_id query int true "User ID"
 // @Param date query string true "Date in YYYY-MM-DD format"
+// @Param holidays query bool false "Also list the holidays of the period"
 // @Success 200 {object} ListOfEventsResponseV1
 // @Failure 400 {object} ErrorResponse400
 // @Failure 500 {object} ErrorResponse500
@@ -219,7 +276,7 @@ func (h *Handler) getEvents(c *gin.Context, period models.Period) {
 		return
 	}
 
-	events, err := h.service.GetEvents(&models.Meta{UserID: userId, EventDate: eventDate}, period)
+	events, err := h.service.GetEvents(c.Request.Context(), &models.Meta{UserID: userId, EventDate: eventDate}, period)
 	if err != nil {
 		respondError(c, err)
 		return
@@ -228,9 +285,426 @@ func (h *Handler) getEvents(c *gin.Context, period models.Period) {
 	respEvents := make([]EventDtoV1, len(events))
 
 	for i, e := range events {
-		respEvents[i] = EventDtoV1{Text: e.Data.Text, EventDate: e.Meta.EventDate.Format("2006-01-02"), EventID: e.Meta.EventID}
+		respEvents[i] = EventDtoV1{
+			Text:          e.Data.Text,
+			EventDate:     e.Meta.EventDate.Format("2006-01-02"),
+			EventID:       e.Meta.EventID,
+			Holiday:       e.Meta.Holiday,
+			NonWorkingDay: e.Meta.NonWorking,
+		}
+	}
+
+	response := ListOfEventsResponseV1{Events: respEvents}
+
+	if c.Query("holidays") == "true" {
+
+		periodHolidays, err := h.service.GetHolidays(c.Request.Context(), &models.Meta{UserID: userId, EventDate: eventDate}, period)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		response.Holidays = make([]HolidayDtoV1, len(periodHolidays))
+		for i, holiday := range periodHolidays {
+			response.Holidays[i] = HolidayDtoV1{Date: holiday.Date.Format("2006-01-02"), Name: holiday.Name}
+		}
+
+	}
+
+	respondOK(c, response)
+
+}
+
+// UpdateSettings handles HTTP POST requests to change a user's working calendar settings.
+//
+// @Summary Update working calendar settings
+// @Description Sets the user's holiday calendar country and working week
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param request body UpdateSettingsRequestV1 true "Working calendar settings"
+// @Success 200 {object} UpdateSettingsResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/update_settings [post]
+func (h *Handler) UpdateSettings(c *gin.Context) {
+
+	var request UpdateSettingsRequestV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	workingDays, err := holidays.ParseWeekdays(request.WorkingDays)
+	if err != nil {
+		respondError(c, errs.ErrInvalidWorkingDay)
+		return
+	}
+
+	settings := models.Settings{UserID: request.UserID, Country: request.Country, WorkingDays: workingDays}
+
+	if err := h.service.UpdateSettings(c.Request.Context(), &settings); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, UpdateSettingsResponseV1{Updated: true})
+
+}
+
+// GetSettings handles HTTP GET requests to retrieve a user's working calendar settings.
+//
+// @Summary Get working calendar settings
+// @Description Returns the user's holiday calendar country and working week, or the defaults
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param user_id query int true "User ID"
+// @Success 200 {object} SettingsResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/settings [get]
+func (h *Handler) GetSettings(c *gin.Context) {
+
+	userID, err := parseUserID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	settings, err := h.service.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, SettingsResponseV1{
+		UserID:      settings.UserID,
+		Country:     settings.Country,
+		WorkingDays: holidays.FormatWeekdays(settings.WorkingDays),
+	})
+
+}
+
+// UploadAttachment handles HTTP POST requests to attach a file to an event.
+// The file is streamed from the multipart body to the blob store without being buffered whole.
+//
+// @Summary Attach a file to an event
+// @Description Uploads a file as multipart/form-data and attaches it to an event of the user. The file type is detected from its content.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param user_id query int true "User ID"
+// @Param event_id query string true "Event ID"
+// @Param file formData file true "File to attach"
+// @Success 200 {object} UploadAttachmentResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 413 {object} ErrorResponse413
+// @Failure 415 {object} ErrorResponse415
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/upload_attachment [post]
+func (h *Handler) UploadAttachment(c *gin.Context) {
+
+	userID, err := parseUserID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	part, err := filePart(c.Request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer part.Close()
+
+	attachment := models.Attachment{UserID: userID, EventID: c.Query("event_id"), Name: part.FileName()}
+
+	if err := h.service.AddAttachment(c.Request.Context(), &attachment, part); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, UploadAttachmentResponseV1{Attachment: toAttachmentDto(attachment)})
+
+}
+
+// GetAttachments handles HTTP GET requests to list the files attached to an event.
+//
+// @Summary List event attachments
+// @Description Returns the files attached to an event of the user, in upload order
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param user_id query int true "User ID"
+// @Param event_id query string true "Event ID"
+// @Success 200 {object} ListOfAttachmentsResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/attachments [get]
+func (h *Handler) GetAttachments(c *gin.Context) {
+
+	userID, err := parseUserID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	attachments, err := h.service.GetAttachments(c.Request.Context(), userID, c.Query("event_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respAttachments := make([]AttachmentDtoV1, len(attachments))
+	for i, a := range attachments {
+		respAttachments[i] = toAttachmentDto(a)
+	}
+
+	respondOK(c, ListOfAttachmentsResponseV1{Attachments: respAttachments})
+
+}
+
+// DownloadAttachment handles HTTP GET requests to download a file attached to an event.
+// The file is always served as a download with its detected content type, never rendered inline.
+//
+// @Summary Download an event attachment
+// @Description Returns the content of a file attached to an event of the user
+// @Tags attachments
+// @Produce octet-stream
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param user_id query int true "User ID"
+// @Param event_id query string true "Event ID"
+// @Param attachment_id query string true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/download_attachment [get]
+func (h *Handler) DownloadAttachment(c *gin.Context) {
+
+	userID, err := parseUserID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	attachment, content, err := h.service.OpenAttachment(c.Request.Context(), userID, c.Query("event_id"), c.Query("attachment_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}),
+		"X-Content-Type-Options": "nosniff",
+	})
+
+}
+
+// DeleteAttachment handles HTTP POST requests to remove a file attached to an event.
+//
+// @Summary Delete an event attachment
+// @Description Removes a file attached to an event of the user
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param request body DeleteAttachmentRequestV1 true "Attachment delete data"
+// @Success 200 {object} DeleteAttachmentResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/delete_attachment [post]
+func (h *Handler) DeleteAttachment(c *gin.Context) {
+
+	var request DeleteAttachmentRequestV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.service.DeleteAttachment(c.Request.Context(), request.UserID, request.EventID, request.AttachmentID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, DeleteAttachmentResponseV1{Deleted: true})
+
+}
+
+// CreateWebhook handles HTTP POST requests to register a webhook notified about changes of the user's events.
+//
+// @Summary Register a webhook
+// @Description Registers a URL that receives a signed JSON notification whenever an event of the user is created, updated or deleted. The signing secret is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param request body CreateWebhookRequestV1 true "Webhook data"
+// @Success 200 {object} CreateWebhookResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/create_webhook [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+
+	var request CreateWebhookRequestV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	webhook := models.Webhook{UserID: request.UserID, URL: request.URL}
+
+	if err := h.service.CreateWebhook(c.Request.Context(), &webhook); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, CreateWebhookResponseV1{Webhook: toWebhookDto(webhook), Secret: webhook.Secret})
+
+}
+
+// GetWebhooks handles HTTP GET requests to list the webhooks of a user.
+//
+// @Summary List webhooks
+// @Description Returns the webhooks of the user, in registration order. Secrets are not included.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param user_id query int true "User ID"
+// @Success 200 {object} ListOfWebhooksResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/webhooks [get]
+func (h *Handler) GetWebhooks(c *gin.Context) {
+
+	userID, err := parseUserID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	webhooks, err := h.service.GetWebhooks(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respWebhooks := make([]WebhookDtoV1, len(webhooks))
+	for i, w := range webhooks {
+		respWebhooks[i] = toWebhookDto(w)
+	}
+
+	respondOK(c, ListOfWebhooksResponseV1{Webhooks: respWebhooks})
+
+}
+
+// DeleteWebhook handles HTTP POST requests to remove a webhook.
+//
+// @Summary Delete a webhook
+// @Description Removes a webhook of the user together with its pending deliveries and delivery log
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param request body DeleteWebhookRequestV1 true "Webhook delete data"
+// @Success 200 {object} DeleteWebhookResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/delete_webhook [post]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+
+	var request DeleteWebhookRequestV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), request.UserID, request.WebhookID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, DeleteWebhookResponseV1{Deleted: true})
+
+}
+
+// GetDeliveries handles HTTP GET requests to read the delivery log of a webhook.
+//
+// @Summary Webhook delivery log
+// @Description Returns the notifications sent to a webhook of the user, newest first. Filter by status=dead to get the dead-letter list.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param user_id query int true "User ID"
+// @Param webhook_id query string true "Webhook ID"
+// @Param status query string false "Delivery status" Enums(pending, delivered, dead)
+// @Success 200 {object} ListOfDeliveriesResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/webhook_deliveries [get]
+func (h *Handler) GetDeliveries(c *gin.Context) {
+
+	userID, err := parseUserID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	status := models.DeliveryStatus(c.Query("status"))
+
+	deliveries, err := h.service.GetDeliveries(c.Request.Context(), userID, c.Query("webhook_id"), status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respDeliveries := make([]DeliveryDtoV1, len(deliveries))
+	for i, d := range deliveries {
+		respDeliveries[i] = toDeliveryDto(d)
+	}
+
+	respondOK(c, ListOfDeliveriesResponseV1{Deliveries: respDeliveries})
+
+}
+
+// RetryDelivery handles HTTP POST requests to queue a dead-lettered delivery again.
+//
+// @Summary Retry a dead-lettered delivery
+// @Description Moves a delivery from the dead-letter list of a webhook back into the queue with a fresh attempt budget
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Tenant ID, used when tenancy is enabled with the header source"
+// @Param request body RetryDeliveryRequestV1 true "Delivery retry data"
+// @Success 200 {object} RetryDeliveryResponseV1
+// @Failure 400 {object} ErrorResponse400
+// @Failure 500 {object} ErrorResponse500
+// @Router /api/v1/retry_delivery [post]
+func (h *Handler) RetryDelivery(c *gin.Context) {
+
+	var request RetryDeliveryRequestV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.service.RetryDelivery(c.Request.Context(), request.UserID, request.WebhookID, request.DeliveryID); err != nil {
+		respondError(c, err)
+		return
 	}
 
-	respondOK(c, ListOfEventsResponseV1{Events: respEvents})
+	respondOK(c, RetryDeliveryResponseV1{Queued: true})
 
 }
@@ -2,10 +2,16 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,7 +45,7 @@ func TestHandler_CreateEvent_Success(t *testing.T) {
 	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockService.EXPECT().CreateEvent(gomock.Any()).Return("event-id", nil)
+	mockService.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Return("event-id", nil)
 
 	testHandler.CreateEvent(c)
 
@@ -125,7 +131,7 @@ func TestHandler_CreateEvent_ErrService(t *testing.T) {
 	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockService.EXPECT().CreateEvent(gomock.Any()).Return("", errors.New("service error"))
+	mockService.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Return("", errors.New("service error"))
 
 	testHandler.CreateEvent(c)
 
@@ -152,7 +158,7 @@ func TestHandler_UpdateEvent_Success(t *testing.T) {
 	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockService.EXPECT().UpdateEvent(gomock.Any()).Return(nil)
+	mockService.EXPECT().UpdateEvent(gomock.Any(), gomock.Any()).Return(nil)
 
 	testHandler.UpdateEvent(c)
 
@@ -241,7 +247,7 @@ func TestHandler_UpdateEvent_ErrService(t *testing.T) {
 	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockService.EXPECT().UpdateEvent(gomock.Any()).Return(errors.New("service error"))
+	mockService.EXPECT().UpdateEvent(gomock.Any(), gomock.Any()).Return(errors.New("service error"))
 
 	testHandler.UpdateEvent(c)
 
@@ -268,7 +274,7 @@ func TestHandler_DeleteEvent_Success(t *testing.T) {
 	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockService.EXPECT().DeleteEvent(gomock.Any()).Return(nil)
+	mockService.EXPECT().DeleteEvent(gomock.Any(), gomock.Any()).Return(nil)
 
 	testHandler.DeleteEvent(c)
 
@@ -323,7 +329,7 @@ func TestHandler_DeleteEvent_ErrService(t *testing.T) {
 	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockService.EXPECT().DeleteEvent(gomock.Any()).Return(errors.New("service error"))
+	mockService.EXPECT().DeleteEvent(gomock.Any(), gomock.Any()).Return(errors.New("service error"))
 
 	testHandler.DeleteEvent(c)
 
@@ -346,7 +352,7 @@ func TestHandler_GetEvents_Success(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&date=2025-12-03", nil)
 
-	mockService.EXPECT().GetEvents(gomock.Any(), models.Day).Return([]models.Event{
+	mockService.EXPECT().GetEvents(gomock.Any(), gomock.Any(), models.Day).Return([]models.Event{
 		{Meta: models.Meta{UserID: 1, EventDate: time.Now()}, Data: models.Data{Text: "ok"}},
 	}, nil)
 
@@ -379,7 +385,7 @@ func TestHandler_GetEvents_Week(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&date=2025-12-03", nil)
 
-	mockService.EXPECT().GetEvents(gomock.Any(), models.Week).Return([]models.Event{
+	mockService.EXPECT().GetEvents(gomock.Any(), gomock.Any(), models.Week).Return([]models.Event{
 		{Meta: models.Meta{UserID: 1, EventDate: time.Now()}, Data: models.Data{Text: "ok"}},
 	}, nil)
 
@@ -412,7 +418,7 @@ func TestHandler_GetEvents_Month(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&date=2025-12-03", nil)
 
-	mockService.EXPECT().GetEvents(gomock.Any(), models.Month).Return([]models.Event{
+	mockService.EXPECT().GetEvents(gomock.Any(), gomock.Any(), models.Month).Return([]models.Event{
 		{Meta: models.Meta{UserID: 1, EventDate: time.Now()}, Data: models.Data{Text: "ok"}},
 	}, nil)
 
@@ -476,7 +482,7 @@ func TestHandler_GetEvents_ErrService(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&date=2025-12-03", nil)
 
-	mockService.EXPECT().GetEvents(gomock.Any(), models.Day).Return(nil, errors.New("service error"))
+	mockService.EXPECT().GetEvents(gomock.Any(), gomock.Any(), models.Day).Return(nil, errors.New("service error"))
 
 	testHandler.GetEventsDay(c)
 
@@ -484,6 +490,608 @@ func TestHandler_GetEvents_ErrService(t *testing.T) {
 
 }
 
+func TestHandler_GetEvents_WithHolidays(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&date=2030-12-25&holidays=true", nil)
+
+	christmas := time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	mockService.EXPECT().GetEvents(gomock.Any(), gomock.Any(), models.Week).Return([]models.Event{
+		{Meta: models.Meta{UserID: 1, EventDate: christmas, Holiday: "Christmas Day", NonWorking: true}, Data: models.Data{Text: "ok"}},
+	}, nil)
+	mockService.EXPECT().GetHolidays(gomock.Any(), gomock.Any(), models.Week).Return([]models.Holiday{{Date: christmas, Name: "Christmas Day"}}, nil)
+
+	testHandler.GetEventsWeek(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result ListOfEventsResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Christmas Day", resp.Result.Events[0].Holiday)
+	assert.True(t, resp.Result.Events[0].NonWorkingDay)
+	assert.Equal(t, []HolidayDtoV1{{Date: "2030-12-25", Name: "Christmas Day"}}, resp.Result.Holidays)
+
+}
+
+func TestHandler_UpdateSettings_Success(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	body, _ := json.Marshal(UpdateSettingsRequestV1{UserID: 1, Country: "RU", WorkingDays: []string{"sun", "mon"}})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockService.EXPECT().UpdateSettings(gomock.Any(), &models.Settings{UserID: 1, Country: "RU", WorkingDays: []time.Weekday{time.Sunday, time.Monday}}).Return(nil)
+
+	testHandler.UpdateSettings(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+}
+
+func TestHandler_UpdateSettings_ErrInvalidWorkingDay(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	body, _ := json.Marshal(UpdateSettingsRequestV1{UserID: 1, WorkingDays: []string{"someday"}})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	testHandler.UpdateSettings(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrInvalidWorkingDay.Error())
+
+}
+
+func TestHandler_GetSettings(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1", nil)
+
+	mockService.EXPECT().GetSettings(gomock.Any(), 1).Return(models.Settings{UserID: 1, Country: "RU", WorkingDays: []time.Weekday{time.Monday, time.Friday}}, nil)
+
+	testHandler.GetSettings(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result SettingsResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, SettingsResponseV1{UserID: 1, Country: "RU", WorkingDays: []string{"mon", "fri"}}, resp.Result)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=abc", nil)
+
+	testHandler.GetSettings(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrInvalidUserID.Error())
+
+}
+
+func TestHandler_UploadAttachment(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("comment", "ignored")
+	file, _ := form.CreateFormFile("file", "notes.txt")
+	_, _ = file.Write([]byte("meeting notes"))
+	_ = form.Close()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/?user_id=1&event_id=event-id", &body)
+	c.Request.Header.Set("Content-Type", form.FormDataContentType())
+
+	mockService.EXPECT().AddAttachment(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, attachment *models.Attachment, content io.Reader) error {
+			data, _ := io.ReadAll(content)
+			assert.Equal(t, "meeting notes", string(data))
+			assert.Equal(t, models.Attachment{UserID: 1, EventID: "event-id", Name: "notes.txt"}, *attachment)
+			attachment.AttachmentID = "attachment-id"
+			attachment.ContentType = "text/plain; charset=utf-8"
+			attachment.Size = int64(len(data))
+			return nil
+		})
+
+	testHandler.UploadAttachment(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result UploadAttachmentResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "attachment-id", resp.Result.Attachment.AttachmentID)
+	assert.Equal(t, int64(13), resp.Result.Attachment.Size)
+
+}
+
+func TestHandler_UploadAttachment_Errors(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	upload := func() *http.Request {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		file, _ := form.CreateFormFile("file", "setup.exe")
+		_, _ = file.Write([]byte("MZ"))
+		_ = form.Close()
+		r, _ := http.NewRequest(http.MethodPost, "/?user_id=1&event_id=event-id", &body)
+		r.Header.Set("Content-Type", form.FormDataContentType())
+		return r
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/?user_id=1&event_id=event-id", strings.NewReader("{}"))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	testHandler.UploadAttachment(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrMissingFile.Error())
+
+	mockService.EXPECT().AddAttachment(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("%w: application/x-msdownload", errs.ErrAttachmentType))
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = upload()
+
+	testHandler.UploadAttachment(c)
+
+	assertErrorResponse(t, w, http.StatusUnsupportedMediaType, errs.ErrAttachmentType.Error()+": application/x-msdownload")
+
+	mockService.EXPECT().AddAttachment(gomock.Any(), gomock.Any(), gomock.Any()).Return(errs.ErrAttachmentSize)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = upload()
+
+	testHandler.UploadAttachment(c)
+
+	assertErrorResponse(t, w, http.StatusRequestEntityTooLarge, errs.ErrAttachmentSize.Error())
+
+}
+
+func TestHandler_GetAttachments(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	uploadedAt := time.Date(2028, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&event_id=event-id", nil)
+
+	mockService.EXPECT().GetAttachments(gomock.Any(), 1, "event-id").Return([]models.Attachment{
+		{AttachmentID: "attachment-id", Name: "agenda.pdf", ContentType: "application/pdf", Size: 42, CreatedAt: uploadedAt},
+	}, nil)
+
+	testHandler.GetAttachments(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result ListOfAttachmentsResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []AttachmentDtoV1{
+		{AttachmentID: "attachment-id", Name: "agenda.pdf", ContentType: "application/pdf", Size: 42, UploadedAt: "2028-12-01T10:00:00Z"},
+	}, resp.Result.Attachments)
+
+}
+
+func TestHandler_DownloadAttachment(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&event_id=event-id&attachment_id=attachment-id", nil)
+
+	attachment := models.Attachment{AttachmentID: "attachment-id", Name: "план встречи.txt", ContentType: "text/plain; charset=utf-8", Size: 13}
+
+	mockService.EXPECT().OpenAttachment(gomock.Any(), 1, "event-id", "attachment-id").Return(attachment, io.NopCloser(strings.NewReader("meeting notes")), nil)
+
+	testHandler.DownloadAttachment(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "meeting notes", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+	_, params, err := mime.ParseMediaType(w.Header().Get("Content-Disposition"))
+	assert.NoError(t, err)
+	assert.Equal(t, "план встречи.txt", params["filename"])
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&event_id=event-id&attachment_id=attachment-id", nil)
+
+	mockService.EXPECT().OpenAttachment(gomock.Any(), 1, "event-id", "attachment-id").Return(models.Attachment{}, nil, errs.ErrAttachmentNotFound)
+
+	testHandler.DownloadAttachment(c)
+
+	assertErrorResponse(t, w, http.StatusServiceUnavailable, errs.ErrAttachmentNotFound.Error())
+
+}
+
+func TestHandler_DeleteAttachment(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	body, _ := json.Marshal(DeleteAttachmentRequestV1{UserID: 1, EventID: "event-id", AttachmentID: "attachment-id"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockService.EXPECT().DeleteAttachment(gomock.Any(), 1, "event-id", "attachment-id").Return(nil)
+
+	testHandler.DeleteAttachment(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result DeleteAttachmentResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Result.Deleted)
+
+}
+
+func TestHandler_QuickAdd(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	post := func(request QuickAddRequestV1) (*httptest.ResponseRecorder, *gin.Context) {
+		body, _ := json.Marshal(request)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		return w, c
+	}
+
+	reference := time.Date(2028, 11, 29, 0, 0, 0, 0, time.UTC)
+	friday := time.Date(2028, 12, 8, 0, 0, 0, 0, time.UTC)
+
+	mockService.EXPECT().QuickAdd(gomock.Any(), 1, "lunch with Ann next friday", reference, false).Return(models.QuickAdd{
+		Event:       models.Event{Meta: models.Meta{UserID: 1, EventDate: friday}, Data: models.Data{Text: "lunch with Ann"}},
+		Occurrences: []time.Time{friday},
+		Language:    "en",
+		DatePhrase:  "next friday",
+	}, nil)
+
+	w, c := post(QuickAddRequestV1{UserID: 1, Text: "lunch with Ann next friday", ReferenceDate: "2028-11-29"})
+	testHandler.QuickAdd(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result QuickAddResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, QuickAddResponseV1{Date: "2028-12-08", Text: "lunch with Ann", Language: "en", Matched: "next friday"}, resp.Result)
+
+	mockService.EXPECT().QuickAdd(gomock.Any(), 1, "каждую пятницу standup", time.Time{}, true).Return(models.QuickAdd{
+		Event:       models.Event{Meta: models.Meta{UserID: 1, EventID: "event-id", EventDate: friday}, Data: models.Data{Text: "standup"}},
+		Recurrence:  &models.Recurrence{Frequency: models.Weekly, Weekday: time.Friday},
+		Occurrences: []time.Time{friday, friday.AddDate(0, 0, 7)},
+		Language:    "ru",
+		DatePhrase:  "каждую пятницу",
+		Created:     true,
+	}, nil)
+
+	w, c = post(QuickAddRequestV1{UserID: 1, Text: "каждую пятницу standup", Confirm: true})
+	testHandler.QuickAdd(c)
+
+	resp.Result = QuickAddResponseV1{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, QuickAddResponseV1{
+		Date:       "2028-12-08",
+		Text:       "standup",
+		Recurrence: &RecurrenceDtoV1{Frequency: "weekly", Weekday: "fri"},
+		Upcoming:   []string{"2028-12-08", "2028-12-15"},
+		Language:   "ru",
+		Matched:    "каждую пятницу",
+		Created:    true,
+		EventID:    "event-id",
+	}, resp.Result)
+
+	w, c = post(QuickAddRequestV1{UserID: 1, Text: "lunch tomorrow", ReferenceDate: "29.11.2028"})
+	testHandler.QuickAdd(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrInvalidDateFormat.Error())
+
+	mockService.EXPECT().QuickAdd(gomock.Any(), 1, "lunch", time.Time{}, false).Return(models.QuickAdd{}, errs.ErrNoDateInPhrase)
+
+	w, c = post(QuickAddRequestV1{UserID: 1, Text: "lunch"})
+	testHandler.QuickAdd(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrNoDateInPhrase.Error())
+
+}
+
+func TestHandler_CreateWebhook(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	post := func(request CreateWebhookRequestV1) (*httptest.ResponseRecorder, *gin.Context) {
+		body, _ := json.Marshal(request)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		return w, c
+	}
+
+	createdAt := time.Date(2028, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	mockService.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, webhook *models.Webhook) error {
+		assert.Equal(t, 1, webhook.UserID)
+		assert.Equal(t, "https://bot.example.com/hook", webhook.URL)
+		webhook.WebhookID, webhook.Secret, webhook.CreatedAt = "webhook-id", "secret", createdAt
+		return nil
+	})
+
+	w, c := post(CreateWebhookRequestV1{UserID: 1, URL: "https://bot.example.com/hook"})
+	testHandler.CreateWebhook(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result CreateWebhookResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, CreateWebhookResponseV1{
+		Webhook: WebhookDtoV1{WebhookID: "webhook-id", URL: "https://bot.example.com/hook", CreatedAt: "2028-12-01T10:00:00Z"},
+		Secret:  "secret",
+	}, resp.Result)
+
+	w, c = post(CreateWebhookRequestV1{UserID: 1})
+	testHandler.CreateWebhook(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrInvalidJSON.Error())
+
+	mockService.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(errs.ErrInvalidWebhookURL)
+
+	w, c = post(CreateWebhookRequestV1{UserID: 1, URL: "ftp://bot.example.com"})
+	testHandler.CreateWebhook(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrInvalidWebhookURL.Error())
+
+	mockService.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(errs.ErrMaxWebhooks)
+
+	w, c = post(CreateWebhookRequestV1{UserID: 1, URL: "https://bot.example.com/hook"})
+	testHandler.CreateWebhook(c)
+
+	assertErrorResponse(t, w, http.StatusServiceUnavailable, errs.ErrMaxWebhooks.Error())
+
+}
+
+func TestHandler_GetWebhooks(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1", nil)
+
+	mockService.EXPECT().GetWebhooks(gomock.Any(), 1).Return([]models.Webhook{
+		{WebhookID: "webhook-id", URL: "https://bot.example.com/hook", Secret: "secret", CreatedAt: time.Date(2028, 12, 1, 10, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	testHandler.GetWebhooks(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var resp struct{ Result ListOfWebhooksResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []WebhookDtoV1{
+		{WebhookID: "webhook-id", URL: "https://bot.example.com/hook", CreatedAt: "2028-12-01T10:00:00Z"},
+	}, resp.Result.Webhooks)
+
+}
+
+func TestHandler_DeleteWebhook(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	body, _ := json.Marshal(DeleteWebhookRequestV1{UserID: 1, WebhookID: "webhook-id"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockService.EXPECT().DeleteWebhook(gomock.Any(), 1, "webhook-id").Return(errs.ErrWebhookNotFound)
+
+	testHandler.DeleteWebhook(c)
+
+	assertErrorResponse(t, w, http.StatusServiceUnavailable, errs.ErrWebhookNotFound.Error())
+
+}
+
+func TestHandler_GetDeliveries(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	at := time.Date(2028, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&webhook_id=webhook-id&status=dead", nil)
+
+	mockService.EXPECT().GetDeliveries(gomock.Any(), 1, "webhook-id", models.DeliveryDead).Return([]models.Delivery{
+		{DeliveryID: "d2", Type: "event.updated", Status: models.DeliveryPending, Attempts: 1, LastStatus: 502, LastError: "bad gateway", NextAttempt: at.Add(10 * time.Second), CreatedAt: at, UpdatedAt: at},
+		{DeliveryID: "d1", Type: "event.created", Status: models.DeliveryDead, Attempts: 8, LastError: "timeout", NextAttempt: at, CreatedAt: at, UpdatedAt: at},
+	}, nil)
+
+	testHandler.GetDeliveries(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result ListOfDeliveriesResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []DeliveryDtoV1{
+		{DeliveryID: "d2", Type: "event.updated", Status: "pending", Attempts: 1, LastStatus: 502, LastError: "bad gateway", NextAttempt: "2028-12-01T10:00:10Z", CreatedAt: "2028-12-01T10:00:00Z", UpdatedAt: "2028-12-01T10:00:00Z"},
+		{DeliveryID: "d1", Type: "event.created", Status: "dead", Attempts: 8, LastError: "timeout", CreatedAt: "2028-12-01T10:00:00Z", UpdatedAt: "2028-12-01T10:00:00Z"},
+	}, resp.Result.Deliveries)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?user_id=1&webhook_id=webhook-id&status=failed", nil)
+
+	mockService.EXPECT().GetDeliveries(gomock.Any(), 1, "webhook-id", models.DeliveryStatus("failed")).Return(nil, errs.ErrInvalidDeliveryStatus)
+
+	testHandler.GetDeliveries(c)
+
+	assertErrorResponse(t, w, http.StatusBadRequest, errs.ErrInvalidDeliveryStatus.Error())
+
+}
+
+func TestHandler_RetryDelivery(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockService := serviceMock.NewMockService(controller)
+	mockLogger := loggerMock.NewMockLogger(controller)
+
+	testHandler := NewHandler(mockService, mockLogger)
+	gin.SetMode(gin.TestMode)
+
+	body, _ := json.Marshal(RetryDeliveryRequestV1{UserID: 1, WebhookID: "webhook-id", DeliveryID: "delivery-id"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockService.EXPECT().RetryDelivery(gomock.Any(), 1, "webhook-id", "delivery-id").Return(nil)
+
+	testHandler.RetryDelivery(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct{ Result RetryDeliveryResponseV1 }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Result.Queued)
+
+}
+
 func assertErrorResponse(t *testing.T, w *httptest.ResponseRecorder, wantStatus int, wantMsg string) {
 	t.Helper()
 	assert.Equal(t, wantStatus, w.Code)
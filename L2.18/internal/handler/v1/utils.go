@@ -2,11 +2,14 @@ package v1
 
 import (
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"time"
 
 	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/pkg/holidays"
 	"github.com/gin-gonic/gin"
 )
 
@@ -39,6 +42,28 @@ func parseQuery(userID string, eventDate string) (int, time.Time, error) {
 
 }
 
+// parseUserID parses and validates a user ID query parameter.
+//
+// userID: string representing the user's ID from query parameters.
+//
+// Returns:
+// - user ID as int
+// - error if the parameter is missing or not a number
+func parseUserID(userID string) (int, error) {
+
+	if userID == "" {
+		return 0, errs.ErrInvalidUserID
+	}
+
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return 0, errs.ErrInvalidUserID
+	}
+
+	return id, nil
+
+}
+
 // parseDate parses a date string in "YYYY-MM-DD" format.
 //
 // date: string representation of the date.
@@ -61,6 +86,111 @@ func parseDate(date string) (time.Time, error) {
 
 }
 
+// filePart returns the multipart part of the request carrying the file field, positioned
+// at the start of its content, so the file can be streamed without buffering it whole.
+//
+// Returns:
+// - the part of the "file" form field
+// - errs.ErrMissingFile if the request is not multipart or has no file field
+func filePart(r *http.Request) (*multipart.Part, error) {
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errs.ErrMissingFile
+	}
+
+	for {
+
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, errs.ErrMissingFile
+		}
+
+		if part.FormName() == "file" {
+			return part, nil
+		}
+
+		part.Close()
+
+	}
+
+}
+
+// toAttachmentDto converts an attachment to its API representation.
+func toAttachmentDto(attachment models.Attachment) AttachmentDtoV1 {
+	return AttachmentDtoV1{
+		AttachmentID: attachment.AttachmentID,
+		Name:         attachment.Name,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		UploadedAt:   attachment.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// toQuickAddResponse converts the interpretation of a quick-add phrase to its API representation.
+// Upcoming dates are only listed for recurring events.
+func toQuickAddResponse(result models.QuickAdd) QuickAddResponseV1 {
+
+	response := QuickAddResponseV1{
+		Date:     result.Event.Meta.EventDate.Format("2006-01-02"),
+		Text:     result.Event.Data.Text,
+		Language: result.Language,
+		Matched:  result.DatePhrase,
+		Created:  result.Created,
+		EventID:  result.Event.Meta.EventID,
+		Warning:  result.Event.Meta.Warning,
+	}
+
+	if result.Recurrence == nil {
+		return response
+	}
+
+	response.Recurrence = &RecurrenceDtoV1{Frequency: string(result.Recurrence.Frequency)}
+	if result.Recurrence.Frequency == models.Weekly {
+		response.Recurrence.Weekday = holidays.FormatWeekdays([]time.Weekday{result.Recurrence.Weekday})[0]
+	}
+
+	response.Upcoming = make([]string, len(result.Occurrences))
+	for i, date := range result.Occurrences {
+		response.Upcoming[i] = date.Format("2006-01-02")
+	}
+
+	return response
+
+}
+
+// toWebhookDto converts a webhook to its API representation. The secret is left out.
+func toWebhookDto(webhook models.Webhook) WebhookDtoV1 {
+	return WebhookDtoV1{
+		WebhookID: webhook.WebhookID,
+		URL:       webhook.URL,
+		CreatedAt: webhook.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// toDeliveryDto converts a delivery to its API representation. The next attempt is only
+// reported for deliveries still waiting in the queue.
+func toDeliveryDto(delivery models.Delivery) DeliveryDtoV1 {
+
+	dto := DeliveryDtoV1{
+		DeliveryID: delivery.DeliveryID,
+		Type:       delivery.Type,
+		Status:     string(delivery.Status),
+		Attempts:   delivery.Attempts,
+		LastStatus: delivery.LastStatus,
+		LastError:  delivery.LastError,
+		CreatedAt:  delivery.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  delivery.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if delivery.Status == models.DeliveryPending {
+		dto.NextAttempt = delivery.NextAttempt.Format(time.RFC3339)
+	}
+
+	return dto
+
+}
+
 // respondOK sends a successful JSON response to the client.
 //
 // c: Gin context
@@ -99,15 +229,41 @@ func mapErrorToStatus(err error) (int, string) {
 		errors.Is(err, errs.ErrEventTextTooLong),
 		errors.Is(err, errs.ErrMissingEventID),
 		errors.Is(err, errs.ErrMissingParams),
-		errors.Is(err, errs.ErrMissingDate):
+		errors.Is(err, errs.ErrMissingDate),
+		errors.Is(err, errs.ErrUnknownCountry),
+		errors.Is(err, errs.ErrInvalidWorkingDay),
+		errors.Is(err, errs.ErrMissingFile),
+		errors.Is(err, errs.ErrInvalidAttachmentID),
+		errors.Is(err, errs.ErrInvalidWebhookURL),
+		errors.Is(err, errs.ErrWebhookHost),
+		errors.Is(err, errs.ErrInvalidWebhookID),
+		errors.Is(err, errs.ErrInvalidDeliveryStatus),
+		errors.Is(err, errs.ErrEmptyPhrase),
+		errors.Is(err, errs.ErrNoDateInPhrase),
+		errors.Is(err, errs.ErrManyDatesInPhrase),
+		errors.Is(err, errs.ErrNoSuchDate):
 		return http.StatusBadRequest, err.Error()
 
+	case errors.Is(err, errs.ErrAttachmentSize):
+		return http.StatusRequestEntityTooLarge, err.Error()
+
+	case errors.Is(err, errs.ErrAttachmentType):
+		return http.StatusUnsupportedMediaType, err.Error()
+
 	case errors.Is(err, errs.ErrMaxEvents),
 		errors.Is(err, errs.ErrEventNotFound),
 		errors.Is(err, errs.ErrNothingToUpdate),
 		errors.Is(err, errs.ErrEventInPast),
 		errors.Is(err, errs.ErrEventTooFar),
-		errors.Is(err, errs.ErrUnauthorized):
+		errors.Is(err, errs.ErrNonWorkingDay),
+		errors.Is(err, errs.ErrMaxAttachments),
+		errors.Is(err, errs.ErrAttachmentNotFound),
+		errors.Is(err, errs.ErrMaxWebhooks),
+		errors.Is(err, errs.ErrWebhookNotFound),
+		errors.Is(err, errs.ErrDeliveryNotFound),
+		errors.Is(err, errs.ErrNotDeadLetter),
+		errors.Is(err, errs.ErrUnauthorized),
+		errors.Is(err, errs.ErrStorageClosed):
 		return http.StatusServiceUnavailable, err.Error()
 
 	default:
@@ -94,6 +94,7 @@ func TestServiceUnavailable(t *testing.T) {
 		errs.ErrEventInPast,
 		errs.ErrEventTooFar,
 		errs.ErrUnauthorized,
+		errs.ErrStorageClosed,
 	}
 
 	for _, e := range tests {
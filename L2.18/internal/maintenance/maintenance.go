@@ -0,0 +1,466 @@
+// Package maintenance implements the offline subcommands of the calendar binary: backup,
+// restore and migrate.
+//
+// They work on files while the service is stopped. Data is always loaded in full into
+// a scratch storage first, so every record is validated and checked for duplicates
+// before anything is written, and outputs replace their files atomically.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"L2.18/internal/backup"
+	"L2.18/internal/config"
+	"L2.18/internal/repository"
+	"L2.18/pkg/clock"
+)
+
+// Exit codes returned by Run.
+const (
+	exitOK      = 0 // command succeeded
+	exitFailure = 1 // unexpected failure, e.g. an unreadable file
+	exitUsage   = 2 // invalid command line
+	exitInvalid = 3 // the input is not a valid backup or snapshot
+)
+
+// Kinds of data a migration reads from and writes to.
+const (
+	kindSnapshot = "memory-snapshot" // snapshot file of the in-memory storage
+	kindBackup   = "backup"          // backup file
+	kindSQL      = "sql"             // PostgreSQL script, written only
+)
+
+// usage is printed by "calendar help" and on invalid invocations.
+const usage = `calendar — calendar service and its offline maintenance commands
+
+Usage:
+  calendar                                    run the service
+  calendar <command> [flags]                  run a maintenance command, with the service stopped
+
+Commands:
+  backup  --out FILE                          back up the storage snapshot to FILE
+  restore --in FILE                           validate the backup FILE and make it the storage snapshot
+  migrate --from KIND --to KIND               convert data between kinds:
+          [--in FILE] [--out FILE]              memory-snapshot  the storage snapshot file
+                                                backup           a backup file (--in or --out)
+                                                sql              a PostgreSQL script (--out, target only)
+
+Flags:
+  --snapshot PATH   storage snapshot file (default snapshot_file of config.yaml)
+  --force           replace the output if it exists
+
+Exit codes:
+  0 success, 1 unexpected failure, 2 invalid usage, 3 invalid backup or snapshot
+`
+
+// usageError is an invalid command line.
+type usageError struct {
+	msg string
+}
+
+// Error returns the usage error message.
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usageErrorf creates a usageError with a formatted message.
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// endpoint is the source or the target of a transfer.
+type endpoint struct {
+	kind string // kindSnapshot, kindBackup or kindSQL
+	path string // file read or written
+}
+
+// String describes the endpoint in messages.
+func (e endpoint) String() string {
+	return e.kind + " " + e.path
+}
+
+// output is a writer of records in a file format, completed by Close.
+type output interface {
+	backup.Sink
+	Records() int
+	Close() error
+}
+
+// session carries the state of a single invocation.
+type session struct {
+	stdout io.Writer   // destination of command output
+	stderr io.Writer   // destination of errors and usage
+	clock  clock.Clock // time stamped in written files
+}
+
+// Run executes a maintenance command with the given arguments and returns the process exit code.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+
+	s := &session{stdout: stdout, stderr: stderr, clock: clock.System()}
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	err := s.dispatch(ctx, args[0], args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	fmt.Fprintln(stderr, "calendar:", err)
+
+	return exitCode(err)
+
+}
+
+// dispatch runs the named command.
+func (s *session) dispatch(ctx context.Context, command string, args []string) error {
+
+	switch command {
+
+	case "help", "-h", "--help":
+		fmt.Fprint(s.stdout, usage)
+		return nil
+
+	case "backup":
+		return s.backup(ctx, args)
+
+	case "restore":
+		return s.restore(ctx, args)
+
+	case "migrate":
+		return s.migrate(ctx, args)
+
+	default:
+		return usageErrorf("unknown command %q, run 'calendar help' for usage", command)
+
+	}
+
+}
+
+// flagSet creates a flag set for a command with the common flags registered.
+func (s *session) flagSet(name string, snapshot *string, force *bool) *flag.FlagSet {
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(s.stderr)
+
+	flags.StringVar(snapshot, "snapshot", "", "storage snapshot file")
+	flags.BoolVar(force, "force", false, "replace the output if it exists")
+
+	return flags
+
+}
+
+// parse parses the command flags.
+func parse(flags *flag.FlagSet, args []string) error {
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+
+	if flags.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	return nil
+
+}
+
+// backup runs the "backup" command.
+func (s *session) backup(ctx context.Context, args []string) error {
+
+	var snapshot, out string
+	var force bool
+
+	flags := s.flagSet("backup", &snapshot, &force)
+	flags.StringVar(&out, "out", "", "backup file to write")
+
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if out == "" {
+		return usageErrorf("backup requires --out")
+	}
+
+	snapshot, err := snapshotPath(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return s.transfer(ctx, endpoint{kindSnapshot, snapshot}, endpoint{kindBackup, out}, force)
+
+}
+
+// restore runs the "restore" command.
+func (s *session) restore(ctx context.Context, args []string) error {
+
+	var snapshot, in string
+	var force bool
+
+	flags := s.flagSet("restore", &snapshot, &force)
+	flags.StringVar(&in, "in", "", "backup file to read")
+
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if in == "" {
+		return usageErrorf("restore requires --in")
+	}
+
+	snapshot, err := snapshotPath(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return s.transfer(ctx, endpoint{kindBackup, in}, endpoint{kindSnapshot, snapshot}, force)
+
+}
+
+// migrate runs the "migrate" command.
+func (s *session) migrate(ctx context.Context, args []string) error {
+
+	var snapshot, from, to, in, out string
+	var force bool
+
+	flags := s.flagSet("migrate", &snapshot, &force)
+	flags.StringVar(&from, "from", "", "kind of data to read: memory-snapshot or backup")
+	flags.StringVar(&to, "to", "", "kind of data to write: memory-snapshot, backup or sql")
+	flags.StringVar(&in, "in", "", "file to read, when migrating from a backup")
+	flags.StringVar(&out, "out", "", "file to write, when migrating to a backup or sql")
+
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if from != kindSnapshot && from != kindBackup {
+		return usageErrorf("migrate requires --from memory-snapshot or backup, got %q", from)
+	}
+	if to != kindSnapshot && to != kindBackup && to != kindSQL {
+		return usageErrorf("migrate requires --to memory-snapshot, backup or sql, got %q", to)
+	}
+	if from == to {
+		return usageErrorf("migrate requires different --from and --to")
+	}
+
+	source, err := s.endpoint(from, in, "in", snapshot)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.endpoint(to, out, "out", snapshot)
+	if err != nil {
+		return err
+	}
+
+	return s.transfer(ctx, source, target, force)
+
+}
+
+// endpoint resolves the file of a migration source or target: the snapshot file for
+// kindSnapshot, otherwise path, which must be given with the named flag.
+func (s *session) endpoint(kind, path, flagName, snapshot string) (endpoint, error) {
+
+	if kind != kindSnapshot {
+		if path == "" {
+			return endpoint{}, usageErrorf("migrate requires --%s for %s", flagName, kind)
+		}
+		return endpoint{kind, path}, nil
+	}
+
+	if path != "" {
+		return endpoint{}, usageErrorf("--%s does not apply to memory-snapshot, use --snapshot", flagName)
+	}
+
+	snapshot, err := snapshotPath(snapshot)
+	if err != nil {
+		return endpoint{}, err
+	}
+
+	return endpoint{kind, snapshot}, nil
+
+}
+
+// snapshotPath returns flagValue if set, otherwise the snapshot file of config.yaml.
+func snapshotPath(flagValue string) (string, error) {
+
+	if flagValue != "" {
+		return flagValue, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return "", usageErrorf("cannot read config.yaml (%v), pass --snapshot", err)
+	}
+
+	if cfg.Storage.SnapshotFile == "" {
+		return "", usageErrorf("snapshots are disabled in config.yaml, pass --snapshot")
+	}
+
+	return cfg.Storage.SnapshotFile, nil
+
+}
+
+// transfer loads all data of source into a scratch storage and writes it to target.
+// Unless force is set, an existing target is left alone.
+func (s *session) transfer(ctx context.Context, source, target endpoint, force bool) error {
+
+	if !force {
+		if _, err := os.Stat(target.path); err == nil {
+			return fmt.Errorf("%s already exists, pass --force to replace it", target.path)
+		}
+	}
+
+	snapshotFile := source.path
+	if target.kind == kindSnapshot {
+		snapshotFile = target.path
+	}
+
+	storage := repository.NewStorage(nil, config.Storage{SnapshotFile: snapshotFile}, s.clock, quietLogger{})
+	defer storage.Close()
+
+	records, err := load(ctx, storage, source)
+	if err != nil {
+		return err
+	}
+
+	if target.kind == kindSnapshot {
+		err = storage.(repository.Snapshotter).Snapshot(ctx)
+	} else {
+		err = s.write(ctx, storage.(repository.Dumper), target)
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", target, err)
+	}
+
+	fmt.Fprintf(s.stdout, "%d records copied from %s to %s\n", records, source, target)
+
+	return nil
+
+}
+
+// load reads all data of source into storage, whose snapshot file is source.path if
+// source is a snapshot. Returns the number of records loaded.
+func load(ctx context.Context, storage repository.Storage, source endpoint) (int, error) {
+
+	if _, err := os.Stat(source.path); errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("read %s: no such file", source)
+	}
+
+	if source.kind == kindSnapshot {
+		return storage.(repository.Snapshotter).LoadSnapshot(ctx)
+	}
+
+	file, err := os.Open(source.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	r, err := backup.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("read %s: %w", source, err)
+	}
+
+	records, err := storage.(repository.Dumper).Load(ctx, r)
+	if err != nil {
+		return 0, fmt.Errorf("read %s: %w", source, err)
+	}
+
+	return records, nil
+
+}
+
+// write dumps the storage to the backup or SQL file of target, replacing it atomically.
+func (s *session) write(ctx context.Context, dumper repository.Dumper, target endpoint) (err error) {
+
+	file, err := os.CreateTemp(filepath.Dir(target.path), ".calendar-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	err = dump(ctx, dumper, file, target.kind, s.clock)
+	if syncErr := file.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), target.path)
+
+}
+
+// dump writes the data of dumper to w in the format of kind.
+func dump(ctx context.Context, dumper repository.Dumper, w io.Writer, kind string, clock clock.Clock) error {
+
+	var out output
+	var err error
+
+	if kind == kindSQL {
+		out, err = backup.NewSQLWriter(w, clock.Now())
+	} else {
+		out, err = backup.NewWriter(w, clock.Now())
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := dumper.Dump(ctx, out); err != nil {
+		return err
+	}
+
+	return out.Close()
+
+}
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+
+	for _, invalid := range []error{backup.ErrNotBackup, backup.ErrVersion, backup.ErrInvalidRecord, backup.ErrTruncated,
+		backup.ErrRecordCount, backup.ErrTrailingData, backup.ErrDuplicate} {
+		if errors.Is(err, invalid) {
+			return exitInvalid
+		}
+	}
+
+	return exitFailure
+
+}
+
+// quietLogger is the logger of the scratch storage, discarding everything: the
+// commands report on stdout and stderr instead.
+type quietLogger struct{}
+
+func (quietLogger) LogFatal(string, error, ...any) {}
+func (quietLogger) LogError(string, error, ...any) {}
+func (quietLogger) LogWarn(string, ...any)         {}
+func (quietLogger) LogInfo(string, ...any)         {}
+func (quietLogger) Debug(string, ...any)           {}
+func (quietLogger) SetDebug(bool)                  {}
+func (quietLogger) Close()                         {}
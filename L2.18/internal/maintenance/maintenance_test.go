@@ -0,0 +1,142 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sampleBackup = `{"format":"l2.18-backup","version":1,"created_at":"2030-01-07T12:00:00Z"}
+{"kind":"settings","tenant":"default","settings":{"user_id":1,"country":"RU","working_days":[1,2,3,4,5]}}
+{"kind":"event","tenant":"default","event":{"event_id":"3383503d-fb71-4b8c-85bd-a914c84252a9","user_id":1,"date":"2030-01-08","text":"standup"}}
+{"kind":"event","tenant":"team-b","event":{"event_id":"9c1d6a3e-2f4b-4e8a-9d7c-5b6a1e2f3d4c","user_id":2,"date":"2030-01-09","text":"retro"}}
+{"kind":"end","records":3}
+`
+
+// run executes a maintenance command and returns its exit code, stdout and stderr.
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// write creates a file named name in dir with content and returns its path.
+func write(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRun_RestoreBackupRoundTrip(t *testing.T) {
+
+	dir := t.TempDir()
+	in := write(t, dir, "in.jsonl", sampleBackup)
+	snapshot := filepath.Join(dir, "snapshot.jsonl")
+	out := filepath.Join(dir, "out.jsonl")
+
+	code, stdout, stderr := run("restore", "--in", in, "--snapshot", snapshot)
+	require.Equal(t, exitOK, code, stderr)
+	require.Equal(t, "3 records copied from backup "+in+" to memory-snapshot "+snapshot+"\n", stdout)
+
+	code, _, stderr = run("backup", "--out", out, "--snapshot", snapshot)
+	require.Equal(t, exitOK, code, stderr)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	// Only the creation time in the header differs.
+	got := strings.SplitN(string(data), "\n", 2)
+	want := strings.SplitN(sampleBackup, "\n", 2)
+	require.Equal(t, want[1], got[1])
+
+	code, _, stderr = run("backup", "--out", out, "--snapshot", snapshot)
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "already exists, pass --force")
+
+	code, _, stderr = run("backup", "--out", out, "--snapshot", snapshot, "--force")
+	require.Equal(t, exitOK, code, stderr)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "no temporary files are left behind")
+
+}
+
+func TestRun_MigrateToSQL(t *testing.T) {
+
+	dir := t.TempDir()
+	snapshot := filepath.Join(dir, "snapshot.jsonl")
+	out := filepath.Join(dir, "calendar.sql")
+
+	code, _, stderr := run("migrate", "--from", "backup", "--to", "memory-snapshot", "--in", write(t, dir, "in.jsonl", sampleBackup), "--snapshot", snapshot)
+	require.Equal(t, exitOK, code, stderr)
+
+	code, stdout, stderr := run("migrate", "--from", "memory-snapshot", "--to", "sql", "--out", out, "--snapshot", snapshot)
+	require.Equal(t, exitOK, code, stderr)
+	require.Contains(t, stdout, "3 records copied")
+
+	script, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Contains(t, string(script), "INSERT INTO events (event_id, tenant, user_id, event_date, text) VALUES ('9c1d6a3e-2f4b-4e8a-9d7c-5b6a1e2f3d4c', 'team-b', 2, '2030-01-09', 'retro');")
+	require.True(t, strings.HasSuffix(string(script), "COMMIT;\n"))
+
+}
+
+func TestRun_InvalidInput(t *testing.T) {
+
+	dir := t.TempDir()
+	snapshot := filepath.Join(dir, "snapshot.jsonl")
+
+	invalid := strings.Replace(sampleBackup, `"user_id":2`, `"user_id":-2`, 1)
+	code, _, stderr := run("restore", "--in", write(t, dir, "invalid.jsonl", invalid), "--snapshot", snapshot)
+	require.Equal(t, exitInvalid, code)
+	require.Contains(t, stderr, "line 4")
+	require.NoFileExists(t, snapshot)
+
+	truncated := strings.Join(strings.Split(sampleBackup, "\n")[:3], "\n")
+	code, _, stderr = run("restore", "--in", write(t, dir, "truncated.jsonl", truncated), "--snapshot", snapshot)
+	require.Equal(t, exitInvalid, code)
+	require.Contains(t, stderr, "truncated")
+	require.NoFileExists(t, snapshot)
+
+	code, _, stderr = run("backup", "--out", filepath.Join(dir, "out.jsonl"), "--snapshot", snapshot)
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "no such file")
+
+}
+
+func TestRun_Usage(t *testing.T) {
+
+	tests := []struct {
+		name string
+		args []string
+		msg  string
+	}{
+		{"unknown command", []string{"serve"}, `unknown command "serve"`},
+		{"backup without output", []string{"backup", "--snapshot", "s"}, "backup requires --out"},
+		{"restore without input", []string{"restore", "--snapshot", "s"}, "restore requires --in"},
+		{"unknown source", []string{"migrate", "--from", "sql", "--to", "backup"}, "--from memory-snapshot or backup"},
+		{"same kinds", []string{"migrate", "--from", "backup", "--to", "backup"}, "different --from and --to"},
+		{"missing file", []string{"migrate", "--from", "backup", "--to", "sql", "--in", "a"}, "requires --out for sql"},
+		{"file for snapshot", []string{"migrate", "--from", "memory-snapshot", "--to", "sql", "--in", "a", "--out", "b"}, "--in does not apply"},
+		{"arguments", []string{"backup", "--out", "a", "extra"}, "unexpected arguments: extra"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := run(tt.args...)
+			require.Equal(t, exitUsage, code)
+			require.Contains(t, stderr, tt.msg)
+		})
+	}
+
+	code, stdout, _ := run("help")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "migrate --from KIND --to KIND")
+
+}
@@ -24,9 +24,120 @@ type Meta struct {
 	EventID   string    // Unique identifier for the event
 	EventDate time.Time // Original date of the event
 	NewDate   time.Time // Updated date of the event (if modified)
+
+	Holiday    string // Name of the holiday on the event date, if any (set by the service)
+	NonWorking bool   // Whether the event date is a non-working day for the user (set by the service)
+	Warning    string // Non-fatal validation warning for the caller (set by the service)
 }
 
 // Data contains the actual content of the event.
 type Data struct {
 	Text string // Text description of the event
 }
+
+// UserStats describes a user and the number of events they own.
+type UserStats struct {
+	UserID int // ID of the user
+	Events int // Number of events owned by the user
+}
+
+// DayCount is a single bucket of the events-per-day histogram.
+type DayCount struct {
+	Date   time.Time // Day of the bucket
+	Events int       // Number of events on that day across all users
+}
+
+// Stats contains global statistics of the event store.
+type Stats struct {
+	Users        int        // Number of users with at least one event
+	Events       int        // Total number of events
+	Upcoming     int        // Number of events dated today or later
+	EventsPerDay []DayCount // Events per day histogram, ordered by date
+	SizeBytes    int64      // Approximate memory or disk footprint of the stored events
+}
+
+// Settings holds a user's working calendar preferences.
+type Settings struct {
+	UserID      int            // ID of the user
+	Country     string         // Country code of the user's holiday calendar, empty for none
+	WorkingDays []time.Weekday // Days of the week the user works, ordered from Sunday
+}
+
+// Holiday is a named holiday on a specific day.
+type Holiday struct {
+	Date time.Time // Day of the holiday
+	Name string    // Name of the holiday
+}
+
+// Attachment describes a file attached to an event. The file content itself is kept in a blob store.
+type Attachment struct {
+	AttachmentID string    // Unique identifier of the attachment
+	EventID      string    // ID of the event the file is attached to
+	UserID       int       // ID of the user who owns the event
+	Name         string    // Original file name, without directories
+	ContentType  string    // MIME type detected from the file content
+	Size         int64     // Size of the file in bytes
+	CreatedAt    time.Time // Time the file was uploaded
+}
+
+// Webhook is a URL registered by a user to be notified about changes of their events.
+type Webhook struct {
+	WebhookID string    // Unique identifier of the webhook
+	Tenant    string    // Tenant of the user who registered the webhook
+	UserID    int       // ID of the user whose events are reported
+	URL       string    // Absolute http or https URL the notifications are posted to
+	Secret    string    // Key used to sign the notifications with HMAC-SHA256
+	CreatedAt time.Time // Time the webhook was registered
+}
+
+// DeliveryStatus is the state of a webhook delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // DeliveryPending is waiting for its first or next attempt.
+	DeliveryDelivered DeliveryStatus = "delivered" // DeliveryDelivered was accepted by the receiver.
+	DeliveryDead      DeliveryStatus = "dead"      // DeliveryDead ran out of attempts and sits in the dead-letter list.
+)
+
+// Delivery is a single notification sent, or to be sent, to a webhook.
+type Delivery struct {
+	DeliveryID  string         // Unique identifier of the delivery, also sent to the receiver
+	WebhookID   string         // ID of the webhook the notification is sent to
+	Tenant      string         // Tenant of the webhook
+	UserID      int            // ID of the user who owns the webhook
+	Type        string         // Kind of change, e.g. event.created
+	Payload     []byte         // JSON body posted to the webhook
+	Status      DeliveryStatus // Current state of the delivery
+	Attempts    int            // Number of attempts made so far
+	NextAttempt time.Time      // Earliest time of the next attempt of a pending delivery
+	LastStatus  int            // HTTP status code of the last attempt, 0 if there was no response
+	LastError   string         // Error of the last failed attempt, empty if none
+	CreatedAt   time.Time      // Time the delivery was queued
+	UpdatedAt   time.Time      // Time of the last attempt or state change
+}
+
+// Frequency is how often a recurring event repeats.
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"   // Daily repeats every day.
+	Weekly  Frequency = "weekly"  // Weekly repeats every week on the same day of the week.
+	Monthly Frequency = "monthly" // Monthly repeats every month on the same day.
+	Yearly  Frequency = "yearly"  // Yearly repeats every year on the same date.
+)
+
+// Recurrence describes how an event repeats.
+type Recurrence struct {
+	Frequency Frequency    // How often the event repeats
+	Weekday   time.Weekday // Day of the week of weekly events
+}
+
+// QuickAdd is the interpretation of a free-form quick-add phrase.
+type QuickAdd struct {
+	Event       Event       // Event read from the phrase; its ID is set once it is created
+	Recurrence  *Recurrence // How the event repeats, nil for one-off events
+	Occurrences []time.Time // Upcoming dates of a recurring event, starting with the event date
+	Language    string      // Language the date was read in: en or ru
+	DatePhrase  string      // Words of the phrase read as the date and recurrence
+	Created     bool        // Whether the event was created
+}
@@ -0,0 +1,368 @@
+// Package jsonfile provides a WebhookStore implementation persisting webhooks and
+// their delivery queue to a single JSON file.
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"L2.18/internal/models"
+	"L2.18/pkg/logger"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the webhook store spans.
+var tracer = tracing.Tracer("L2.18/internal/repository/jsonfile")
+
+// errClosed is returned by writes made after the store has been closed.
+var errClosed = errors.New("webhook store is closed")
+
+// WebhookStore is a JSON-file implementation of the repository.WebhookStore interface.
+//
+// All data is kept in memory and the whole file is rewritten after every change, so a
+// queued delivery is on disk before the call that queued it returns. The file is written
+// to a temporary file first and renamed into place, so a crash never leaves it truncated.
+// This suits the modest number of webhooks and pending deliveries of a calendar; the log
+// of finished deliveries is capped per webhook to keep the file small.
+//
+// All methods are thread-safe using an internal mutex.
+type WebhookStore struct {
+	path       string            // file holding the persisted state
+	logSize    int               // finished deliveries kept per webhook, separately for delivered and dead ones
+	webhooks   []models.Webhook  // registered webhooks in registration order
+	deliveries []models.Delivery // deliveries in queue order
+	closed     bool              // set by Close; later writes fail
+	logger     logger.Logger     // logger instance
+	mu         sync.Mutex        // protects all fields above
+}
+
+// state is the persisted content of the file.
+type state struct {
+	Webhooks   []models.Webhook  `json:"webhooks"`
+	Deliveries []models.Delivery `json:"deliveries"`
+}
+
+// NewWebhookStore opens the store persisted in path, creating its directory if needed.
+// A missing file is treated as an empty store; a file that cannot be parsed is an error,
+// so a damaged queue is never silently replaced.
+func NewWebhookStore(path string, logSize int, logger logger.Logger) (*WebhookStore, error) {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create webhook store directory: %w", err)
+	}
+
+	store := &WebhookStore{path: path, logSize: logSize, logger: logger}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved state
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook store %s: %w", path, err)
+	}
+
+	store.webhooks, store.deliveries = saved.Webhooks, saved.Deliveries
+
+	return store, nil
+
+}
+
+// SaveWebhook registers a webhook for the tenant in ctx.
+func (s *WebhookStore) SaveWebhook(ctx context.Context, webhook *models.Webhook) (err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.jsonfile.SaveWebhook", trace.WithAttributes(attribute.Int("user.id", webhook.UserID)))
+	defer func() { tracing.End(span, err) }()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errClosed
+	}
+
+	webhook.Tenant = tenant.ID(ctx)
+
+	if err := s.update(func() { s.webhooks = append(s.webhooks, *webhook) }); err != nil {
+		return err
+	}
+
+	s.logger.Debug("repository — webhook saved", "UserID", webhook.UserID, "WebhookID", webhook.WebhookID, "request_id", tracing.RequestID(ctx), "layer", "repository.jsonfile")
+
+	return nil
+
+}
+
+// GetWebhooks returns the webhooks of a user of the tenant in ctx, in registration order.
+func (s *WebhookStore) GetWebhooks(ctx context.Context, userID int) []models.Webhook {
+
+	ctx, span := tracer.Start(ctx, "repository.jsonfile.GetWebhooks", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := tenant.ID(ctx)
+
+	var res []models.Webhook
+	for _, w := range s.webhooks {
+		if w.Tenant == id && w.UserID == userID {
+			res = append(res, w)
+		}
+	}
+
+	return res
+
+}
+
+// DeleteWebhook removes a webhook of a user of the tenant in ctx together with its pending
+// and logged deliveries. Deleting a missing webhook is not an error.
+func (s *WebhookStore) DeleteWebhook(ctx context.Context, userID int, webhookID string) (err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.jsonfile.DeleteWebhook", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("webhook.id", webhookID)))
+	defer func() { tracing.End(span, err) }()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errClosed
+	}
+
+	id := tenant.ID(ctx)
+
+	err = s.update(func() {
+		s.webhooks = slices.DeleteFunc(s.webhooks, func(w models.Webhook) bool {
+			return w.Tenant == id && w.UserID == userID && w.WebhookID == webhookID
+		})
+		s.deliveries = slices.DeleteFunc(s.deliveries, func(d models.Delivery) bool {
+			return d.Tenant == id && d.UserID == userID && d.WebhookID == webhookID
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("repository — webhook deleted", "UserID", userID, "WebhookID", webhookID, "request_id", tracing.RequestID(ctx), "layer", "repository.jsonfile")
+
+	return nil
+
+}
+
+// Enqueue appends deliveries for the tenant in ctx to the queue.
+func (s *WebhookStore) Enqueue(ctx context.Context, deliveries []models.Delivery) (err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.jsonfile.Enqueue", trace.WithAttributes(attribute.Int("deliveries", len(deliveries))))
+	defer func() { tracing.End(span, err) }()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errClosed
+	}
+
+	id := tenant.ID(ctx)
+
+	err = s.update(func() {
+		for _, d := range deliveries {
+			d.Tenant = id
+			s.deliveries = append(s.deliveries, d)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("repository — webhook deliveries queued", "deliveries", len(deliveries), "request_id", tracing.RequestID(ctx), "layer", "repository.jsonfile")
+
+	return nil
+
+}
+
+// Due returns at most limit pending deliveries of any tenant whose next attempt is not
+// later than now, in queue order.
+func (s *WebhookStore) Due(ctx context.Context, now time.Time, limit int) []models.Delivery {
+
+	_, span := tracer.Start(ctx, "repository.jsonfile.Due")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []models.Delivery
+	for _, d := range s.deliveries {
+		if len(res) == limit {
+			break
+		}
+		if d.Status == models.DeliveryPending && !d.NextAttempt.After(now) {
+			res = append(res, d)
+		}
+	}
+
+	return res
+
+}
+
+// UpdateDelivery replaces the stored delivery with the same ID, of any tenant. Updating
+// a delivery whose webhook has been deleted in the meantime is not an error and has no
+// effect. Once the delivery is finished, the oldest finished deliveries of its webhook
+// beyond the log size are dropped.
+func (s *WebhookStore) UpdateDelivery(ctx context.Context, delivery models.Delivery) (err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.jsonfile.UpdateDelivery", trace.WithAttributes(attribute.String("delivery.id", delivery.DeliveryID), attribute.String("delivery.status", string(delivery.Status))))
+	defer func() { tracing.End(span, err) }()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errClosed
+	}
+
+	i := slices.IndexFunc(s.deliveries, func(d models.Delivery) bool { return d.DeliveryID == delivery.DeliveryID })
+	if i < 0 {
+		return nil
+	}
+
+	err = s.update(func() {
+		s.deliveries[i] = delivery
+		if delivery.Status != models.DeliveryPending {
+			s.trimLog(delivery.WebhookID, delivery.Status)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("repository — webhook delivery updated", "DeliveryID", delivery.DeliveryID, "status", delivery.Status, "attempts", delivery.Attempts, "request_id", tracing.RequestID(ctx), "layer", "repository.jsonfile")
+
+	return nil
+
+}
+
+// GetDeliveries returns the pending and logged deliveries of a webhook of a user of the
+// tenant in ctx, newest first.
+func (s *WebhookStore) GetDeliveries(ctx context.Context, userID int, webhookID string) []models.Delivery {
+
+	ctx, span := tracer.Start(ctx, "repository.jsonfile.GetDeliveries", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("webhook.id", webhookID)))
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := tenant.ID(ctx)
+
+	var res []models.Delivery
+	for i := len(s.deliveries) - 1; i >= 0; i-- {
+		if d := s.deliveries[i]; d.Tenant == id && d.UserID == userID && d.WebhookID == webhookID {
+			res = append(res, d)
+		}
+	}
+
+	return res
+
+}
+
+// Close stops the store. Everything is persisted as it changes, so nothing is flushed;
+// later writes fail instead of overwriting the file.
+func (s *WebhookStore) Close() {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.webhooks, s.deliveries = nil, nil
+
+	s.logger.LogInfo("repository — webhook store closed", "layer", "repository.jsonfile")
+
+}
+
+// trimLog drops the oldest deliveries of a webhook with the given finished status beyond
+// the log size. The caller must hold the lock.
+func (s *WebhookStore) trimLog(webhookID string, status models.DeliveryStatus) {
+
+	excess := -s.logSize
+	for _, d := range s.deliveries {
+		if d.WebhookID == webhookID && d.Status == status {
+			excess++
+		}
+	}
+
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d models.Delivery) bool {
+		if excess > 0 && d.WebhookID == webhookID && d.Status == status {
+			excess--
+			return true
+		}
+		return false
+	})
+
+}
+
+// update applies change to the in-memory state and persists it. If the state cannot be
+// persisted, the change is rolled back, so memory never holds what the file does not and
+// a failed call has no effect. The caller must hold the lock.
+func (s *WebhookStore) update(change func()) error {
+
+	webhooks, deliveries := slices.Clone(s.webhooks), slices.Clone(s.deliveries)
+
+	change()
+
+	if err := s.persist(); err != nil {
+		s.webhooks, s.deliveries = webhooks, deliveries
+		return err
+	}
+
+	return nil
+
+}
+
+// persist writes the current state to the file, replacing it atomically.
+// The caller must hold the lock.
+func (s *WebhookStore) persist() (err error) {
+
+	data, err := json.Marshal(state{Webhooks: s.webhooks, Deliveries: s.deliveries})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), ".webhooks-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	_, err = file.Write(data)
+	if syncErr := file.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write webhook store: %w", err)
+	}
+
+	return os.Rename(file.Name(), s.path)
+
+}
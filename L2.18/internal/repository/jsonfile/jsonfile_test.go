@@ -0,0 +1,144 @@
+package jsonfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"L2.18/internal/models"
+	"L2.18/pkg/logger/mocks"
+	"L2.18/pkg/tenant"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// openStore opens a store in path with a logger accepting any debug message.
+func openStore(t *testing.T, path string, logSize int) *WebhookStore {
+	t.Helper()
+	controller := gomock.NewController(t)
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().LogInfo("repository — webhook store closed", "layer", "repository.jsonfile").AnyTimes()
+	store, err := NewWebhookStore(path, logSize, mockLogger)
+	require.NoError(t, err)
+	return store
+}
+
+func TestWebhookStore_Persistence(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "data", "webhooks.json")
+	store := openStore(t, path, 10)
+
+	ctx := tenant.WithID(context.Background(), "team-a")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	webhook := models.Webhook{WebhookID: "w1", UserID: 1, URL: "https://example.com/hook", Secret: "s3cr3t", CreatedAt: now}
+	require.NoError(t, store.SaveWebhook(ctx, &webhook))
+	require.Equal(t, "team-a", webhook.Tenant)
+
+	delivery := models.Delivery{DeliveryID: "d1", WebhookID: "w1", UserID: 1, Type: "event.created", Payload: []byte(`{"id":"d1"}`), Status: models.DeliveryPending, NextAttempt: now}
+	require.NoError(t, store.Enqueue(ctx, []models.Delivery{delivery}))
+
+	reopened := openStore(t, path, 10)
+
+	require.Equal(t, []models.Webhook{webhook}, reopened.GetWebhooks(ctx, 1))
+	require.Empty(t, reopened.GetWebhooks(ctx, 2))
+	require.Empty(t, reopened.GetWebhooks(tenant.WithID(context.Background(), "team-b"), 1))
+
+	delivery.Tenant = "team-a"
+	require.Equal(t, []models.Delivery{delivery}, reopened.Due(context.Background(), now, 10))
+
+	require.NoError(t, os.WriteFile(path, []byte("{broken"), 0o600))
+	_, err := NewWebhookStore(path, 10, nil)
+	require.Error(t, err)
+
+}
+
+func TestWebhookStore_Queue(t *testing.T) {
+
+	store := openStore(t, filepath.Join(t.TempDir(), "webhooks.json"), 1)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	require.NoError(t, store.SaveWebhook(ctx, &models.Webhook{WebhookID: "w1", UserID: 1}))
+	require.NoError(t, store.Enqueue(ctx, []models.Delivery{
+		{DeliveryID: "d1", WebhookID: "w1", UserID: 1, Status: models.DeliveryPending, NextAttempt: now},
+		{DeliveryID: "d2", WebhookID: "w1", UserID: 1, Status: models.DeliveryPending, NextAttempt: now.Add(time.Minute)},
+		{DeliveryID: "d3", WebhookID: "w1", UserID: 1, Status: models.DeliveryPending, NextAttempt: now.Add(-time.Minute)},
+	}))
+
+	due := store.Due(ctx, now, 10)
+	require.Len(t, due, 2)
+	require.Equal(t, "d1", due[0].DeliveryID)
+	require.Equal(t, "d3", due[1].DeliveryID)
+	require.Len(t, store.Due(ctx, now, 1), 1)
+
+	for _, d := range due {
+		d.Status = models.DeliveryDelivered
+		require.NoError(t, store.UpdateDelivery(ctx, d))
+	}
+
+	log := store.GetDeliveries(ctx, 1, "w1")
+	require.Len(t, log, 2, "only the newest delivered delivery is kept besides the pending one")
+	require.Equal(t, "d3", log[0].DeliveryID)
+	require.Equal(t, "d2", log[1].DeliveryID)
+
+	require.NoError(t, store.UpdateDelivery(ctx, models.Delivery{DeliveryID: "gone", Status: models.DeliveryDead}))
+	require.Empty(t, store.GetDeliveries(ctx, 2, "w1"))
+
+	require.NoError(t, store.DeleteWebhook(ctx, 1, "w1"))
+	require.Empty(t, store.GetWebhooks(ctx, 1))
+	require.Empty(t, store.GetDeliveries(ctx, 1, "w1"))
+	require.Empty(t, store.Due(ctx, now.Add(time.Hour), 10))
+
+}
+
+func TestWebhookStore_Close(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	store := openStore(t, path, 10)
+
+	ctx := context.Background()
+
+	require.NoError(t, store.SaveWebhook(ctx, &models.Webhook{WebhookID: "w1", UserID: 1}))
+
+	store.Close()
+
+	require.Error(t, store.SaveWebhook(ctx, &models.Webhook{WebhookID: "w2", UserID: 1}))
+	require.Error(t, store.Enqueue(ctx, []models.Delivery{{DeliveryID: "d1"}}))
+	require.Error(t, store.DeleteWebhook(ctx, 1, "w1"))
+
+	require.Len(t, openStore(t, path, 10).GetWebhooks(ctx, 1), 1, "writes after Close must not touch the file")
+
+}
+
+func TestWebhookStore_RollsBackFailedWrites(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	store := openStore(t, path, 1)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	webhook := models.Webhook{WebhookID: "w1", UserID: 1}
+	delivery := models.Delivery{DeliveryID: "d1", WebhookID: "w1", UserID: 1, Status: models.DeliveryPending, NextAttempt: now}
+	require.NoError(t, store.SaveWebhook(ctx, &webhook))
+	require.NoError(t, store.Enqueue(ctx, []models.Delivery{delivery}))
+	delivery.Tenant = tenant.Default
+
+	// A directory in place of the file makes every later write fail at the rename.
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.Mkdir(path, 0o700))
+
+	require.Error(t, store.SaveWebhook(ctx, &models.Webhook{WebhookID: "w2", UserID: 1}))
+	require.Error(t, store.Enqueue(ctx, []models.Delivery{{DeliveryID: "d2", WebhookID: "w1", UserID: 1, Status: models.DeliveryPending, NextAttempt: now}}))
+	require.Error(t, store.UpdateDelivery(ctx, models.Delivery{DeliveryID: "d1", WebhookID: "w1", UserID: 1, Status: models.DeliveryDelivered}))
+	require.Error(t, store.DeleteWebhook(ctx, 1, "w1"))
+
+	require.Equal(t, []models.Webhook{webhook}, store.GetWebhooks(ctx, 1), "failed writes must leave the webhooks unchanged")
+	require.Equal(t, []models.Delivery{delivery}, store.GetDeliveries(ctx, 1, "w1"), "failed writes must leave the queue unchanged")
+
+}
@@ -0,0 +1,131 @@
+// Package localfs provides a BlobStore implementation keeping files on the local disk.
+package localfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"L2.18/pkg/logger"
+	"L2.18/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the blob store spans.
+var tracer = tracing.Tracer("L2.18/internal/repository/localfs")
+
+// BlobStore is a local-filesystem implementation of the repository.BlobStore interface.
+// Every key maps to a file below the root directory, with slashes in the key becoming
+// directory separators.
+//
+// Files are written to a temporary file first and renamed into place once complete,
+// so readers never see partially written content.
+type BlobStore struct {
+	root   string        // directory holding all stored files
+	logger logger.Logger // logger instance
+}
+
+// NewBlobStore creates a BlobStore keeping its files in dir, creating the directory if needed.
+func NewBlobStore(dir string, logger logger.Logger) (*BlobStore, error) {
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory %s: %w", dir, err)
+	}
+
+	return &BlobStore{root: dir, logger: logger}, nil
+
+}
+
+// Put stores the content read from r under key and returns the number of bytes written.
+// If reading r or writing the file fails, the partial file is removed and the error,
+// wrapped, is returned.
+func (b *BlobStore) Put(ctx context.Context, key string, r io.Reader) (written int64, err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.localfs.Put", trace.WithAttributes(attribute.String("blob.key", key)))
+	defer func() { tracing.End(span, err) }()
+
+	path, err := b.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	written, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write blob %s: %w", key, err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return 0, err
+	}
+
+	b.logger.Debug("repository — blob stored", "key", key, "bytes", written, "request_id", tracing.RequestID(ctx), "layer", "repository.localfs")
+
+	return written, nil
+
+}
+
+// Get opens the file stored under key. Returns an error wrapping fs.ErrNotExist if there is none.
+func (b *BlobStore) Get(ctx context.Context, key string) (content io.ReadCloser, err error) {
+
+	_, span := tracer.Start(ctx, "repository.localfs.Get", trace.WithAttributes(attribute.String("blob.key", key)))
+	defer func() { tracing.End(span, err) }()
+
+	path, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+
+}
+
+// Delete removes the file stored under key and the directory of keys below it, if any.
+func (b *BlobStore) Delete(ctx context.Context, key string) (err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.localfs.Delete", trace.WithAttributes(attribute.String("blob.key", key)))
+	defer func() { tracing.End(span, err) }()
+
+	path, err := b.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+
+	b.logger.Debug("repository — blob deleted", "key", key, "request_id", tracing.RequestID(ctx), "layer", "repository.localfs")
+
+	return nil
+
+}
+
+// path returns the file path of key, rejecting keys that would escape the root directory.
+func (b *BlobStore) path(key string) (string, error) {
+	local := filepath.FromSlash(key)
+	if key == "" || !filepath.IsLocal(local) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(b.root, local), nil
+}
@@ -0,0 +1,102 @@
+package localfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"L2.18/pkg/logger/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader returns some content and then an error.
+type failingReader struct {
+	sent bool
+}
+
+// Read returns a chunk of content on the first call and an error afterwards.
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.sent {
+		return 0, errors.New("connection reset")
+	}
+	r.sent = true
+	return copy(p, "partial"), nil
+}
+
+func TestBlobStore_PutGetDelete(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug("repository — blob stored", "key", gomock.Any(), "bytes", gomock.Any(), "request_id", "", "layer", "repository.localfs").Times(2)
+	mockLogger.EXPECT().Debug("repository — blob deleted", "key", "team/1", "request_id", "", "layer", "repository.localfs").Times(1)
+
+	root := filepath.Join(t.TempDir(), "attachments")
+	store, err := NewBlobStore(root, mockLogger)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	written, err := store.Put(ctx, "team/1/event/a", strings.NewReader("agenda"))
+	require.NoError(t, err)
+	require.Equal(t, int64(6), written)
+
+	_, err = store.Put(ctx, "team/1/event/b", strings.NewReader("minutes"))
+	require.NoError(t, err)
+
+	content, err := store.Get(ctx, "team/1/event/a")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	require.Equal(t, "agenda", string(data))
+
+	require.NoError(t, store.Delete(ctx, "team/1"))
+
+	_, err = store.Get(ctx, "team/1/event/b")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+}
+
+func TestBlobStore_FailedPutLeavesNothing(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	root := t.TempDir()
+	store, err := NewBlobStore(root, mocks.NewMockLogger(controller))
+	require.NoError(t, err)
+
+	_, err = store.Put(context.Background(), "team/1/event/a", &failingReader{})
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(root, "team", "1", "event"))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+}
+
+func TestBlobStore_InvalidKeys(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store, err := NewBlobStore(t.TempDir(), mocks.NewMockLogger(controller))
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../escape", "/etc/passwd", "team/../../escape"} {
+		_, err := store.Put(context.Background(), key, strings.NewReader("x"))
+		require.Error(t, err, key)
+		_, err = store.Get(context.Background(), key)
+		require.Error(t, err, key)
+		require.Error(t, store.Delete(context.Background(), key), key)
+	}
+
+}
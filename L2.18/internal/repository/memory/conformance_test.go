@@ -0,0 +1,26 @@
+package memory_test
+
+import (
+	"testing"
+
+	"L2.18/internal/config"
+	"L2.18/internal/repository"
+	"L2.18/internal/repository/memory"
+	"L2.18/internal/repository/storagetest"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, clock clock.Clock) repository.Storage {
+
+		mockLogger := mocks.NewMockLogger(gomock.NewController(t))
+		mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().LogInfo(gomock.Any(), gomock.Any()).AnyTimes()
+
+		return memory.NewStorage(config.Storage{}, clock, mockLogger)
+
+	})
+}
@@ -2,75 +2,167 @@
 package memory
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"time"
+	"unsafe"
 
 	"L2.18/internal/config"
+	"L2.18/internal/errs"
 	"L2.18/internal/models"
+	"L2.18/pkg/clock"
 	"L2.18/pkg/logger"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer creates the repository layer spans.
+var tracer = tracing.Tracer("L2.18/internal/repository/memory")
+
 // Storage is an in-memory implementation of the repository.Storage interface.
-// It stores events per user and per date, supports CRUD operations,
+// It stores events per tenant, per user and per date, supports CRUD operations,
 // and keeps auxiliary maps for fast lookup and user event counts.
 //
-// All methods are thread-safe using an internal RWMutex.
+// Every method works within the partition of the tenant carried by its context,
+// so users and events of different tenants never see each other.
+//
+// All methods are thread-safe using an internal RWMutex. Once the storage is closed,
+// reads find no data and writes fail with errs.ErrStorageClosed.
 type Storage struct {
+	tenants       map[string]*partition // tenantID -> data of the tenant
+	expectedUsers int                   // initial capacity of new partitions
+	snapshotFile  string                // file snapshots are written to and loaded from, empty for none
+	clock         clock.Clock           // tells which events are upcoming
+	logger        logger.Logger         // logger instance
+	closed        bool                  // set by Close; later writes fail
+	mu            sync.RWMutex          // protects all partitions
+}
+
+// partition holds the data of a single tenant. Partitions share nothing, so a lookup
+// within one of them can never return data owned by another tenant.
+type partition struct {
 	db             map[int]map[string][]*models.Event // userID -> date string -> list of events
 	eventsByID     map[string]*models.Event           // eventID -> event pointer
 	userEventCount map[int]int                        // userID -> total number of events
-	logger         logger.Logger                      // logger instance
-	mu             sync.RWMutex                       // protects all maps
+	settings       map[int]*models.Settings           // userID -> working calendar settings
+	attachments    map[string][]*models.Attachment    // eventID -> attachments in upload order
 }
 
 // NewStorage creates a new in-memory Storage instance.
-// The initial map capacities are set based on the ExpectedUsers config value.
-func NewStorage(config config.Storage, logger logger.Logger) *Storage {
+// The initial map capacities of each tenant are set based on the ExpectedUsers config value,
+// and snapshots are kept in the SnapshotFile, if set.
+func NewStorage(config config.Storage, clock clock.Clock, logger logger.Logger) *Storage {
 	return &Storage{
-		db:             make(map[int]map[string][]*models.Event, config.ExpectedUsers),
-		eventsByID:     make(map[string]*models.Event, config.ExpectedUsers),
-		userEventCount: make(map[int]int, config.ExpectedUsers),
-		logger:         logger,
+		tenants:       make(map[string]*partition),
+		expectedUsers: config.ExpectedUsers,
+		snapshotFile:  config.SnapshotFile,
+		clock:         clock,
+		logger:        logger,
+	}
+}
+
+// newPartition creates an empty partition sized for the expected number of users.
+func newPartition(expectedUsers int) *partition {
+	return &partition{
+		db:             make(map[int]map[string][]*models.Event, expectedUsers),
+		eventsByID:     make(map[string]*models.Event, expectedUsers),
+		userEventCount: make(map[int]int, expectedUsers),
+		settings:       make(map[int]*models.Settings),
+		attachments:    make(map[string][]*models.Attachment),
 	}
 }
 
+// partition returns the partition of the tenant in ctx for reading. A tenant without
+// any data gets an empty partition whose nil maps read as empty.
+// The caller must hold the lock.
+func (s *Storage) partition(ctx context.Context) *partition {
+	if p, found := s.tenants[tenant.ID(ctx)]; found {
+		return p
+	}
+	return &partition{}
+}
+
+// writablePartition returns the partition of the tenant in ctx, creating it on first write.
+// The caller must hold the write lock.
+func (s *Storage) writablePartition(ctx context.Context) *partition {
+
+	id := tenant.ID(ctx)
+
+	p, found := s.tenants[id]
+	if !found {
+		p = newPartition(s.expectedUsers)
+		s.tenants[id] = p
+		s.logger.Debug("repository — new tenant partition created", "tenant", id, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+	}
+
+	return p
+
+}
+
 // CreateEvent stores a new event in memory.
 // Generates a unique UUID for the event and updates internal maps and counters.
-func (s *Storage) CreateEvent(event *models.Event) (string, error) {
+func (s *Storage) CreateEvent(ctx context.Context, event *models.Event) (string, error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.CreateEvent", trace.WithAttributes(attribute.Int("user.id", event.Meta.UserID)))
+	defer span.End()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, userExists := s.db[event.Meta.UserID]; !userExists {
-		s.db[event.Meta.UserID] = make(map[string][]*models.Event)
-		s.logger.Debug("repository — new user created", "UserID", event.Meta.UserID, "layer", "repository.memory")
+	if s.closed {
+		return "", errs.ErrStorageClosed
+	}
+
+	p := s.writablePartition(ctx)
+
+	if _, userExists := p.db[event.Meta.UserID]; !userExists {
+		p.db[event.Meta.UserID] = make(map[string][]*models.Event)
+		s.logger.Debug("repository — new user created", "UserID", event.Meta.UserID, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
 	}
 
 	eventDate := format(event.Meta.EventDate)
 	event.Meta.EventID = uuid.New().String()
 
-	s.db[event.Meta.UserID][eventDate] = append(s.db[event.Meta.UserID][eventDate], event)
-	s.eventsByID[event.Meta.EventID] = event
-	s.userEventCount[event.Meta.UserID]++
+	p.db[event.Meta.UserID][eventDate] = append(p.db[event.Meta.UserID][eventDate], event)
+	p.eventsByID[event.Meta.EventID] = event
+	p.userEventCount[event.Meta.UserID]++
 
 	return event.Meta.EventID, nil
 
 }
 
 // UpdateEvent updates an existing event's data or moves it to a new date.
-// Thread-safe with write lock. Updates are logged.
-func (s *Storage) UpdateEvent(new *models.Event) error {
+// An empty text keeps the current one. Thread-safe with write lock. Updates are logged.
+// Returns errs.ErrEventNotFound if the event was deleted after the service looked it up,
+// e.g. by an admin purge or compaction.
+func (s *Storage) UpdateEvent(ctx context.Context, new *models.Event) error {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.UpdateEvent", trace.WithAttributes(attribute.Int("user.id", new.Meta.UserID), attribute.String("event.id", new.Meta.EventID)))
+	defer span.End()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	current := s.eventsByID[new.Meta.EventID]
+	if s.closed {
+		return errs.ErrStorageClosed
+	}
+
+	p := s.partition(ctx)
+	current, found := p.eventsByID[new.Meta.EventID]
+	if !found {
+		return errs.ErrEventNotFound
+	}
 
-	if current.Data != new.Data {
+	if new.Data.Text != "" && current.Data != new.Data {
 		updateData(&current.Data, &new.Data)
-		s.logger.Debug("repository — event data updated", "UserID", new.Meta.UserID, "EventID", new.Meta.EventID, "layer", "repository.memory")
+		s.logger.Debug("repository — event data updated", "UserID", new.Meta.UserID, "EventID", new.Meta.EventID, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
 	}
 
 	if !new.Meta.NewDate.IsZero() && !new.Meta.NewDate.Equal(current.Meta.EventDate) {
@@ -78,7 +170,7 @@ func (s *Storage) UpdateEvent(new *models.Event) error {
 		newDate := format(new.Meta.NewDate)
 		oldDate := format(current.Meta.EventDate)
 
-		dayEvents := s.db[current.Meta.UserID][oldDate]
+		dayEvents := p.db[current.Meta.UserID][oldDate]
 
 		for i, e := range dayEvents {
 
@@ -92,15 +184,15 @@ func (s *Storage) UpdateEvent(new *models.Event) error {
 		}
 
 		if len(dayEvents) == 0 {
-			delete(s.db[current.Meta.UserID], oldDate)
+			delete(p.db[current.Meta.UserID], oldDate)
 		} else {
-			s.db[current.Meta.UserID][oldDate] = dayEvents
+			p.db[current.Meta.UserID][oldDate] = dayEvents
 		}
 
 		current.Meta.EventDate = new.Meta.NewDate
-		s.db[current.Meta.UserID][newDate] = append(s.db[current.Meta.UserID][newDate], current)
+		p.db[current.Meta.UserID][newDate] = append(p.db[current.Meta.UserID][newDate], current)
 
-		s.logger.Debug("repository — event meta updated", "UserID", new.Meta.UserID, "EventID", new.Meta.EventID, "layer", "repository.memory")
+		s.logger.Debug("repository — event meta updated", "UserID", new.Meta.UserID, "EventID", new.Meta.EventID, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
 
 	}
 
@@ -108,18 +200,30 @@ func (s *Storage) UpdateEvent(new *models.Event) error {
 
 }
 
-// DeleteEvent removes an event from memory and updates counters.
-// Uses write lock for thread safety.
-func (s *Storage) DeleteEvent(meta *models.Meta) error {
+// DeleteEvent removes an event and its attachment metadata from memory and updates counters.
+// Uses write lock for thread safety. Returns errs.ErrEventNotFound if the event is already gone.
+func (s *Storage) DeleteEvent(ctx context.Context, meta *models.Meta) error {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.DeleteEvent", trace.WithAttributes(attribute.String("event.id", meta.EventID)))
+	defer span.End()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	current := s.eventsByID[meta.EventID]
+	if s.closed {
+		return errs.ErrStorageClosed
+	}
+
+	p := s.partition(ctx)
+	current, found := p.eventsByID[meta.EventID]
+	if !found {
+		return errs.ErrEventNotFound
+	}
+
 	date := format(current.Meta.EventDate)
 
 	userID := current.Meta.UserID
-	dayEvents := s.db[userID][date]
+	dayEvents := p.db[userID][date]
 
 	for i, e := range dayEvents {
 
@@ -133,26 +237,31 @@ func (s *Storage) DeleteEvent(meta *models.Meta) error {
 	}
 
 	if len(dayEvents) == 0 {
-		delete(s.db[userID], date)
+		delete(p.db[userID], date)
 	} else {
-		s.db[userID][date] = dayEvents
+		p.db[userID][date] = dayEvents
 	}
 
-	s.userEventCount[userID]--
-	delete(s.eventsByID, meta.EventID)
+	p.userEventCount[userID]--
+	delete(p.eventsByID, meta.EventID)
+	delete(p.attachments, meta.EventID)
 
 	return nil
 
 }
 
 // GetEventByID retrieves an event by its ID. Returns nil if not found.
-// Thread-safe using read lock.
-func (s *Storage) GetEventByID(eventID string) *models.Event {
+// Only the partition of the tenant in ctx is searched, so events of other tenants
+// are reported as not found. Thread-safe using read lock.
+func (s *Storage) GetEventByID(ctx context.Context, eventID string) *models.Event {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.GetEventByID", trace.WithAttributes(attribute.String("event.id", eventID)))
+	defer span.End()
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if event, eventFound := s.eventsByID[eventID]; eventFound {
+	if event, eventFound := s.partition(ctx).eventsByID[eventID]; eventFound {
 		return event
 	}
 
@@ -161,15 +270,20 @@ func (s *Storage) GetEventByID(eventID string) *models.Event {
 }
 
 // CountUserEvents returns the total number of events for a given user.
-func (s *Storage) CountUserEvents(userID int) (int, error) {
+func (s *Storage) CountUserEvents(ctx context.Context, userID int) (int, error) {
+	ctx, span := tracer.Start(ctx, "repository.memory.CountUserEvents", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer span.End()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.userEventCount[userID], nil
+	return s.partition(ctx).userEventCount[userID], nil
 }
 
 // GetEvents retrieves all events for a user filtered by period: day, week, or month.
 // Returns empty slice if no events exist for the period.
-func (s *Storage) GetEvents(meta *models.Meta, period models.Period) ([]models.Event, error) {
+func (s *Storage) GetEvents(ctx context.Context, meta *models.Meta, period models.Period) (events []models.Event, err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.GetEvents", trace.WithAttributes(attribute.Int("user.id", meta.UserID), attribute.String("period", string(period))))
+	defer func() { tracing.End(span, err) }()
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -178,7 +292,7 @@ func (s *Storage) GetEvents(meta *models.Meta, period models.Period) ([]models.E
 		return nil, fmt.Errorf("unknown period: %s", period)
 	}
 
-	allUserEvents, eventsFound := s.db[meta.UserID]
+	allUserEvents, eventsFound := s.partition(ctx).db[meta.UserID]
 	if !eventsFound {
 		return []models.Event{}, nil
 	}
@@ -198,6 +312,315 @@ func (s *Storage) GetEvents(meta *models.Meta, period models.Period) ([]models.E
 
 }
 
+// SaveSettings stores a copy of the user's working calendar settings.
+// Uses write lock for thread safety.
+func (s *Storage) SaveSettings(ctx context.Context, settings *models.Settings) error {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.SaveSettings", trace.WithAttributes(attribute.Int("user.id", settings.UserID)))
+	defer span.End()
+
+	saved := *settings
+	saved.WorkingDays = slices.Clone(settings.WorkingDays)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errs.ErrStorageClosed
+	}
+
+	s.writablePartition(ctx).settings[settings.UserID] = &saved
+
+	s.logger.Debug("repository — user settings saved", "UserID", settings.UserID, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return nil
+
+}
+
+// GetSettings returns a copy of the user's working calendar settings, or nil if none were saved.
+// Thread-safe using read lock.
+func (s *Storage) GetSettings(ctx context.Context, userID int) *models.Settings {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.GetSettings", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer span.End()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	saved, found := s.partition(ctx).settings[userID]
+	if !found {
+		return nil
+	}
+
+	settings := *saved
+	settings.WorkingDays = slices.Clone(saved.WorkingDays)
+
+	return &settings
+
+}
+
+// SaveAttachment stores a copy of the metadata of a file attached to an event.
+// Uses write lock for thread safety.
+func (s *Storage) SaveAttachment(ctx context.Context, attachment *models.Attachment) error {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.SaveAttachment", trace.WithAttributes(attribute.String("event.id", attachment.EventID), attribute.String("attachment.id", attachment.AttachmentID)))
+	defer span.End()
+
+	saved := *attachment
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errs.ErrStorageClosed
+	}
+
+	p := s.writablePartition(ctx)
+	p.attachments[attachment.EventID] = append(p.attachments[attachment.EventID], &saved)
+
+	s.logger.Debug("repository — attachment saved", "EventID", attachment.EventID, "AttachmentID", attachment.AttachmentID, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return nil
+
+}
+
+// GetAttachments returns copies of the metadata of the files attached to an event, in upload order.
+// Thread-safe using read lock.
+func (s *Storage) GetAttachments(ctx context.Context, eventID string) []models.Attachment {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.GetAttachments", trace.WithAttributes(attribute.String("event.id", eventID)))
+	defer span.End()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	eventAttachments := s.partition(ctx).attachments[eventID]
+
+	res := make([]models.Attachment, len(eventAttachments))
+	for i, attachment := range eventAttachments {
+		res[i] = *attachment
+	}
+
+	return res
+
+}
+
+// DeleteAttachment removes the metadata of a file attached to an event.
+// Uses write lock for thread safety.
+func (s *Storage) DeleteAttachment(ctx context.Context, eventID, attachmentID string) error {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.DeleteAttachment", trace.WithAttributes(attribute.String("event.id", eventID), attribute.String("attachment.id", attachmentID)))
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errs.ErrStorageClosed
+	}
+
+	p := s.partition(ctx)
+
+	remaining := slices.DeleteFunc(p.attachments[eventID], func(a *models.Attachment) bool { return a.AttachmentID == attachmentID })
+	if len(remaining) == 0 {
+		delete(p.attachments, eventID)
+	} else {
+		p.attachments[eventID] = remaining
+	}
+
+	s.logger.Debug("repository — attachment deleted", "EventID", eventID, "AttachmentID", attachmentID, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return nil
+
+}
+
+// ListUsers returns every user of the tenant in ctx owning at least one event together
+// with their event count, ordered by user ID.
+func (s *Storage) ListUsers(ctx context.Context) ([]models.UserStats, error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.ListUsers")
+	defer span.End()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	userEventCount := s.partition(ctx).userEventCount
+
+	res := make([]models.UserStats, 0, len(userEventCount))
+	for userID, count := range userEventCount {
+		if count > 0 {
+			res = append(res, models.UserStats{UserID: userID, Events: count})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b models.UserStats) int { return cmp.Compare(a.UserID, b.UserID) })
+
+	return res, nil
+
+}
+
+// DeleteUserEvents removes all events of a user of the tenant in ctx and returns how many were removed.
+// Uses write lock for thread safety.
+func (s *Storage) DeleteUserEvents(ctx context.Context, userID int) (int, error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.DeleteUserEvents", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, errs.ErrStorageClosed
+	}
+
+	p := s.partition(ctx)
+
+	deleted := 0
+	for _, dayEvents := range p.db[userID] {
+		for _, event := range dayEvents {
+			delete(p.eventsByID, event.Meta.EventID)
+			delete(p.attachments, event.Meta.EventID)
+			deleted++
+		}
+	}
+
+	delete(p.db, userID)
+	delete(p.userEventCount, userID)
+
+	s.logger.Debug("repository — user events deleted", "UserID", userID, "deleted", deleted, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return deleted, nil
+
+}
+
+// Stats returns statistics of the tenant in ctx: user and event totals, the number
+// of events dated today (UTC) or later, the events per day histogram and the
+// approximate memory used by the events.
+func (s *Storage) Stats(ctx context.Context) (models.Stats, error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.Stats")
+	defer span.End()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	perDay := make(map[string]int)
+	today := format(s.clock.Now().UTC())
+	var stats models.Stats
+
+	for _, userEvents := range s.partition(ctx).db {
+
+		if len(userEvents) > 0 {
+			stats.Users++
+		}
+
+		for date, dayEvents := range userEvents {
+			perDay[date] += len(dayEvents)
+			stats.Events += len(dayEvents)
+			if date >= today {
+				stats.Upcoming += len(dayEvents)
+			}
+			for _, event := range dayEvents {
+				stats.SizeBytes += eventSize(event)
+			}
+		}
+
+	}
+
+	stats.EventsPerDay = make([]models.DayCount, 0, len(perDay))
+	for date, count := range perDay {
+		day, _ := time.Parse("2006-01-02", date)
+		stats.EventsPerDay = append(stats.EventsPerDay, models.DayCount{Date: day, Events: count})
+	}
+
+	slices.SortFunc(stats.EventsPerDay, func(a, b models.DayCount) int { return a.Date.Compare(b.Date) })
+
+	return stats, nil
+
+}
+
+// Compact rebuilds the internal maps of every tenant sized to their current contents.
+//
+// Go maps never shrink, so after many deletions the storage keeps the memory of its
+// peak size; compaction releases it, drops users that no longer own any events and
+// tenants left without any data. Unlike the other methods it is not limited to the
+// tenant in ctx, as it changes no data visible to any tenant.
+func (s *Storage) Compact(ctx context.Context) error {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.Compact")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errs.ErrStorageClosed
+	}
+
+	tenants := make(map[string]*partition, len(s.tenants))
+	users, events, removedUsers := 0, 0, 0
+
+	for id, p := range s.tenants {
+
+		compacted := p.compact()
+
+		users += len(compacted.db)
+		events += len(compacted.eventsByID)
+		removedUsers += len(p.db) - len(compacted.db)
+
+		if len(compacted.db) > 0 || len(compacted.settings) > 0 {
+			tenants[id] = compacted
+		}
+
+	}
+
+	removedTenants := len(s.tenants) - len(tenants)
+	s.tenants = tenants
+
+	s.logger.Debug("repository — storage compacted", "tenants", len(tenants), "users", users, "events", events, "removed_users", removedUsers, "removed_tenants", removedTenants, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return nil
+
+}
+
+// compact returns a copy of the partition with maps sized to their current contents,
+// without the users that no longer own any events.
+func (p *partition) compact() *partition {
+
+	db := make(map[int]map[string][]*models.Event, len(p.db))
+	for userID, userEvents := range p.db {
+		if len(userEvents) == 0 {
+			continue
+		}
+		days := make(map[string][]*models.Event, len(userEvents))
+		for date, dayEvents := range userEvents {
+			days[date] = slices.Clip(dayEvents)
+		}
+		db[userID] = days
+	}
+
+	eventsByID := make(map[string]*models.Event, len(p.eventsByID))
+	maps.Copy(eventsByID, p.eventsByID)
+
+	userEventCount := make(map[int]int, len(db))
+	for userID, count := range p.userEventCount {
+		if count > 0 {
+			userEventCount[userID] = count
+		}
+	}
+
+	settings := make(map[int]*models.Settings, len(p.settings))
+	maps.Copy(settings, p.settings)
+
+	attachments := make(map[string][]*models.Attachment, len(p.attachments))
+	for eventID, eventAttachments := range p.attachments {
+		attachments[eventID] = slices.Clip(eventAttachments)
+	}
+
+	return &partition{db: db, eventsByID: eventsByID, userEventCount: userEventCount, settings: settings, attachments: attachments}
+
+}
+
 // getEventsForDay returns all events for a specific day for a user.
 // It extracts events from the provided map, keyed by date strings (YYYY-MM-DD),
 // and returns a slice of Event structs. If no events are found for the given day,
@@ -273,15 +696,19 @@ func (s *Storage) getEventsForMonth(allUserEvents map[string][]*models.Event, me
 
 }
 
-// Close clears all in-memory data and logs the shutdown.
+// Close clears all in-memory data and logs the shutdown. Writes made afterwards fail
+// with errs.ErrStorageClosed instead of recreating the data; closing twice is a no-op.
 func (s *Storage) Close() {
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.db = nil
-	s.eventsByID = nil
-	s.userEventCount = nil
+	if s.closed {
+		return
+	}
+
+	s.tenants = nil
+	s.closed = true
 
 	s.logger.LogInfo("in-memory storage — cleared and stopped", "layer", "repository.memory")
 
@@ -292,6 +719,12 @@ func updateData(current *models.Data, new *models.Data) {
 	current.Text = new.Text
 }
 
+// eventSize approximates the memory held by a stored event: the struct itself, its
+// strings and the pointers referencing it from the date and ID indexes.
+func eventSize(event *models.Event) int64 {
+	return int64(unsafe.Sizeof(*event)) + int64(len(event.Meta.EventID)+len(event.Data.Text)) + 2*int64(unsafe.Sizeof(event))
+}
+
 // format formats time.Time as a string in YYYY-MM-DD format.
 func format(date time.Time) string {
 	return date.Format("2006-01-02")
@@ -1,12 +1,15 @@
 package memory
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"L2.18/internal/config"
 	"L2.18/internal/models"
+	"L2.18/pkg/clock"
 	"L2.18/pkg/logger/mocks"
+	"L2.18/pkg/tenant"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -17,9 +20,10 @@ func TestStorage_CreateEvent(t *testing.T) {
 	defer controller.Finish()
 
 	mockLogger := mocks.NewMockLogger(controller)
-	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 42, "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 42, "request_id", "", "layer", "repository.memory").Times(1)
 
-	storage := NewStorage(config.Storage{ExpectedUsers: 1, MaxEventsPerUser: 1, MaxEventsPerDay: 1}, mockLogger)
+	storage := NewStorage(config.Storage{ExpectedUsers: 1, MaxEventsPerUser: 1, MaxEventsPerDay: 1}, clock.System(), mockLogger)
 	eventDate := time.Date(2025, 12, 3, 10, 0, 0, 0, time.UTC)
 
 	event := &models.Event{
@@ -27,11 +31,11 @@ func TestStorage_CreateEvent(t *testing.T) {
 		Data: models.Data{Text: "aboba"},
 	}
 
-	id, err := storage.CreateEvent(event)
+	id, err := storage.CreateEvent(context.Background(), event)
 	require.NoError(t, err)
 	require.NotEmpty(t, id)
 
-	found := storage.GetEventByID(id)
+	found := storage.GetEventByID(context.Background(), id)
 	require.NotNil(t, found)
 	require.Equal(t, "aboba", found.Data.Text)
 
@@ -43,11 +47,12 @@ func TestStorage_UpdateEvent(t *testing.T) {
 	defer controller.Finish()
 
 	mockLogger := mocks.NewMockLogger(controller)
-	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 7, "layer", "repository.memory").Times(1)
-	mockLogger.EXPECT().Debug("repository — event data updated", "UserID", 7, "EventID", gomock.Any(), "layer", "repository.memory").Times(1)
-	mockLogger.EXPECT().Debug("repository — event meta updated", "UserID", 7, "EventID", gomock.Any(), "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 7, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — event data updated", "UserID", 7, "EventID", gomock.Any(), "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — event meta updated", "UserID", 7, "EventID", gomock.Any(), "request_id", "", "layer", "repository.memory").Times(1)
 
-	storage := NewStorage(config.Storage{}, mockLogger)
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
 
 	eventDate := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
 	event := &models.Event{
@@ -55,7 +60,7 @@ func TestStorage_UpdateEvent(t *testing.T) {
 		Data: models.Data{Text: "old, not cool text"},
 	}
 
-	id, err := storage.CreateEvent(event)
+	id, err := storage.CreateEvent(context.Background(), event)
 	require.NoError(t, err)
 
 	updatedEvent := &models.Event{
@@ -63,26 +68,26 @@ func TestStorage_UpdateEvent(t *testing.T) {
 		Data: models.Data{Text: "new, really cool text"},
 	}
 
-	err = storage.UpdateEvent(updatedEvent)
+	err = storage.UpdateEvent(context.Background(), updatedEvent)
 	require.NoError(t, err)
 
 	oldMeta := &models.Meta{UserID: 7, EventDate: eventDate}
-	oldEvents, err := storage.GetEvents(oldMeta, models.Day)
+	oldEvents, err := storage.GetEvents(context.Background(), oldMeta, models.Day)
 	require.NoError(t, err)
 	require.Len(t, oldEvents, 0)
 
 	newMeta := &models.Meta{UserID: 7, EventDate: eventDate.Add(24 * time.Hour)}
-	newEvents, err := storage.GetEvents(newMeta, models.Day)
+	newEvents, err := storage.GetEvents(context.Background(), newMeta, models.Day)
 	require.NoError(t, err)
 	require.Len(t, newEvents, 1)
 	require.Equal(t, "new, really cool text", newEvents[0].Data.Text)
 
-	found := storage.GetEventByID(id)
+	found := storage.GetEventByID(context.Background(), id)
 	require.NotNil(t, found)
 	require.Equal(t, "new, really cool text", found.Data.Text)
 	require.True(t, found.Meta.EventDate.Equal(eventDate.Add(24*time.Hour)))
 
-	count, err := storage.CountUserEvents(7)
+	count, err := storage.CountUserEvents(context.Background(), 7)
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 
@@ -94,10 +99,11 @@ func TestStorage_UpdateEvent_Else(t *testing.T) {
 	defer controller.Finish()
 
 	mockLogger := mocks.NewMockLogger(controller)
-	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 7, "layer", "repository.memory").Times(1)
-	mockLogger.EXPECT().Debug("repository — event meta updated", "UserID", 7, "EventID", gomock.Any(), "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 7, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — event meta updated", "UserID", 7, "EventID", gomock.Any(), "request_id", "", "layer", "repository.memory").Times(1)
 
-	storage := NewStorage(config.Storage{}, mockLogger)
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
 
 	eventDate := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
 
@@ -111,9 +117,9 @@ func TestStorage_UpdateEvent_Else(t *testing.T) {
 		Data: models.Data{Text: "second"},
 	}
 
-	id1, err := storage.CreateEvent(event1)
+	id1, err := storage.CreateEvent(context.Background(), event1)
 	require.NoError(t, err)
-	_, err = storage.CreateEvent(event2)
+	_, err = storage.CreateEvent(context.Background(), event2)
 	require.NoError(t, err)
 
 	updatedEvent := &models.Event{
@@ -121,11 +127,11 @@ func TestStorage_UpdateEvent_Else(t *testing.T) {
 		Data: models.Data{Text: "first"},
 	}
 
-	err = storage.UpdateEvent(updatedEvent)
+	err = storage.UpdateEvent(context.Background(), updatedEvent)
 	require.NoError(t, err)
 
 	oldMeta := &models.Meta{UserID: 7, EventDate: eventDate}
-	remainingEvents, err := storage.GetEvents(oldMeta, models.Day)
+	remainingEvents, err := storage.GetEvents(context.Background(), oldMeta, models.Day)
 	require.NoError(t, err)
 	require.Len(t, remainingEvents, 1)
 	require.Equal(t, "second", remainingEvents[0].Data.Text)
@@ -138,10 +144,11 @@ func TestStorage_DeleteEvent_AllBranches(t *testing.T) {
 	defer controller.Finish()
 
 	mockLogger := mocks.NewMockLogger(controller)
-	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 5, "layer", "repository.memory").Times(1)
-	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 6, "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 5, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 6, "request_id", "", "layer", "repository.memory").Times(1)
 
-	storage := NewStorage(config.Storage{}, mockLogger)
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
 
 	eventDate1 := time.Date(2025, 12, 2, 8, 0, 0, 0, time.UTC)
 	event1 := &models.Event{
@@ -149,21 +156,21 @@ func TestStorage_DeleteEvent_AllBranches(t *testing.T) {
 		Data: models.Data{Text: "Qwe? Qwe!"},
 	}
 
-	id1, err := storage.CreateEvent(event1)
+	id1, err := storage.CreateEvent(context.Background(), event1)
 	require.NoError(t, err)
 
-	err = storage.DeleteEvent(&models.Meta{EventID: id1})
+	err = storage.DeleteEvent(context.Background(), &models.Meta{EventID: id1})
 	require.NoError(t, err)
 
-	found := storage.GetEventByID(id1)
+	found := storage.GetEventByID(context.Background(), id1)
 	require.Nil(t, found)
 
-	count, err := storage.CountUserEvents(5)
+	count, err := storage.CountUserEvents(context.Background(), 5)
 	require.NoError(t, err)
 	require.Equal(t, 0, count)
 
 	meta1 := &models.Meta{UserID: 5, EventDate: eventDate1}
-	events1, err := storage.GetEvents(meta1, models.Day)
+	events1, err := storage.GetEvents(context.Background(), meta1, models.Day)
 	require.NoError(t, err)
 	require.Len(t, events1, 0)
 
@@ -172,15 +179,15 @@ func TestStorage_DeleteEvent_AllBranches(t *testing.T) {
 	event2a := &models.Event{Meta: models.Meta{UserID: 6, EventDate: eventDate2}, Data: models.Data{Text: "first"}}
 	event2b := &models.Event{Meta: models.Meta{UserID: 6, EventDate: eventDate2}, Data: models.Data{Text: "second"}}
 
-	id2a, err := storage.CreateEvent(event2a)
+	id2a, err := storage.CreateEvent(context.Background(), event2a)
 	require.NoError(t, err)
-	_, err = storage.CreateEvent(event2b)
+	_, err = storage.CreateEvent(context.Background(), event2b)
 	require.NoError(t, err)
 
-	err = storage.DeleteEvent(&models.Meta{EventID: id2a})
+	err = storage.DeleteEvent(context.Background(), &models.Meta{EventID: id2a})
 	require.NoError(t, err)
 
-	events2, err := storage.GetEvents(&models.Meta{UserID: 6, EventDate: eventDate2}, models.Day)
+	events2, err := storage.GetEvents(context.Background(), &models.Meta{UserID: 6, EventDate: eventDate2}, models.Day)
 	require.NoError(t, err)
 	require.Len(t, events2, 1)
 	require.Equal(t, "second", events2[0].Data.Text)
@@ -193,50 +200,51 @@ func TestStorage_GetEvents_Periods(t *testing.T) {
 	defer controller.Finish()
 
 	mockLogger := mocks.NewMockLogger(controller)
-	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 8, "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 8, "request_id", "", "layer", "repository.memory").Times(1)
 
-	storage := NewStorage(config.Storage{}, mockLogger)
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
 
 	baseDate := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
 
-	_, err := storage.CreateEvent(&models.Event{
+	_, err := storage.CreateEvent(context.Background(), &models.Event{
 		Meta: models.Meta{UserID: 8, EventDate: baseDate},
 		Data: models.Data{Text: "average event"},
 	})
 	require.NoError(t, err)
 
-	_, err = storage.CreateEvent(&models.Event{
+	_, err = storage.CreateEvent(context.Background(), &models.Event{
 		Meta: models.Meta{UserID: 8, EventDate: baseDate.Add(24 * time.Hour)},
 		Data: models.Data{Text: "lame event"},
 	})
 	require.NoError(t, err)
 
-	_, err = storage.CreateEvent(&models.Event{
+	_, err = storage.CreateEvent(context.Background(), &models.Event{
 		Meta: models.Meta{UserID: 8, EventDate: baseDate.Add(-24 * time.Hour)},
 		Data: models.Data{Text: "cool event"},
 	})
 	require.NoError(t, err)
 
 	metaDay := &models.Meta{UserID: 8, EventDate: baseDate}
-	dayEvents, err := storage.GetEvents(metaDay, models.Day)
+	dayEvents, err := storage.GetEvents(context.Background(), metaDay, models.Day)
 	require.NoError(t, err)
 	require.Len(t, dayEvents, 1)
 
 	metaWeek := &models.Meta{UserID: 8, EventDate: baseDate}
-	weekEvents, err := storage.GetEvents(metaWeek, models.Week)
+	weekEvents, err := storage.GetEvents(context.Background(), metaWeek, models.Week)
 	require.NoError(t, err)
 	require.GreaterOrEqual(t, len(weekEvents), 2)
 
 	metaMonth := &models.Meta{UserID: 8, EventDate: baseDate}
-	monthEvents, err := storage.GetEvents(metaMonth, models.Month)
+	monthEvents, err := storage.GetEvents(context.Background(), metaMonth, models.Month)
 	require.NoError(t, err)
 	require.GreaterOrEqual(t, len(monthEvents), 2)
 
-	_, err = storage.GetEvents(metaMonth, "hour")
+	_, err = storage.GetEvents(context.Background(), metaMonth, "hour")
 	require.Error(t, err)
 
 	metaNotFound := &models.Meta{UserID: 99, EventDate: time.Now()}
-	events, err := storage.GetEvents(metaNotFound, models.Day)
+	events, err := storage.GetEvents(context.Background(), metaNotFound, models.Day)
 	require.NoError(t, err)
 	require.Empty(t, events)
 
@@ -248,24 +256,194 @@ func TestStorage_Close(t *testing.T) {
 	defer controller.Finish()
 
 	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
 
-	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 11, "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 11, "request_id", "", "layer", "repository.memory").Times(1)
 	mockLogger.EXPECT().LogInfo("in-memory storage — cleared and stopped", "layer", "repository.memory").Times(1)
 
-	storage := NewStorage(config.Storage{}, mockLogger)
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
 
 	event := &models.Event{
 		Meta: models.Meta{UserID: 11, EventDate: time.Now()},
 		Data: models.Data{Text: "hello there"},
 	}
 
-	_, err := storage.CreateEvent(event)
+	_, err := storage.CreateEvent(context.Background(), event)
 	require.NoError(t, err)
 
 	storage.Close()
 
-	require.Nil(t, storage.db)
-	require.Nil(t, storage.eventsByID)
-	require.Nil(t, storage.userEventCount)
+	require.Nil(t, storage.tenants)
+
+}
+
+func TestStorage_AdminOperations(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", gomock.Any(), gomock.Any(), "request_id", "", "layer", "repository.memory").Times(2)
+	mockLogger.EXPECT().Debug("repository — user events deleted", "UserID", 2, "deleted", 2, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — storage compacted", "tenants", 1, "users", 1, "events", 1, "removed_users", 0, "removed_tenants", 0, "request_id", "", "layer", "repository.memory").Times(1)
+
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
+	ctx := context.Background()
+
+	first := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	for _, event := range []*models.Event{
+		{Meta: models.Meta{UserID: 2, EventDate: second}, Data: models.Data{Text: "b"}},
+		{Meta: models.Meta{UserID: 1, EventDate: first}, Data: models.Data{Text: "a"}},
+		{Meta: models.Meta{UserID: 2, EventDate: first}, Data: models.Data{Text: "c"}},
+	} {
+		_, err := storage.CreateEvent(ctx, event)
+		require.NoError(t, err)
+	}
+
+	users, err := storage.ListUsers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.UserStats{{UserID: 1, Events: 1}, {UserID: 2, Events: 2}}, users)
+
+	stats, err := storage.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Users)
+	require.Equal(t, 3, stats.Events)
+	require.Equal(t, []models.DayCount{{Date: first, Events: 2}, {Date: second, Events: 1}}, stats.EventsPerDay)
+	require.Positive(t, stats.SizeBytes)
+
+	deleted, err := storage.DeleteUserEvents(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+	require.Len(t, storage.tenants[tenant.Default].eventsByID, 1)
+
+	require.NoError(t, storage.Compact(ctx))
+
+	users, err = storage.ListUsers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.UserStats{{UserID: 1, Events: 1}}, users)
+
+	stats, err = storage.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.DayCount{{Date: first, Events: 1}}, stats.EventsPerDay)
+
+}
+
+func TestStorage_Settings(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — user settings saved", "UserID", 3, "request_id", "", "layer", "repository.memory").Times(1)
+
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
+	ctx := context.Background()
+
+	require.Nil(t, storage.GetSettings(ctx, 3))
+
+	settings := &models.Settings{UserID: 3, Country: "RU", WorkingDays: []time.Weekday{time.Monday}}
+	require.NoError(t, storage.SaveSettings(ctx, settings))
+
+	settings.WorkingDays[0] = time.Sunday
+
+	saved := storage.GetSettings(ctx, 3)
+	require.Equal(t, &models.Settings{UserID: 3, Country: "RU", WorkingDays: []time.Weekday{time.Monday}}, saved)
+
+}
+
+func TestStorage_TenantIsolation(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", "team-a", "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", "team-b", "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 1, "request_id", "", "layer", "repository.memory").Times(2)
+	mockLogger.EXPECT().Debug("repository — user events deleted", "UserID", 1, "deleted", 1, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — storage compacted", "tenants", 1, "users", 1, "events", 1, "removed_users", 0, "removed_tenants", 1, "request_id", "", "layer", "repository.memory").Times(1)
+
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
+
+	teamA := tenant.WithID(context.Background(), "team-a")
+	teamB := tenant.WithID(context.Background(), "team-b")
+	date := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC)
+
+	idA, err := storage.CreateEvent(teamA, &models.Event{Meta: models.Meta{UserID: 1, EventDate: date}, Data: models.Data{Text: "team a"}})
+	require.NoError(t, err)
+
+	require.NotNil(t, storage.GetEventByID(teamA, idA))
+	require.Nil(t, storage.GetEventByID(teamB, idA))
+	require.Nil(t, storage.GetEventByID(context.Background(), idA))
+
+	count, err := storage.CountUserEvents(teamB, 1)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	events, err := storage.GetEvents(teamB, &models.Meta{UserID: 1, EventDate: date}, models.Day)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	users, err := storage.ListUsers(teamB)
+	require.NoError(t, err)
+	require.Empty(t, users)
+
+	idB, err := storage.CreateEvent(teamB, &models.Event{Meta: models.Meta{UserID: 1, EventDate: date}, Data: models.Data{Text: "team b"}})
+	require.NoError(t, err)
+
+	events, err = storage.GetEvents(teamA, &models.Meta{UserID: 1, EventDate: date}, models.Day)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, idA, events[0].Meta.EventID)
+
+	deleted, err := storage.DeleteUserEvents(teamB, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	require.Nil(t, storage.GetEventByID(teamB, idB))
+	require.NotNil(t, storage.GetEventByID(teamA, idA))
+
+	require.NoError(t, storage.Compact(context.Background()))
+	require.Len(t, storage.tenants, 1)
+
+}
+
+func TestStorage_Attachments(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug("repository — new tenant partition created", "tenant", tenant.Default, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — new user created", "UserID", 5, "request_id", "", "layer", "repository.memory").Times(1)
+	mockLogger.EXPECT().Debug("repository — attachment saved", "EventID", gomock.Any(), "AttachmentID", gomock.Any(), "request_id", "", "layer", "repository.memory").Times(2)
+	mockLogger.EXPECT().Debug("repository — attachment deleted", "EventID", gomock.Any(), "AttachmentID", "a", "request_id", "", "layer", "repository.memory").Times(1)
+
+	storage := NewStorage(config.Storage{}, clock.System(), mockLogger)
+	ctx := context.Background()
+
+	eventID, err := storage.CreateEvent(ctx, &models.Event{Meta: models.Meta{UserID: 5, EventDate: time.Now()}})
+	require.NoError(t, err)
+
+	require.Empty(t, storage.GetAttachments(ctx, eventID))
+
+	for _, id := range []string{"a", "b"} {
+		require.NoError(t, storage.SaveAttachment(ctx, &models.Attachment{AttachmentID: id, EventID: eventID, UserID: 5, Name: id + ".pdf"}))
+	}
+
+	attachments := storage.GetAttachments(ctx, eventID)
+	require.Len(t, attachments, 2)
+	require.Equal(t, "a", attachments[0].AttachmentID)
+	require.Equal(t, "b", attachments[1].AttachmentID)
+
+	require.NoError(t, storage.DeleteAttachment(ctx, eventID, "a"))
+	require.Len(t, storage.GetAttachments(ctx, eventID), 1)
+
+	require.NoError(t, storage.DeleteEvent(ctx, &models.Meta{UserID: 5, EventID: eventID}))
+	require.Empty(t, storage.GetAttachments(ctx, eventID))
+	require.Empty(t, storage.tenants[tenant.Default].attachments)
 
 }
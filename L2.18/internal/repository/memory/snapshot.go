@@ -0,0 +1,269 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"L2.18/internal/backup"
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
+)
+
+// Snapshot writes the data of every tenant to the configured snapshot file in the backup
+// format, replacing the previous snapshot atomically. Returns errs.ErrNotSupported if no
+// snapshot file is configured, and errs.ErrStorageClosed, leaving the previous snapshot
+// in place, once the storage is closed.
+func (s *Storage) Snapshot(ctx context.Context) (err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.Snapshot")
+	defer func() { tracing.End(span, err) }()
+
+	if s.snapshotFile == "" {
+		return errs.ErrNotSupported
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.snapshotFile), ".snapshot-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	records, err := s.dumpTo(ctx, file)
+	if syncErr := file.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), s.snapshotFile); err != nil {
+		return err
+	}
+
+	s.logger.Debug("repository — snapshot written", "file", s.snapshotFile, "records", records, "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return nil
+
+}
+
+// dumpTo writes a complete backup of every tenant to file and returns the number of records.
+func (s *Storage) dumpTo(ctx context.Context, file *os.File) (int, error) {
+
+	w, err := backup.NewWriter(file, s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.Dump(ctx, w); err != nil {
+		return 0, err
+	}
+
+	return w.Records(), w.Close()
+
+}
+
+// LoadSnapshot loads the configured snapshot file, if there is one, into the storage
+// and returns the number of records loaded. A missing file loads nothing.
+func (s *Storage) LoadSnapshot(ctx context.Context) (loaded int, err error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.LoadSnapshot")
+	defer func() { tracing.End(span, err) }()
+
+	if s.snapshotFile == "" {
+		return 0, nil
+	}
+
+	file, err := os.Open(s.snapshotFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	r, err := backup.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("snapshot %s: %w", s.snapshotFile, err)
+	}
+
+	loaded, err = s.Load(ctx, r)
+	if err != nil {
+		return 0, fmt.Errorf("snapshot %s: %w", s.snapshotFile, err)
+	}
+
+	return loaded, nil
+
+}
+
+// Dump writes the data of every tenant to sink: per tenant, the settings of its users
+// followed by their events, each event followed by its attachments. Tenants, users
+// and dates come in order. Like Compact, it is not limited to the tenant in ctx.
+// A closed storage has nothing left to dump and returns errs.ErrStorageClosed.
+func (s *Storage) Dump(ctx context.Context, sink backup.Sink) error {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.Dump")
+	defer span.End()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return errs.ErrStorageClosed
+	}
+
+	for _, tenantID := range slices.Sorted(maps.Keys(s.tenants)) {
+
+		p := s.tenants[tenantID]
+
+		for _, userID := range slices.Sorted(maps.Keys(p.settings)) {
+			if err := sink.Write(backup.Record{Tenant: tenantID, Settings: p.settings[userID]}); err != nil {
+				return err
+			}
+		}
+
+		for _, userID := range slices.Sorted(maps.Keys(p.db)) {
+			for _, date := range slices.Sorted(maps.Keys(p.db[userID])) {
+				for _, event := range p.db[userID][date] {
+
+					if err := sink.Write(backup.Record{Tenant: tenantID, Event: event}); err != nil {
+						return err
+					}
+
+					for _, attachment := range p.attachments[event.Meta.EventID] {
+						if err := sink.Write(backup.Record{Tenant: tenantID, Attachment: attachment}); err != nil {
+							return err
+						}
+					}
+
+				}
+			}
+		}
+
+	}
+
+	s.logger.Debug("repository — storage dumped", "tenants", len(s.tenants), "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return nil
+
+}
+
+// Load reads every record of r and adds it to the tenant it names, keeping the IDs of
+// events and attachments. Nothing is added unless the whole backup is valid and none
+// of its events, attachments or settings already exist (backup.ErrDuplicate).
+// Returns the number of records loaded.
+func (s *Storage) Load(ctx context.Context, r *backup.Reader) (int, error) {
+
+	ctx, span := tracer.Start(ctx, "repository.memory.Load")
+	defer span.End()
+
+	var records []backup.Record
+	if _, err := backup.Copy(collector{&records}, r); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, errs.ErrStorageClosed
+	}
+
+	if err := s.checkCollisions(records); err != nil {
+		return 0, err
+	}
+
+	for _, rec := range records {
+
+		p := s.writablePartition(tenant.WithID(ctx, rec.Tenant))
+
+		switch {
+
+		case rec.Settings != nil:
+			p.settings[rec.Settings.UserID] = rec.Settings
+
+		case rec.Event != nil:
+			event := rec.Event
+			if _, userExists := p.db[event.Meta.UserID]; !userExists {
+				p.db[event.Meta.UserID] = make(map[string][]*models.Event)
+			}
+			date := format(event.Meta.EventDate)
+			p.db[event.Meta.UserID][date] = append(p.db[event.Meta.UserID][date], event)
+			p.eventsByID[event.Meta.EventID] = event
+			p.userEventCount[event.Meta.UserID]++
+
+		default:
+			p.attachments[rec.Attachment.EventID] = append(p.attachments[rec.Attachment.EventID], rec.Attachment)
+
+		}
+
+	}
+
+	s.logger.Debug("repository — backup loaded", "records", len(records), "request_id", tracing.RequestID(ctx), "layer", "repository.memory")
+
+	return len(records), nil
+
+}
+
+// checkCollisions returns backup.ErrDuplicate if any of records already exists in its tenant.
+// The caller must hold the lock.
+func (s *Storage) checkCollisions(records []backup.Record) error {
+
+	for _, rec := range records {
+
+		p, found := s.tenants[rec.Tenant]
+		if !found {
+			continue
+		}
+
+		switch {
+
+		case rec.Settings != nil:
+			if _, exists := p.settings[rec.Settings.UserID]; exists {
+				return fmt.Errorf("%w: settings of user %d of tenant %s", backup.ErrDuplicate, rec.Settings.UserID, rec.Tenant)
+			}
+
+		case rec.Event != nil:
+			if _, exists := p.eventsByID[rec.Event.Meta.EventID]; exists {
+				return fmt.Errorf("%w: event %s of tenant %s", backup.ErrDuplicate, rec.Event.Meta.EventID, rec.Tenant)
+			}
+
+		default:
+			if slices.ContainsFunc(p.attachments[rec.Attachment.EventID], func(a *models.Attachment) bool { return a.AttachmentID == rec.Attachment.AttachmentID }) {
+				return fmt.Errorf("%w: attachment %s of tenant %s", backup.ErrDuplicate, rec.Attachment.AttachmentID, rec.Tenant)
+			}
+
+		}
+
+	}
+
+	return nil
+
+}
+
+// collector is a backup.Sink gathering records in a slice.
+type collector struct {
+	records *[]backup.Record
+}
+
+// Write appends rec to the collected records.
+func (c collector) Write(rec backup.Record) error {
+	*c.records = append(*c.records, rec)
+	return nil
+}
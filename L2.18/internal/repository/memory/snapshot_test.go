@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"L2.18/internal/backup"
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/logger/mocks"
+	"L2.18/pkg/tenant"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// quietStorage creates a storage snapshotted to file that accepts any debug log.
+func quietStorage(t *testing.T, file string) *Storage {
+	mockLogger := mocks.NewMockLogger(gomock.NewController(t))
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	return NewStorage(config.Storage{SnapshotFile: file}, clock.NewFake(time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)), mockLogger)
+}
+
+func TestStorage_SnapshotRoundTrip(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "snapshot.jsonl")
+	storage := quietStorage(t, file)
+
+	loaded, err := storage.LoadSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, loaded)
+
+	ctx := context.Background()
+	teamB := tenant.WithID(ctx, "team-b")
+	date := time.Date(2030, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	first, err := storage.CreateEvent(ctx, &models.Event{Meta: models.Meta{UserID: 1, EventDate: date}, Data: models.Data{Text: "standup"}})
+	require.NoError(t, err)
+	_, err = storage.CreateEvent(teamB, &models.Event{Meta: models.Meta{UserID: 1, EventDate: date}, Data: models.Data{Text: "retro"}})
+	require.NoError(t, err)
+	require.NoError(t, storage.SaveAttachment(ctx, &models.Attachment{AttachmentID: "a", EventID: first, UserID: 1, Name: "a.pdf"}))
+	require.NoError(t, storage.SaveSettings(ctx, &models.Settings{UserID: 1, Country: "RU", WorkingDays: []time.Weekday{time.Monday}}))
+
+	require.NoError(t, storage.Snapshot(ctx))
+
+	restored := quietStorage(t, file)
+	loaded, err = restored.LoadSnapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 4, loaded)
+
+	require.Equal(t, "standup", restored.GetEventByID(ctx, first).Data.Text)
+	require.Len(t, restored.GetAttachments(ctx, first), 1)
+
+	require.Equal(t, "RU", restored.GetSettings(ctx, 1).Country)
+
+	events, err := restored.GetEvents(teamB, &models.Meta{UserID: 1, EventDate: date}, models.Day)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	_, err = restored.LoadSnapshot(ctx)
+	require.ErrorIs(t, err, backup.ErrDuplicate)
+
+}
+
+func TestStorage_SnapshotAfterClose(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "snapshot.jsonl")
+	storage := quietStorage(t, file)
+	ctx := context.Background()
+
+	_, err := storage.CreateEvent(ctx, &models.Event{Meta: models.Meta{UserID: 1, EventDate: time.Date(2030, 1, 8, 0, 0, 0, 0, time.UTC)}, Data: models.Data{Text: "standup"}})
+	require.NoError(t, err)
+	require.NoError(t, storage.Snapshot(ctx))
+
+	storage.logger.(*mocks.MockLogger).EXPECT().LogInfo(gomock.Any(), gomock.Any()).Times(1)
+	storage.Close()
+
+	require.ErrorIs(t, storage.Snapshot(ctx), errs.ErrStorageClosed)
+
+	restored := quietStorage(t, file)
+	loaded, err := restored.LoadSnapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, loaded, "a closed storage must not overwrite the last snapshot")
+
+	_, err = storage.LoadSnapshot(ctx)
+	require.ErrorIs(t, err, errs.ErrStorageClosed)
+
+	entries, err := os.ReadDir(filepath.Dir(file))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary snapshot files must be removed")
+
+}
+
+func TestStorage_SnapshotDisabled(t *testing.T) {
+
+	storage := quietStorage(t, "")
+
+	require.ErrorIs(t, storage.Snapshot(context.Background()), errs.ErrNotSupported)
+
+	loaded, err := storage.LoadSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, loaded)
+
+}
+
+func TestStorage_LoadInvalid(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "snapshot.jsonl")
+	require.NoError(t, os.WriteFile(file, []byte("{\"format\":\"l2.18-backup\",\"version\":1}\n{\"kind\":\"event\",\"tenant\":\"default\"}\n"), 0o600))
+
+	storage := quietStorage(t, file)
+
+	_, err := storage.LoadSnapshot(context.Background())
+	require.ErrorIs(t, err, backup.ErrInvalidRecord)
+	require.Contains(t, err.Error(), "line 2")
+	require.Empty(t, storage.tenants)
+
+}
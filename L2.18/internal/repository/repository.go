@@ -1,47 +1,182 @@
 // Package repository provides the abstraction for data storage operations,
-// including CRUD operations on events and user event queries.
+// including CRUD operations on events and user event queries, for the
+// blob store holding the files attached to events, and for the store of
+// webhooks and their delivery queue.
 package repository
 
 import (
+	"context"
+	"io"
+	"time"
+
+	"L2.18/internal/backup"
 	"L2.18/internal/config"
 	"L2.18/internal/models"
+	"L2.18/internal/repository/jsonfile"
+	"L2.18/internal/repository/localfs"
 	"L2.18/internal/repository/memory"
+	"L2.18/pkg/clock"
 	"L2.18/pkg/logger"
 )
 
 // Storage defines the interface for interacting with the application's
 // persistent or in-memory storage layer.
+//
+// Every data method takes the request context, which carries the trace span,
+// request ID and tenant of the caller. Implementations keep the data of each
+// tenant apart: a method never reads or changes data of another tenant.
 type Storage interface {
 	// CreateEvent stores a new event and returns its unique ID.
-	CreateEvent(event *models.Event) (string, error)
+	CreateEvent(ctx context.Context, event *models.Event) (string, error)
 
-	// UpdateEvent updates an existing event identified by its ID.
-	UpdateEvent(event *models.Event) error
+	// UpdateEvent updates an existing event identified by its ID: it replaces the text
+	// unless the new one is empty, and moves the event to NewDate unless it is zero.
+	UpdateEvent(ctx context.Context, event *models.Event) error
 
-	// DeleteEvent removes an event based on metadata (user ID + event ID).
-	DeleteEvent(meta *models.Meta) error
+	// DeleteEvent removes an event based on metadata (user ID + event ID),
+	// together with the metadata of its attachments.
+	DeleteEvent(ctx context.Context, meta *models.Meta) error
 
 	// GetEventByID retrieves an event by its unique ID.
-	// Returns nil if no event is found.
-	GetEventByID(eventID string) *models.Event
+	// Returns nil if no event is found, including events of other tenants.
+	GetEventByID(ctx context.Context, eventID string) *models.Event
 
 	// CountUserEvents returns the number of events associated with a user.
-	CountUserEvents(userID int) (int, error)
+	CountUserEvents(ctx context.Context, userID int) (int, error)
 
 	// GetEvents retrieves all events for a user filtered by a given period
 	// (day, week, month).
-	GetEvents(meta *models.Meta, period models.Period) ([]models.Event, error)
+	GetEvents(ctx context.Context, meta *models.Meta, period models.Period) ([]models.Event, error)
+
+	// SaveSettings stores the working calendar settings of a user, replacing previous ones.
+	SaveSettings(ctx context.Context, settings *models.Settings) error
+
+	// GetSettings retrieves the working calendar settings of a user.
+	// Returns nil if the user has not saved any.
+	GetSettings(ctx context.Context, userID int) *models.Settings
+
+	// SaveAttachment stores the metadata of a file attached to an event.
+	SaveAttachment(ctx context.Context, attachment *models.Attachment) error
+
+	// GetAttachments returns the metadata of the files attached to an event, in upload order.
+	GetAttachments(ctx context.Context, eventID string) []models.Attachment
+
+	// DeleteAttachment removes the metadata of a file attached to an event.
+	DeleteAttachment(ctx context.Context, eventID, attachmentID string) error
+
+	// ListUsers returns every user owning at least one event together with
+	// their event count, ordered by user ID.
+	ListUsers(ctx context.Context) ([]models.UserStats, error)
+
+	// DeleteUserEvents removes all events of a user and returns how many were removed.
+	DeleteUserEvents(ctx context.Context, userID int) (int, error)
+
+	// Stats returns statistics of the tenant's data.
+	Stats(ctx context.Context) (models.Stats, error)
 
-	// Close cleans up any resources held by the storage.
+	// Close cleans up any resources held by the storage. Writes made afterwards
+	// fail with errs.ErrStorageClosed; closing twice is allowed.
+	Close()
+}
+
+// Snapshotter is implemented by storage backends that can persist a
+// point-in-time snapshot of their data on demand and load it back on start.
+type Snapshotter interface {
+	// Snapshot writes a snapshot of the stored data.
+	Snapshot(ctx context.Context) error
+
+	// LoadSnapshot loads the last snapshot written, if any, and returns the number of records loaded.
+	LoadSnapshot(ctx context.Context) (int, error)
+}
+
+// Dumper is implemented by storage backends that can export the data of every tenant
+// in the backup format and import it back, for offline backups and migrations.
+type Dumper interface {
+	// Dump writes the data of every tenant to sink.
+	Dump(ctx context.Context, sink backup.Sink) error
+
+	// Load adds the data read from r, keeping its IDs, and returns the number of records loaded.
+	// Nothing is added if r is invalid or collides with stored data.
+	Load(ctx context.Context, r *backup.Reader) (int, error)
+}
+
+// Compactor is implemented by storage backends that can reclaim space
+// left behind by deleted or moved events.
+type Compactor interface {
+	// Compact reclaims unused space.
+	Compact(ctx context.Context) error
+}
+
+// BlobStore defines the interface for storing file contents, such as event
+// attachments, under slash-separated keys.
+type BlobStore interface {
+	// Put stores the content read from r under key, replacing any previous content,
+	// and returns the number of bytes written. Nothing is stored if reading r fails.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+
+	// Get opens the content stored under key for reading.
+	// Returns an error wrapping fs.ErrNotExist if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the content stored under key together with every key below it
+	// (key/...). Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// WebhookStore defines the interface for keeping registered webhooks and the queue of
+// their deliveries. Implementations must persist the queue, so that notifications
+// accepted before a restart are still delivered after it.
+//
+// Webhook and delivery log methods are scoped to the tenant in the context, like the
+// Storage methods. Due and UpdateDelivery serve the background dispatcher, which has
+// no tenant of its own, and work across tenants.
+type WebhookStore interface {
+	// SaveWebhook registers a webhook, recording the tenant in the context as its tenant.
+	SaveWebhook(ctx context.Context, webhook *models.Webhook) error
+
+	// GetWebhooks returns the webhooks of a user, in registration order.
+	GetWebhooks(ctx context.Context, userID int) []models.Webhook
+
+	// DeleteWebhook removes a webhook of a user together with all its deliveries.
+	DeleteWebhook(ctx context.Context, userID int, webhookID string) error
+
+	// Enqueue appends deliveries to the queue, recording the tenant in the context as their tenant.
+	Enqueue(ctx context.Context, deliveries []models.Delivery) error
+
+	// Due returns at most limit pending deliveries of any tenant whose next attempt
+	// is not later than now, in queue order.
+	Due(ctx context.Context, now time.Time, limit int) []models.Delivery
+
+	// UpdateDelivery replaces the stored delivery with the same ID, e.g. after an attempt.
+	// Finished deliveries are kept in the delivery log, which may drop the oldest ones.
+	UpdateDelivery(ctx context.Context, delivery models.Delivery) error
+
+	// GetDeliveries returns the pending and logged deliveries of a webhook of a user, newest first.
+	GetDeliveries(ctx context.Context, userID int, webhookID string) []models.Delivery
+
+	// Close releases the store; writes made afterwards fail.
 	Close()
 }
 
 // NewStorage creates a new Storage instance. If db is nil, it returns
-// an in-memory implementation. Panics if an unsupported storage type is provided.
-func NewStorage(db any, config config.Storage, logger logger.Logger) Storage {
+// an in-memory implementation telling the time by clock. Panics if an unsupported storage type is provided.
+func NewStorage(db any, config config.Storage, clock clock.Clock, logger logger.Logger) Storage {
 	if db == nil {
-		return memory.NewStorage(config, logger)
+		return memory.NewStorage(config, clock, logger)
 	} else {
 		panic("unsupported storage type")
 	}
 }
+
+// NewBlobStore creates the blob store for event attachments, keeping files in the
+// configured attachments directory on the local disk.
+func NewBlobStore(config config.Storage, logger logger.Logger) (BlobStore, error) {
+	return localfs.NewBlobStore(config.AttachmentsDir, logger)
+}
+
+// NewWebhookStore creates the store of webhooks and their delivery queue, persisted in
+// the configured webhooks file and keeping the configured number of finished deliveries
+// per webhook.
+func NewWebhookStore(config config.Storage, webhooks config.Webhooks, logger logger.Logger) (WebhookStore, error) {
+	return jsonfile.NewWebhookStore(config.WebhooksFile, webhooks.LogSize, logger)
+}
@@ -0,0 +1,198 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"L2.18/internal/models"
+	"L2.18/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// load is the amount of concurrent work a test puts on the storage.
+type load struct {
+	tenants int // number of tenants the writers are spread over
+	writers int // number of goroutines changing events, each of its own user
+	readers int // number of goroutines reading while the writers run
+	ops     int // number of changes made by each writer and of query rounds made by each reader
+}
+
+// testConcurrency checks that concurrent writers and readers leave the storage consistent.
+func testConcurrency(t *testing.T, factory Factory) {
+	hammer(t, factory, load{tenants: 2, writers: 8, readers: 4, ops: 50})
+}
+
+// testStress runs a heavier concurrent workload, meant to be watched by the race detector.
+func testStress(t *testing.T, factory Factory) {
+
+	if !raceEnabled {
+		t.Skip("stress test runs only with the race detector (go test -race)")
+	}
+
+	if testing.Short() {
+		t.Skip("stress test skipped in short mode")
+	}
+
+	hammer(t, factory, load{tenants: 4, writers: 32, readers: 8, ops: 300})
+
+}
+
+// hammer runs the writers of l, each creating, moving, renaming and deleting events of its
+// own user, alongside the readers querying the same tenants. When all of them are done,
+// every tenant must hold exactly the events the writers kept.
+func hammer(t *testing.T, factory Factory, l load) {
+
+	storage, _ := newStorage(t, factory)
+
+	tenantOf := func(writer int) context.Context {
+		return ofTenant(fmt.Sprintf("tenant-%d", writer%l.tenants))
+	}
+
+	kept := make([]map[string]time.Time, l.writers) // writer -> IDs and dates of the events it kept
+
+	var wg sync.WaitGroup
+
+	for r := range l.readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			read(t, storage, tenantOf(r), l.writers, l.ops)
+		}()
+	}
+
+	for w := range l.writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			kept[w] = write(t, storage, tenantOf(w), w+1, l.ops, rand.New(rand.NewPCG(uint64(w), 0)))
+		}()
+	}
+
+	wg.Wait()
+
+	want := make(map[string]map[int]int, l.tenants) // tenant -> user -> events
+
+	for w, events := range kept {
+
+		ctx := tenantOf(w)
+		tenantID := fmt.Sprintf("tenant-%d", w%l.tenants)
+
+		if want[tenantID] == nil {
+			want[tenantID] = make(map[int]int)
+		}
+		want[tenantID][w+1] = len(events)
+
+		for id, date := range events {
+			event := storage.GetEventByID(ctx, id)
+			if assert.NotNil(t, event, "kept event %s of user %d lost", id, w+1) {
+				assert.True(t, event.Meta.EventDate.Equal(date), "kept event %s of user %d on a wrong date", id, w+1)
+			}
+		}
+
+		var listed int
+		for month := time.January; month <= time.December; month++ {
+			listed += len(query(t, ctx, storage, w+1, day(2030, month, 1), models.Month))
+		}
+		assert.Equal(t, len(events), listed, "events of user %d listed by month", w+1)
+
+	}
+
+	for tenantID, users := range want {
+		checkCounts(t, ofTenant(tenantID), storage, users)
+	}
+
+}
+
+// write makes ops random changes to the events of the user and returns the events left,
+// by ID with their dates. Only events the writer created itself are changed.
+func write(t *testing.T, storage repository.Storage, ctx context.Context, userID, ops int, rnd *rand.Rand) map[string]time.Time {
+
+	events := make(map[string]time.Time)
+	ids := make([]string, 0, ops)
+
+	randomDate := func() time.Time {
+		return day(2030, time.Month(1+rnd.IntN(12)), 1+rnd.IntN(28))
+	}
+
+	for i := range ops {
+
+		if len(ids) == 0 || rnd.IntN(3) == 0 {
+
+			date := randomDate()
+			id, err := storage.CreateEvent(ctx, &models.Event{
+				Meta: models.Meta{UserID: userID, EventDate: date},
+				Data: models.Data{Text: fmt.Sprintf("event %d of user %d", i, userID)},
+			})
+			if !assert.NoError(t, err) {
+				return events
+			}
+
+			events[id] = date
+			ids = append(ids, id)
+			continue
+
+		}
+
+		n := rnd.IntN(len(ids))
+		id := ids[n]
+
+		switch rnd.IntN(4) {
+
+		case 0:
+			date := randomDate()
+			assert.NoError(t, storage.UpdateEvent(ctx, &models.Event{Meta: models.Meta{UserID: userID, EventID: id, NewDate: date}}))
+			events[id] = date
+
+		case 1:
+			assert.NoError(t, storage.UpdateEvent(ctx, &models.Event{Meta: models.Meta{UserID: userID, EventID: id}, Data: models.Data{Text: fmt.Sprintf("renamed at %d", i)}}))
+
+		case 2:
+			assert.NoError(t, storage.SaveAttachment(ctx, &models.Attachment{AttachmentID: fmt.Sprintf("a%d", i), EventID: id, UserID: userID, Name: "file.txt"}))
+
+		default:
+			assert.NoError(t, storage.DeleteEvent(ctx, &models.Meta{UserID: userID, EventID: id}))
+			delete(events, id)
+			ids[n] = ids[len(ids)-1]
+			ids = ids[:len(ids)-1]
+
+		}
+
+	}
+
+	return events
+
+}
+
+// read queries the tenant in ctx through every read method for the given number of rounds,
+// each round about another user and date.
+func read(t *testing.T, storage repository.Storage, ctx context.Context, users, rounds int) {
+
+	for i := range rounds {
+
+		userID := 1 + i%users
+		date := day(2030, time.Month(1+i%12), 1+i%28)
+
+		for _, period := range []models.Period{models.Day, models.Week, models.Month} {
+			events, err := storage.GetEvents(ctx, &models.Meta{UserID: userID, EventDate: date}, period)
+			if assert.NoError(t, err) && len(events) > 0 {
+				storage.GetAttachments(ctx, events[0].Meta.EventID)
+			}
+		}
+
+		_, err := storage.CountUserEvents(ctx, userID)
+		assert.NoError(t, err)
+
+		_, err = storage.ListUsers(ctx)
+		assert.NoError(t, err)
+
+		_, err = storage.Stats(ctx)
+		assert.NoError(t, err)
+
+	}
+
+}
@@ -0,0 +1,143 @@
+package storagetest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"L2.18/internal/models"
+	"L2.18/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCounting checks that event counts, the user list and the statistics agree with
+// each other and with the events stored, after every kind of change.
+func testCounting(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	checkCounts(t, ctx, storage, map[int]int{})
+
+	a := create(t, ctx, storage, 3, day(2030, 1, 8), "a")
+	create(t, ctx, storage, 3, day(2030, 1, 8), "b")
+	c := create(t, ctx, storage, 1, day(2030, 1, 9), "c")
+	checkCounts(t, ctx, storage, map[int]int{1: 1, 3: 2})
+	checkHistogram(t, ctx, storage, map[time.Time]int{day(2030, 1, 8): 2, day(2030, 1, 9): 1})
+
+	require.NoError(t, storage.UpdateEvent(ctx, &models.Event{Meta: models.Meta{UserID: 3, EventID: a, NewDate: day(2030, 1, 9)}}))
+	checkCounts(t, ctx, storage, map[int]int{1: 1, 3: 2})
+	checkHistogram(t, ctx, storage, map[time.Time]int{day(2030, 1, 8): 1, day(2030, 1, 9): 2})
+
+	require.NoError(t, storage.DeleteEvent(ctx, &models.Meta{UserID: 1, EventID: c}))
+	checkCounts(t, ctx, storage, map[int]int{3: 2})
+	checkHistogram(t, ctx, storage, map[time.Time]int{day(2030, 1, 8): 1, day(2030, 1, 9): 1})
+
+	create(t, ctx, storage, 2, day(2030, 3, 1), "d")
+	_, err := storage.DeleteUserEvents(ctx, 3)
+	require.NoError(t, err)
+	checkCounts(t, ctx, storage, map[int]int{2: 1})
+	checkHistogram(t, ctx, storage, map[time.Time]int{day(2030, 3, 1): 1})
+
+}
+
+// testUpcoming checks that events dated today (UTC) or later are counted as upcoming,
+// as told by the clock the storage was created with.
+func testUpcoming(t *testing.T, factory Factory) {
+
+	storage, fake := newStorage(t, factory)
+	ctx := context.Background()
+
+	create(t, ctx, storage, 1, day(2030, 1, 6), "yesterday")
+	create(t, ctx, storage, 1, day(2030, 1, 7), "today")
+	create(t, ctx, storage, 2, day(2030, 1, 8), "tomorrow")
+
+	upcoming := func() int {
+		stats, err := storage.Stats(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, stats.Events)
+		return stats.Upcoming
+	}
+
+	assert.Equal(t, 2, upcoming())
+
+	fake.Set(time.Date(2030, 1, 7, 23, 59, 59, 0, time.UTC))
+	assert.Equal(t, 2, upcoming(), "today's events are upcoming until the day ends")
+
+	fake.Advance(time.Second)
+	assert.Equal(t, 1, upcoming())
+
+	fake.Set(day(2029, 12, 31))
+	assert.Equal(t, 3, upcoming())
+
+}
+
+// checkCounts checks that the event counts, the user list and the statistics of the
+// tenant in ctx all match want, the number of events of each user.
+func checkCounts(t *testing.T, ctx context.Context, storage repository.Storage, want map[int]int) {
+
+	t.Helper()
+
+	var wantUsers []models.UserStats
+	total := 0
+
+	for userID, events := range want {
+		count, err := storage.CountUserEvents(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, events, count, "events of user %d", userID)
+		if events > 0 {
+			wantUsers = append(wantUsers, models.UserStats{UserID: userID, Events: events})
+		}
+		total += events
+	}
+
+	slices.SortFunc(wantUsers, func(a, b models.UserStats) int { return cmp.Compare(a.UserID, b.UserID) })
+
+	users, err := storage.ListUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(wantUsers), len(users), "listed users")
+	if len(wantUsers) > 0 {
+		assert.Equal(t, wantUsers, users)
+	}
+
+	stats, err := storage.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(wantUsers), stats.Users, "users in stats")
+	assert.Equal(t, total, stats.Events, "events in stats")
+
+	perDay := 0
+	for i, bucket := range stats.EventsPerDay {
+		assert.Positive(t, bucket.Events, "empty histogram bucket %s", bucket.Date.Format("2006-01-02"))
+		if i > 0 {
+			assert.True(t, bucket.Date.After(stats.EventsPerDay[i-1].Date), "histogram not ordered by date")
+		}
+		perDay += bucket.Events
+	}
+	assert.Equal(t, total, perDay, "events in the histogram")
+
+	if total > 0 {
+		assert.Positive(t, stats.SizeBytes)
+	}
+
+}
+
+// checkHistogram checks that the events per day histogram of the tenant in ctx equals want.
+func checkHistogram(t *testing.T, ctx context.Context, storage repository.Storage, want map[time.Time]int) {
+
+	t.Helper()
+
+	stats, err := storage.Stats(ctx)
+	require.NoError(t, err)
+
+	got := make(map[time.Time]int, len(stats.EventsPerDay))
+	for _, bucket := range stats.EventsPerDay {
+		got[bucket.Date.UTC()] = bucket.Events
+	}
+
+	assert.Equal(t, want, got)
+
+}
@@ -0,0 +1,384 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCreateAndGet checks that created events get distinct IDs and are found by them.
+func testCreateAndGet(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	date := day(2030, 1, 8)
+
+	first := create(t, ctx, storage, 1, date, "first")
+	second := create(t, ctx, storage, 1, date, "second")
+	assert.NotEqual(t, first, second)
+
+	found := storage.GetEventByID(ctx, first)
+	require.NotNil(t, found)
+	assert.Equal(t, first, found.Meta.EventID)
+	assert.Equal(t, 1, found.Meta.UserID)
+	assert.True(t, found.Meta.EventDate.Equal(date))
+	assert.Equal(t, "first", found.Data.Text)
+
+	assert.Nil(t, storage.GetEventByID(ctx, "00000000-0000-0000-0000-000000000000"))
+
+	count, err := storage.CountUserEvents(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+}
+
+// testUpdateText checks that changing the text of an event keeps its date.
+func testUpdateText(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	date := day(2030, 1, 8)
+	id := create(t, ctx, storage, 1, date, "old")
+
+	require.NoError(t, storage.UpdateEvent(ctx, &models.Event{
+		Meta: models.Meta{UserID: 1, EventID: id},
+		Data: models.Data{Text: "new"},
+	}))
+
+	found := storage.GetEventByID(ctx, id)
+	require.NotNil(t, found)
+	assert.Equal(t, "new", found.Data.Text)
+	assert.True(t, found.Meta.EventDate.Equal(date))
+
+	assert.Equal(t, []string{"new"}, query(t, ctx, storage, 1, date, models.Day))
+
+}
+
+// testMoveDate checks that moving an event changes the day it is listed on and leaves
+// the other events of the old day in place.
+func testMoveDate(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	from, to := day(2030, 1, 8), day(2030, 1, 20)
+
+	moved := create(t, ctx, storage, 1, from, "moved")
+	create(t, ctx, storage, 1, from, "stays")
+
+	require.NoError(t, storage.UpdateEvent(ctx, &models.Event{
+		Meta: models.Meta{UserID: 1, EventID: moved, NewDate: to},
+		Data: models.Data{Text: "moved"},
+	}))
+
+	found := storage.GetEventByID(ctx, moved)
+	require.NotNil(t, found)
+	assert.True(t, found.Meta.EventDate.Equal(to))
+	assert.Equal(t, "moved", found.Data.Text, "moving an event must keep its text")
+
+	assert.Equal(t, []string{"stays"}, query(t, ctx, storage, 1, from, models.Day))
+	assert.Equal(t, []string{"moved"}, query(t, ctx, storage, 1, to, models.Day))
+
+	require.NoError(t, storage.UpdateEvent(ctx, &models.Event{
+		Meta: models.Meta{UserID: 1, EventID: moved, NewDate: from},
+		Data: models.Data{Text: "back"},
+	}))
+
+	assert.ElementsMatch(t, []string{"stays", "back"}, query(t, ctx, storage, 1, from, models.Day))
+	assert.Empty(t, query(t, ctx, storage, 1, to, models.Day))
+
+	count, err := storage.CountUserEvents(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "moving an event must not change the event count")
+
+}
+
+// testMoveKeepsText checks that an update carrying only a new date keeps the text
+// of the event, as the service sends moves without text.
+func testMoveKeepsText(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	to := day(2030, 1, 20)
+	id := create(t, ctx, storage, 1, day(2030, 1, 8), "kept")
+
+	require.NoError(t, storage.UpdateEvent(ctx, &models.Event{
+		Meta: models.Meta{UserID: 1, EventID: id, NewDate: to},
+	}))
+
+	found := storage.GetEventByID(ctx, id)
+	require.NotNil(t, found)
+	assert.True(t, found.Meta.EventDate.Equal(to))
+	assert.Equal(t, "kept", found.Data.Text, "an update without text must keep the current one")
+
+	assert.Equal(t, []string{"kept"}, query(t, ctx, storage, 1, to, models.Day))
+
+}
+
+// testDelete checks that a deleted event disappears from every lookup together with its attachments.
+func testDelete(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	date := day(2030, 1, 8)
+
+	deleted := create(t, ctx, storage, 1, date, "deleted")
+	kept := create(t, ctx, storage, 1, date, "kept")
+
+	require.NoError(t, storage.SaveAttachment(ctx, &models.Attachment{AttachmentID: "a1", EventID: deleted, UserID: 1, Name: "a.txt"}))
+	require.NoError(t, storage.SaveAttachment(ctx, &models.Attachment{AttachmentID: "a2", EventID: kept, UserID: 1, Name: "b.txt"}))
+
+	require.NoError(t, storage.DeleteEvent(ctx, &models.Meta{UserID: 1, EventID: deleted}))
+
+	assert.Nil(t, storage.GetEventByID(ctx, deleted))
+	assert.NotNil(t, storage.GetEventByID(ctx, kept))
+	assert.Equal(t, []string{"kept"}, query(t, ctx, storage, 1, date, models.Day))
+
+	assert.Empty(t, storage.GetAttachments(ctx, deleted))
+	assert.Len(t, storage.GetAttachments(ctx, kept), 1)
+
+	count, err := storage.CountUserEvents(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.NoError(t, storage.DeleteEvent(ctx, &models.Meta{UserID: 1, EventID: kept}))
+
+	assert.Empty(t, query(t, ctx, storage, 1, date, models.Day))
+
+	users, err := storage.ListUsers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users, "a user without events must not be listed")
+
+}
+
+// testWritesToMissingEvent checks that updating or deleting an event that no longer exists,
+// e.g. because an admin purge removed it after the service looked it up, fails with
+// errs.ErrEventNotFound and changes nothing.
+func testWritesToMissingEvent(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	date := day(2030, 1, 8)
+
+	purged := create(t, ctx, storage, 1, date, "purged")
+	kept := create(t, ctx, storage, 2, date, "kept")
+
+	_, err := storage.DeleteUserEvents(ctx, 1)
+	require.NoError(t, err)
+
+	for _, id := range []string{purged, "00000000-0000-0000-0000-000000000000"} {
+
+		err := storage.UpdateEvent(ctx, &models.Event{
+			Meta: models.Meta{UserID: 1, EventID: id, NewDate: day(2030, 1, 20)},
+			Data: models.Data{Text: "changed"},
+		})
+		assert.ErrorIs(t, err, errs.ErrEventNotFound, "updating a missing event")
+
+		err = storage.DeleteEvent(ctx, &models.Meta{UserID: 1, EventID: id})
+		assert.ErrorIs(t, err, errs.ErrEventNotFound, "deleting a missing event")
+
+	}
+
+	assert.ErrorIs(t, storage.UpdateEvent(ofTenant("other"), &models.Event{
+		Meta: models.Meta{UserID: 2, EventID: kept},
+		Data: models.Data{Text: "changed"},
+	}), errs.ErrEventNotFound, "an event of another tenant is missing")
+
+	assert.Nil(t, storage.GetEventByID(ctx, purged))
+	assert.Equal(t, []string{"kept"}, query(t, ctx, storage, 2, date, models.Day))
+
+	count, err := storage.CountUserEvents(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, count, "a failed delete must not change the event count")
+
+}
+
+// testDeleteUserEvents checks that deleting the events of a user leaves other users alone.
+func testDeleteUserEvents(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	date := day(2030, 1, 8)
+
+	first := create(t, ctx, storage, 1, date, "one")
+	create(t, ctx, storage, 1, day(2030, 2, 1), "two")
+	other := create(t, ctx, storage, 2, date, "other")
+
+	require.NoError(t, storage.SaveAttachment(ctx, &models.Attachment{AttachmentID: "a1", EventID: first, UserID: 1, Name: "a.txt"}))
+
+	deleted, err := storage.DeleteUserEvents(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	assert.Nil(t, storage.GetEventByID(ctx, first))
+	assert.Empty(t, storage.GetAttachments(ctx, first))
+	assert.NotNil(t, storage.GetEventByID(ctx, other))
+
+	count, err := storage.CountUserEvents(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	users, err := storage.ListUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.UserStats{{UserID: 2, Events: 1}}, users)
+
+	deleted, err = storage.DeleteUserEvents(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "deleting the events of a user without events removes nothing")
+
+}
+
+// testSettings checks that settings are replaced on save and never shared with the caller.
+func testSettings(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	assert.Nil(t, storage.GetSettings(ctx, 1))
+
+	settings := &models.Settings{UserID: 1, Country: "RU", WorkingDays: []time.Weekday{time.Monday, time.Tuesday}}
+	require.NoError(t, storage.SaveSettings(ctx, settings))
+
+	settings.WorkingDays[0] = time.Sunday
+
+	saved := storage.GetSettings(ctx, 1)
+	require.NotNil(t, saved)
+	assert.Equal(t, "RU", saved.Country)
+	assert.Equal(t, []time.Weekday{time.Monday, time.Tuesday}, saved.WorkingDays, "saved settings must not share memory with the caller")
+
+	saved.WorkingDays[0] = time.Sunday
+	assert.Equal(t, time.Monday, storage.GetSettings(ctx, 1).WorkingDays[0], "returned settings must not share memory with the storage")
+
+	require.NoError(t, storage.SaveSettings(ctx, &models.Settings{UserID: 1, WorkingDays: []time.Weekday{time.Friday}}))
+
+	replaced := storage.GetSettings(ctx, 1)
+	require.NotNil(t, replaced)
+	assert.Empty(t, replaced.Country)
+	assert.Equal(t, []time.Weekday{time.Friday}, replaced.WorkingDays)
+
+	assert.Nil(t, storage.GetSettings(ctx, 2))
+
+}
+
+// testAttachments checks that attachment metadata is listed in upload order and deleted one by one.
+func testAttachments(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	id := create(t, ctx, storage, 1, day(2030, 1, 8), "with files")
+	uploaded := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+
+	for _, name := range []string{"c.txt", "a.txt", "b.txt"} {
+		require.NoError(t, storage.SaveAttachment(ctx, &models.Attachment{
+			AttachmentID: "id-" + name, EventID: id, UserID: 1, Name: name, ContentType: "text/plain", Size: 3, CreatedAt: uploaded,
+		}))
+	}
+
+	attachments := storage.GetAttachments(ctx, id)
+	require.Len(t, attachments, 3)
+	assert.Equal(t, "c.txt", attachments[0].Name)
+	assert.Equal(t, "a.txt", attachments[1].Name)
+	assert.Equal(t, "b.txt", attachments[2].Name)
+	assert.Equal(t, models.Attachment{AttachmentID: "id-c.txt", EventID: id, UserID: 1, Name: "c.txt", ContentType: "text/plain", Size: 3, CreatedAt: uploaded}, attachments[0])
+
+	require.NoError(t, storage.DeleteAttachment(ctx, id, "id-a.txt"))
+
+	attachments = storage.GetAttachments(ctx, id)
+	require.Len(t, attachments, 2)
+	assert.Equal(t, "c.txt", attachments[0].Name)
+	assert.Equal(t, "b.txt", attachments[1].Name)
+
+	assert.Empty(t, storage.GetAttachments(ctx, "no-such-event"))
+
+}
+
+// testTenantIsolation checks that no method reads or changes data of another tenant.
+func testTenantIsolation(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+
+	a, b := ofTenant("team-a"), ofTenant("team-b")
+	date := day(2030, 1, 8)
+
+	id := create(t, a, storage, 1, date, "of team a")
+	require.NoError(t, storage.SaveSettings(a, &models.Settings{UserID: 1, Country: "RU"}))
+	require.NoError(t, storage.SaveAttachment(a, &models.Attachment{AttachmentID: "a1", EventID: id, UserID: 1, Name: "a.txt"}))
+
+	assert.Nil(t, storage.GetEventByID(b, id))
+	assert.Nil(t, storage.GetSettings(b, 1))
+	assert.Empty(t, storage.GetAttachments(b, id))
+	assert.Empty(t, query(t, b, storage, 1, date, models.Month))
+
+	count, err := storage.CountUserEvents(b, 1)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	users, err := storage.ListUsers(b)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	stats, err := storage.Stats(b)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Events)
+
+	create(t, b, storage, 1, date, "of team b")
+
+	deleted, err := storage.DeleteUserEvents(b, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	assert.NotNil(t, storage.GetEventByID(a, id), "deleting the events of a user must not reach into other tenants")
+	assert.Equal(t, []string{"of team a"}, query(t, a, storage, 1, date, models.Day))
+	assert.Len(t, storage.GetAttachments(a, id), 1)
+
+}
+
+// testWritesAfterClose checks that a closed storage refuses writes with errs.ErrStorageClosed
+// instead of failing in some other way, and can be closed again.
+func testWritesAfterClose(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	date := day(2030, 1, 8)
+	id := create(t, ctx, storage, 1, date, "before close")
+
+	storage.Close()
+
+	_, err := storage.CreateEvent(ctx, &models.Event{Meta: models.Meta{UserID: 1, EventDate: date}, Data: models.Data{Text: "after close"}})
+	assert.ErrorIs(t, err, errs.ErrStorageClosed)
+
+	err = storage.UpdateEvent(ctx, &models.Event{Meta: models.Meta{UserID: 1, EventID: id}, Data: models.Data{Text: "after close"}})
+	assert.ErrorIs(t, err, errs.ErrStorageClosed)
+
+	assert.ErrorIs(t, storage.DeleteEvent(ctx, &models.Meta{UserID: 1, EventID: id}), errs.ErrStorageClosed)
+	assert.ErrorIs(t, storage.SaveSettings(ctx, &models.Settings{UserID: 1, Country: "RU"}), errs.ErrStorageClosed)
+	assert.ErrorIs(t, storage.SaveAttachment(ctx, &models.Attachment{AttachmentID: "a1", EventID: id, UserID: 1, Name: "a.txt"}), errs.ErrStorageClosed)
+	assert.ErrorIs(t, storage.DeleteAttachment(ctx, id, "a1"), errs.ErrStorageClosed)
+
+	_, err = storage.DeleteUserEvents(ctx, 1)
+	assert.ErrorIs(t, err, errs.ErrStorageClosed)
+
+	assert.NotPanics(t, func() {
+		storage.GetEventByID(ctx, id)
+		storage.GetSettings(ctx, 1)
+		storage.GetAttachments(ctx, id)
+		_, _ = storage.ListUsers(ctx)
+		_, _ = storage.Stats(ctx)
+		storage.Close()
+	})
+
+}
@@ -0,0 +1,125 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"L2.18/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPeriodDay checks that a day query returns the events of that day of the user only.
+func testPeriodDay(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	create(t, ctx, storage, 1, day(2030, 1, 7), "monday")
+	create(t, ctx, storage, 1, day(2030, 1, 8), "tuesday")
+	create(t, ctx, storage, 1, day(2030, 1, 8), "tuesday too")
+	create(t, ctx, storage, 2, day(2030, 1, 8), "of user 2")
+
+	assert.ElementsMatch(t, []string{"tuesday", "tuesday too"}, query(t, ctx, storage, 1, day(2030, 1, 8), models.Day))
+	assert.Equal(t, []string{"monday"}, query(t, ctx, storage, 1, day(2030, 1, 7), models.Day))
+	assert.Empty(t, query(t, ctx, storage, 1, day(2030, 1, 9), models.Day))
+	assert.Empty(t, query(t, ctx, storage, 3, day(2030, 1, 8), models.Day), "a user without events has an empty day")
+
+}
+
+// testPeriodWeek checks that a week query returns the events of the ISO week, Monday to
+// Sunday, including weeks spanning two years and the 53rd week of long years.
+func testPeriodWeek(t *testing.T, factory Factory) {
+
+	cases := []struct {
+		name   string
+		events map[string]time.Time
+		target time.Time
+		want   []string
+	}{
+		{
+			name: "within a month",
+			events: map[string]time.Time{
+				"sunday before": day(2030, 1, 13), "monday": day(2030, 1, 14), "wednesday": day(2030, 1, 16),
+				"sunday": day(2030, 1, 20), "monday after": day(2030, 1, 21),
+			},
+			target: day(2030, 1, 17),
+			want:   []string{"monday", "wednesday", "sunday"},
+		},
+		{
+			name: "week one starting in december",
+			events: map[string]time.Time{
+				"week 52": day(2030, 12, 29), "monday": day(2030, 12, 30), "new year": day(2031, 1, 1),
+				"sunday": day(2031, 1, 5), "week 2": day(2031, 1, 6),
+			},
+			target: day(2030, 12, 31),
+			want:   []string{"monday", "new year", "sunday"},
+		},
+		{
+			name: "week 53 ending in january",
+			events: map[string]time.Time{
+				"week 52": day(2026, 12, 27), "monday": day(2026, 12, 28), "sunday": day(2027, 1, 3),
+				"week 1": day(2027, 1, 4),
+			},
+			target: day(2027, 1, 1),
+			want:   []string{"monday", "sunday"},
+		},
+		{
+			name:   "same week number of another year",
+			events: map[string]time.Time{"2030": day(2030, 1, 8), "2031": day(2031, 1, 7)},
+			target: day(2031, 1, 9),
+			want:   []string{"2031"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			storage, _ := newStorage(t, factory)
+			ctx := context.Background()
+
+			for text, date := range tc.events {
+				create(t, ctx, storage, 1, date, text)
+			}
+			create(t, ctx, storage, 2, tc.target, "of user 2")
+
+			assert.ElementsMatch(t, tc.want, query(t, ctx, storage, 1, tc.target, models.Week))
+
+		})
+	}
+
+}
+
+// testPeriodMonth checks that a month query returns the events of the calendar month,
+// including the leap day, and not those of the same month of another year.
+func testPeriodMonth(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	create(t, ctx, storage, 1, day(2028, 1, 31), "january")
+	create(t, ctx, storage, 1, day(2028, 2, 1), "first")
+	create(t, ctx, storage, 1, day(2028, 2, 29), "leap day")
+	create(t, ctx, storage, 1, day(2028, 3, 1), "march")
+	create(t, ctx, storage, 1, day(2029, 2, 10), "next year")
+	create(t, ctx, storage, 2, day(2028, 2, 10), "of user 2")
+
+	assert.ElementsMatch(t, []string{"first", "leap day"}, query(t, ctx, storage, 1, day(2028, 2, 15), models.Month))
+	assert.Equal(t, []string{"next year"}, query(t, ctx, storage, 1, day(2029, 2, 28), models.Month))
+	assert.Empty(t, query(t, ctx, storage, 1, day(2028, 4, 1), models.Month))
+
+}
+
+// testUnknownPeriod checks that a query for an unsupported period fails.
+func testUnknownPeriod(t *testing.T, factory Factory) {
+
+	storage, _ := newStorage(t, factory)
+	ctx := context.Background()
+
+	create(t, ctx, storage, 1, day(2030, 1, 8), "event")
+
+	_, err := storage.GetEvents(ctx, &models.Meta{UserID: 1, EventDate: day(2030, 1, 8)}, models.Period("year"))
+	assert.Error(t, err)
+
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package storagetest
+
+// raceEnabled reports whether the race detector is enabled, which turns the stress test on.
+const raceEnabled = true
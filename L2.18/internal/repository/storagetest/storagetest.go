@@ -0,0 +1,144 @@
+// Package storagetest provides a conformance test suite for implementations of
+// repository.Storage, so that every backend is held to the same contract.
+//
+// A backend runs the whole suite with a single call from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T, clock clock.Clock) repository.Storage {
+//			return NewStorage(config.Storage{}, clock, logger)
+//		})
+//	}
+//
+// The suite talks to the storage directly, without the service layer in front of it,
+// and only relies on what the service guarantees before calling the storage: updated
+// and deleted events belong to the user, and dates are UTC midnights. Events may still
+// disappear between the service's lookup and the write, so writes to missing events
+// must fail with errs.ErrEventNotFound.
+//
+// Built with the race detector (go test -race), the suite also runs a stress test
+// hammering the storage from many goroutines across tenants and users. Without the
+// race detector, or with -short, the stress test is skipped, as it would only burn time.
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"L2.18/internal/models"
+	"L2.18/internal/repository"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/tenant"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Factory creates an empty storage for a single test. Backends that tell the time,
+// e.g. to count upcoming events, must read it from clock.
+type Factory func(t *testing.T, clock clock.Clock) repository.Storage
+
+// now is the time the fake clock of every test starts at, a Monday.
+var now = time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+
+// suite lists the conformance tests in the order they run.
+var suite = []struct {
+	name string
+	run  func(t *testing.T, factory Factory)
+}{
+	{"CreateAndGet", testCreateAndGet},
+	{"UpdateText", testUpdateText},
+	{"MoveDate", testMoveDate},
+	{"MoveKeepsText", testMoveKeepsText},
+	{"Delete", testDelete},
+	{"WritesToMissingEvent", testWritesToMissingEvent},
+	{"DeleteUserEvents", testDeleteUserEvents},
+	{"Settings", testSettings},
+	{"Attachments", testAttachments},
+	{"TenantIsolation", testTenantIsolation},
+	{"WritesAfterClose", testWritesAfterClose},
+	{"PeriodDay", testPeriodDay},
+	{"PeriodWeek", testPeriodWeek},
+	{"PeriodMonth", testPeriodMonth},
+	{"UnknownPeriod", testUnknownPeriod},
+	{"Counting", testCounting},
+	{"Upcoming", testUpcoming},
+	{"Concurrency", testConcurrency},
+	{"Stress", testStress},
+}
+
+// Run runs the conformance suite against storages created by factory,
+// each test as a subtest of t with a fresh storage.
+func Run(t *testing.T, factory Factory) {
+	for _, test := range suite {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, factory)
+		})
+	}
+}
+
+// newStorage creates a storage telling the time by a fake clock set to now,
+// and closes it when the test ends.
+func newStorage(t *testing.T, factory Factory) (repository.Storage, *clock.Fake) {
+
+	t.Helper()
+
+	fake := clock.NewFake(now)
+
+	storage := factory(t, fake)
+	require.NotNil(t, storage, "factory returned no storage")
+	t.Cleanup(storage.Close)
+
+	return storage, fake
+
+}
+
+// day returns midnight UTC of the given date, the form in which the service passes event dates.
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// ofTenant returns a background context carrying the tenant id.
+func ofTenant(id string) context.Context {
+	return tenant.WithID(context.Background(), id)
+}
+
+// create stores an event of the user on date with the given text and returns its ID.
+func create(t *testing.T, ctx context.Context, storage repository.Storage, userID int, date time.Time, text string) string {
+
+	t.Helper()
+
+	id, err := storage.CreateEvent(ctx, &models.Event{
+		Meta: models.Meta{UserID: userID, EventDate: date},
+		Data: models.Data{Text: text},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	return id
+
+}
+
+// texts returns the texts of events, in the order returned by the storage.
+func texts(events []models.Event) []string {
+	res := make([]string, len(events))
+	for i, event := range events {
+		res[i] = event.Data.Text
+	}
+	return res
+}
+
+// query returns the texts of the user's events in the period containing date.
+func query(t *testing.T, ctx context.Context, storage repository.Storage, userID int, date time.Time, period models.Period) []string {
+
+	t.Helper()
+
+	events, err := storage.GetEvents(ctx, &models.Meta{UserID: userID, EventDate: date}, period)
+	require.NoError(t, err)
+
+	for _, event := range events {
+		require.Equal(t, userID, event.Meta.UserID, "event of another user returned")
+	}
+
+	return texts(events)
+
+}
@@ -0,0 +1,124 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+
+	"L2.18/pkg/logger"
+	"github.com/fsnotify/fsnotify"
+)
+
+// certReloader serves the TLS certificate loaded from a certificate and a key file and
+// swaps it whenever the files change, so renewed certificates apply without a restart.
+type certReloader struct {
+	certFile string                          // PEM certificate chain
+	keyFile  string                          // PEM private key
+	current  atomic.Pointer[tls.Certificate] // certificate handed out to new connections
+	logger   logger.Logger                   // reports reloads and failures
+}
+
+// newCertReloader loads the certificate from certFile and keyFile.
+func newCertReloader(certFile, keyFile string, logger logger.Logger) (*certReloader, error) {
+
+	r := &certReloader{certFile: certFile, keyFile: keyFile, logger: logger}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+
+}
+
+// certificate returns the current certificate, as tls.Config.GetCertificate.
+func (r *certReloader) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current.Load(), nil
+}
+
+// load reads the certificate and key files and, if they form a valid pair, makes
+// them the current certificate.
+func (r *certReloader) load() error {
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	r.current.Store(&cert)
+
+	return nil
+
+}
+
+// reload loads the files again after a change. On failure, e.g. while only one of
+// the files has been replaced, the current certificate stays in use.
+func (r *certReloader) reload() {
+	if err := r.load(); err != nil {
+		r.logger.LogWarn("server — TLS certificate reload failed, keeping the current certificate", "err", err.Error(), "layer", "server")
+		return
+	}
+	r.logger.LogInfo("server — TLS certificate reloaded", "cert_file", r.certFile, "layer", "server")
+}
+
+// watch reloads the certificate whenever the certificate or key file is written or
+// replaced, until ctx is cancelled. The directories of the files are watched rather
+// than the files, so tools that replace the files on renewal are handled as well.
+func (r *certReloader) watch(ctx context.Context) error {
+
+	certFile, err := filepath.Abs(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyFile, err := filepath.Abs(r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("fsnotify: %w", err)
+	}
+
+	for _, dir := range []string{filepath.Dir(certFile), filepath.Dir(keyFile)} {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return fmt.Errorf("fsnotify: %w", err)
+		}
+	}
+
+	go func() {
+
+		defer watcher.Close()
+
+		for {
+			select {
+
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				name := filepath.Clean(event.Name)
+				if (name == certFile || name == keyFile) && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
+					r.reload()
+				}
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				r.logger.LogWarn("server — TLS certificate watcher error", "err", err.Error(), "layer", "server")
+
+			}
+		}
+
+	}()
+
+	return nil
+
+}
@@ -0,0 +1,94 @@
+package httpserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/pkg/devcert"
+	"L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCert writes a new development certificate for host to certFile and keyFile.
+func writeCert(t *testing.T, certFile, keyFile, host string) {
+	require.NoError(t, devcert.WriteFiles(certFile, keyFile, []string{host}, time.Now()))
+}
+
+// servedHost returns the DNS name of the certificate currently served by r.
+func servedHost(t *testing.T, r *certReloader) string {
+	cert, err := r.certificate(nil)
+	require.NoError(t, err)
+	require.NotNil(t, cert.Leaf)
+	return cert.Leaf.DNSNames[0]
+}
+
+func TestCertReloader(t *testing.T) {
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+
+	controller := gomock.NewController(t)
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().LogInfo("server — TLS certificate reloaded", "cert_file", certFile, "layer", "server").MinTimes(1)
+	mockLogger.EXPECT().LogWarn("server — TLS certificate reload failed, keeping the current certificate", "err", gomock.Any(), "layer", "server").AnyTimes()
+
+	_, err := newCertReloader(certFile, keyFile, mockLogger)
+	require.Error(t, err, "a missing certificate is an error at start")
+
+	writeCert(t, certFile, keyFile, "first.test")
+
+	reloader, err := newCertReloader(certFile, keyFile, mockLogger)
+	require.NoError(t, err)
+	require.Equal(t, "first.test", servedHost(t, reloader))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, reloader.watch(ctx))
+
+	writeCert(t, certFile, keyFile, "second.test")
+	require.Eventually(t, func() bool { return servedHost(t, reloader) == "second.test" }, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o644))
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, "second.test", servedHost(t, reloader), "a broken certificate must not replace the current one")
+
+}
+
+func TestEnsureDevCert(t *testing.T) {
+
+	dir := t.TempDir()
+	tls := config.TLS{Enabled: true, CertFile: filepath.Join(dir, "certs", "server.crt"), KeyFile: filepath.Join(dir, "certs", "server.key")}
+
+	controller := gomock.NewController(t)
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().LogWarn("server — generated a self-signed development certificate, do not use it in production", "cert_file", tls.CertFile, "layer", "server").Times(1)
+
+	server := NewServer(config.Server{TLS: tls}, nil, mockLogger)
+	require.NoError(t, server.ensureDevCert())
+	require.NoFileExists(t, tls.CertFile, "certificates are only generated when enabled")
+
+	tls.DevCert = true
+	server = NewServer(config.Server{TLS: tls}, nil, mockLogger)
+	require.NoError(t, server.ensureDevCert())
+	require.FileExists(t, tls.CertFile)
+
+	created, err := os.ReadFile(tls.CertFile)
+	require.NoError(t, err)
+
+	require.NoError(t, server.ensureDevCert())
+	kept, err := os.ReadFile(tls.CertFile)
+	require.NoError(t, err)
+	require.Equal(t, created, kept, "existing certificates are kept")
+
+	reloader, err := newCertReloader(tls.CertFile, tls.KeyFile, mockLogger)
+	require.NoError(t, err)
+	require.Equal(t, "localhost", servedHost(t, reloader))
+
+}
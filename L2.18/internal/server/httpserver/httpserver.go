@@ -1,26 +1,36 @@
 // Package httpserver provides a concrete implementation of an HTTP server.
-// It wraps the standard net/http.Server and adds graceful shutdown and logging capabilities.
+// It wraps the standard net/http.Server and adds optional TLS termination with certificate
+// hot reload, graceful shutdown and logging capabilities.
 package httpserver
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"io/fs"
 	"net/http"
+	"os"
 	"time"
 
 	"L2.18/internal/config"
+	"L2.18/pkg/devcert"
 	"L2.18/pkg/logger"
 )
 
 // HttpServer represents an HTTP server with logging and graceful shutdown support.
-// It wraps an underlying http.Server and keeps track of shutdown timeout and logger.
+// It wraps an underlying http.Server and keeps track of its TLS settings and logger.
 type HttpServer struct {
-	srv             *http.Server  // srv is the underlying HTTP server that handles incoming requests.
-	shutdownTimeout time.Duration // shutdownTimeout specifies how long to wait for active connections to finish during shutdown.
-	logger          logger.Logger // logger is used to log server events, errors, and shutdown information.
+	srv    *http.Server  // srv is the underlying HTTP server that handles incoming requests.
+	tls    config.TLS    // tls holds the TLS termination settings; plain HTTP is served unless enabled.
+	logger logger.Logger // logger is used to log server events, errors, and shutdown information.
+
+	ctx    context.Context    // ctx bounds background work such as certificate watching.
+	cancel context.CancelFunc // cancel stops the background work on shutdown.
 }
 
 // NewServer creates a new HttpServer instance with the specified configuration, HTTP handler, and logger.
-// The configuration provides the port, read/write timeouts, max header size, and shutdown timeout.
+// The configuration provides the port, read/write timeouts, max header size and TLS settings;
+// the shutdown timeout is applied by the caller of Shutdown.
 func NewServer(config config.Server, handler http.Handler, logger logger.Logger) *HttpServer {
 	server := new(HttpServer)
 	server.srv = &http.Server{
@@ -30,26 +40,89 @@ func NewServer(config config.Server, handler http.Handler, logger logger.Logger)
 		WriteTimeout:   config.WriteTimeout,
 		MaxHeaderBytes: config.MaxHeaderBytes,
 	}
-	server.shutdownTimeout = config.ShutdownTimeout
+	server.tls = config.TLS
 	server.logger = logger
+	server.ctx, server.cancel = context.WithCancel(context.Background())
 	return server
 }
 
 // Run starts the HTTP server and begins listening for requests.
 // It blocks until the server is stopped or an error occurs. Errors are returned to the caller.
+//
+// With TLS enabled, the certificate is loaded first (generating a development certificate
+// if configured and missing) and reloaded whenever its files change; a certificate that
+// cannot be loaded at start is an error.
 func (s *HttpServer) Run() error {
-	s.logger.LogInfo("server — receiving requests", "layer", "server")
-	return s.srv.ListenAndServe()
+
+	if !s.tls.Enabled {
+		s.logger.LogInfo("server — receiving requests", "layer", "server")
+		return s.srv.ListenAndServe()
+	}
+
+	if err := s.ensureDevCert(); err != nil {
+		return err
+	}
+
+	certs, err := newCertReloader(s.tls.CertFile, s.tls.KeyFile, s.logger)
+	if err != nil {
+		return err
+	}
+
+	if err := certs.watch(s.ctx); err != nil {
+		s.logger.LogWarn("server — TLS certificate watching disabled, restart to apply renewed certificates", "err", err.Error(), "layer", "server")
+	}
+
+	s.srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: certs.certificate}
+
+	s.logger.LogInfo("server — receiving requests over TLS", "cert_file", s.tls.CertFile, "layer", "server")
+
+	return s.srv.ListenAndServeTLS("", "")
+
 }
 
-// Shutdown gracefully stops the server, allowing active connections to complete within the configured timeout.
-// Logs either a successful shutdown or any errors encountered during the shutdown process.
-func (s *HttpServer) Shutdown() {
-	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
-	defer cancel()
+// ensureDevCert generates a self-signed localhost certificate into the configured files
+// if development certificates are enabled and either file is missing.
+func (s *HttpServer) ensureDevCert() error {
+
+	if !s.tls.DevCert {
+		return nil
+	}
+
+	_, certErr := os.Stat(s.tls.CertFile)
+	_, keyErr := os.Stat(s.tls.KeyFile)
+	if !errors.Is(certErr, fs.ErrNotExist) && !errors.Is(keyErr, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err := devcert.WriteFiles(s.tls.CertFile, s.tls.KeyFile, devcert.DefaultHosts, time.Now()); err != nil {
+		return err
+	}
+
+	s.logger.LogWarn("server — generated a self-signed development certificate, do not use it in production", "cert_file", s.tls.CertFile, "layer", "server")
+
+	return nil
+
+}
+
+// Shutdown stops certificate watching and gracefully stops the server, allowing active connections
+// to complete until ctx is done. Connections still active then are closed forcibly, cutting off
+// their requests, and the error of ctx is returned.
+// Logs how long draining took and whether it had to be cut short.
+func (s *HttpServer) Shutdown(ctx context.Context) error {
+
+	s.cancel()
+	start := time.Now()
+
 	if err := s.srv.Shutdown(ctx); err != nil {
-		s.logger.LogError("server — failed to shutdown gracefully", err, "layer", "server")
-	} else {
-		s.logger.LogInfo("server — shutdown complete", "layer", "server")
+		s.logger.LogWarn("server — requests still in flight at the shutdown deadline, closing their connections", "err", err.Error(), "drained_for", time.Since(start).String(), "layer", "server")
+		if closeErr := s.srv.Close(); closeErr != nil {
+			s.logger.LogError("server — failed to close connections", closeErr, "layer", "server")
+		}
+		return err
 	}
+
+	s.logger.LogInfo("server — shutdown complete", "drained_for", time.Since(start).String(), "layer", "server")
+
+	return nil
+
 }
@@ -0,0 +1,87 @@
+package httpserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// serve starts s on a random local port and returns its base URL.
+func serve(t *testing.T, s *HttpServer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = s.srv.Serve(listener) }()
+	return "http://" + listener.Addr().String()
+}
+
+func TestShutdown(t *testing.T) {
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			started <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("drained", func(t *testing.T) {
+
+		mockLogger := mocks.NewMockLogger(gomock.NewController(t))
+		mockLogger.EXPECT().LogInfo("server — shutdown complete", "drained_for", gomock.Any(), "layer", "server")
+
+		s := NewServer(config.Server{}, handler, mockLogger)
+		url := serve(t, s)
+
+		response, err := http.Get(url + "/fast")
+		require.NoError(t, err)
+		response.Body.Close()
+
+		require.NoError(t, s.Shutdown(context.Background()))
+
+	})
+
+	t.Run("forced", func(t *testing.T) {
+
+		mockLogger := mocks.NewMockLogger(gomock.NewController(t))
+		mockLogger.EXPECT().LogWarn("server — requests still in flight at the shutdown deadline, closing their connections", "err", context.DeadlineExceeded.Error(), "drained_for", gomock.Any(), "layer", "server")
+
+		s := NewServer(config.Server{}, handler, mockLogger)
+		url := serve(t, s)
+
+		failed := make(chan error, 1)
+		go func() {
+			response, err := http.Get(url + "/slow")
+			if err == nil {
+				response.Body.Close()
+			}
+			failed <- err
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, s.Shutdown(ctx), context.DeadlineExceeded)
+
+		select {
+		case err := <-failed:
+			require.Error(t, err, "the in-flight request must be cut off")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the in-flight request was not cut off")
+		}
+
+	})
+
+}
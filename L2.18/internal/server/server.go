@@ -3,6 +3,7 @@
 package server
 
 import (
+	"context"
 	"net/http"
 
 	"L2.18/internal/config"
@@ -17,9 +18,10 @@ type Server interface {
 	// Returns a non-nil error if the server fails to start or stops unexpectedly.
 	Run() error
 
-	// Shutdown gracefully stops the server, waiting for active requests to finish.
+	// Shutdown gracefully stops the server, waiting for active requests to finish until ctx is done.
+	// Connections still open at that point are closed forcibly and ctx's error is returned.
 	// Should be called when the server needs to stop in response to a signal or application shutdown.
-	Shutdown()
+	Shutdown(ctx context.Context) error
 }
 
 // NewServer creates and returns a new Server instance.
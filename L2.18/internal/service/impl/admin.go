@@ -0,0 +1,117 @@
+package impl
+
+import (
+	"context"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/internal/repository"
+	"L2.18/pkg/logger"
+	"L2.18/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Admin is the implementation of the administrative service.
+// It exposes storage-wide operations that are not scoped to a single user's request,
+// such as listing users, bulk deletion, statistics and storage maintenance.
+type Admin struct {
+	Storage repository.Storage   // underlying storage for events
+	blobs   repository.BlobStore // storage for the content of event attachments
+	logger  logger.Logger        // logger for service-level logging
+}
+
+// NewAdmin creates a new Admin instance with the provided storage, attachment blob store and logger.
+func NewAdmin(storage repository.Storage, blobs repository.BlobStore, logger logger.Logger) *Admin {
+	return &Admin{Storage: storage, blobs: blobs, logger: logger}
+}
+
+// ListUsers returns every user owning at least one event with their event counts.
+func (a *Admin) ListUsers(ctx context.Context) (users []models.UserStats, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.admin.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	return a.Storage.ListUsers(ctx)
+
+}
+
+// DeleteUserEvents removes all events of a user, with the files attached to them,
+// and returns how many events were removed. Returns an error if the user ID is invalid.
+func (a *Admin) DeleteUserEvents(ctx context.Context, userID int) (deleted int, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.admin.DeleteUserEvents", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	if userID <= 0 {
+		return 0, errs.ErrInvalidUserID
+	}
+
+	deleted, err = a.Storage.DeleteUserEvents(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := a.blobs.Delete(ctx, attachmentKey(ctx, userID)); err != nil {
+		a.logger.LogWarn("service — failed to remove attachment files", "UserID", userID, "err", err.Error(), "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+	}
+
+	a.logger.LogInfo("service — admin deleted all user events", "UserID", userID, "deleted", deleted, "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+
+	return deleted, nil
+
+}
+
+// Stats returns global statistics of the event store.
+func (a *Admin) Stats(ctx context.Context) (stats models.Stats, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.admin.Stats")
+	defer func() { tracing.End(span, err) }()
+
+	return a.Storage.Stats(ctx)
+
+}
+
+// Snapshot forces the storage backend to persist a snapshot of its data.
+// Returns errs.ErrNotSupported if the backend cannot take snapshots.
+func (a *Admin) Snapshot(ctx context.Context) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.admin.Snapshot")
+	defer func() { tracing.End(span, err) }()
+
+	snapshotter, ok := a.Storage.(repository.Snapshotter)
+	if !ok {
+		return errs.ErrNotSupported
+	}
+
+	if err := snapshotter.Snapshot(ctx); err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("service — storage snapshot taken", "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+
+	return nil
+
+}
+
+// Compact forces the storage backend to reclaim unused space.
+// Returns errs.ErrNotSupported if the backend does not support compaction.
+func (a *Admin) Compact(ctx context.Context) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.admin.Compact")
+	defer func() { tracing.End(span, err) }()
+
+	compactor, ok := a.Storage.(repository.Compactor)
+	if !ok {
+		return errs.ErrNotSupported
+	}
+
+	if err := compactor.Compact(ctx); err != nil {
+		return err
+	}
+
+	a.logger.LogInfo("service — storage compacted", "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+
+	return nil
+
+}
@@ -0,0 +1,106 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	storageMock "L2.18/internal/repository/mocks"
+	loggerMock "L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// compactingStorage is a storage mock that also supports compaction.
+type compactingStorage struct {
+	*storageMock.MockStorage
+	*storageMock.MockCompactor
+}
+
+func TestAdmin_DeleteUserEvents_Success(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStorage := storageMock.NewMockStorage(controller)
+	mockBlobs := storageMock.NewMockBlobStore(controller)
+
+	admin := NewAdmin(mockStorage, mockBlobs, mockLogger)
+
+	mockStorage.EXPECT().DeleteUserEvents(gomock.Any(), 7).Return(3, nil)
+	mockBlobs.EXPECT().Delete(gomock.Any(), "default/7").Return(nil)
+	mockLogger.EXPECT().LogInfo("service — admin deleted all user events", "UserID", 7, "deleted", 3, "request_id", "", "layer", "service.impl")
+
+	deleted, err := admin.DeleteUserEvents(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+
+}
+
+func TestAdmin_DeleteUserEvents_ErrInvalidUserID(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	admin := NewAdmin(storageMock.NewMockStorage(controller), storageMock.NewMockBlobStore(controller), loggerMock.NewMockLogger(controller))
+
+	_, err := admin.DeleteUserEvents(context.Background(), 0)
+	assert.ErrorIs(t, err, errs.ErrInvalidUserID)
+
+}
+
+func TestAdmin_ListUsersAndStats(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStorage := storageMock.NewMockStorage(controller)
+	admin := NewAdmin(mockStorage, storageMock.NewMockBlobStore(controller), loggerMock.NewMockLogger(controller))
+
+	users := []models.UserStats{{UserID: 1, Events: 2}}
+	stats := models.Stats{Users: 1, Events: 2, SizeBytes: 100}
+
+	mockStorage.EXPECT().ListUsers(gomock.Any()).Return(users, nil)
+	mockStorage.EXPECT().Stats(gomock.Any()).Return(stats, nil)
+
+	gotUsers, err := admin.ListUsers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, users, gotUsers)
+
+	gotStats, err := admin.Stats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, stats, gotStats)
+
+}
+
+func TestAdmin_Compact_Supported(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	storage := compactingStorage{storageMock.NewMockStorage(controller), storageMock.NewMockCompactor(controller)}
+
+	admin := NewAdmin(storage, storageMock.NewMockBlobStore(controller), mockLogger)
+
+	storage.MockCompactor.EXPECT().Compact(gomock.Any()).Return(nil)
+	mockLogger.EXPECT().LogInfo("service — storage compacted", "request_id", "", "layer", "service.impl")
+
+	assert.NoError(t, admin.Compact(context.Background()))
+
+}
+
+func TestAdmin_ErrNotSupported(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	admin := NewAdmin(storageMock.NewMockStorage(controller), storageMock.NewMockBlobStore(controller), loggerMock.NewMockLogger(controller))
+
+	assert.ErrorIs(t, admin.Snapshot(context.Background()), errs.ErrNotSupported)
+	assert.ErrorIs(t, admin.Compact(context.Background()), errs.ErrNotSupported)
+
+}
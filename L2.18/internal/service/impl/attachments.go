@@ -0,0 +1,248 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxNameLength is the maximum length of an attachment file name in bytes.
+const maxNameLength = 255
+
+// AddAttachment validates a file and attaches it to an event of the user.
+//
+// The MIME type is detected from the content rather than trusted from the client, and
+// must be one of the allowed types. Content beyond the size limit is not stored. On
+// success the attachment ID, content type, size and upload time are filled in.
+func (s *Service) AddAttachment(ctx context.Context, attachment *models.Attachment, content io.Reader) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.AddAttachment", trace.WithAttributes(attribute.Int("user.id", attachment.UserID), attribute.String("event.id", attachment.EventID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.validateEventOwner(ctx, attachment.UserID, attachment.EventID); err != nil {
+		return err
+	}
+
+	name, err := attachmentName(attachment.Name)
+	if err != nil {
+		return err
+	}
+
+	limits := s.attachments.Load()
+
+	if len(s.Storage.GetAttachments(ctx, attachment.EventID)) >= limits.MaxPerEvent {
+		return errs.ErrMaxAttachments
+	}
+
+	head := make([]byte, 3072)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if n == 0 {
+		return errs.ErrMissingFile
+	}
+	head = head[:n]
+
+	contentType := mimetype.Detect(head)
+	if !allowedType(contentType, limits.AllowedTypes) {
+		return fmt.Errorf("%w: %s", errs.ErrAttachmentType, contentType.String())
+	}
+
+	attachment.AttachmentID = uuid.New().String()
+	attachment.Name = name
+	attachment.ContentType = contentType.String()
+	attachment.CreatedAt = s.clock.Now().UTC()
+
+	maxSize := int64(limits.MaxSizeMB) << 20
+	key := attachmentKey(ctx, attachment.UserID, attachment.EventID, attachment.AttachmentID)
+
+	size, err := s.blobs.Put(ctx, key, &sizeLimiter{r: io.MultiReader(bytes.NewReader(head), content), remaining: maxSize})
+	if err != nil {
+		return err
+	}
+	attachment.Size = size
+
+	if err := s.Storage.SaveAttachment(ctx, attachment); err != nil {
+		s.removeBlobs(ctx, key)
+		return err
+	}
+
+	s.logger.Debug("service — attachment added", "UserID", attachment.UserID, "EventID", attachment.EventID, "AttachmentID", attachment.AttachmentID, "type", attachment.ContentType, "bytes", size, "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+
+	return nil
+
+}
+
+// GetAttachments returns the files attached to an event of the user, in upload order.
+func (s *Service) GetAttachments(ctx context.Context, userID int, eventID string) (attachments []models.Attachment, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.GetAttachments", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("event.id", eventID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.validateEventOwner(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	return s.Storage.GetAttachments(ctx, eventID), nil
+
+}
+
+// OpenAttachment returns a file attached to an event of the user together with a reader of its
+// content. The caller must close the reader.
+func (s *Service) OpenAttachment(ctx context.Context, userID int, eventID, attachmentID string) (attachment models.Attachment, content io.ReadCloser, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.OpenAttachment", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("event.id", eventID), attribute.String("attachment.id", attachmentID)))
+	defer func() { tracing.End(span, err) }()
+
+	attachment, err = s.findAttachment(ctx, userID, eventID, attachmentID)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	content, err = s.blobs.Get(ctx, attachmentKey(ctx, userID, eventID, attachmentID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return models.Attachment{}, nil, errs.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+
+}
+
+// DeleteAttachment removes a file attached to an event of the user.
+func (s *Service) DeleteAttachment(ctx context.Context, userID int, eventID, attachmentID string) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.DeleteAttachment", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("event.id", eventID), attribute.String("attachment.id", attachmentID)))
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.findAttachment(ctx, userID, eventID, attachmentID); err != nil {
+		return err
+	}
+
+	if err := s.Storage.DeleteAttachment(ctx, eventID, attachmentID); err != nil {
+		return err
+	}
+
+	s.removeBlobs(ctx, attachmentKey(ctx, userID, eventID, attachmentID))
+
+	return nil
+
+}
+
+// validateEventOwner checks that the IDs are well-formed and that the event exists and belongs to the user.
+func (s *Service) validateEventOwner(ctx context.Context, userID int, eventID string) error {
+
+	if err := validateIDs(userID, eventID); err != nil {
+		return err
+	}
+
+	return validateDelete(&models.Meta{UserID: userID, EventID: eventID}, s.Storage.GetEventByID(ctx, eventID))
+
+}
+
+// findAttachment returns the metadata of a file attached to an event of the user.
+func (s *Service) findAttachment(ctx context.Context, userID int, eventID, attachmentID string) (models.Attachment, error) {
+
+	if err := s.validateEventOwner(ctx, userID, eventID); err != nil {
+		return models.Attachment{}, err
+	}
+
+	if _, err := uuid.Parse(attachmentID); err != nil {
+		return models.Attachment{}, errs.ErrInvalidAttachmentID
+	}
+
+	attachments := s.Storage.GetAttachments(ctx, eventID)
+
+	i := slices.IndexFunc(attachments, func(a models.Attachment) bool { return a.AttachmentID == attachmentID })
+	if i < 0 {
+		return models.Attachment{}, errs.ErrAttachmentNotFound
+	}
+
+	return attachments[i], nil
+
+}
+
+// removeBlobs deletes stored files whose metadata is already gone. A failure leaves only
+// unreachable files behind, so it is logged rather than reported to the caller.
+func (s *Service) removeBlobs(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		s.logger.LogWarn("service — failed to remove attachment files", "key", key, "err", err.Error(), "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+	}
+}
+
+// attachmentKey returns the blob key of an attachment. Keys are grouped by tenant, user and
+// event, so every file of an event or of a user can be removed at once by deleting a prefix.
+func attachmentKey(ctx context.Context, userID int, ids ...string) string {
+	return path.Join(append([]string{tenant.ID(ctx), strconv.Itoa(userID)}, ids...)...)
+}
+
+// attachmentName strips any directories from a client-supplied file name and checks what is left.
+func attachmentName(name string) (string, error) {
+
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "." || name == ".." || name == "/" || name == "" {
+		return "", errs.ErrMissingFile
+	}
+
+	if len(name) > maxNameLength {
+		name = strings.ToValidUTF8(name[len(name)-maxNameLength:], "")
+	}
+
+	return name, nil
+
+}
+
+// allowedType reports whether the detected type, or one of the more generic types it
+// belongs to, is among the allowed MIME types. Parameters such as charset are ignored.
+func allowedType(detected *mimetype.MIME, allowed []string) bool {
+
+	for m := detected; m != nil; m = m.Parent() {
+		base, _, err := mime.ParseMediaType(m.String())
+		if err == nil && slices.Contains(allowed, base) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// sizeLimiter is a reader that fails with errs.ErrAttachmentSize once more than
+// the remaining number of bytes has been read from the underlying reader.
+type sizeLimiter struct {
+	r         io.Reader // underlying reader
+	remaining int64     // bytes that may still be read
+}
+
+// Read reads from the underlying reader, failing once the limit is exceeded.
+func (l *sizeLimiter) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, errs.ErrAttachmentSize
+	}
+	return n, err
+}
@@ -0,0 +1,162 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	storageMock "L2.18/internal/repository/mocks"
+	"L2.18/pkg/clock"
+	loggerMock "L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// attachmentLimits are the attachment limits used by the tests.
+var attachmentLimits = config.Attachments{MaxSizeMB: 1, MaxPerEvent: 2, AllowedTypes: []string{"application/pdf", "text/plain"}}
+
+// attachmentFixture wires a service with storage and blob store mocks, and an event of user 1.
+func attachmentFixture(t *testing.T) (*Service, *storageMock.MockStorage, *storageMock.MockBlobStore, *loggerMock.MockLogger, *models.Event) {
+
+	controller := gomock.NewController(t)
+	t.Cleanup(controller.Finish)
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStorage := storageMock.NewMockStorage(controller)
+	mockBlobs := storageMock.NewMockBlobStore(controller)
+
+	service := NewService(config.Service{MaxEventsPerUser: 5, Attachments: attachmentLimits}, mockStorage, mockBlobs, noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
+	event := &models.Event{Meta: models.Meta{UserID: 1, EventID: uuid.New().String()}}
+
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), event.Meta.EventID).Return(event).AnyTimes()
+
+	return service, mockStorage, mockBlobs, mockLogger, event
+
+}
+
+// drain is a BlobStore.Put stub consuming the content like a real store would.
+func drain(_ context.Context, _ string, r io.Reader) (int64, error) {
+	return io.Copy(io.Discard, r)
+}
+
+func TestAddAttachment_Success(t *testing.T) {
+
+	service, mockStorage, mockBlobs, mockLogger, event := attachmentFixture(t)
+	content := "%PDF-1.7\n" + strings.Repeat("agenda ", 1000)
+
+	mockStorage.EXPECT().GetAttachments(gomock.Any(), event.Meta.EventID).Return(nil)
+	mockBlobs.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, key string, r io.Reader) (int64, error) {
+			require.True(t, strings.HasPrefix(key, "default/1/"+event.Meta.EventID+"/"))
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, content, string(data))
+			return int64(len(data)), nil
+		})
+	mockStorage.EXPECT().SaveAttachment(gomock.Any(), gomock.Any()).Return(nil)
+	mockLogger.EXPECT().Debug("service — attachment added", gomock.Any()).Times(1)
+
+	attachment := &models.Attachment{UserID: 1, EventID: event.Meta.EventID, Name: `C:\Users\me\agenda.pdf`}
+
+	require.NoError(t, service.AddAttachment(context.Background(), attachment, strings.NewReader(content)))
+	assert.Equal(t, "agenda.pdf", attachment.Name)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, int64(len(content)), attachment.Size)
+	assert.NotEmpty(t, attachment.AttachmentID)
+	assert.False(t, attachment.CreatedAt.IsZero())
+
+}
+
+func TestAddAttachment_Rejected(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		existing int
+		content  []byte
+		want     error
+	}{
+		{"empty file", 0, nil, errs.ErrMissingFile},
+		{"disallowed type", 0, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), errs.ErrAttachmentType},
+		{"too large", 0, bytes.Repeat([]byte("a"), 1<<20+1), errs.ErrAttachmentSize},
+		{"too many attachments", 2, []byte("notes"), errs.ErrMaxAttachments},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			service, mockStorage, mockBlobs, _, event := attachmentFixture(t)
+
+			mockStorage.EXPECT().GetAttachments(gomock.Any(), event.Meta.EventID).Return(make([]models.Attachment, tc.existing))
+			mockBlobs.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(drain).AnyTimes()
+
+			attachment := &models.Attachment{UserID: 1, EventID: event.Meta.EventID, Name: "file"}
+
+			err := service.AddAttachment(context.Background(), attachment, bytes.NewReader(tc.content))
+			assert.ErrorIs(t, err, tc.want)
+
+		})
+	}
+
+}
+
+func TestAddAttachment_ErrUnauthorized(t *testing.T) {
+
+	service, _, _, _, event := attachmentFixture(t)
+
+	attachment := &models.Attachment{UserID: 2, EventID: event.Meta.EventID, Name: "notes.txt"}
+
+	err := service.AddAttachment(context.Background(), attachment, strings.NewReader("notes"))
+	assert.ErrorIs(t, err, errs.ErrUnauthorized)
+
+}
+
+func TestOpenAttachment(t *testing.T) {
+
+	service, mockStorage, mockBlobs, _, event := attachmentFixture(t)
+
+	stored := models.Attachment{AttachmentID: uuid.New().String(), EventID: event.Meta.EventID, UserID: 1, Name: "notes.txt"}
+	key := "default/1/" + event.Meta.EventID + "/" + stored.AttachmentID
+
+	mockStorage.EXPECT().GetAttachments(gomock.Any(), event.Meta.EventID).Return([]models.Attachment{stored}).Times(3)
+	mockBlobs.EXPECT().Get(gomock.Any(), key).Return(io.NopCloser(strings.NewReader("notes")), nil)
+	mockBlobs.EXPECT().Get(gomock.Any(), key).Return(nil, fs.ErrNotExist)
+
+	attachment, content, err := service.OpenAttachment(context.Background(), 1, event.Meta.EventID, stored.AttachmentID)
+	require.NoError(t, err)
+	assert.Equal(t, stored, attachment)
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, "notes", string(data))
+
+	_, _, err = service.OpenAttachment(context.Background(), 1, event.Meta.EventID, stored.AttachmentID)
+	assert.ErrorIs(t, err, errs.ErrAttachmentNotFound)
+
+	_, _, err = service.OpenAttachment(context.Background(), 1, event.Meta.EventID, uuid.New().String())
+	assert.ErrorIs(t, err, errs.ErrAttachmentNotFound)
+
+	_, _, err = service.OpenAttachment(context.Background(), 1, event.Meta.EventID, "not-a-uuid")
+	assert.ErrorIs(t, err, errs.ErrInvalidAttachmentID)
+
+}
+
+func TestDeleteAttachment(t *testing.T) {
+
+	service, mockStorage, mockBlobs, _, event := attachmentFixture(t)
+
+	stored := models.Attachment{AttachmentID: uuid.New().String(), EventID: event.Meta.EventID, UserID: 1}
+
+	mockStorage.EXPECT().GetAttachments(gomock.Any(), event.Meta.EventID).Return([]models.Attachment{stored})
+	mockStorage.EXPECT().DeleteAttachment(gomock.Any(), event.Meta.EventID, stored.AttachmentID).Return(nil)
+	mockBlobs.EXPECT().Delete(gomock.Any(), "default/1/"+event.Meta.EventID+"/"+stored.AttachmentID).Return(nil)
+
+	assert.NoError(t, service.DeleteAttachment(context.Background(), 1, event.Meta.EventID, stored.AttachmentID))
+
+}
@@ -0,0 +1,171 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Policies for events on non-working days, see config.Service.NonWorkingDays.
+const (
+	policyAllow  = "allow"  // events on non-working days are accepted silently
+	policyWarn   = "warn"   // events are accepted and the caller is warned
+	policyReject = "reject" // events are rejected with errs.ErrNonWorkingDay
+)
+
+// UpdateSettings validates and saves a user's working calendar settings.
+// An empty country clears the holiday calendar; a known country code is required otherwise.
+func (s *Service) UpdateSettings(ctx context.Context, settings *models.Settings) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.UpdateSettings", trace.WithAttributes(attribute.Int("user.id", settings.UserID)))
+	defer func() { tracing.End(span, err) }()
+
+	if settings.UserID <= 0 {
+		return errs.ErrInvalidUserID
+	}
+
+	settings.Country = strings.ToUpper(settings.Country)
+	if settings.Country != "" && !s.calendar.Has(settings.Country) {
+		return fmt.Errorf("%w: %s", errs.ErrUnknownCountry, settings.Country)
+	}
+
+	if len(settings.WorkingDays) == 0 {
+		return errs.ErrInvalidWorkingDay
+	}
+
+	return s.Storage.SaveSettings(ctx, settings)
+
+}
+
+// GetSettings returns a user's working calendar settings, falling back to the
+// configured defaults for users who have not saved their own.
+func (s *Service) GetSettings(ctx context.Context, userID int) (settings models.Settings, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.GetSettings", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	if userID <= 0 {
+		return models.Settings{}, errs.ErrInvalidUserID
+	}
+
+	return s.settingsFor(ctx, userID), nil
+
+}
+
+// GetHolidays returns the holidays of the user's calendar within the day, ISO week
+// or month containing meta.EventDate, ordered by date.
+func (s *Service) GetHolidays(ctx context.Context, meta *models.Meta, period models.Period) (res []models.Holiday, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.GetHolidays", trace.WithAttributes(attribute.Int("user.id", meta.UserID), attribute.String("period", string(period))))
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateGet(meta); err != nil {
+		return nil, err
+	}
+
+	from, to := periodRange(meta.EventDate, period)
+	settings := s.settingsFor(ctx, meta.UserID)
+
+	for _, holiday := range s.calendar.Holidays(settings.Country, from, to) {
+		res = append(res, models.Holiday{Date: holiday.Date, Name: holiday.Name})
+	}
+
+	return res, nil
+
+}
+
+// settingsFor returns the saved settings of a user or the configured defaults.
+func (s *Service) settingsFor(ctx context.Context, userID int) models.Settings {
+
+	if settings := s.Storage.GetSettings(ctx, userID); settings != nil {
+		return *settings
+	}
+
+	country, workingDays := s.calendar.Defaults()
+
+	return models.Settings{UserID: userID, Country: country, WorkingDays: workingDays}
+
+}
+
+// checkWorkingDay applies the non-working day policy to an event planned on date.
+//
+// With the "warn" policy the event is accepted and meta.Warning explains why the day
+// is not a working one; with "reject" errs.ErrNonWorkingDay is returned. The policy
+// "allow" skips the check entirely.
+func (s *Service) checkWorkingDay(ctx context.Context, meta *models.Meta, date time.Time) error {
+
+	policy := *s.nonWorkingDays.Load()
+	if policy != policyWarn && policy != policyReject {
+		return nil
+	}
+
+	settings := s.settingsFor(ctx, meta.UserID)
+
+	working, holiday := s.calendar.IsWorkingDay(settings.Country, settings.WorkingDays, date)
+	if working {
+		return nil
+	}
+
+	reason := date.Format("2006-01-02") + " (" + date.Weekday().String() + ")"
+	if holiday != "" {
+		reason = date.Format("2006-01-02") + " (" + holiday + ")"
+	}
+
+	if policy == policyReject {
+		return fmt.Errorf("%w: %s", errs.ErrNonWorkingDay, reason)
+	}
+
+	meta.Holiday, meta.NonWorking = holiday, true
+	meta.Warning = fmt.Sprintf("%s: %s", errs.ErrNonWorkingDay, reason)
+
+	s.logger.Debug("service — event planned on a non-working day", "UserID", meta.UserID, "date", date.Format("2006-01-02"), "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+
+	return nil
+
+}
+
+// annotate marks the events falling on holidays or non-working days of the user.
+func (s *Service) annotate(ctx context.Context, userID int, events []models.Event) {
+
+	if len(events) == 0 {
+		return
+	}
+
+	settings := s.settingsFor(ctx, userID)
+
+	for i := range events {
+		working, holiday := s.calendar.IsWorkingDay(settings.Country, settings.WorkingDays, events[i].Meta.EventDate)
+		events[i].Meta.Holiday, events[i].Meta.NonWorking = holiday, !working
+	}
+
+}
+
+// periodRange returns the first and last day of the day, ISO week (Monday to Sunday)
+// or month containing date.
+func periodRange(date time.Time, period models.Period) (time.Time, time.Time) {
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+
+	case models.Week:
+		monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return monday, monday.AddDate(0, 0, 6)
+
+	case models.Month:
+		first := day.AddDate(0, 0, 1-day.Day())
+		return first, first.AddDate(0, 1, -1)
+
+	default:
+		return day, day
+
+	}
+
+}
@@ -0,0 +1,204 @@
+package impl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	storageMock "L2.18/internal/repository/mocks"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/holidays"
+	loggerMock "L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCalendar creates a registry with a single XX calendar and a Monday to Friday
+// default working week, without a default country.
+func testCalendar(t *testing.T) *holidays.Registry {
+
+	t.Helper()
+
+	dir := t.TempDir()
+	data := "12-25 Christmas Day\n2030-01-07 Special Day\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "XX.txt"), []byte(data), 0o644))
+
+	registry, err := holidays.Load(config.Calendar{HolidaysDir: dir, WorkingWeek: []string{"mon", "tue", "wed", "thu", "fri"}})
+	require.NoError(t, err)
+
+	return registry
+
+}
+
+// weekdays are the settings of a user working Monday to Friday under the XX calendar.
+var weekdays = &models.Settings{UserID: 1, Country: "XX", WorkingDays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}
+
+func TestCreateEvent_NonWorkingDayPolicies(t *testing.T) {
+
+	christmas := time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2030, 12, 28, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name    string
+		policy  string
+		date    time.Time
+		wantErr error
+		warning string
+	}{
+		{"reject holiday", policyReject, christmas, errs.ErrNonWorkingDay, ""},
+		{"reject weekend", policyReject, saturday, errs.ErrNonWorkingDay, ""},
+		{"warn holiday", policyWarn, christmas, nil, "event date falls on a non-working day: 2030-12-25 (Christmas Day)"},
+		{"warn weekend", policyWarn, saturday, nil, "event date falls on a non-working day: 2030-12-28 (Saturday)"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockLogger := loggerMock.NewMockLogger(controller)
+			mockStorage := storageMock.NewMockStorage(controller)
+
+			service := NewService(config.Service{MaxEventsPerUser: 5, NonWorkingDays: tc.policy}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
+			event := &models.Event{Meta: models.Meta{UserID: 1, EventDate: tc.date}}
+
+			mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			mockStorage.EXPECT().CountUserEvents(gomock.Any(), 1).Return(0, nil)
+			mockStorage.EXPECT().GetSettings(gomock.Any(), 1).Return(weekdays)
+
+			if tc.wantErr == nil {
+				mockStorage.EXPECT().CreateEvent(gomock.Any(), event).Return("id", nil)
+			}
+
+			_, err := service.CreateEvent(context.Background(), event)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.warning, event.Meta.Warning)
+
+		})
+	}
+
+}
+
+func TestCreateEvent_WorkingDayAllowed(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStorage := storageMock.NewMockStorage(controller)
+
+	service := NewService(config.Service{MaxEventsPerUser: 5, NonWorkingDays: policyReject}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
+	event := &models.Event{Meta: models.Meta{UserID: 1, EventDate: time.Date(2030, 12, 24, 0, 0, 0, 0, time.UTC)}}
+
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), 1).Return(0, nil)
+	mockStorage.EXPECT().GetSettings(gomock.Any(), 1).Return(nil)
+	mockStorage.EXPECT().CreateEvent(gomock.Any(), event).Return("id", nil)
+
+	_, err := service.CreateEvent(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Empty(t, event.Meta.Warning)
+
+}
+
+func TestGetEvents_Annotated(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStorage := storageMock.NewMockStorage(controller)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), loggerMock.NewMockLogger(controller))
+
+	meta := &models.Meta{UserID: 1, EventDate: time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)}
+	events := []models.Event{
+		{Meta: models.Meta{UserID: 1, EventDate: time.Date(2030, 12, 24, 0, 0, 0, 0, time.UTC)}},
+		{Meta: models.Meta{UserID: 1, EventDate: time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	mockStorage.EXPECT().GetEvents(gomock.Any(), meta, models.Week).Return(events, nil)
+	mockStorage.EXPECT().GetSettings(gomock.Any(), 1).Return(weekdays)
+
+	got, err := service.GetEvents(context.Background(), meta, models.Week)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Christmas Day", got[0].Meta.Holiday)
+	assert.True(t, got[0].Meta.NonWorking)
+	assert.Empty(t, got[1].Meta.Holiday)
+	assert.False(t, got[1].Meta.NonWorking)
+
+}
+
+func TestGetHolidays_Month(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStorage := storageMock.NewMockStorage(controller)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), loggerMock.NewMockLogger(controller))
+
+	mockStorage.EXPECT().GetSettings(gomock.Any(), 1).Return(weekdays)
+
+	got, err := service.GetHolidays(context.Background(), &models.Meta{UserID: 1, EventDate: time.Date(2030, 12, 3, 0, 0, 0, 0, time.UTC)}, models.Month)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Holiday{{Date: time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas Day"}}, got)
+
+}
+
+func TestUpdateSettings(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStorage := storageMock.NewMockStorage(controller)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), loggerMock.NewMockLogger(controller))
+
+	assert.ErrorIs(t, service.UpdateSettings(context.Background(), &models.Settings{UserID: 1, Country: "ZZ", WorkingDays: weekdays.WorkingDays}), errs.ErrUnknownCountry)
+	assert.ErrorIs(t, service.UpdateSettings(context.Background(), &models.Settings{UserID: 1, Country: "xx"}), errs.ErrInvalidWorkingDay)
+	assert.ErrorIs(t, service.UpdateSettings(context.Background(), &models.Settings{Country: "xx"}), errs.ErrInvalidUserID)
+
+	mockStorage.EXPECT().SaveSettings(gomock.Any(), weekdays).Return(nil)
+	assert.NoError(t, service.UpdateSettings(context.Background(), &models.Settings{UserID: 1, Country: "xx", WorkingDays: weekdays.WorkingDays}))
+
+}
+
+func TestGetSettings_Defaults(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStorage := storageMock.NewMockStorage(controller)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), loggerMock.NewMockLogger(controller))
+
+	mockStorage.EXPECT().GetSettings(gomock.Any(), 2).Return(nil)
+
+	settings, err := service.GetSettings(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, models.Settings{UserID: 2, WorkingDays: weekdays.WorkingDays}, settings)
+
+}
+
+func TestPeriodRange(t *testing.T) {
+
+	date := time.Date(2030, 12, 25, 15, 0, 0, 0, time.UTC)
+
+	from, to := periodRange(date, models.Day)
+	assert.Equal(t, time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, from, to)
+
+	from, to = periodRange(date, models.Week)
+	assert.Equal(t, time.Date(2030, 12, 23, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2030, 12, 29, 0, 0, 0, 0, time.UTC), to)
+
+	from, to = periodRange(time.Date(2028, 2, 10, 0, 0, 0, 0, time.UTC), models.Month)
+	assert.Equal(t, time.Date(2028, 2, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), to)
+
+}
@@ -0,0 +1,212 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/internal/repository/memory"
+	storageMock "L2.18/internal/repository/mocks"
+	"L2.18/pkg/clock"
+	loggerMock "L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clockFixture wires a service on top of the in-memory storage, both reading a fake clock set to now.
+func clockFixture(t *testing.T, now time.Time) (*Service, *memory.Storage, *clock.Fake) {
+
+	controller := gomock.NewController(t)
+	t.Cleanup(controller.Finish)
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+	fake := clock.NewFake(now)
+	storage := memory.NewStorage(config.Storage{}, fake, mockLogger)
+
+	service := NewService(config.Service{MaxEventsPerUser: 10}, storage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), fake, mockLogger)
+
+	return service, storage, fake
+
+}
+
+// create adds an event of user 1 on date.
+func create(service *Service, date time.Time) error {
+	_, err := service.CreateEvent(context.Background(), &models.Event{
+		Meta: models.Meta{UserID: 1, EventDate: date},
+		Data: models.Data{Text: date.Format("2006-01-02")},
+	})
+	return err
+}
+
+// eventDays returns the dates of events, oldest first.
+func eventDays(events []models.Event) []string {
+	days := make([]string, len(events))
+	for i, event := range events {
+		days[len(events)-1-i] = event.Meta.EventDate.Format("2006-01-02")
+	}
+	return days
+}
+
+// day returns midnight UTC of the given date, the form in which the API receives event dates.
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestClock_DaylightSavingTransitions(t *testing.T) {
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name    string
+		now     time.Time
+		date    time.Time
+		wantErr error
+	}{
+		// 23:30 EST on the eve of spring forward is already the 10th in UTC.
+		{"local eve of spring forward is past in UTC", time.Date(2030, 3, 9, 23, 30, 0, 0, newYork), day(2030, 3, 9), errs.ErrEventInPast},
+		{"spring forward day", time.Date(2030, 3, 9, 23, 30, 0, 0, newYork), day(2030, 3, 10), nil},
+		// 02:30 does not exist on spring forward day and normalizes to 03:30 EDT.
+		{"skipped local hour", time.Date(2030, 3, 10, 2, 30, 0, 0, newYork), day(2030, 3, 10), nil},
+		// 18:59 EDT is 22:59 UTC, one hour earlier in UTC than the same reading in winter.
+		{"summer evening is still today in UTC", time.Date(2030, 7, 1, 18, 59, 0, 0, newYork), day(2030, 7, 1), nil},
+		{"summer night is tomorrow in UTC", time.Date(2030, 7, 1, 20, 0, 0, 0, newYork), day(2030, 7, 1), errs.ErrEventInPast},
+		// 01:30 happens twice on fall back day, at 05:30 and 06:30 UTC.
+		{"repeated local hour", time.Date(2030, 11, 3, 1, 30, 0, 0, newYork), day(2030, 11, 3), nil},
+		{"day before fall back", time.Date(2030, 11, 3, 1, 30, 0, 0, newYork), day(2030, 11, 2), errs.ErrEventInPast},
+		// An event dated local midnight in New York falls on the same UTC day.
+		{"local midnight date", time.Date(2030, 11, 3, 12, 0, 0, 0, newYork), time.Date(2030, 11, 3, 0, 0, 0, 0, newYork), nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, _, _ := clockFixture(t, tc.now)
+			assert.ErrorIs(t, create(service, tc.date), tc.wantErr)
+		})
+	}
+
+}
+
+func TestClock_AdvanceAcrossDaylightSaving(t *testing.T) {
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	service, _, fake := clockFixture(t, time.Date(2030, 3, 9, 12, 0, 0, 0, newYork))
+
+	require.NoError(t, create(service, day(2030, 3, 9)))
+
+	// 24 hours later the local clock reads 13:00 instead of noon, but exactly one UTC day has passed.
+	fake.Advance(24 * time.Hour)
+	assert.Equal(t, 13, fake.Now().In(newYork).Hour())
+
+	assert.ErrorIs(t, create(service, day(2030, 3, 9)), errs.ErrEventInPast)
+	assert.NoError(t, create(service, day(2030, 3, 10)))
+
+	// On fall back day the local clock reads 01:30 twice, an hour apart, within the same UTC day.
+	fake.Set(time.Date(2030, 11, 3, 5, 30, 0, 0, time.UTC))
+	assert.Equal(t, 1, fake.Now().In(newYork).Hour())
+	fake.Advance(time.Hour)
+	assert.Equal(t, 1, fake.Now().In(newYork).Hour())
+
+	assert.NoError(t, create(service, day(2030, 11, 3)))
+	assert.ErrorIs(t, create(service, day(2030, 11, 2)), errs.ErrEventInPast)
+
+}
+
+func TestClock_ISOWeekYearRollover(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		now    time.Time
+		events []time.Time
+		target time.Time
+		year   int
+		week   int
+		want   []string
+	}{
+		{
+			name:   "week one starting in the previous year",
+			now:    day(2030, 12, 1),
+			events: []time.Time{day(2030, 12, 29), day(2030, 12, 30), day(2031, 1, 5), day(2031, 1, 6)},
+			target: day(2030, 12, 31),
+			year:   2031, week: 1,
+			want: []string{"2030-12-30", "2031-01-05"},
+		},
+		{
+			name:   "week 53 ending in the next year",
+			now:    day(2026, 12, 1),
+			events: []time.Time{day(2026, 12, 27), day(2026, 12, 28), day(2027, 1, 3), day(2027, 1, 4)},
+			target: day(2027, 1, 1),
+			year:   2026, week: 53,
+			want: []string{"2026-12-28", "2027-01-03"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			service, _, _ := clockFixture(t, tc.now)
+
+			for _, date := range tc.events {
+				require.NoError(t, create(service, date))
+			}
+
+			year, week := tc.target.ISOWeek()
+			assert.Equal(t, tc.year, year)
+			assert.Equal(t, tc.week, week)
+
+			events, err := service.GetEvents(context.Background(), &models.Meta{UserID: 1, EventDate: tc.target}, models.Week)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, eventDays(events))
+
+			from, to := periodRange(tc.target, models.Week)
+			assert.Equal(t, tc.want[0], from.Format("2006-01-02"))
+			assert.Equal(t, tc.want[1], to.Format("2006-01-02"))
+
+		})
+	}
+
+}
+
+func TestClock_LeapDay(t *testing.T) {
+
+	service, storage, fake := clockFixture(t, time.Date(2028, 2, 29, 23, 59, 59, 0, time.UTC))
+
+	require.NoError(t, create(service, day(2028, 2, 29)))
+	require.NoError(t, create(service, day(2028, 3, 1)))
+
+	// Ten years after a leap day normalizes to the 1st of March.
+	assert.NoError(t, create(service, day(2038, 3, 1)))
+	assert.ErrorIs(t, create(service, day(2038, 3, 2)), errs.ErrEventTooFar)
+
+	stats, err := storage.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Upcoming)
+
+	fake.Advance(time.Second)
+
+	assert.ErrorIs(t, create(service, day(2028, 2, 29)), errs.ErrEventInPast)
+
+	stats, err = storage.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Events)
+	assert.Equal(t, 2, stats.Upcoming)
+
+	events, err := service.GetEvents(context.Background(), &models.Meta{UserID: 1, EventDate: day(2028, 2, 15)}, models.Month)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2028-02-29"}, eventDays(events))
+
+	from, to := periodRange(day(2028, 2, 15), models.Month)
+	assert.Equal(t, day(2028, 2, 1), from)
+	assert.Equal(t, day(2028, 2, 29), to)
+
+}
@@ -3,97 +3,202 @@
 package impl
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"sync/atomic"
 
 	"L2.18/internal/config"
 	"L2.18/internal/errs"
 	"L2.18/internal/models"
 	"L2.18/internal/repository"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/egress"
+	"L2.18/pkg/holidays"
 	"L2.18/pkg/logger"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer creates the service layer spans.
+var tracer = tracing.Tracer("L2.18/internal/service/impl")
+
 // Service is the implementation of the event management service.
 // It interacts with the repository layer to perform CRUD operations on events
 // and enforces business rules such as user limits and validation checks.
 type Service struct {
-	Storage          repository.Storage // underlying storage for events
-	logger           logger.Logger      // logger for service-level logging
-	maxEventsPerUser int                // maximum number of events allowed per user
+	Storage            repository.Storage                             // underlying storage for events
+	blobs              repository.BlobStore                           // storage for the content of event attachments
+	webhooks           repository.WebhookStore                        // registered webhooks and their delivery queue
+	egress             *egress.Policy                                 // hosts webhook URLs may point to
+	calendar           *holidays.Registry                             // holiday calendars and default working week
+	clock              clock.Clock                                    // source of the current time for date checks and timestamps
+	logger             logger.Logger                                  // logger for service-level logging
+	maxEventsPerUser   atomic.Int64                                   // maximum number of events allowed per user, replaceable at runtime
+	maxWebhooksPerUser atomic.Int64                                   // maximum number of webhooks allowed per user, replaceable at runtime
+	nonWorkingDays     atomic.Pointer[string]                         // policy for events on non-working days, replaceable at runtime
+	attachments        atomic.Pointer[config.Attachments]             // attachment limits, replaceable at runtime
+	tenantLimits       atomic.Pointer[map[string]config.TenantLimits] // per-tenant limit overrides, replaceable at runtime
+}
+
+// NewService creates a new Service instance with the provided configuration, storage,
+// attachment blob store, webhook store, egress policy of webhook URLs, holiday calendars, clock, and logger. The maxEventsPerUser field and its per-tenant overrides
+// are set from the configuration and enforce limits on event creation.
+func NewService(config config.Service, storage repository.Storage, blobs repository.BlobStore, webhooks repository.WebhookStore, egress *egress.Policy, calendar *holidays.Registry, clock clock.Clock, logger logger.Logger) *Service {
+	service := &Service{Storage: storage, blobs: blobs, webhooks: webhooks, egress: egress, calendar: calendar, clock: clock, logger: logger}
+	service.Reconfigure(config)
+	return service
 }
 
-// NewService creates a new Service instance with the provided configuration, storage, and logger.
-// The maxEventsPerUser field is set from the configuration and enforces limits on event creation.
-func NewService(config config.Service, storage repository.Storage, logger logger.Logger) *Service {
-	return &Service{Storage: storage, logger: logger, maxEventsPerUser: config.MaxEventsPerUser}
+// Reconfigure atomically applies new service limits and policies. Requests already in
+// progress keep the settings they started with; subsequent requests observe the new ones.
+func (s *Service) Reconfigure(config config.Service) {
+	s.maxEventsPerUser.Store(int64(config.MaxEventsPerUser))
+	s.maxWebhooksPerUser.Store(int64(config.MaxWebhooksPerUser))
+	s.nonWorkingDays.Store(&config.NonWorkingDays)
+	s.attachments.Store(&config.Attachments)
+	s.tenantLimits.Store(&config.Tenants)
+}
+
+// maxEvents returns the maximum number of events per user of the tenant in ctx:
+// the tenant's own limit if it has one, the service-wide limit otherwise.
+func (s *Service) maxEvents(ctx context.Context) int {
+	if limits := (*s.tenantLimits.Load())[tenant.ID(ctx)]; limits.MaxEventsPerUser > 0 {
+		return limits.MaxEventsPerUser
+	}
+	return int(s.maxEventsPerUser.Load())
 }
 
 // CreateEvent validates and creates a new event for a user.
 // Returns the generated event ID on success, or an error if creation fails.
-// It enforces per-user limits and validates the event data before calling the repository.
-func (s *Service) CreateEvent(event *models.Event) (string, error) {
+// It enforces per-user limits and validates the event data before calling the repository,
+// and notifies the user's webhooks once the event is stored.
+func (s *Service) CreateEvent(ctx context.Context, event *models.Event) (eventID string, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.CreateEvent", trace.WithAttributes(attribute.Int("user.id", event.Meta.UserID)))
+	defer func() { tracing.End(span, err) }()
 
-	if err := validateCreate(event); err != nil {
+	if err := validateCreate(event, s.clock.Now()); err != nil {
 		return "", err
 	}
 
-	count, err := s.Storage.CountUserEvents(event.Meta.UserID)
+	count, err := s.Storage.CountUserEvents(ctx, event.Meta.UserID)
 	if err != nil {
 		return "", err
 	}
 
-	s.logger.Debug(fmt.Sprintf("service — user %d has %d remaining event slots", event.Meta.UserID, s.maxEventsPerUser-count), "UserID", event.Meta.UserID, "layer", "service.impl")
+	maxEventsPerUser := s.maxEvents(ctx)
 
-	if count >= s.maxEventsPerUser {
+	s.logger.Debug(fmt.Sprintf("service — user %d has %d remaining event slots", event.Meta.UserID, maxEventsPerUser-count), "UserID", event.Meta.UserID, "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+
+	if count >= maxEventsPerUser {
 		return "", errs.ErrMaxEvents
 	}
 
-	return s.Storage.CreateEvent(event)
+	if err := s.checkWorkingDay(ctx, &event.Meta, event.Meta.EventDate); err != nil {
+		return "", err
+	}
+
+	eventID, err = s.Storage.CreateEvent(ctx, event)
+	if err != nil {
+		return "", err
+	}
+
+	created := *event
+	created.Meta.EventID = eventID
+
+	s.notify(ctx, eventCreated, created)
+
+	return eventID, nil
 
 }
 
-// UpdateEvent validates and updates an existing event.
+// UpdateEvent validates and updates an existing event, then notifies the user's webhooks.
 // Returns an error if validation fails, the event does not exist, or the update cannot be applied.
-func (s *Service) UpdateEvent(event *models.Event) error {
+func (s *Service) UpdateEvent(ctx context.Context, event *models.Event) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.UpdateEvent", trace.WithAttributes(attribute.Int("user.id", event.Meta.UserID), attribute.String("event.id", event.Meta.EventID)))
+	defer func() { tracing.End(span, err) }()
 
 	if err := validateIDs(event.Meta.UserID, event.Meta.EventID); err != nil {
 		return err
 	}
 
-	if err := validateUpdate(event, s.Storage.GetEventByID(event.Meta.EventID)); err != nil {
+	current := s.Storage.GetEventByID(ctx, event.Meta.EventID)
+	if err := validateUpdate(event, current, s.clock.Now()); err != nil {
 		return err
 	}
 
-	return s.Storage.UpdateEvent(event)
+	if !event.Meta.NewDate.IsZero() {
+		if err := s.checkWorkingDay(ctx, &event.Meta, event.Meta.NewDate); err != nil {
+			return err
+		}
+	}
+
+	updated := *current
+
+	if err := s.Storage.UpdateEvent(ctx, event); err != nil {
+		return err
+	}
+
+	if event.Data.Text != "" {
+		updated.Data = event.Data
+	}
+	if !event.Meta.NewDate.IsZero() {
+		updated.Meta.EventDate = event.Meta.NewDate
+	}
+
+	s.notify(ctx, eventUpdated, updated)
+
+	return nil
 
 }
 
-// DeleteEvent validates and deletes an event identified by the provided metadata.
-// Returns an error if validation fails or the event cannot be deleted.
-func (s *Service) DeleteEvent(meta *models.Meta) error {
+// DeleteEvent validates and deletes an event identified by the provided metadata,
+// removing the files attached to it and notifying the user's webhooks. Returns an error
+// if validation fails or the event cannot be deleted.
+func (s *Service) DeleteEvent(ctx context.Context, meta *models.Meta) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.DeleteEvent", trace.WithAttributes(attribute.Int("user.id", meta.UserID), attribute.String("event.id", meta.EventID)))
+	defer func() { tracing.End(span, err) }()
 
 	if err := validateIDs(meta.UserID, meta.EventID); err != nil {
 		return err
 	}
 
-	if err := validateDelete(meta, s.Storage.GetEventByID(meta.EventID)); err != nil {
+	current := s.Storage.GetEventByID(ctx, meta.EventID)
+	if err := validateDelete(meta, current); err != nil {
 		return err
 	}
 
-	return s.Storage.DeleteEvent(meta)
+	deleted := *current
+
+	if err := s.Storage.DeleteEvent(ctx, meta); err != nil {
+		return err
+	}
+
+	s.removeBlobs(ctx, attachmentKey(ctx, meta.UserID, meta.EventID))
+	s.notify(ctx, eventDeleted, deleted)
+
+	return nil
 
 }
 
 // GetEvents retrieves all events for a user within the specified period (day, week, month).
 // Events are returned in descending order by date. Returns an error if validation fails
 // or if the repository fails to fetch events.
-func (s *Service) GetEvents(meta *models.Meta, period models.Period) ([]models.Event, error) {
+func (s *Service) GetEvents(ctx context.Context, meta *models.Meta, period models.Period) (events []models.Event, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.GetEvents", trace.WithAttributes(attribute.Int("user.id", meta.UserID), attribute.String("period", string(period))))
+	defer func() { tracing.End(span, err) }()
 
 	if err := validateGet(meta); err != nil {
 		return nil, err
 	}
 
-	events, err := s.Storage.GetEvents(meta, period)
+	events, err = s.Storage.GetEvents(ctx, meta, period)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +207,8 @@ func (s *Service) GetEvents(meta *models.Meta, period models.Period) ([]models.E
 		return events[i].Meta.EventDate.After(events[j].Meta.EventDate)
 	})
 
+	s.annotate(ctx, meta.UserID, events)
+
 	return events, nil
 
 }
@@ -1,15 +1,18 @@
 package impl
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"L2.18/internal/config"
 	"L2.18/internal/errs"
+	"L2.18/pkg/clock"
 
 	"L2.18/internal/models"
 	storageMock "L2.18/internal/repository/mocks"
 	loggerMock "L2.18/pkg/logger/mocks"
+	"L2.18/pkg/tenant"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -24,7 +27,7 @@ func TestCreateEvent_Success(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	now := time.Now().UTC().Add(24 * time.Hour)
 	event := &models.Event{
@@ -32,11 +35,11 @@ func TestCreateEvent_Success(t *testing.T) {
 		Data: models.Data{Text: "ok"},
 	}
 
-	mockLogger.EXPECT().Debug("service — user 1 has 5 remaining event slots", "UserID", event.Meta.UserID, "layer", "service.impl")
-	mockStorage.EXPECT().CountUserEvents(event.Meta.UserID).Return(0, nil)
-	mockStorage.EXPECT().CreateEvent(event).Return("result id", nil)
+	mockLogger.EXPECT().Debug("service — user 1 has 5 remaining event slots", "UserID", event.Meta.UserID, "request_id", "", "layer", "service.impl")
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), event.Meta.UserID).Return(0, nil)
+	mockStorage.EXPECT().CreateEvent(gomock.Any(), event).Return("result id", nil)
 
-	id, err := service.CreateEvent(event)
+	id, err := service.CreateEvent(context.Background(), event)
 	assert.NoError(t, err)
 	assert.Equal(t, "result id", id)
 
@@ -50,14 +53,14 @@ func TestCreateEvent_ErrValidateCreate(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	event := &models.Event{
 		Meta: models.Meta{UserID: 0, EventDate: time.Now().Add(24 * time.Hour)},
 		Data: models.Data{Text: "ok"},
 	}
 
-	id, err := service.CreateEvent(event)
+	id, err := service.CreateEvent(context.Background(), event)
 	assert.ErrorIs(t, err, errs.ErrInvalidUserID)
 	assert.Equal(t, "", id)
 
@@ -71,7 +74,7 @@ func TestCreateEvent_ErrCountUserEvents(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 	now := time.Now().Add(24 * time.Hour)
 
 	event := &models.Event{
@@ -79,9 +82,9 @@ func TestCreateEvent_ErrCountUserEvents(t *testing.T) {
 		Data: models.Data{Text: "ok"},
 	}
 
-	mockStorage.EXPECT().CountUserEvents(event.Meta.UserID).Return(0, assert.AnError)
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), event.Meta.UserID).Return(0, assert.AnError)
 
-	id, err := service.CreateEvent(event)
+	id, err := service.CreateEvent(context.Background(), event)
 	assert.Equal(t, "", id)
 	assert.ErrorIs(t, err, assert.AnError)
 
@@ -95,7 +98,7 @@ func TestCreateEvent_ErrMaxEvents(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 	now := time.Now().Add(24 * time.Hour)
 
 	event := &models.Event{
@@ -103,15 +106,72 @@ func TestCreateEvent_ErrMaxEvents(t *testing.T) {
 		Data: models.Data{Text: "ok"},
 	}
 
-	mockStorage.EXPECT().CountUserEvents(event.Meta.UserID).Return(5, nil)
-	mockLogger.EXPECT().Debug("service — user 1 has 0 remaining event slots", "UserID", event.Meta.UserID, "layer", "service.impl")
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), event.Meta.UserID).Return(5, nil)
+	mockLogger.EXPECT().Debug("service — user 1 has 0 remaining event slots", "UserID", event.Meta.UserID, "request_id", "", "layer", "service.impl")
 
-	id, err := service.CreateEvent(event)
+	id, err := service.CreateEvent(context.Background(), event)
 	assert.Equal(t, "", id)
 	assert.ErrorIs(t, err, errs.ErrMaxEvents)
 
 }
 
+func TestCreateEvent_Reconfigure(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStorage := storageMock.NewMockStorage(controller)
+
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
+	service.Reconfigure(config.Service{MaxEventsPerUser: 2})
+
+	event := &models.Event{
+		Meta: models.Meta{UserID: 1, EventDate: time.Now().Add(24 * time.Hour)},
+		Data: models.Data{Text: "ok"},
+	}
+
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), event.Meta.UserID).Return(2, nil)
+	mockLogger.EXPECT().Debug("service — user 1 has 0 remaining event slots", "UserID", event.Meta.UserID, "request_id", "", "layer", "service.impl")
+
+	id, err := service.CreateEvent(context.Background(), event)
+	assert.Equal(t, "", id)
+	assert.ErrorIs(t, err, errs.ErrMaxEvents)
+
+}
+
+func TestCreateEvent_TenantLimits(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStorage := storageMock.NewMockStorage(controller)
+
+	service := NewService(config.Service{
+		MaxEventsPerUser: 5,
+		Tenants:          map[string]config.TenantLimits{"team-a": {MaxEventsPerUser: 2}, "team-b": {}},
+	}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
+
+	event := &models.Event{
+		Meta: models.Meta{UserID: 1, EventDate: time.Now().Add(24 * time.Hour)},
+		Data: models.Data{Text: "ok"},
+	}
+
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), event.Meta.UserID).Return(2, nil).Times(2)
+	mockStorage.EXPECT().CreateEvent(gomock.Any(), event).Return("id", nil).Times(1)
+	mockLogger.EXPECT().Debug("service — user 1 has 0 remaining event slots", "UserID", event.Meta.UserID, "request_id", "", "layer", "service.impl").Times(1)
+	mockLogger.EXPECT().Debug("service — user 1 has 3 remaining event slots", "UserID", event.Meta.UserID, "request_id", "", "layer", "service.impl").Times(1)
+
+	_, err := service.CreateEvent(tenant.WithID(context.Background(), "team-a"), event)
+	assert.ErrorIs(t, err, errs.ErrMaxEvents)
+
+	id, err := service.CreateEvent(tenant.WithID(context.Background(), "team-b"), event)
+	assert.NoError(t, err)
+	assert.Equal(t, "id", id)
+
+}
+
 func TestUpdateEvent_Success(t *testing.T) {
 
 	controller := gomock.NewController(t)
@@ -120,7 +180,7 @@ func TestUpdateEvent_Success(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	now := time.Now().UTC().Add(24 * time.Hour)
 	eventID := uuid.New().String()
@@ -135,10 +195,10 @@ func TestUpdateEvent_Success(t *testing.T) {
 		Data: models.Data{Text: "new"},
 	}
 
-	mockStorage.EXPECT().GetEventByID(eventID).Return(oldEvent)
-	mockStorage.EXPECT().UpdateEvent(event).Return(nil)
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), eventID).Return(oldEvent)
+	mockStorage.EXPECT().UpdateEvent(gomock.Any(), event).Return(nil)
 
-	assert.NoError(t, service.UpdateEvent(event))
+	assert.NoError(t, service.UpdateEvent(context.Background(), event))
 
 }
 
@@ -150,13 +210,13 @@ func TestUpdateEvent_ErrInvalidUserID(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	event := &models.Event{
 		Meta: models.Meta{UserID: 0, EventID: uuid.New().String()},
 	}
 
-	assert.ErrorIs(t, service.UpdateEvent(event), errs.ErrInvalidUserID)
+	assert.ErrorIs(t, service.UpdateEvent(context.Background(), event), errs.ErrInvalidUserID)
 
 }
 
@@ -168,16 +228,16 @@ func TestUpdateEvent_ErrEventNotFound(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 	eventID := uuid.New().String()
 
 	event := &models.Event{
 		Meta: models.Meta{UserID: 1, EventID: eventID},
 	}
 
-	mockStorage.EXPECT().GetEventByID(eventID).Return((*models.Event)(nil))
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), eventID).Return((*models.Event)(nil))
 
-	assert.ErrorIs(t, service.UpdateEvent(event), errs.ErrEventNotFound)
+	assert.ErrorIs(t, service.UpdateEvent(context.Background(), event), errs.ErrEventNotFound)
 
 }
 
@@ -189,7 +249,7 @@ func TestUpdateEvent_ErrUnauthorized(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	now := time.Now().UTC().Add(24 * time.Hour)
 	eventID := uuid.New().String()
@@ -204,9 +264,9 @@ func TestUpdateEvent_ErrUnauthorized(t *testing.T) {
 		Data: models.Data{Text: "new"},
 	}
 
-	mockStorage.EXPECT().GetEventByID(eventID).Return(oldEvent)
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), eventID).Return(oldEvent)
 
-	assert.ErrorIs(t, service.UpdateEvent(event), errs.ErrUnauthorized)
+	assert.ErrorIs(t, service.UpdateEvent(context.Background(), event), errs.ErrUnauthorized)
 
 }
 
@@ -218,7 +278,7 @@ func TestUpdateEvent_ErrNothingToUpdate(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	now := time.Now().UTC().Add(24 * time.Hour)
 	eventID := uuid.New().String()
@@ -233,9 +293,9 @@ func TestUpdateEvent_ErrNothingToUpdate(t *testing.T) {
 		Data: models.Data{Text: "same"},
 	}
 
-	mockStorage.EXPECT().GetEventByID(eventID).Return(oldEvent)
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), eventID).Return(oldEvent)
 
-	assert.ErrorIs(t, service.UpdateEvent(event), errs.ErrNothingToUpdate)
+	assert.ErrorIs(t, service.UpdateEvent(context.Background(), event), errs.ErrNothingToUpdate)
 
 }
 
@@ -246,8 +306,9 @@ func TestDeleteEvent_Success(t *testing.T) {
 
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
+	mockBlobs := storageMock.NewMockBlobStore(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockBlobs, noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	now := time.Now().UTC().Add(24 * time.Hour)
 	meta := &models.Meta{UserID: 1, EventID: uuid.New().String()}
@@ -257,10 +318,11 @@ func TestDeleteEvent_Success(t *testing.T) {
 		Data: models.Data{Text: "ok"},
 	}
 
-	mockStorage.EXPECT().GetEventByID(meta.EventID).Return(oldEvent)
-	mockStorage.EXPECT().DeleteEvent(meta).Return(nil)
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), meta.EventID).Return(oldEvent)
+	mockStorage.EXPECT().DeleteEvent(gomock.Any(), meta).Return(nil)
+	mockBlobs.EXPECT().Delete(gomock.Any(), "default/1/"+meta.EventID).Return(nil)
 
-	assert.NoError(t, service.DeleteEvent(meta))
+	assert.NoError(t, service.DeleteEvent(context.Background(), meta))
 
 }
 
@@ -272,11 +334,11 @@ func TestDeleteEvent_ErrInvalidUserID(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	meta := &models.Meta{UserID: 0, EventID: uuid.New().String()}
 
-	assert.ErrorIs(t, service.DeleteEvent(meta), errs.ErrInvalidUserID)
+	assert.ErrorIs(t, service.DeleteEvent(context.Background(), meta), errs.ErrInvalidUserID)
 
 }
 
@@ -288,13 +350,13 @@ func TestDeleteEvent_ErrEventNotFound(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	meta := &models.Meta{UserID: 1, EventID: uuid.New().String()}
 
-	mockStorage.EXPECT().GetEventByID(meta.EventID).Return((*models.Event)(nil))
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), meta.EventID).Return((*models.Event)(nil))
 
-	assert.ErrorIs(t, service.DeleteEvent(meta), errs.ErrEventNotFound)
+	assert.ErrorIs(t, service.DeleteEvent(context.Background(), meta), errs.ErrEventNotFound)
 
 }
 
@@ -306,14 +368,14 @@ func TestDeleteEvent_ErrUnauthorized(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	meta := &models.Meta{UserID: 1, EventID: uuid.New().String()}
 	oldEvent := &models.Event{Meta: models.Meta{UserID: 2, EventID: meta.EventID}}
 
-	mockStorage.EXPECT().GetEventByID(meta.EventID).Return(oldEvent)
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), meta.EventID).Return(oldEvent)
 
-	assert.ErrorIs(t, service.DeleteEvent(meta), errs.ErrUnauthorized)
+	assert.ErrorIs(t, service.DeleteEvent(context.Background(), meta), errs.ErrUnauthorized)
 
 }
 
@@ -325,7 +387,7 @@ func TestGetEvents_SuccessSorted(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	meta := &models.Meta{UserID: 1, EventDate: time.Now().UTC().Add(24 * time.Hour)}
 	soon := models.Event{Meta: models.Meta{UserID: 1, EventDate: meta.EventDate.Add(24 * time.Hour)}, Data: models.Data{Text: "soon"}}
@@ -334,9 +396,10 @@ func TestGetEvents_SuccessSorted(t *testing.T) {
 
 	unsorted := []models.Event{soon, later, earlier}
 
-	mockStorage.EXPECT().GetEvents(meta, models.Day).Return(unsorted, nil)
+	mockStorage.EXPECT().GetEvents(gomock.Any(), meta, models.Day).Return(unsorted, nil)
+	mockStorage.EXPECT().GetSettings(gomock.Any(), 1).Return(nil)
 
-	events, err := service.GetEvents(meta, models.Day)
+	events, err := service.GetEvents(context.Background(), meta, models.Day)
 	assert.NoError(t, err)
 
 	if assert.Len(t, events, 3) {
@@ -354,11 +417,11 @@ func TestGetEvents_ErrInvalidUserID(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	meta := &models.Meta{UserID: 0}
 
-	_, err := service.GetEvents(meta, models.Day)
+	_, err := service.GetEvents(context.Background(), meta, models.Day)
 	assert.ErrorIs(t, err, errs.ErrInvalidUserID)
 
 }
@@ -371,12 +434,12 @@ func TestGetEvents_ErrStorage(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 	meta := &models.Meta{UserID: 1, EventDate: time.Now().UTC().Add(24 * time.Hour)}
 
-	mockStorage.EXPECT().GetEvents(meta, models.Day).Return(nil, assert.AnError)
+	mockStorage.EXPECT().GetEvents(gomock.Any(), meta, models.Day).Return(nil, assert.AnError)
 
-	events, err := service.GetEvents(meta, models.Day)
+	events, err := service.GetEvents(context.Background(), meta, models.Day)
 	assert.Nil(t, events)
 	assert.ErrorIs(t, err, assert.AnError)
 
@@ -390,14 +453,14 @@ func TestGetEvents_ErrMissingDate(t *testing.T) {
 	mockLogger := loggerMock.NewMockLogger(controller)
 	mockStorage := storageMock.NewMockStorage(controller)
 
-	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, mockLogger)
+	service := NewService(config.Service{MaxEventsPerUser: 5}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.System(), mockLogger)
 
 	meta := &models.Meta{
 		UserID:    1,
 		EventDate: time.Time{},
 	}
 
-	_, err := service.GetEvents(meta, models.Day)
+	_, err := service.GetEvents(context.Background(), meta, models.Day)
 	assert.ErrorIs(t, err, errs.ErrMissingDate)
 
 }
@@ -0,0 +1,99 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/pkg/quickadd"
+	"L2.18/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// upcomingOccurrences is the number of dates of a recurring event returned with its interpretation.
+const upcomingOccurrences = 5
+
+// QuickAdd reads a free-form phrase as an event of the user and, if create is set, creates it.
+//
+// Relative dates are resolved against the calendar day of reference in its own location, so
+// clients can pass their local time; a zero reference means the current time of the service
+// clock in UTC. The interpretation
+// is validated like a regular event either way, so a preview already reports dates in the
+// past or on non-working days. Storage has no notion of recurring events: for a recurring
+// phrase the first occurrence is created and the recurrence is returned with its upcoming
+// dates for the client to schedule.
+func (s *Service) QuickAdd(ctx context.Context, userID int, phrase string, reference time.Time, create bool) (result models.QuickAdd, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.QuickAdd", trace.WithAttributes(attribute.Int("user.id", userID), attribute.Bool("quickadd.create", create)))
+	defer func() { tracing.End(span, err) }()
+
+	if reference.IsZero() {
+		reference = s.clock.Now().UTC()
+	}
+
+	interpretation, err := quickadd.Parse(phrase, reference)
+	if err != nil {
+		return models.QuickAdd{}, quickAddError(err)
+	}
+
+	result = models.QuickAdd{
+		Event: models.Event{
+			Meta: models.Meta{UserID: userID, EventDate: interpretation.Date},
+			Data: models.Data{Text: interpretation.Text},
+		},
+		Occurrences: []time.Time{interpretation.Date},
+		Language:    interpretation.Language,
+		DatePhrase:  interpretation.DatePhrase,
+	}
+
+	if r := interpretation.Recurrence; r != nil {
+		result.Recurrence = &models.Recurrence{Frequency: models.Frequency(r.Frequency), Weekday: r.Weekday}
+		result.Occurrences = r.Dates(interpretation.Date, upcomingOccurrences)
+	}
+
+	s.logger.Debug("service — quick-add phrase interpreted", "UserID", userID, "date", interpretation.Date.Format("2006-01-02"), "language", interpretation.Language, "create", create, "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+
+	if !create {
+
+		if err := validateCreate(&result.Event, s.clock.Now()); err != nil {
+			return models.QuickAdd{}, err
+		}
+
+		if err := s.checkWorkingDay(ctx, &result.Event.Meta, interpretation.Date); err != nil {
+			return models.QuickAdd{}, err
+		}
+
+		return result, nil
+
+	}
+
+	eventID, err := s.CreateEvent(ctx, &result.Event)
+	if err != nil {
+		return models.QuickAdd{}, err
+	}
+
+	result.Event.Meta.EventID = eventID
+	result.Created = true
+
+	return result, nil
+
+}
+
+// quickAddError translates a phrase parsing error into the matching application error.
+func quickAddError(err error) error {
+	switch {
+	case errors.Is(err, quickadd.ErrEmpty):
+		return errs.ErrEmptyPhrase
+	case errors.Is(err, quickadd.ErrNoDate):
+		return errs.ErrNoDateInPhrase
+	case errors.Is(err, quickadd.ErrManyDates):
+		return errs.ErrManyDatesInPhrase
+	case errors.Is(err, quickadd.ErrNoSuchDate):
+		return errs.ErrNoSuchDate
+	default:
+		return err
+	}
+}
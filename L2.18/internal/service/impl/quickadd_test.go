@@ -0,0 +1,112 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	storageMock "L2.18/internal/repository/mocks"
+	"L2.18/pkg/clock"
+	loggerMock "L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reference is the reference time of the quick-add tests, a Monday two days before Christmas.
+var reference = time.Date(2030, 12, 23, 9, 0, 0, 0, time.UTC)
+
+// quickAddFixture wires a service warning about events on non-working days.
+func quickAddFixture(t *testing.T) (*Service, *storageMock.MockStorage) {
+
+	controller := gomock.NewController(t)
+	t.Cleanup(controller.Finish)
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStorage := storageMock.NewMockStorage(controller)
+
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+	service := NewService(config.Service{MaxEventsPerUser: 5, NonWorkingDays: policyWarn}, mockStorage, storageMock.NewMockBlobStore(controller), noWebhooks(controller), nil, testCalendar(t), clock.NewFake(reference), mockLogger)
+
+	return service, mockStorage
+
+}
+
+func TestQuickAdd_Preview(t *testing.T) {
+
+	service, mockStorage := quickAddFixture(t)
+
+	mockStorage.EXPECT().GetSettings(gomock.Any(), 1).Return(weekdays)
+
+	result, err := service.QuickAdd(context.Background(), 1, "party on 25 december", reference, false)
+	require.NoError(t, err)
+
+	christmas := time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	assert.False(t, result.Created)
+	assert.Empty(t, result.Event.Meta.EventID)
+	assert.Equal(t, christmas, result.Event.Meta.EventDate)
+	assert.Equal(t, "party", result.Event.Data.Text)
+	assert.Equal(t, "event date falls on a non-working day: 2030-12-25 (Christmas Day)", result.Event.Meta.Warning)
+	assert.Nil(t, result.Recurrence)
+	assert.Equal(t, []time.Time{christmas}, result.Occurrences)
+	assert.Equal(t, "en", result.Language)
+	assert.Equal(t, "on 25 december", result.DatePhrase)
+
+}
+
+func TestQuickAdd_CreateRecurring(t *testing.T) {
+
+	service, mockStorage := quickAddFixture(t)
+
+	monday := time.Date(2030, 12, 23, 0, 0, 0, 0, time.UTC)
+
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), 1).Return(0, nil)
+	mockStorage.EXPECT().GetSettings(gomock.Any(), 1).Return(weekdays)
+	mockStorage.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *models.Event) (string, error) {
+		assert.Equal(t, monday, event.Meta.EventDate)
+		assert.Equal(t, "standup", event.Data.Text)
+		return "event-id", nil
+	})
+
+	result, err := service.QuickAdd(context.Background(), 1, "каждый понедельник standup", reference, true)
+	require.NoError(t, err)
+
+	assert.True(t, result.Created)
+	assert.Equal(t, "event-id", result.Event.Meta.EventID)
+	assert.Equal(t, &models.Recurrence{Frequency: models.Weekly, Weekday: time.Monday}, result.Recurrence)
+	assert.Equal(t, "ru", result.Language)
+	require.Len(t, result.Occurrences, upcomingOccurrences)
+	assert.Equal(t, monday, result.Occurrences[0])
+	assert.Equal(t, time.Date(2031, 1, 20, 0, 0, 0, 0, time.UTC), result.Occurrences[4])
+
+}
+
+func TestQuickAdd_Errors(t *testing.T) {
+
+	service, _ := quickAddFixture(t)
+
+	cases := []struct {
+		userID  int
+		phrase  string
+		wantErr error
+	}{
+		{1, "  ", errs.ErrEmptyPhrase},
+		{1, "call mom", errs.ErrNoDateInPhrase},
+		{1, "call mom tomorrow or on friday", errs.ErrManyDatesInPhrase},
+		{1, "дедлайн 31.02", errs.ErrNoSuchDate},
+		{1, "dentist 2030-12-22", errs.ErrEventInPast},
+		{0, "dentist tomorrow", errs.ErrInvalidUserID},
+	}
+
+	for _, tc := range cases {
+		_, err := service.QuickAdd(context.Background(), tc.userID, tc.phrase, reference, false)
+		assert.ErrorIs(t, err, tc.wantErr, tc.phrase)
+	}
+
+}
@@ -10,15 +10,15 @@ import (
 )
 
 // validateCreate performs validation on a new event before creation.
-// It checks that the user ID is valid, the event date is acceptable,
+// It checks that the user ID is valid, the event date is acceptable as of now,
 // and the event data meets length constraints.
-func validateCreate(event *models.Event) error {
+func validateCreate(event *models.Event, now time.Time) error {
 
 	if event.Meta.UserID <= 0 {
 		return errs.ErrInvalidUserID
 	}
 
-	if err := validateDate(event.Meta.EventDate); err != nil {
+	if err := validateDate(event.Meta.EventDate, now); err != nil {
 		return err
 	}
 
@@ -32,8 +32,8 @@ func validateCreate(event *models.Event) error {
 
 // validateUpdate checks whether an update to an existing event is valid.
 // It ensures the event exists, belongs to the user, and that the update actually
-// changes either the date or the text. It also validates any new date or text.
-func validateUpdate(event *models.Event, oldEvent *models.Event) error {
+// changes either the date or the text. It also validates any new date, as of now, or text.
+func validateUpdate(event *models.Event, oldEvent *models.Event, now time.Time) error {
 
 	if oldEvent == nil {
 		return errs.ErrEventNotFound
@@ -48,7 +48,7 @@ func validateUpdate(event *models.Event, oldEvent *models.Event) error {
 	}
 
 	if !event.Meta.NewDate.IsZero() {
-		if err := validateDate(event.Meta.NewDate); err != nil {
+		if err := validateDate(event.Meta.NewDate, now); err != nil {
 			return err
 		}
 	}
@@ -106,12 +106,12 @@ func validateGet(meta *models.Meta) error {
 
 }
 
-// validateDate ensures the event date is not in the past and not more than 10 years ahead.
-// Dates are compared in UTC to prevent timezone-related errors.
-func validateDate(date time.Time) error {
+// validateDate ensures the event date is not in the past and not more than 10 years ahead
+// of now. Dates are compared in UTC to prevent timezone-related errors.
+func validateDate(date time.Time, now time.Time) error {
 
 	eventUTC := date.UTC().Truncate(24 * time.Hour)
-	todayUTC := time.Now().UTC().Truncate(24 * time.Hour)
+	todayUTC := now.UTC().Truncate(24 * time.Hour)
 
 	if eventUTC.Before(todayUTC) {
 		return fmt.Errorf("%w: %s", errs.ErrEventInPast, eventUTC.Format("2006-01-02"))
@@ -13,19 +13,19 @@ import (
 
 func TestValidateDate_Past(t *testing.T) {
 	past := time.Now().UTC().AddDate(0, 0, -1)
-	err := validateDate(past)
+	err := validateDate(past, time.Now())
 	assert.ErrorIs(t, err, errs.ErrEventInPast)
 }
 
 func TestValidateDate_TooFar(t *testing.T) {
 	tooFar := time.Now().UTC().AddDate(11, 0, 0)
-	err := validateDate(tooFar)
+	err := validateDate(tooFar, time.Now())
 	assert.True(t, errors.Is(err, errs.ErrEventTooFar))
 }
 
 func TestValidateDate_Success(t *testing.T) {
 	ok := time.Now().UTC().AddDate(1, 0, 0)
-	err := validateDate(ok)
+	err := validateDate(ok, time.Now())
 	assert.NoError(t, err)
 }
 
@@ -61,7 +61,7 @@ func TestValidateCreate_InvalidUserID(t *testing.T) {
 		Meta: models.Meta{UserID: 0, EventDate: time.Now().Add(24 * time.Hour)},
 		Data: models.Data{Text: "qwe"},
 	}
-	err := validateCreate(event)
+	err := validateCreate(event, time.Now())
 	assert.ErrorIs(t, err, errs.ErrInvalidUserID)
 }
 
@@ -70,7 +70,7 @@ func TestValidateCreate_InvalidDate(t *testing.T) {
 		Meta: models.Meta{UserID: 1, EventDate: time.Now().AddDate(-1, 0, 0)},
 		Data: models.Data{Text: "qwe"},
 	}
-	err := validateCreate(event)
+	err := validateCreate(event, time.Now())
 	assert.True(t, errors.Is(err, errs.ErrEventInPast))
 }
 
@@ -79,7 +79,7 @@ func TestValidateCreate_InvalidData(t *testing.T) {
 		Meta: models.Meta{UserID: 1, EventDate: time.Now().Add(24 * time.Hour)},
 		Data: models.Data{Text: string(make([]byte, 501))},
 	}
-	err := validateCreate(event)
+	err := validateCreate(event, time.Now())
 	assert.ErrorIs(t, err, errs.ErrEventTextTooLong)
 }
 
@@ -97,7 +97,7 @@ func TestValidateUpdate_ErrInvalidNewDate(t *testing.T) {
 		Data: models.Data{Text: "ok"},
 	}
 
-	err := validateUpdate(event, oldEvent)
+	err := validateUpdate(event, oldEvent, time.Now())
 	assert.True(t, errors.Is(err, errs.ErrEventInPast))
 
 }
@@ -116,7 +116,7 @@ func TestValidateUpdate_ErrInvalidData(t *testing.T) {
 		Data: models.Data{Text: string(make([]byte, 501))},
 	}
 
-	err := validateUpdate(event, oldEvent)
+	err := validateUpdate(event, oldEvent, time.Now())
 	assert.ErrorIs(t, err, errs.ErrEventTextTooLong)
 
 }
@@ -0,0 +1,266 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"time"
+
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Kinds of event changes reported to webhooks.
+const (
+	eventCreated = "event.created" // an event was created
+	eventUpdated = "event.updated" // the text or date of an event changed
+	eventDeleted = "event.deleted" // an event was deleted
+)
+
+// maxURLLength is the maximum length of a webhook URL.
+const maxURLLength = 2048
+
+// notification is the JSON body posted to a webhook.
+type notification struct {
+	ID        string            `json:"id"`         // delivery ID, identical across retries so receivers can deduplicate
+	Type      string            `json:"type"`       // kind of change, e.g. event.created
+	Tenant    string            `json:"tenant"`     // tenant of the user
+	CreatedAt string            `json:"created_at"` // time of the change in RFC 3339 format
+	Event     notificationEvent `json:"event"`      // the event after the change, or before it for deletions
+}
+
+// notificationEvent describes the changed event in a notification.
+type notificationEvent struct {
+	UserID  int    `json:"user_id"`  // ID of the user who owns the event
+	EventID string `json:"event_id"` // unique identifier of the event
+	Date    string `json:"date"`     // event date in YYYY-MM-DD format
+	Text    string `json:"text"`     // event text
+}
+
+// CreateWebhook registers a webhook notified about changes of the user's events.
+//
+// On success the webhook ID, signing secret and registration time are filled in. The
+// secret is generated here and is the only copy the caller gets to see.
+func (s *Service) CreateWebhook(ctx context.Context, webhook *models.Webhook) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.CreateWebhook", trace.WithAttributes(attribute.Int("user.id", webhook.UserID)))
+	defer func() { tracing.End(span, err) }()
+
+	if webhook.UserID <= 0 {
+		return errs.ErrInvalidUserID
+	}
+
+	if err := s.validateWebhookURL(webhook.URL); err != nil {
+		return err
+	}
+
+	if len(s.webhooks.GetWebhooks(ctx, webhook.UserID)) >= int(s.maxWebhooksPerUser.Load()) {
+		return errs.ErrMaxWebhooks
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+
+	webhook.WebhookID = uuid.New().String()
+	webhook.Secret = hex.EncodeToString(secret)
+	webhook.CreatedAt = s.clock.Now().UTC()
+
+	return s.webhooks.SaveWebhook(ctx, webhook)
+
+}
+
+// GetWebhooks returns the webhooks of the user, in registration order.
+func (s *Service) GetWebhooks(ctx context.Context, userID int) (webhooks []models.Webhook, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.GetWebhooks", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	if userID <= 0 {
+		return nil, errs.ErrInvalidUserID
+	}
+
+	return s.webhooks.GetWebhooks(ctx, userID), nil
+
+}
+
+// DeleteWebhook removes a webhook of the user, dropping its pending deliveries and delivery log.
+func (s *Service) DeleteWebhook(ctx context.Context, userID int, webhookID string) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.DeleteWebhook", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("webhook.id", webhookID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.findWebhook(ctx, userID, webhookID); err != nil {
+		return err
+	}
+
+	return s.webhooks.DeleteWebhook(ctx, userID, webhookID)
+
+}
+
+// GetDeliveries returns the delivery log of a webhook of the user, newest first. If status
+// is not empty, only deliveries in that state are returned; models.DeliveryDead lists the
+// dead-letter deliveries.
+func (s *Service) GetDeliveries(ctx context.Context, userID int, webhookID string, status models.DeliveryStatus) (deliveries []models.Delivery, err error) {
+
+	ctx, span := tracer.Start(ctx, "service.GetDeliveries", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("webhook.id", webhookID)))
+	defer func() { tracing.End(span, err) }()
+
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return nil, errs.ErrInvalidDeliveryStatus
+	}
+
+	if err := s.findWebhook(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries = s.webhooks.GetDeliveries(ctx, userID, webhookID)
+
+	if status != "" {
+		deliveries = slices.DeleteFunc(deliveries, func(d models.Delivery) bool { return d.Status != status })
+	}
+
+	return deliveries, nil
+
+}
+
+// RetryDelivery moves a dead-letter delivery of a webhook of the user back into the queue,
+// to be attempted again right away with a fresh attempt budget.
+func (s *Service) RetryDelivery(ctx context.Context, userID int, webhookID, deliveryID string) (err error) {
+
+	ctx, span := tracer.Start(ctx, "service.RetryDelivery", trace.WithAttributes(attribute.Int("user.id", userID), attribute.String("webhook.id", webhookID), attribute.String("delivery.id", deliveryID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.findWebhook(ctx, userID, webhookID); err != nil {
+		return err
+	}
+
+	deliveries := s.webhooks.GetDeliveries(ctx, userID, webhookID)
+
+	i := slices.IndexFunc(deliveries, func(d models.Delivery) bool { return d.DeliveryID == deliveryID })
+	if i < 0 {
+		return errs.ErrDeliveryNotFound
+	}
+
+	delivery := deliveries[i]
+	if delivery.Status != models.DeliveryDead {
+		return errs.ErrNotDeadLetter
+	}
+
+	now := s.clock.Now().UTC()
+
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttempt = now
+	delivery.UpdatedAt = now
+
+	return s.webhooks.UpdateDelivery(ctx, delivery)
+
+}
+
+// findWebhook checks that the IDs are well-formed and that the webhook exists and belongs to the user.
+func (s *Service) findWebhook(ctx context.Context, userID int, webhookID string) error {
+
+	if userID <= 0 {
+		return errs.ErrInvalidUserID
+	}
+
+	if _, err := uuid.Parse(webhookID); err != nil {
+		return errs.ErrInvalidWebhookID
+	}
+
+	if !slices.ContainsFunc(s.webhooks.GetWebhooks(ctx, userID), func(w models.Webhook) bool { return w.WebhookID == webhookID }) {
+		return errs.ErrWebhookNotFound
+	}
+
+	return nil
+
+}
+
+// notify queues a notification about a change of an event for every webhook of its owner.
+//
+// The change has already been applied, so a failure to queue the notifications is
+// logged rather than reported to the caller.
+func (s *Service) notify(ctx context.Context, change string, event models.Event) {
+
+	webhooks := s.webhooks.GetWebhooks(ctx, event.Meta.UserID)
+	if len(webhooks) == 0 {
+		return
+	}
+
+	now := s.clock.Now().UTC()
+	deliveries := make([]models.Delivery, 0, len(webhooks))
+
+	for _, webhook := range webhooks {
+
+		deliveryID := uuid.New().String()
+
+		payload, err := json.Marshal(notification{
+			ID:        deliveryID,
+			Type:      change,
+			Tenant:    tenant.ID(ctx),
+			CreatedAt: now.Format(time.RFC3339),
+			Event: notificationEvent{
+				UserID:  event.Meta.UserID,
+				EventID: event.Meta.EventID,
+				Date:    event.Meta.EventDate.Format("2006-01-02"),
+				Text:    event.Data.Text,
+			},
+		})
+		if err != nil {
+			s.logger.LogError("service — failed to encode webhook notification", err, "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+			return
+		}
+
+		deliveries = append(deliveries, models.Delivery{
+			DeliveryID:  deliveryID,
+			WebhookID:   webhook.WebhookID,
+			UserID:      webhook.UserID,
+			Type:        change,
+			Payload:     payload,
+			Status:      models.DeliveryPending,
+			NextAttempt: now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+
+	}
+
+	if err := s.webhooks.Enqueue(ctx, deliveries); err != nil {
+		s.logger.LogWarn("service — failed to queue webhook deliveries", "UserID", event.Meta.UserID, "EventID", event.Meta.EventID, "type", change, "err", err.Error(), "request_id", tracing.RequestID(ctx), "layer", "service.impl")
+	}
+
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http or https URL of reasonable
+// length whose host the egress policy allows. Host names are only refused here if they always
+// stand for the local host; the addresses they resolve to are checked when delivering.
+func (s *Service) validateWebhookURL(rawURL string) error {
+
+	if len(rawURL) > maxURLLength {
+		return errs.ErrInvalidWebhookURL
+	}
+
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errs.ErrInvalidWebhookURL
+	}
+
+	if !s.egress.AllowedHost(u.Hostname()) {
+		return errs.ErrWebhookHost
+	}
+
+	return nil
+
+}
@@ -0,0 +1,217 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/errs"
+	"L2.18/internal/models"
+	storageMock "L2.18/internal/repository/mocks"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/egress"
+	loggerMock "L2.18/pkg/logger/mocks"
+	"L2.18/pkg/tenant"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noWebhooks returns a webhook store mock of users without any webhooks.
+func noWebhooks(controller *gomock.Controller) *storageMock.MockWebhookStore {
+	mockWebhooks := storageMock.NewMockWebhookStore(controller)
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return mockWebhooks
+}
+
+// webhookFixture wires a service with storage and webhook store mocks. Removing attachment files always succeeds.
+func webhookFixture(t *testing.T) (*Service, *storageMock.MockStorage, *storageMock.MockWebhookStore) {
+
+	controller := gomock.NewController(t)
+	t.Cleanup(controller.Finish)
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStorage := storageMock.NewMockStorage(controller)
+	mockBlobs := storageMock.NewMockBlobStore(controller)
+	mockWebhooks := storageMock.NewMockWebhookStore(controller)
+
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	mockBlobs.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewService(config.Service{MaxEventsPerUser: 5, MaxWebhooksPerUser: 2}, mockStorage, mockBlobs, mockWebhooks, nil, testCalendar(t), clock.System(), mockLogger)
+
+	return service, mockStorage, mockWebhooks
+
+}
+
+func TestCreateWebhook(t *testing.T) {
+
+	service, _, mockWebhooks := webhookFixture(t)
+
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), 1).Return(nil)
+	mockWebhooks.EXPECT().SaveWebhook(gomock.Any(), gomock.Any()).Return(nil)
+
+	webhook := &models.Webhook{UserID: 1, URL: "https://bot.example.com/calendar?team=a"}
+
+	require.NoError(t, service.CreateWebhook(context.Background(), webhook))
+	assert.NotEmpty(t, webhook.WebhookID)
+	assert.Len(t, webhook.Secret, 64)
+	assert.False(t, webhook.CreatedAt.IsZero())
+
+	for _, url := range []string{"", "bot.example.com/hook", "ftp://bot.example.com", "https://", "/relative"} {
+		err := service.CreateWebhook(context.Background(), &models.Webhook{UserID: 1, URL: url})
+		assert.ErrorIs(t, err, errs.ErrInvalidWebhookURL, url)
+	}
+
+	for _, url := range []string{"http://127.0.0.1:8080/hook", "http://[::1]/hook", "http://localhost/hook", "https://10.0.0.5", "http://169.254.169.254/latest/meta-data"} {
+		err := service.CreateWebhook(context.Background(), &models.Webhook{UserID: 1, URL: url})
+		assert.ErrorIs(t, err, errs.ErrWebhookHost, url)
+	}
+
+	err := service.CreateWebhook(context.Background(), &models.Webhook{UserID: 0, URL: "https://bot.example.com"})
+	assert.ErrorIs(t, err, errs.ErrInvalidUserID)
+
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), 1).Return(make([]models.Webhook, 2))
+
+	err = service.CreateWebhook(context.Background(), &models.Webhook{UserID: 1, URL: "https://bot.example.com"})
+	assert.ErrorIs(t, err, errs.ErrMaxWebhooks)
+
+}
+
+func TestCreateWebhook_AllowedNetworks(t *testing.T) {
+
+	service, _, mockWebhooks := webhookFixture(t)
+
+	policy, err := egress.NewPolicy([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	service.egress = policy
+
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), 1).Return(nil)
+	mockWebhooks.EXPECT().SaveWebhook(gomock.Any(), gomock.Any()).Return(nil)
+
+	require.NoError(t, service.CreateWebhook(context.Background(), &models.Webhook{UserID: 1, URL: "http://10.0.0.5:8080/hook"}))
+
+	err = service.CreateWebhook(context.Background(), &models.Webhook{UserID: 1, URL: "http://192.168.1.5/hook"})
+	assert.ErrorIs(t, err, errs.ErrWebhookHost)
+
+}
+
+func TestNotify(t *testing.T) {
+
+	service, mockStorage, mockWebhooks := webhookFixture(t)
+
+	ctx := tenant.WithID(context.Background(), "team-a")
+	date := time.Now().UTC().Add(48 * time.Hour).Truncate(24 * time.Hour)
+	eventID := uuid.New().String()
+
+	webhooks := []models.Webhook{{WebhookID: "w1", UserID: 1}, {WebhookID: "w2", UserID: 1}}
+
+	var queued []models.Delivery
+
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), 1).Return(webhooks).Times(3)
+	mockWebhooks.EXPECT().Enqueue(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, deliveries []models.Delivery) error {
+		queued = append(queued, deliveries...)
+		return nil
+	}).Times(3)
+
+	event := &models.Event{Meta: models.Meta{UserID: 1, EventDate: date}, Data: models.Data{Text: "standup"}}
+
+	mockStorage.EXPECT().CountUserEvents(gomock.Any(), 1).Return(0, nil)
+	mockStorage.EXPECT().CreateEvent(gomock.Any(), event).Return(eventID, nil)
+
+	_, err := service.CreateEvent(ctx, event)
+	require.NoError(t, err)
+
+	stored := &models.Event{Meta: models.Meta{UserID: 1, EventID: eventID, EventDate: date}, Data: models.Data{Text: "standup"}}
+
+	mockStorage.EXPECT().GetEventByID(gomock.Any(), eventID).Return(stored).Times(2)
+	mockStorage.EXPECT().UpdateEvent(gomock.Any(), gomock.Any()).Return(nil)
+	mockStorage.EXPECT().DeleteEvent(gomock.Any(), gomock.Any()).Return(nil)
+
+	require.NoError(t, service.UpdateEvent(ctx, &models.Event{Meta: models.Meta{UserID: 1, EventID: eventID, NewDate: date.Add(24 * time.Hour)}}))
+
+	require.NoError(t, service.DeleteEvent(ctx, &models.Meta{UserID: 1, EventID: eventID}))
+
+	require.Len(t, queued, 6)
+
+	wantTypes := []string{eventCreated, eventCreated, eventUpdated, eventUpdated, eventDeleted, eventDeleted}
+	wantDates := []time.Time{date, date, date.Add(24 * time.Hour), date.Add(24 * time.Hour), date, date}
+
+	for i, d := range queued {
+
+		assert.Equal(t, webhooks[i%2].WebhookID, d.WebhookID)
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		assert.Equal(t, wantTypes[i], d.Type)
+
+		var body notification
+		require.NoError(t, json.Unmarshal(d.Payload, &body))
+		assert.Equal(t, d.DeliveryID, body.ID)
+		assert.Equal(t, "team-a", body.Tenant)
+		assert.Equal(t, notificationEvent{UserID: 1, EventID: eventID, Date: wantDates[i].Format("2006-01-02"), Text: "standup"}, body.Event)
+
+	}
+
+}
+
+func TestGetDeliveries(t *testing.T) {
+
+	service, _, mockWebhooks := webhookFixture(t)
+
+	webhookID := uuid.New().String()
+	deliveries := []models.Delivery{
+		{DeliveryID: "d3", Status: models.DeliveryPending},
+		{DeliveryID: "d2", Status: models.DeliveryDead},
+		{DeliveryID: "d1", Status: models.DeliveryDelivered},
+	}
+
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), 1).Return([]models.Webhook{{WebhookID: webhookID, UserID: 1}}).AnyTimes()
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), 2).Return(nil).AnyTimes()
+	mockWebhooks.EXPECT().GetDeliveries(gomock.Any(), 1, webhookID).DoAndReturn(func(context.Context, int, string) []models.Delivery {
+		return append([]models.Delivery(nil), deliveries...)
+	}).Times(2)
+
+	all, err := service.GetDeliveries(context.Background(), 1, webhookID, "")
+	require.NoError(t, err)
+	assert.Equal(t, deliveries, all)
+
+	dead, err := service.GetDeliveries(context.Background(), 1, webhookID, models.DeliveryDead)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Delivery{deliveries[1]}, dead)
+
+	_, err = service.GetDeliveries(context.Background(), 1, webhookID, "failed")
+	assert.ErrorIs(t, err, errs.ErrInvalidDeliveryStatus)
+
+	_, err = service.GetDeliveries(context.Background(), 2, webhookID, "")
+	assert.ErrorIs(t, err, errs.ErrWebhookNotFound)
+
+	_, err = service.GetDeliveries(context.Background(), 1, "not-a-uuid", "")
+	assert.ErrorIs(t, err, errs.ErrInvalidWebhookID)
+
+}
+
+func TestRetryDelivery(t *testing.T) {
+
+	service, _, mockWebhooks := webhookFixture(t)
+
+	webhookID := uuid.New().String()
+	dead := models.Delivery{DeliveryID: "d1", WebhookID: webhookID, UserID: 1, Status: models.DeliveryDead, Attempts: 8, LastError: "timeout"}
+
+	mockWebhooks.EXPECT().GetWebhooks(gomock.Any(), 1).Return([]models.Webhook{{WebhookID: webhookID, UserID: 1}}).AnyTimes()
+	mockWebhooks.EXPECT().GetDeliveries(gomock.Any(), 1, webhookID).Return([]models.Delivery{dead, {DeliveryID: "d2", Status: models.DeliveryDelivered}}).Times(3)
+	mockWebhooks.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d models.Delivery) error {
+		assert.Equal(t, "d1", d.DeliveryID)
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		assert.Zero(t, d.Attempts)
+		assert.False(t, d.NextAttempt.After(time.Now()))
+		return nil
+	})
+
+	require.NoError(t, service.RetryDelivery(context.Background(), 1, webhookID, "d1"))
+	assert.ErrorIs(t, service.RetryDelivery(context.Background(), 1, webhookID, "d2"), errs.ErrNotDeadLetter)
+	assert.ErrorIs(t, service.RetryDelivery(context.Background(), 1, webhookID, "d3"), errs.ErrDeliveryNotFound)
+
+}
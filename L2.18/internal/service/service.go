@@ -3,37 +3,124 @@
 package service
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"L2.18/internal/config"
 	"L2.18/internal/models"
 	"L2.18/internal/repository"
 	"L2.18/internal/service/impl"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/egress"
+	"L2.18/pkg/holidays"
 	"L2.18/pkg/logger"
 )
 
 // Service defines the interface for the event management service.
 // It encapsulates all business logic related to events, including creation,
 // updates, deletion, and retrieval for different time periods.
+//
+// Every event method takes the request context, which carries the trace span
+// and request ID of the caller down to the storage layer.
 type Service interface {
 	// CreateEvent creates a new event for a user and returns the generated event ID.
 	// Returns an error if the creation fails or validation rules are violated.
-	CreateEvent(event *models.Event) (string, error)
+	CreateEvent(ctx context.Context, event *models.Event) (string, error)
 
 	// UpdateEvent updates an existing event's data or date.
 	// Returns an error if the event does not exist or no changes are detected.
-	UpdateEvent(event *models.Event) error
+	UpdateEvent(ctx context.Context, event *models.Event) error
 
 	// DeleteEvent removes an event identified by the provided metadata.
 	// Returns an error if the event does not exist or cannot be deleted.
-	DeleteEvent(meta *models.Meta) error
+	DeleteEvent(ctx context.Context, meta *models.Meta) error
 
 	// GetEvents retrieves all events for a user within a specified period (day, week, month).
 	// Returns a slice of events and an error if retrieval fails.
-	GetEvents(meta *models.Meta, period models.Period) ([]models.Event, error)
+	GetEvents(ctx context.Context, meta *models.Meta, period models.Period) ([]models.Event, error)
+
+	// UpdateSettings saves a user's working calendar settings (holiday country and working week).
+	// Returns an error if the country has no holiday calendar or no working day is given.
+	UpdateSettings(ctx context.Context, settings *models.Settings) error
+
+	// GetSettings returns a user's working calendar settings, or the defaults if the user has none.
+	GetSettings(ctx context.Context, userID int) (models.Settings, error)
+
+	// GetHolidays returns the holidays of the user's calendar within a period (day, week, month).
+	GetHolidays(ctx context.Context, meta *models.Meta, period models.Period) ([]models.Holiday, error)
+
+	// AddAttachment attaches a file to an event of the user, filling in the attachment ID,
+	// detected content type, size and upload time. Returns an error if the file is empty,
+	// too large, of a disallowed type, or the event already has the maximum number of files.
+	AddAttachment(ctx context.Context, attachment *models.Attachment, content io.Reader) error
+
+	// GetAttachments returns the files attached to an event of the user, in upload order.
+	GetAttachments(ctx context.Context, userID int, eventID string) ([]models.Attachment, error)
+
+	// OpenAttachment returns a file attached to an event of the user and a reader of its content,
+	// which the caller must close.
+	OpenAttachment(ctx context.Context, userID int, eventID, attachmentID string) (models.Attachment, io.ReadCloser, error)
+
+	// DeleteAttachment removes a file attached to an event of the user.
+	DeleteAttachment(ctx context.Context, userID int, eventID, attachmentID string) error
+
+	// CreateWebhook registers a webhook notified about changes of the user's events, filling in
+	// the webhook ID, signing secret and registration time. Returns an error if the URL is not
+	// an absolute http or https URL, points to a host the egress policy refuses, or the user
+	// already has the maximum number of webhooks.
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+
+	// GetWebhooks returns the webhooks of the user, in registration order.
+	GetWebhooks(ctx context.Context, userID int) ([]models.Webhook, error)
+
+	// DeleteWebhook removes a webhook of the user together with its pending deliveries and delivery log.
+	DeleteWebhook(ctx context.Context, userID int, webhookID string) error
+
+	// GetDeliveries returns the delivery log of a webhook of the user, newest first,
+	// optionally only the deliveries in the given state.
+	GetDeliveries(ctx context.Context, userID int, webhookID string, status models.DeliveryStatus) ([]models.Delivery, error)
+
+	// RetryDelivery moves a dead-letter delivery of a webhook of the user back into the queue.
+	RetryDelivery(ctx context.Context, userID int, webhookID, deliveryID string) error
+
+	// QuickAdd reads a free-form English or Russian phrase as an event of the user, resolving
+	// relative dates against the reference time (now if zero). The event is created only if
+	// create is set; otherwise the interpretation is returned for confirmation.
+	QuickAdd(ctx context.Context, userID int, phrase string, reference time.Time, create bool) (models.QuickAdd, error)
+
+	// Reconfigure atomically applies reloadable service settings, such as per-user limits.
+	Reconfigure(config config.Service)
+}
+
+// Admin defines the interface for the administrative service.
+// It provides storage-wide operations used by the admin API; unlike Service,
+// these operations are not scoped to the user making the request.
+type Admin interface {
+	// ListUsers returns every user owning at least one event with their event counts.
+	ListUsers(ctx context.Context) ([]models.UserStats, error)
+
+	// DeleteUserEvents removes all events of a user, with their attachments, and returns how many events were removed.
+	DeleteUserEvents(ctx context.Context, userID int) (int, error)
+
+	// Stats returns global statistics, such as the events per day histogram and storage size.
+	Stats(ctx context.Context) (models.Stats, error)
+
+	// Snapshot forces the storage to persist a snapshot, if the backend supports it.
+	Snapshot(ctx context.Context) error
+
+	// Compact forces the storage to reclaim unused space, if the backend supports it.
+	Compact(ctx context.Context) error
 }
 
 // NewService creates a new Service implementation using the provided configuration,
-// repository storage, and logger. The returned Service implements all event management
-// operations defined in the Service interface.
-func NewService(config config.Service, storage repository.Storage, logger logger.Logger) Service {
-	return impl.NewService(config, storage, logger)
+// repository storage, attachment blob store, webhook store, egress policy of webhook URLs, holiday calendars, clock, and logger. The returned Service implements all
+// event management operations defined in the Service interface.
+func NewService(config config.Service, storage repository.Storage, blobs repository.BlobStore, webhooks repository.WebhookStore, egress *egress.Policy, calendar *holidays.Registry, clock clock.Clock, logger logger.Logger) Service {
+	return impl.NewService(config, storage, blobs, webhooks, egress, calendar, clock, logger)
+}
+
+// NewAdmin creates a new Admin implementation operating on the provided storage and attachment blob store.
+func NewAdmin(storage repository.Storage, blobs repository.BlobStore, logger logger.Logger) Admin {
+	return impl.NewAdmin(storage, blobs, logger)
 }
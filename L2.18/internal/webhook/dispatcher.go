@@ -0,0 +1,287 @@
+// Package webhook delivers queued webhook notifications in the background.
+//
+// Notifications are queued by the service layer whenever an event changes. The Dispatcher
+// polls the queue, posts every due notification to its webhook signed with the webhook's
+// secret, and records the outcome: successful deliveries go to the delivery log, failed
+// ones are retried with exponential backoff until they run out of attempts and are moved
+// to the dead-letter list.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/models"
+	"L2.18/internal/repository"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/egress"
+	"L2.18/pkg/logger"
+	"L2.18/pkg/tenant"
+	"L2.18/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers sent with every notification.
+const (
+	HeaderEvent     = "X-Calendar-Event"     // kind of change, e.g. event.created
+	HeaderDelivery  = "X-Calendar-Delivery"  // delivery ID, identical across retries
+	HeaderTimestamp = "X-Calendar-Timestamp" // Unix time of the attempt, covered by the signature
+	HeaderSignature = "X-Calendar-Signature" // "sha256=" followed by the hex HMAC of the timestamp and body
+)
+
+const (
+	batchSize      = 100      // maximum number of deliveries attempted per poll
+	workers        = 4        // maximum number of webhooks posted to concurrently
+	maxErrorLength = 200      // longest error message recorded for a failed attempt
+	maxResponse    = 64 << 10 // bytes of a response body read before the connection is released
+)
+
+// tracer creates the dispatcher spans.
+var tracer = tracing.Tracer("L2.18/internal/webhook")
+
+// Dispatcher posts queued notifications to their webhooks.
+type Dispatcher struct {
+	store  repository.WebhookStore // queue of deliveries and registered webhooks
+	client *http.Client            // HTTP client used for every attempt
+	config config.Webhooks         // timeouts, retry and backoff settings
+	clock  clock.Clock             // source of attempt times and timestamps
+	logger logger.Logger           // logger instance
+}
+
+// NewDispatcher creates a Dispatcher delivering the notifications queued in store.
+// Redirects are not followed: a webhook answering with one counts as failed.
+//
+// Connections are only made to addresses allowed by policy, checked after the webhook's
+// host name is resolved, so an attempt to a refused address fails like an unreachable
+// webhook. Proxies from the environment are not used, since the policy must see the
+// address of the webhook itself.
+func NewDispatcher(config config.Webhooks, policy *egress.Policy, store repository.WebhookStore, clock clock.Clock, logger logger.Logger) *Dispatcher {
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Control: policy.Control}).DialContext
+
+	return &Dispatcher{
+		store: store,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   config.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config: config,
+		clock:  clock,
+		logger: logger,
+	}
+
+}
+
+// Run delivers due notifications every poll interval until ctx is cancelled.
+// Attempts interrupted by the cancellation are not counted and are repeated after a restart.
+func (d *Dispatcher) Run(ctx context.Context) {
+
+	d.logger.LogInfo("webhook — dispatcher started", "poll_interval", d.config.PollInterval.String(), "layer", "webhook")
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+
+		d.Flush(ctx)
+
+		select {
+		case <-ctx.Done():
+			d.logger.LogInfo("webhook — dispatcher stopped", "layer", "webhook")
+			return
+		case <-ticker.C:
+		}
+
+	}
+
+}
+
+// Flush attempts the deliveries that are due now and returns how many were attempted.
+//
+// Deliveries of the same webhook are posted one after another in queue order, while
+// different webhooks are served concurrently, so a slow receiver does not hold up others.
+func (d *Dispatcher) Flush(ctx context.Context) int {
+
+	due := d.store.Due(ctx, d.clock.Now().UTC(), batchSize)
+	if len(due) == 0 {
+		return 0
+	}
+
+	var order []string
+	byWebhook := make(map[string][]models.Delivery)
+	for _, delivery := range due {
+		if _, found := byWebhook[delivery.WebhookID]; !found {
+			order = append(order, delivery.WebhookID)
+		}
+		byWebhook[delivery.WebhookID] = append(byWebhook[delivery.WebhookID], delivery)
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, workers)
+
+	for _, webhookID := range order {
+		slots <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-slots }()
+			for _, delivery := range byWebhook[webhookID] {
+				d.deliver(ctx, delivery)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return len(due)
+
+}
+
+// deliver makes one attempt to post a delivery and records its outcome.
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.Delivery) {
+
+	ctx = tenant.WithID(ctx, delivery.Tenant)
+
+	ctx, span := tracer.Start(ctx, "webhook.deliver", trace.WithAttributes(
+		attribute.String("delivery.id", delivery.DeliveryID),
+		attribute.String("webhook.id", delivery.WebhookID),
+		attribute.Int("delivery.attempt", delivery.Attempts+1),
+	))
+
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	webhooks := d.store.GetWebhooks(ctx, delivery.UserID)
+
+	i := slices.IndexFunc(webhooks, func(w models.Webhook) bool { return w.WebhookID == delivery.WebhookID })
+	if i < 0 {
+		return
+	}
+
+	status, err := d.post(ctx, webhooks[i], delivery)
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := d.clock.Now().UTC()
+
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+	delivery.LastStatus = status
+	delivery.LastError = ""
+
+	fields := []any{"DeliveryID", delivery.DeliveryID, "WebhookID", delivery.WebhookID, "tenant", delivery.Tenant, "attempts", delivery.Attempts, "status", status, "layer", "webhook"}
+
+	switch {
+
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		d.logger.Debug("webhook — notification delivered", fields...)
+
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = truncate(err.Error())
+		d.logger.LogWarn("webhook — delivery moved to the dead-letter list", append(fields, "err", delivery.LastError)...)
+
+	default:
+		delivery.NextAttempt = now.Add(backoff(d.config, delivery.Attempts))
+		delivery.LastError = truncate(err.Error())
+		d.logger.Debug("webhook — delivery failed, retry scheduled", append(fields, "err", delivery.LastError, "next_attempt", delivery.NextAttempt.Format(time.RFC3339))...)
+
+	}
+
+	if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+		d.logger.LogError("webhook — failed to record delivery attempt", err, "DeliveryID", delivery.DeliveryID, "layer", "webhook")
+	}
+
+}
+
+// post sends a delivery to its webhook. It returns the HTTP status code of the response,
+// or 0 if there was none, and an error unless the status code is 2xx.
+func (d *Dispatcher) post(ctx context.Context, webhook models.Webhook, delivery models.Delivery) (int, error) {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.clock.Now().UTC().Unix()
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "calendar-webhooks/1.0")
+	request.Header.Set(HeaderEvent, delivery.Type)
+	request.Header.Set(HeaderDelivery, delivery.DeliveryID)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponse))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("webhook responded with %s", response.Status)
+	}
+
+	return response.StatusCode, nil
+
+}
+
+// Sign returns the signature of a notification body sent at the given Unix time:
+// "sha256=" followed by the hex-encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with
+// the webhook secret. Receivers recompute it to verify the sender, and reject old
+// timestamps to prevent replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid signature of body sent at timestamp.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// backoff returns the delay before the next attempt of a delivery that has failed the
+// given number of times: the initial backoff, doubled after every further failure,
+// capped at the maximum backoff.
+func backoff(config config.Webhooks, attempts int) time.Duration {
+	delay := config.InitialBackoff
+	for i := 1; i < attempts && delay < config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, config.MaxBackoff)
+}
+
+// truncate shortens an error message to the length recorded in the delivery log.
+func truncate(msg string) string {
+	if len(msg) <= maxErrorLength {
+		return msg
+	}
+	return strings.ToValidUTF8(msg[:maxErrorLength], "") + "…"
+}
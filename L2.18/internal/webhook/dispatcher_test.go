@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/internal/models"
+	storageMock "L2.18/internal/repository/mocks"
+	"L2.18/pkg/clock"
+	"L2.18/pkg/egress"
+	loggerMock "L2.18/pkg/logger/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig are the delivery settings used by the tests.
+var testConfig = config.Webhooks{Timeout: time.Second, PollInterval: 10 * time.Millisecond, MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, LogSize: 10}
+
+// testReceivers allows the tests to deliver to receivers started by httptest on the loopback address.
+var testReceivers = []string{"127.0.0.0/8"}
+
+// dispatcherFixture wires a dispatcher with a webhook store mock serving one webhook at url,
+// an egress policy allowing the given networks, and a frozen clock.
+func dispatcherFixture(t *testing.T, url string, allowed []string) (*Dispatcher, *storageMock.MockWebhookStore, models.Webhook, time.Time) {
+
+	controller := gomock.NewController(t)
+	t.Cleanup(controller.Finish)
+
+	mockLogger := loggerMock.NewMockLogger(controller)
+	mockStore := storageMock.NewMockWebhookStore(controller)
+
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().LogWarn(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().LogInfo(gomock.Any(), gomock.Any()).AnyTimes()
+
+	webhook := models.Webhook{WebhookID: "w1", Tenant: "team-a", UserID: 1, URL: url, Secret: "s3cr3t"}
+	mockStore.EXPECT().GetWebhooks(gomock.Any(), 1).Return([]models.Webhook{webhook}).AnyTimes()
+
+	policy, err := egress.NewPolicy(allowed)
+	require.NoError(t, err)
+
+	now := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+
+	dispatcher := NewDispatcher(testConfig, policy, mockStore, clock.NewFake(now), mockLogger)
+
+	return dispatcher, mockStore, webhook, now
+
+}
+
+// pending returns a pending delivery of webhook w1 that has failed the given number of times.
+func pending(id string, attempts int) models.Delivery {
+	return models.Delivery{DeliveryID: id, WebhookID: "w1", Tenant: "team-a", UserID: 1, Type: "event.created", Payload: []byte(`{"id":"` + id + `"}`), Status: models.DeliveryPending, Attempts: attempts}
+}
+
+func TestDispatcher_Delivered(t *testing.T) {
+
+	var received atomic.Int32
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+
+		if err != nil || !Verify("s3cr3t", timestamp, body, r.Header.Get(HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "event.created", r.Header.Get(HeaderEvent))
+		assert.Equal(t, `{"id":"`+r.Header.Get(HeaderDelivery)+`"}`, string(body))
+
+		received.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+
+	}))
+	defer receiver.Close()
+
+	dispatcher, mockStore, _, now := dispatcherFixture(t, receiver.URL, testReceivers)
+
+	mockStore.EXPECT().Due(gomock.Any(), now, batchSize).Return([]models.Delivery{pending("d1", 0), pending("d2", 1)})
+	mockStore.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d models.Delivery) error {
+		assert.Equal(t, models.DeliveryDelivered, d.Status)
+		assert.Equal(t, http.StatusNoContent, d.LastStatus)
+		assert.Empty(t, d.LastError)
+		assert.Equal(t, now, d.UpdatedAt)
+		return nil
+	}).Times(2)
+
+	require.Equal(t, 2, dispatcher.Flush(context.Background()))
+	require.Equal(t, int32(2), received.Load())
+
+}
+
+func TestDispatcher_Retry(t *testing.T) {
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	dispatcher, mockStore, _, now := dispatcherFixture(t, receiver.URL, testReceivers)
+
+	updates := make(map[string]models.Delivery)
+
+	mockStore.EXPECT().Due(gomock.Any(), now, batchSize).Return([]models.Delivery{pending("first", 0), pending("second", 1), pending("last", 2)})
+	mockStore.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d models.Delivery) error {
+		updates[d.DeliveryID] = d
+		return nil
+	}).Times(3)
+
+	dispatcher.Flush(context.Background())
+
+	assert.Equal(t, models.DeliveryPending, updates["first"].Status)
+	assert.Equal(t, now.Add(time.Second), updates["first"].NextAttempt)
+	assert.Equal(t, http.StatusBadGateway, updates["first"].LastStatus)
+	assert.Equal(t, "webhook responded with 502 Bad Gateway", updates["first"].LastError)
+
+	assert.Equal(t, models.DeliveryPending, updates["second"].Status)
+	assert.Equal(t, now.Add(2*time.Second), updates["second"].NextAttempt)
+
+	assert.Equal(t, models.DeliveryDead, updates["last"].Status)
+	assert.Equal(t, 3, updates["last"].Attempts)
+
+}
+
+func TestDispatcher_Unreachable(t *testing.T) {
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	receiver.Close()
+
+	dispatcher, mockStore, _, now := dispatcherFixture(t, receiver.URL, testReceivers)
+
+	mockStore.EXPECT().Due(gomock.Any(), now, batchSize).Return([]models.Delivery{pending("d1", 0)})
+	mockStore.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d models.Delivery) error {
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		assert.Zero(t, d.LastStatus)
+		assert.NotEmpty(t, d.LastError)
+		return nil
+	})
+
+	dispatcher.Flush(context.Background())
+
+}
+
+func TestDispatcher_Refused(t *testing.T) {
+
+	var received atomic.Int32
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	defer receiver.Close()
+
+	dispatcher, mockStore, _, now := dispatcherFixture(t, receiver.URL, nil)
+
+	mockStore.EXPECT().Due(gomock.Any(), now, batchSize).Return([]models.Delivery{pending("d1", 0)})
+	mockStore.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d models.Delivery) error {
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		assert.Zero(t, d.LastStatus)
+		assert.Contains(t, d.LastError, "address not allowed")
+		return nil
+	})
+
+	dispatcher.Flush(context.Background())
+
+	assert.Zero(t, received.Load(), "a loopback receiver must not be reached unless allowed")
+
+}
+
+func TestDispatcher_Redirect(t *testing.T) {
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer receiver.Close()
+
+	dispatcher, mockStore, _, now := dispatcherFixture(t, receiver.URL, testReceivers)
+
+	mockStore.EXPECT().Due(gomock.Any(), now, batchSize).Return([]models.Delivery{pending("d1", 0)})
+	mockStore.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d models.Delivery) error {
+		assert.Equal(t, http.StatusFound, d.LastStatus)
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		return nil
+	})
+
+	dispatcher.Flush(context.Background())
+
+}
+
+func TestDispatcher_DeletedWebhook(t *testing.T) {
+
+	dispatcher, mockStore, _, now := dispatcherFixture(t, "http://127.0.0.1:1", testReceivers)
+
+	delivery := pending("d1", 0)
+	delivery.WebhookID = "deleted"
+
+	mockStore.EXPECT().Due(gomock.Any(), now, batchSize).Return([]models.Delivery{delivery})
+
+	require.Equal(t, 1, dispatcher.Flush(context.Background()))
+
+}
+
+func TestDispatcher_Run(t *testing.T) {
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+
+	dispatcher, mockStore, _, _ := dispatcherFixture(t, receiver.URL, testReceivers)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	delivered := make(chan struct{})
+
+	gomock.InOrder(
+		mockStore.EXPECT().Due(gomock.Any(), gomock.Any(), batchSize).Return(nil),
+		mockStore.EXPECT().Due(gomock.Any(), gomock.Any(), batchSize).Return([]models.Delivery{pending("d1", 0)}),
+	)
+	mockStore.EXPECT().Due(gomock.Any(), gomock.Any(), batchSize).Return(nil).AnyTimes()
+	mockStore.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, models.Delivery) error {
+		close(delivered)
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("delivery was not attempted")
+	}
+
+	cancel()
+	<-done
+
+}
+
+func TestBackoff(t *testing.T) {
+
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+
+	for i, delay := range want {
+		assert.Equal(t, delay, backoff(testConfig, i+1), "attempt %d", i+1)
+	}
+
+}
+
+func TestSign(t *testing.T) {
+
+	signature := Sign("s3cr3t", 1700000000, []byte(`{"id":"d1"}`))
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.Len(t, signature, 7+64)
+	assert.True(t, Verify("s3cr3t", 1700000000, []byte(`{"id":"d1"}`), signature))
+	assert.False(t, Verify("s3cr3t", 1700000001, []byte(`{"id":"d1"}`), signature))
+	assert.False(t, Verify("other", 1700000000, []byte(`{"id":"d1"}`), signature))
+
+}
@@ -0,0 +1,65 @@
+// Package clock abstracts the current time, so that date-dependent behaviour such as
+// "event in the past" checks can be tested deterministically.
+//
+// Production code uses System, which reads the wall clock. Tests use a Fake, which
+// stands still until it is moved explicitly and can be placed in any location, e.g.
+// right before a daylight saving transition or at the end of a leap day.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// system is the wall clock.
+type system struct{}
+
+// Now returns the current wall clock time.
+func (system) Now() time.Time {
+	return time.Now()
+}
+
+// System returns the wall clock.
+func System() Clock {
+	return system{}
+}
+
+// Fake is a Clock that only moves when told to. It is safe for concurrent use.
+type Fake struct {
+	now time.Time    // current time of the clock
+	mu  sync.RWMutex // protects now
+}
+
+// NewFake creates a Fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the time the clock is set to.
+func (f *Fake) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+// Set moves the clock to now, forwards or backwards.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d, or backward if d is negative. The duration is
+// absolute time: advancing by 24 hours across a daylight saving transition ends at a
+// different local hour.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystem(t *testing.T) {
+
+	before := time.Now()
+	now := System().Now()
+
+	assert.False(t, now.Before(before))
+	assert.False(t, now.After(time.Now()))
+
+}
+
+func TestFake(t *testing.T) {
+
+	start := time.Date(2028, 2, 29, 23, 59, 59, 0, time.UTC)
+	fake := NewFake(start)
+
+	assert.Equal(t, start, fake.Now())
+	assert.Equal(t, start, fake.Now(), "a fake clock must not move by itself")
+
+	fake.Advance(time.Second)
+	assert.Equal(t, time.Date(2028, 3, 1, 0, 0, 0, 0, time.UTC), fake.Now())
+
+	fake.Advance(-2 * time.Second)
+	assert.Equal(t, time.Date(2028, 2, 29, 23, 59, 58, 0, time.UTC), fake.Now())
+
+	fake.Set(start.AddDate(-1, 0, 0))
+	assert.Equal(t, time.Date(2027, 3, 1, 23, 59, 59, 0, time.UTC), fake.Now())
+
+}
+
+func TestFake_Concurrent(t *testing.T) {
+
+	fake := NewFake(time.Time{})
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(2)
+		go func() { defer wg.Done(); fake.Advance(time.Minute) }()
+		go func() { defer wg.Done(); _ = fake.Now() }()
+	}
+	wg.Wait()
+
+	assert.Equal(t, time.Time{}.Add(100*time.Minute), fake.Now())
+
+}
@@ -0,0 +1,110 @@
+// Package devcert generates self-signed TLS certificates for local development.
+//
+// The certificates are their own CA, so a developer can trust them once in the browser
+// or the system store, and cover the names the service is reached by locally. They must
+// never be used in production.
+package devcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultHosts are the names a locally running service is reached by.
+var DefaultHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// Generate creates a self-signed certificate for hosts, which may be DNS names or IP
+// addresses, valid from now for validFor. It returns the certificate and its ECDSA P-256
+// private key, both PEM-encoded.
+func Generate(hosts []string, now time.Time, validFor time.Duration) (certPEM, keyPEM []byte, err error) {
+
+	if len(hosts) == 0 {
+		return nil, nil, errors.New("devcert: no hosts given")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("devcert: generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("devcert: generate serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"L2.18 calendar development"}, CommonName: hosts[0]},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validFor),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("devcert: create certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("devcert: encode key: %w", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, nil
+
+}
+
+// WriteFiles generates a certificate for hosts valid for a year from now and writes it
+// to certFile and keyFile, creating their directories. The key is readable by the
+// owner only.
+func WriteFiles(certFile, keyFile string, hosts []string, now time.Time) error {
+
+	certPEM, keyPEM, err := Generate(hosts, now, 365*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []struct {
+		path string
+		data []byte
+		perm os.FileMode
+	}{
+		{keyFile, keyPEM, 0o600},
+		{certFile, certPEM, 0o644},
+	} {
+		if err := os.MkdirAll(filepath.Dir(file.path), 0o755); err != nil {
+			return fmt.Errorf("devcert: %w", err)
+		}
+		if err := os.WriteFile(file.path, file.data, file.perm); err != nil {
+			return fmt.Errorf("devcert: %w", err)
+		}
+	}
+
+	return nil
+
+}
@@ -0,0 +1,62 @@
+package devcert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+
+	now := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+
+	certPEM, keyPEM, err := Generate(DefaultHosts, now, 24*time.Hour)
+	require.NoError(t, err)
+
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, []string{"localhost"}, cert.DNSNames)
+	require.Len(t, cert.IPAddresses, 2)
+	require.True(t, cert.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")))
+	require.Equal(t, now.Add(24*time.Hour), cert.NotAfter)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	for _, host := range DefaultHosts {
+		_, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: host, CurrentTime: now})
+		require.NoError(t, err, host)
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "example.com", CurrentTime: now})
+	require.Error(t, err)
+
+	_, _, err = Generate(nil, now, time.Hour)
+	require.Error(t, err)
+
+}
+
+func TestWriteFiles(t *testing.T) {
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "certs", "server.crt")
+	keyFile := filepath.Join(dir, "certs", "server.key")
+
+	require.NoError(t, WriteFiles(certFile, keyFile, []string{"calendar.test"}, time.Now()))
+
+	_, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	info, err := os.Stat(keyFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+}
@@ -0,0 +1,157 @@
+// Package egress decides which addresses outbound requests made on behalf of users may go to.
+//
+// Requests such as webhook deliveries are sent to URLs chosen by users, so they must not
+// reach the host the service runs on or the private network around it. A Policy refuses
+// loopback, private, link-local, multicast, unspecified and other non-public addresses
+// unless they belong to one of the networks it is configured to allow. Host names are
+// checked after they are resolved, by the Control function of the dialer, so a name cannot
+// be pointed at a refused address once a URL using it has been accepted.
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// ErrRefused is returned by Control for connections to addresses the policy does not allow.
+var ErrRefused = errors.New("egress: address not allowed")
+
+// reserved lists networks that pass the checks of netip.Addr but are not reachable on
+// the public internet: the "this network" block, which Linux routes to the local host,
+// and the shared address space of carrier-grade NATs, often used inside cloud networks.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// loopback is the address localhost names are checked as.
+var loopback = netip.AddrFrom4([4]byte{127, 0, 0, 1})
+
+// Policy decides which addresses outbound requests may go to. A nil Policy allows public
+// addresses only.
+type Policy struct {
+	allowed []netip.Prefix // non-public networks requests may go to nonetheless
+}
+
+// NewPolicy creates a Policy allowing public addresses and the given networks, each in
+// CIDR notation (e.g. 10.0.0.0/8) or a single IP address.
+func NewPolicy(allowed []string) (*Policy, error) {
+
+	policy := &Policy{}
+
+	for _, value := range allowed {
+		network, err := parseNetwork(value)
+		if err != nil {
+			return nil, err
+		}
+		policy.allowed = append(policy.allowed, network)
+	}
+
+	return policy, nil
+
+}
+
+// parseNetwork parses a network in CIDR notation or a single IP address, which stands
+// for a network of its own. IPv4-mapped IPv6 addresses are treated as IPv4 ones.
+func parseNetwork(value string) (netip.Prefix, error) {
+
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil || addr.Zone() != "" {
+			return netip.Prefix{}, fmt.Errorf("invalid network %q: must be a CIDR or an IP address", value)
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	network, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid network %q: must be a CIDR or an IP address", value)
+	}
+
+	if network.Addr().Is4In6() && network.Bits() >= 96 {
+		network = netip.PrefixFrom(network.Addr().Unmap(), network.Bits()-96)
+	}
+
+	return network.Masked(), nil
+
+}
+
+// Allowed reports whether requests may go to addr: it is public or in an allowed network.
+func (p *Policy) Allowed(addr netip.Addr) bool {
+
+	addr = addr.Unmap().WithZone("")
+
+	if public(addr) {
+		return true
+	}
+
+	if p == nil {
+		return false
+	}
+
+	for _, network := range p.allowed {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// AllowedHost reports whether requests may go to the host of a URL, given without port
+// or brackets. IP addresses are checked like by Allowed, and localhost names, which never
+// leave the local host, like the loopback address. Other names are allowed here and
+// checked once resolved, by Control.
+func (p *Policy) AllowedHost(host string) bool {
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.Allowed(addr)
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+		return p.Allowed(loopback)
+	}
+
+	return true
+
+}
+
+// Control is a net.Dialer Control function refusing connections to addresses the policy
+// does not allow. It runs for every resolved address the dialer tries.
+func (p *Policy) Control(_, address string, _ syscall.RawConn) error {
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRefused, address)
+	}
+
+	if !p.Allowed(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrRefused, addrPort.Addr())
+	}
+
+	return nil
+
+}
+
+// public reports whether addr is a unicast address reachable on the public internet.
+func public(addr netip.Addr) bool {
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, network := range reserved {
+		if network.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+
+}
@@ -0,0 +1,73 @@
+package egress
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Allowed(t *testing.T) {
+
+	var strict *Policy
+
+	for _, addr := range []string{"93.184.216.34", "2606:2800:220:1::1", "::ffff:93.184.216.34"} {
+		require.True(t, strict.Allowed(netip.MustParseAddr(addr)), addr)
+	}
+
+	refused := []string{
+		"127.0.0.1", "::1", "::ffff:127.0.0.1", "0.0.0.0", "::", "0.1.2.3",
+		"10.1.2.3", "172.16.0.1", "192.168.1.1", "100.64.0.1", "fd00::1",
+		"169.254.169.254", "fe80::1", "fe80::1%eth0", "224.0.0.1", "ff02::1", "255.255.255.255",
+	}
+	for _, addr := range refused {
+		require.False(t, strict.Allowed(netip.MustParseAddr(addr)), addr)
+	}
+
+	policy, err := NewPolicy([]string{"10.0.0.0/8", "127.0.0.1", "::ffff:192.168.0.0/112"})
+	require.NoError(t, err)
+
+	for _, addr := range []string{"10.1.2.3", "127.0.0.1", "::ffff:10.0.0.1", "192.168.1.1", "93.184.216.34"} {
+		require.True(t, policy.Allowed(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{"127.0.0.2", "::1", "172.16.0.1", "169.254.169.254"} {
+		require.False(t, policy.Allowed(netip.MustParseAddr(addr)), addr)
+	}
+
+	for _, value := range []string{"", "10.0.0.0/33", "example.com", "fe80::1%eth0", "10.0.0.0/8 "} {
+		_, err := NewPolicy([]string{value})
+		require.Error(t, err, value)
+	}
+
+}
+
+func TestPolicy_AllowedHost(t *testing.T) {
+
+	var strict *Policy
+
+	for _, host := range []string{"example.com", "93.184.216.34", "localhost.example.com"} {
+		require.True(t, strict.AllowedHost(host), host)
+	}
+	for _, host := range []string{"localhost", "LocalHost.", "api.localhost", "127.0.0.1", "::1", "169.254.169.254"} {
+		require.False(t, strict.AllowedHost(host), host)
+	}
+
+	policy, err := NewPolicy([]string{"127.0.0.0/8"})
+	require.NoError(t, err)
+	require.True(t, policy.AllowedHost("localhost"))
+	require.True(t, policy.AllowedHost("127.0.0.1"))
+	require.False(t, policy.AllowedHost("::1"))
+
+}
+
+func TestPolicy_Control(t *testing.T) {
+
+	var strict *Policy
+
+	require.NoError(t, strict.Control("tcp4", "93.184.216.34:443", nil))
+	require.True(t, errors.Is(strict.Control("tcp4", "127.0.0.1:80", nil), ErrRefused))
+	require.True(t, errors.Is(strict.Control("tcp6", "[fe80::1%eth0]:80", nil), ErrRefused))
+	require.True(t, errors.Is(strict.Control("unix", "/run/socket", nil), ErrRefused))
+
+}
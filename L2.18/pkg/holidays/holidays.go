@@ -0,0 +1,258 @@
+// Package holidays loads per-country holiday calendars from local data files and
+// answers working-calendar questions: whether a date is a holiday, and whether it
+// is a working day for a given working week.
+//
+// Each country is described by a text file named after its country code, e.g.
+// holidays/RU.txt. Every non-empty line that is not a comment holds a date and a
+// holiday name separated by whitespace:
+//
+//	# fixed-date holidays, repeated every year
+//	01-01 New Year's Day
+//	# holidays observed on a specific date only (movable holidays, transfers)
+//	2026-11-26 Thanksgiving Day
+//
+// A dated entry takes precedence over a yearly entry for the same day.
+package holidays
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"L2.18/internal/config"
+)
+
+// fileExt is the extension of holiday data files.
+const fileExt = ".txt"
+
+// weekdays maps accepted weekday names to time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Holiday is a named holiday on a specific date.
+type Holiday struct {
+	Date time.Time // Day of the holiday, at midnight UTC
+	Name string    // Holiday name
+}
+
+// Calendar holds the holidays of a single country.
+type Calendar struct {
+	yearly map[string]string // "MM-DD" -> holiday name, repeated every year
+	dated  map[string]string // "YYYY-MM-DD" -> holiday name, for that date only
+}
+
+// Registry holds the loaded calendars together with the default working calendar
+// applied to users who have not chosen their own.
+type Registry struct {
+	calendars      map[string]*Calendar // country code -> calendar
+	defaultCountry string               // country of users without their own setting
+	workingWeek    []time.Weekday       // working days of users without their own setting
+}
+
+// Load creates a Registry from the calendar configuration, reading every holiday
+// file in the configured directory. An empty directory setting loads no calendars.
+func Load(config config.Calendar) (*Registry, error) {
+
+	workingWeek, err := ParseWeekdays(config.WorkingWeek)
+	if err != nil {
+		return nil, fmt.Errorf("calendar.working_week: %w", err)
+	}
+
+	registry := &Registry{
+		calendars:      make(map[string]*Calendar),
+		defaultCountry: strings.ToUpper(config.DefaultCountry),
+		workingWeek:    workingWeek,
+	}
+
+	if config.HolidaysDir != "" {
+		if err := registry.loadDir(config.HolidaysDir); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, found := registry.calendars[registry.defaultCountry]; registry.defaultCountry != "" && !found {
+		return nil, fmt.Errorf("calendar.default_country: no holiday file for %q in %q", registry.defaultCountry, config.HolidaysDir)
+	}
+
+	return registry, nil
+
+}
+
+// loadDir parses every holiday file in dir into the registry.
+func (r *Registry) loadDir(dir string) error {
+
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("failed to open holidays directory: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+fileExt))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		calendar, err := Parse(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		r.calendars[strings.ToUpper(strings.TrimSuffix(filepath.Base(path), fileExt))] = calendar
+
+	}
+
+	return nil
+
+}
+
+// Parse reads a holiday calendar in the data file format described in the package documentation.
+func Parse(reader io.Reader) (*Calendar, error) {
+
+	calendar := &Calendar{yearly: make(map[string]string), dated: make(map[string]string)}
+	scanner := bufio.NewScanner(reader)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		date, name, found := strings.Cut(line, " ")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("line %d: expected a date followed by a holiday name", lineNo)
+		}
+
+		if _, err := time.Parse("2006-01-02", date); err == nil {
+			calendar.dated[date] = name
+			continue
+		}
+
+		// 2024 is a leap year, so "02-29" is accepted as a yearly date
+		if _, err := time.Parse("2006-01-02", "2024-"+date); err == nil {
+			calendar.yearly[date] = name
+			continue
+		}
+
+		return nil, fmt.Errorf("line %d: invalid date %q, expected MM-DD or YYYY-MM-DD", lineNo, date)
+
+	}
+
+	return calendar, scanner.Err()
+
+}
+
+// Holiday returns the name of the holiday on date, if any.
+func (c *Calendar) Holiday(date time.Time) (string, bool) {
+	if name, found := c.dated[date.Format("2006-01-02")]; found {
+		return name, true
+	}
+	name, found := c.yearly[date.Format("01-02")]
+	return name, found
+}
+
+// Has reports whether a calendar for the country is loaded.
+func (r *Registry) Has(country string) bool {
+	_, found := r.calendars[strings.ToUpper(country)]
+	return found
+}
+
+// Countries returns the codes of all loaded calendars in alphabetical order.
+func (r *Registry) Countries() []string {
+	countries := make([]string, 0, len(r.calendars))
+	for country := range r.calendars {
+		countries = append(countries, country)
+	}
+	slices.Sort(countries)
+	return countries
+}
+
+// Defaults returns the country and working week used for users without their own setting.
+func (r *Registry) Defaults() (string, []time.Weekday) {
+	return r.defaultCountry, slices.Clone(r.workingWeek)
+}
+
+// Holiday returns the name of the country's holiday on date, if any.
+// An unknown or empty country has no holidays.
+func (r *Registry) Holiday(country string, date time.Time) (string, bool) {
+	calendar, found := r.calendars[strings.ToUpper(country)]
+	if !found {
+		return "", false
+	}
+	return calendar.Holiday(date)
+}
+
+// Holidays returns the country's holidays between from and to, inclusive, ordered by date.
+func (r *Registry) Holidays(country string, from, to time.Time) []Holiday {
+
+	var res []Holiday
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if name, found := r.Holiday(country, day); found {
+			res = append(res, Holiday{Date: day, Name: name})
+		}
+	}
+
+	return res
+
+}
+
+// IsWorkingDay reports whether date is a working day: one of workingWeek that is
+// not a holiday of the country. The holiday name is returned if there is one.
+func (r *Registry) IsWorkingDay(country string, workingWeek []time.Weekday, date time.Time) (bool, string) {
+	holiday, isHoliday := r.Holiday(country, date)
+	return !isHoliday && slices.Contains(workingWeek, date.Weekday()), holiday
+}
+
+// ParseWeekdays converts weekday names such as "mon" or "Monday" into a sorted,
+// deduplicated list of weekdays.
+func ParseWeekdays(names []string) ([]time.Weekday, error) {
+
+	res := make([]time.Weekday, 0, len(names))
+
+	for _, name := range names {
+		day, found := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !found {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		res = append(res, day)
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("at least one working day is required")
+	}
+
+	slices.Sort(res)
+
+	return slices.Compact(res), nil
+
+}
+
+// FormatWeekdays converts weekdays into their short lowercase names, e.g. "mon".
+func FormatWeekdays(days []time.Weekday) []string {
+	res := make([]string, len(days))
+	for i, day := range days {
+		res[i] = strings.ToLower(day.String()[:3])
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+package holidays
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+
+	calendar, err := Parse(strings.NewReader("# comment\n\n01-01 New Year's Day\n02-29 Leap Day\n2027-01-01 Moved Holiday\n"))
+	require.NoError(t, err)
+
+	name, found := calendar.Holiday(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.True(t, found)
+	require.Equal(t, "New Year's Day", name)
+
+	name, _ = calendar.Holiday(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, "Moved Holiday", name)
+
+	_, found = calendar.Holiday(time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC))
+	require.True(t, found)
+
+	_, err = Parse(strings.NewReader("13-01 Bad Month\n"))
+	require.ErrorContains(t, err, "line 1")
+
+	_, err = Parse(strings.NewReader("01-01\n"))
+	require.ErrorContains(t, err, "expected a date followed by a holiday name")
+
+}
+
+func TestLoad(t *testing.T) {
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ru.txt"), []byte("06-12 Russia Day\n"), 0o644))
+
+	registry, err := Load(config.Calendar{HolidaysDir: dir, DefaultCountry: "ru", WorkingWeek: []string{"Mon", "tue", "WED", "thu", "fri", "mon"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"RU"}, registry.Countries())
+
+	country, week := registry.Defaults()
+	require.Equal(t, "RU", country)
+	require.Equal(t, []string{"mon", "tue", "wed", "thu", "fri"}, FormatWeekdays(week))
+
+	working, holiday := registry.IsWorkingDay(country, week, time.Date(2030, 6, 12, 0, 0, 0, 0, time.UTC))
+	require.False(t, working)
+	require.Equal(t, "Russia Day", holiday)
+
+	working, _ = registry.IsWorkingDay(country, week, time.Date(2030, 6, 11, 0, 0, 0, 0, time.UTC))
+	require.True(t, working)
+
+	_, err = Load(config.Calendar{HolidaysDir: dir, DefaultCountry: "US", WorkingWeek: []string{"mon"}})
+	require.Error(t, err)
+
+	_, err = Load(config.Calendar{HolidaysDir: filepath.Join(dir, "missing"), WorkingWeek: []string{"mon"}})
+	require.Error(t, err)
+
+	_, err = Load(config.Calendar{WorkingWeek: []string{"someday"}})
+	require.Error(t, err)
+
+}
+
+func TestShippedCalendars(t *testing.T) {
+	registry, err := Load(config.Calendar{HolidaysDir: "../../holidays", WorkingWeek: []string{"mon"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"RU", "US"}, registry.Countries())
+}
@@ -1,11 +1,14 @@
 // Package logger provides a unified interface for structured logging across the application.
 // It abstracts different logging levels (fatal, error, warning, info, debug) and allows
 // consistent logging with additional contextual fields. The logger implementation can
-// be swapped (currently using slog) without changing the application code.
+// be swapped (slog or rotating, optionally wrapped with sampling) without changing
+// the application code.
 package logger
 
 import (
 	"L2.18/internal/config"
+	"L2.18/pkg/logger/rotating"
+	"L2.18/pkg/logger/sampling"
 	"L2.18/pkg/logger/slog"
 )
 
@@ -36,13 +39,33 @@ type Logger interface {
 	// for development and troubleshooting, usually verbose.
 	Debug(msg string, args ...any)
 
+	// SetDebug enables or disables debug-level logging at runtime. It is safe
+	// to call concurrently with logging, e.g. when the configuration is reloaded.
+	SetDebug(enabled bool)
+
 	// Close gracefully releases any underlying resources used by the logger,
 	// such as open files or network connections.
 	Close()
 }
 
 // NewLogger creates a new Logger instance using the provided configuration.
-// It returns a logger implementation that satisfies the Logger interface.
+// The backend is selected by config.Backend ("rotating" or the default "slog") and,
+// if sampling is enabled, wrapped with a sampler for high-volume levels.
 func NewLogger(config config.Logger) Logger {
-	return slog.NewLogger(config)
+
+	var logger Logger
+
+	switch config.Backend {
+	case "rotating":
+		logger = rotating.NewLogger(config)
+	default:
+		logger = slog.NewLogger(config)
+	}
+
+	if config.Sampling.Enabled {
+		logger = sampling.NewLogger(logger, config.Sampling)
+	}
+
+	return logger
+
 }
@@ -129,3 +129,15 @@ func (mr *MockLoggerMockRecorder) LogWarn(msg interface{}, args ...interface{})
 	varargs := append([]interface{}{msg}, args...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogWarn", reflect.TypeOf((*MockLogger)(nil).LogWarn), varargs...)
 }
+
+// SetDebug mocks base method.
+func (m *MockLogger) SetDebug(enabled bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDebug", enabled)
+}
+
+// SetDebug indicates an expected call of SetDebug.
+func (mr *MockLoggerMockRecorder) SetDebug(enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDebug", reflect.TypeOf((*MockLogger)(nil).SetDebug), enabled)
+}
@@ -0,0 +1,92 @@
+// Package rotating provides a structured logger implementation that writes to a
+// rotating log file. Files are rotated by size and/or age, optionally compressed
+// with gzip, and pruned according to retention limits. Output is JSON or text.
+package rotating
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"L2.18/internal/config"
+	slogger "L2.18/pkg/logger/slog"
+)
+
+// Logger wraps a slog.Logger writing to a rotating file and implements the Logger interface.
+type Logger struct {
+	logger *slog.Logger
+	level  *slog.LevelVar
+	writer *Writer // nil when logging to stdout
+}
+
+// NewLogger creates a new Logger instance based on the provided configuration.
+// If the log directory is not set or the log file cannot be opened, stdout is used instead.
+func NewLogger(config config.Logger) *Logger {
+
+	var dest io.Writer = os.Stdout
+	var writer *Writer
+
+	if config.LogDir == "" {
+		fmt.Fprintln(os.Stderr, "logger — rotating backend requires a log directory, switching to stdout")
+	} else {
+		var err error
+		if writer, err = NewWriter(config.LogDir, config.Rotation); err != nil {
+			fmt.Fprintf(os.Stderr, "logger — %v, switching to stdout\n", err)
+		} else {
+			dest = writer
+		}
+	}
+
+	level := slogger.NewLevel(config.Debug)
+	logger := &Logger{logger: slog.New(slogger.NewHandler(dest, config.Format, level)), level: level, writer: writer}
+	slog.SetDefault(logger.logger)
+
+	return logger
+
+}
+
+// LogFatal logs a fatal message with an error, flushes the log file and exits the program.
+func (l *Logger) LogFatal(msg string, err error, args ...any) {
+	if err != nil {
+		args = append(args, "err", err.Error())
+	}
+	l.logger.Error(msg, args...)
+	l.Close()
+	os.Exit(1)
+}
+
+// LogError logs an error message with an optional error.
+func (l *Logger) LogError(msg string, err error, args ...any) {
+	if err != nil {
+		args = append(args, "err", err.Error())
+	}
+	l.logger.Error(msg, args...)
+}
+
+// LogWarn writes a warning-level log message with the provided fields.
+func (l *Logger) LogWarn(msg string, args ...any) {
+	l.logger.Warn(msg, args...)
+}
+
+// LogInfo logs an informational message.
+func (l *Logger) LogInfo(msg string, args ...any) {
+	l.logger.Info(msg, args...)
+}
+
+// Debug logs a debug message.
+func (l *Logger) Debug(msg string, args ...any) {
+	l.logger.Debug(msg, args...)
+}
+
+// SetDebug enables or disables debug-level logging.
+func (l *Logger) SetDebug(enabled bool) {
+	slogger.SetLevel(l.level, enabled)
+}
+
+// Close closes the active log file and waits for pending compression and pruning.
+func (l *Logger) Close() {
+	if l.writer != nil {
+		_ = l.writer.Close()
+	}
+}
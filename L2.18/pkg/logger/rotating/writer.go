@@ -0,0 +1,398 @@
+package rotating
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"L2.18/internal/config"
+)
+
+const (
+	logName         = "app.log"                 // name of the active log file
+	backupPrefix    = "app-"                    // prefix of rotated log files
+	backupExt       = ".log"                    // extension of rotated log files
+	compressedExt   = ".gz"                     // extension appended to compressed rotated files
+	backupTimestamp = "2006-01-02T15-04-05.000" // timestamp layout embedded into rotated file names
+	backupSeqSep    = "-"                       // separates the timestamp from the sequence number of a same-millisecond backup
+	megabyte        = 1024 * 1024
+)
+
+// Writer is an io.WriteCloser that writes to app.log inside a directory and rotates
+// the file once it exceeds the configured size or age. Rotated files are renamed
+// with a timestamp suffix, optionally compressed, and pruned according to the
+// retention limits. Compression and pruning run in the background after each rotation.
+//
+// All methods are thread-safe.
+type Writer struct {
+	dir        string           // directory holding the active and rotated files
+	maxSize    int64            // size in bytes that triggers rotation (0 disables)
+	interval   time.Duration    // age of the active file that triggers rotation (0 disables)
+	maxBackups int              // number of rotated files to keep (0 keeps all)
+	maxAge     time.Duration    // age of rotated files before removal (0 keeps all)
+	compress   bool             // compresses rotated files if true
+	file       *os.File         // currently active log file
+	size       int64            // number of bytes written to the active file
+	openedAt   time.Time        // time the active file was started
+	now        func() time.Time // clock used for rotation decisions and file names
+	mu         sync.Mutex       // protects the active file state
+	millMu     sync.Mutex       // serializes compression and pruning runs
+	wg         sync.WaitGroup   // tracks background compression and pruning runs
+}
+
+// NewWriter creates a Writer for the given directory, creating the directory if needed
+// and opening (or appending to) the active log file.
+func NewWriter(dir string, config config.Rotation) (*Writer, error) {
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	writer := &Writer{
+		dir:        dir,
+		maxSize:    int64(config.MaxSizeMB) * megabyte,
+		interval:   config.Interval,
+		maxBackups: config.MaxBackups,
+		maxAge:     config.MaxAge,
+		compress:   config.Compress,
+		now:        time.Now,
+	}
+
+	if err := writer.openExisting(); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+
+}
+
+// Write writes p to the active log file, rotating it first if the write would
+// exceed the size limit or the file is older than the rotation interval.
+func (w *Writer) Write(p []byte) (int, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+
+}
+
+// Rotate forces rotation of the active log file regardless of its size or age.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Close closes the active log file and waits for background compression and pruning to finish.
+func (w *Writer) Close() error {
+
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+
+	return err
+
+}
+
+// shouldRotate reports whether the active file must be rotated before writing n more bytes.
+// A non-empty write into an empty file never triggers size-based rotation, so oversized
+// records still get written instead of rotating endlessly.
+func (w *Writer) shouldRotate(n int64) bool {
+	if w.maxSize > 0 && w.size > 0 && w.size+n > w.maxSize {
+		return true
+	}
+	if w.interval > 0 && w.now().Sub(w.openedAt) >= w.interval {
+		return true
+	}
+	return false
+}
+
+// openExisting opens the active log file for appending, picking up its current size
+// and modification time so that rotation limits survive restarts.
+func (w *Writer) openExisting() error {
+
+	path := filepath.Join(w.dir, logName)
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return w.openNew()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0777)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = info.ModTime()
+
+	return nil
+
+}
+
+// openNew creates a fresh, empty active log file.
+func (w *Writer) openNew() error {
+
+	file, err := os.OpenFile(filepath.Join(w.dir, logName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0777)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
+
+	w.file = file
+	w.size = 0
+	w.openedAt = w.now()
+
+	return nil
+
+}
+
+// rotate closes the active file, renames it to a timestamped backup, opens a new active
+// file, and schedules compression and pruning of backups. Must be called with mu held.
+// Backups rotated within the same millisecond get a sequence number, so none is overwritten.
+func (w *Writer) rotate() error {
+
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	backup, err := w.backupPath(w.now())
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(filepath.Join(w.dir, logName), backup); err != nil {
+		return fmt.Errorf("failed to rename log file: %w", err)
+	}
+
+	if err := w.openNew(); err != nil {
+		return err
+	}
+
+	w.wg.Go(w.mill)
+
+	return nil
+
+}
+
+// backupPath returns the path a backup rotated at t is renamed to: the timestamp alone,
+// or followed by the lowest sequence number no existing backup, compressed or not, uses.
+func (w *Writer) backupPath(t time.Time) (string, error) {
+
+	stamp := t.Format(backupTimestamp)
+
+	for seq := 0; ; seq++ {
+
+		name := backupPrefix + stamp
+		if seq > 0 {
+			name += backupSeqSep + strconv.Itoa(seq)
+		}
+		path := filepath.Join(w.dir, name+backupExt)
+
+		taken, err := exists(path)
+		if err == nil && !taken {
+			taken, err = exists(path + compressedExt)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check rotated log name: %w", err)
+		}
+		if !taken {
+			return path, nil
+		}
+
+	}
+
+}
+
+// exists reports whether a file exists at path.
+func exists(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// mill compresses uncompressed backups and removes backups exceeding the retention limits.
+// Failures are reported to stderr since the logger itself cannot be used here.
+func (w *Writer) mill() {
+
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	backups, err := w.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger — failed to list rotated logs: %v\n", err)
+		return
+	}
+
+	var keep []backup
+	for i, b := range backups {
+		expired := w.maxAge > 0 && w.now().Sub(b.timestamp) > w.maxAge
+		excess := w.maxBackups > 0 && i >= w.maxBackups
+		if expired || excess {
+			if err := os.Remove(b.path); err != nil {
+				fmt.Fprintf(os.Stderr, "logger — failed to remove rotated log: %v\n", err)
+			}
+			continue
+		}
+		keep = append(keep, b)
+	}
+
+	if !w.compress {
+		return
+	}
+
+	for _, b := range keep {
+		if strings.HasSuffix(b.path, compressedExt) {
+			continue
+		}
+		if err := compressFile(b.path); err != nil {
+			fmt.Fprintf(os.Stderr, "logger — failed to compress rotated log: %v\n", err)
+		}
+	}
+
+}
+
+// backup describes a rotated log file.
+type backup struct {
+	path      string    // full path to the rotated file
+	timestamp time.Time // rotation time parsed from the file name
+	seq       int       // sequence number among backups rotated within the same millisecond
+}
+
+// backups returns all rotated log files in the directory, newest first.
+func (w *Writer) backups() ([]backup, error) {
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []backup
+	for _, entry := range entries {
+
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupPrefix) {
+			continue
+		}
+
+		base := strings.TrimSuffix(name, compressedExt)
+		if !strings.HasSuffix(base, backupExt) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(base, backupPrefix), backupExt)
+		timestamp, seq, ok := parseStamp(stamp)
+		if !ok {
+			continue
+		}
+
+		res = append(res, backup{path: filepath.Join(w.dir, name), timestamp: timestamp, seq: seq})
+
+	}
+
+	slices.SortFunc(res, func(a, b backup) int {
+		if c := b.timestamp.Compare(a.timestamp); c != 0 {
+			return c
+		}
+		return b.seq - a.seq
+	})
+
+	return res, nil
+
+}
+
+// parseStamp parses the part of a backup name between prefix and extension: a timestamp,
+// optionally followed by a sequence number. It reports false for names of other files.
+func parseStamp(stamp string) (time.Time, int, bool) {
+
+	if len(stamp) < len(backupTimestamp) {
+		return time.Time{}, 0, false
+	}
+
+	timestamp, err := time.ParseInLocation(backupTimestamp, stamp[:len(backupTimestamp)], time.Local)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	rest := stamp[len(backupTimestamp):]
+	if rest == "" {
+		return timestamp, 0, true
+	}
+
+	digits, found := strings.CutPrefix(rest, backupSeqSep)
+	seq, err := strconv.Atoi(digits)
+	if !found || err != nil || seq < 1 || strconv.Itoa(seq) != digits {
+		return time.Time{}, 0, false
+	}
+
+	return timestamp, seq, true
+
+}
+
+// compressFile gzips the file at path into path+".gz" and removes the original.
+// The original is only removed once the compressed copy is completely written and
+// closed; on any failure the partial copy is removed and the original kept.
+func compressFile(path string) error {
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressedExt, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0777)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path + compressedExt)
+		return err
+	}
+
+	return os.Remove(path)
+
+}
@@ -0,0 +1,215 @@
+package rotating
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_RotatesBySize(t *testing.T) {
+
+	dir := t.TempDir()
+
+	writer, err := NewWriter(dir, config.Rotation{MaxSizeMB: 1})
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 12, 1, 10, 0, 0, 0, time.Local)
+	writer.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	record := []byte(strings.Repeat("a", megabyte/3) + "\n")
+	for range 3 {
+		_, err := writer.Write(record)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+
+	backups, err := writer.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	active, err := os.Stat(filepath.Join(dir, logName))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(record)), active.Size())
+
+}
+
+func TestWriter_RotatesByInterval(t *testing.T) {
+
+	dir := t.TempDir()
+
+	writer, err := NewWriter(dir, config.Rotation{Interval: time.Hour})
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 12, 1, 10, 0, 0, 0, time.Local)
+	writer.now = func() time.Time { return clock }
+	writer.openedAt = clock
+
+	_, err = writer.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	clock = clock.Add(2 * time.Hour)
+
+	_, err = writer.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	backups, err := writer.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	content, err := os.ReadFile(backups[0].path)
+	require.NoError(t, err)
+	require.Equal(t, "first\n", string(content))
+
+}
+
+func TestWriter_CompressesAndPrunes(t *testing.T) {
+
+	dir := t.TempDir()
+
+	writer, err := NewWriter(dir, config.Rotation{MaxBackups: 2, MaxAge: 24 * time.Hour, Compress: true})
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 12, 1, 10, 0, 0, 0, time.Local)
+	writer.now = func() time.Time { return clock }
+
+	expired := filepath.Join(dir, backupPrefix+clock.Add(-48*time.Hour).Format(backupTimestamp)+backupExt)
+	require.NoError(t, os.WriteFile(expired, []byte("old"), 0666))
+
+	for i := range 3 {
+		clock = clock.Add(time.Minute)
+		_, err := writer.Write([]byte{byte('a' + i), '\n'})
+		require.NoError(t, err)
+		require.NoError(t, writer.Rotate())
+		writer.wg.Wait() // The background mill reads the clock, so it must be done before the clock moves
+	}
+
+	require.NoError(t, writer.Close())
+
+	backups, err := writer.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	for _, b := range backups {
+		require.True(t, strings.HasSuffix(b.path, compressedExt))
+	}
+
+	_, err = os.Stat(expired)
+	require.True(t, os.IsNotExist(err))
+
+	file, err := os.Open(backups[0].path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, "c\n", string(content))
+
+}
+
+func TestWriter_SameMillisecondRotations(t *testing.T) {
+
+	dir := t.TempDir()
+
+	writer, err := NewWriter(dir, config.Rotation{Compress: true})
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 12, 1, 10, 0, 0, 0, time.Local)
+	writer.now = func() time.Time { return clock }
+
+	for i := range 3 {
+		_, err := writer.Write([]byte{byte('a' + i), '\n'})
+		require.NoError(t, err)
+		require.NoError(t, writer.Rotate())
+		writer.wg.Wait() // The previous backup is compressed, so only its .gz name is taken
+	}
+
+	require.NoError(t, writer.Close())
+
+	backups, err := writer.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 3, "backups rotated within the same millisecond must not overwrite each other")
+
+	for i, expected := range []string{"c\n", "b\n", "a\n"} {
+
+		require.Equal(t, 2-i, backups[i].seq)
+
+		file, err := os.Open(backups[i].path)
+		require.NoError(t, err)
+
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content))
+
+		require.NoError(t, file.Close())
+
+	}
+
+}
+
+func TestParseStamp(t *testing.T) {
+
+	clock := time.Date(2025, 12, 1, 10, 0, 0, 0, time.Local)
+	stamp := clock.Format(backupTimestamp)
+
+	tests := []struct {
+		stamp string
+		seq   int
+		ok    bool
+	}{
+		{stamp, 0, true},
+		{stamp + "-1", 1, true},
+		{stamp + "-12", 12, true},
+		{stamp + "-0", 0, false},
+		{stamp + "-01", 0, false},
+		{stamp + "-x", 0, false},
+		{stamp + "1", 0, false},
+		{"2025-12-01", 0, false},
+	}
+
+	for _, tt := range tests {
+		timestamp, seq, ok := parseStamp(tt.stamp)
+		require.Equal(t, tt.ok, ok, tt.stamp)
+		if ok {
+			require.True(t, timestamp.Equal(clock), tt.stamp)
+			require.Equal(t, tt.seq, seq, tt.stamp)
+		}
+	}
+
+}
+
+func TestWriter_AppendsToExistingFile(t *testing.T) {
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, logName), []byte("existing\n"), 0666))
+
+	writer, err := NewWriter(dir, config.Rotation{})
+	require.NoError(t, err)
+	require.Equal(t, int64(len("existing\n")), writer.size)
+
+	_, err = writer.Write([]byte("appended\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	content, err := os.ReadFile(filepath.Join(dir, logName))
+	require.NoError(t, err)
+	require.Equal(t, "existing\nappended\n", string(content))
+
+	_, err = writer.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+}
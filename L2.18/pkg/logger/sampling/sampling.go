@@ -0,0 +1,177 @@
+// Package sampling provides a logger implementation that samples high-volume log levels
+// before passing messages to another logger. Within each interval, the first N identical
+// messages of a level are logged, after which only every M-th one passes. Errors and fatal
+// messages are never sampled.
+package sampling
+
+import (
+	"sync"
+	"time"
+
+	"L2.18/internal/config"
+)
+
+// level identifies a sampled log level.
+type level string
+
+const (
+	levelDebug level = "debug"
+	levelInfo  level = "info"
+	levelWarn  level = "warn"
+)
+
+// backend is the logger that receives messages passing the sampler.
+// It mirrors the logger.Logger interface, which cannot be imported here without a cycle.
+type backend interface {
+	LogFatal(msg string, err error, args ...any)
+	LogError(msg string, err error, args ...any)
+	LogWarn(msg string, args ...any)
+	LogInfo(msg string, args ...any)
+	Debug(msg string, args ...any)
+	SetDebug(enabled bool)
+	Close()
+}
+
+// counterKey identifies a group of identical messages of one level.
+type counterKey struct {
+	level level
+	msg   string
+}
+
+// Logger samples messages per level and message text and forwards the accepted ones
+// to the underlying backend. Dropped message counts are reported as a warning at
+// the end of each interval.
+//
+// All methods are thread-safe.
+type Logger struct {
+	backend  backend                       // logger receiving sampled messages
+	interval time.Duration                 // window after which counters are reset
+	rules    map[level]config.SamplingRule // sampling rules per level
+	counters map[counterKey]int            // messages seen in the current interval
+	dropped  map[level]int                 // messages dropped in the current interval
+	resetAt  time.Time                     // end of the current interval
+	now      func() time.Time              // clock used for interval boundaries
+	mu       sync.Mutex                    // protects counters, dropped and resetAt
+}
+
+// NewLogger wraps backend with a sampler configured by the provided sampling settings.
+func NewLogger(backend backend, config config.Sampling) *Logger {
+
+	interval := config.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	logger := &Logger{
+		backend:  backend,
+		interval: interval,
+		rules:    rules(config),
+		counters: make(map[counterKey]int),
+		dropped:  make(map[level]int),
+		now:      time.Now,
+	}
+	logger.resetAt = logger.now().Add(interval)
+
+	return logger
+
+}
+
+// rules maps the configured per-level sampling rules to levels.
+func rules(settings config.Sampling) map[level]config.SamplingRule {
+	return map[level]config.SamplingRule{
+		levelDebug: settings.Debug,
+		levelInfo:  settings.Info,
+		levelWarn:  settings.Warn,
+	}
+}
+
+// LogFatal passes the fatal message to the backend without sampling.
+func (l *Logger) LogFatal(msg string, err error, args ...any) {
+	l.backend.LogFatal(msg, err, args...)
+}
+
+// LogError passes the error message to the backend without sampling.
+func (l *Logger) LogError(msg string, err error, args ...any) {
+	l.backend.LogError(msg, err, args...)
+}
+
+// LogWarn passes the warning to the backend if it is accepted by the sampler.
+func (l *Logger) LogWarn(msg string, args ...any) {
+	if l.allow(levelWarn, msg) {
+		l.backend.LogWarn(msg, args...)
+	}
+}
+
+// LogInfo passes the informational message to the backend if it is accepted by the sampler.
+func (l *Logger) LogInfo(msg string, args ...any) {
+	if l.allow(levelInfo, msg) {
+		l.backend.LogInfo(msg, args...)
+	}
+}
+
+// Debug passes the debug message to the backend if it is accepted by the sampler.
+func (l *Logger) Debug(msg string, args ...any) {
+	if l.allow(levelDebug, msg) {
+		l.backend.Debug(msg, args...)
+	}
+}
+
+// SetDebug enables or disables debug-level logging in the backend.
+func (l *Logger) SetDebug(enabled bool) {
+	l.backend.SetDebug(enabled)
+}
+
+// Close reports messages dropped in the current interval and closes the backend.
+func (l *Logger) Close() {
+	l.mu.Lock()
+	l.reportDropped()
+	l.mu.Unlock()
+	l.backend.Close()
+}
+
+// allow reports whether a message of the given level should be logged.
+// A rule with First set to 0 disables sampling for its level.
+func (l *Logger) allow(lvl level, msg string) bool {
+
+	rule := l.rules[lvl]
+	if rule.First <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now := l.now(); !now.Before(l.resetAt) {
+		l.reportDropped()
+		clear(l.counters)
+		l.resetAt = now.Add(l.interval)
+	}
+
+	key := counterKey{level: lvl, msg: msg}
+	l.counters[key]++
+	seen := l.counters[key]
+
+	if seen <= rule.First {
+		return true
+	}
+
+	if rule.Thereafter > 0 && (seen-rule.First)%rule.Thereafter == 0 {
+		return true
+	}
+
+	l.dropped[lvl]++
+
+	return false
+
+}
+
+// reportDropped logs how many messages were dropped per level and resets the counts.
+// Must be called with mu held.
+func (l *Logger) reportDropped() {
+	for lvl, count := range l.dropped {
+		if count > 0 {
+			l.backend.LogWarn("logger — sampling dropped messages", "level", string(lvl), "dropped", count, "layer", "logger")
+		}
+	}
+	clear(l.dropped)
+}
@@ -0,0 +1,76 @@
+package sampling
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"L2.18/internal/config"
+	"L2.18/pkg/logger/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestLogger_SamplesDebug(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().Debug("hot path").Times(4)
+	mockLogger.EXPECT().Debug("rare path").Times(1)
+	mockLogger.EXPECT().LogWarn("logger — sampling dropped messages", "level", "debug", "dropped", 6, "layer", "logger").Times(1)
+
+	logger := NewLogger(mockLogger, config.Sampling{Interval: time.Minute, Debug: config.SamplingRule{First: 2, Thereafter: 3}})
+
+	for range 10 {
+		logger.Debug("hot path")
+	}
+	logger.Debug("rare path")
+
+	clock := time.Now().Add(time.Hour)
+	logger.now = func() time.Time { return clock }
+
+	mockLogger.EXPECT().Debug("hot path").Times(1)
+	logger.Debug("hot path")
+
+}
+
+func TestLogger_DropsAfterFirst(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().LogInfo("noisy", "k", "v").Times(1)
+	mockLogger.EXPECT().LogWarn("logger — sampling dropped messages", "level", "info", "dropped", 4, "layer", "logger").Times(1)
+	mockLogger.EXPECT().Close().Times(1)
+
+	logger := NewLogger(mockLogger, config.Sampling{Info: config.SamplingRule{First: 1}})
+
+	for range 5 {
+		logger.LogInfo("noisy", "k", "v")
+	}
+
+	logger.Close()
+
+}
+
+func TestLogger_UnsampledLevels(t *testing.T) {
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	err := errors.New("boom")
+
+	mockLogger := mocks.NewMockLogger(controller)
+	mockLogger.EXPECT().LogWarn("warn").Times(3)
+	mockLogger.EXPECT().LogError("error", err).Times(3)
+
+	logger := NewLogger(mockLogger, config.Sampling{Debug: config.SamplingRule{First: 1}})
+
+	for range 3 {
+		logger.LogWarn("warn")
+		logger.LogError("error", err)
+	}
+
+}
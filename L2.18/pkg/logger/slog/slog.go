@@ -1,9 +1,11 @@
-// Package slog provides a JSON-based structured logger implementation for the service.
-// It wraps Go's slog package and supports logging to stdout or a file with configurable log levels.
+// Package slog provides a structured logger implementation for the service.
+// It wraps Go's slog package and supports logging to stdout or a file with configurable log levels
+// and JSON or text output.
 package slog
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -14,6 +16,7 @@ import (
 // Logger wraps a slog.Logger and implements the Logger interface.
 type Logger struct {
 	logger *slog.Logger
+	level  *slog.LevelVar
 	file   *os.File
 }
 
@@ -29,18 +32,38 @@ func NewLogger(config config.Logger) *Logger {
 			logDest = os.Stdout
 		}
 	}
-	var level slog.Level
-	if config.Debug {
-		level = slog.LevelDebug
-	} else {
-		level = slog.LevelInfo
-	}
-	handler := slog.NewJSONHandler(logDest, &slog.HandlerOptions{Level: level})
-	logger := &Logger{logger: slog.New(handler), file: logDest}
+	level := NewLevel(config.Debug)
+	logger := &Logger{logger: slog.New(NewHandler(logDest, config.Format, level)), level: level, file: logDest}
 	slog.SetDefault(logger.logger)
 	return logger
 }
 
+// NewHandler creates a slog handler writing to dest in the given format.
+// Text output is used when format is "text", JSON output otherwise.
+func NewHandler(dest io.Writer, format string, level slog.Leveler) slog.Handler {
+	options := &slog.HandlerOptions{Level: level}
+	if format == "text" {
+		return slog.NewTextHandler(dest, options)
+	}
+	return slog.NewJSONHandler(dest, options)
+}
+
+// NewLevel creates a level variable set to debug or info, which can be changed at runtime.
+func NewLevel(debug bool) *slog.LevelVar {
+	level := new(slog.LevelVar)
+	SetLevel(level, debug)
+	return level
+}
+
+// SetLevel switches the level variable to debug or info.
+func SetLevel(level *slog.LevelVar, debug bool) {
+	if debug {
+		level.Set(slog.LevelDebug)
+	} else {
+		level.Set(slog.LevelInfo)
+	}
+}
+
 // openFile ensures the log directory exists and opens the log file for appending.
 // Returns nil if the file cannot be created, in which case stdout will be used.
 func openFile(logDir string) *os.File {
@@ -89,6 +112,11 @@ func (l *Logger) Debug(msg string, args ...any) {
 	l.logger.Debug(msg, args...)
 }
 
+// SetDebug enables or disables debug-level logging.
+func (l *Logger) SetDebug(enabled bool) {
+	SetLevel(l.level, enabled)
+}
+
 // Close closes the log file, if it was used.
 func (l *Logger) Close() {
 	if l.file != nil {
@@ -0,0 +1,655 @@
+// Package quickadd reads short free-form phrases such as "lunch with Ann next friday",
+// "dentist 2028-12-04" or "каждый понедельник standup" as an event date, text and
+// optional recurrence.
+//
+// English and Russian are understood. Relative dates ("tomorrow", "через 2 недели",
+// "next friday") are resolved against a reference time supplied by the caller, using
+// the calendar day of that time in its own location. The words naming the date or
+// recurrence, together with a preposition directly before them ("on", "в", ...), are
+// removed from the phrase; the remaining words are the event text.
+//
+// Weekday names refer to the nearest such day, today included. "next friday" and
+// "в следующую пятницу" mean the Friday of the following Monday-to-Sunday week, and
+// "this friday" the Friday of the current one. Day and month without a year mean the
+// nearest such date that is not in the past.
+package quickadd
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Frequency is how often a recurring event repeats.
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"   // every day
+	Weekly  Frequency = "weekly"  // every week on the same day of the week
+	Monthly Frequency = "monthly" // every month on the same day, or the last day of shorter months
+	Yearly  Frequency = "yearly"  // every year on the same date, or February 28 outside leap years
+)
+
+// Recurrence describes how an event repeats.
+type Recurrence struct {
+	Frequency Frequency    // how often the event repeats
+	Weekday   time.Weekday // day of the week of weekly events
+}
+
+// Interpretation is what a phrase was read as.
+type Interpretation struct {
+	Text       string      // the phrase without the date words
+	Date       time.Time   // date of the event, or of its first occurrence, at midnight UTC
+	Recurrence *Recurrence // how the event repeats, nil for one-off events
+	Language   string      // language of the date words: "en" or "ru"
+	DatePhrase string      // the words read as the date and recurrence, as written
+}
+
+var (
+	ErrEmpty       = errors.New("quickadd: empty phrase")                  // the phrase has no words
+	ErrNoDate      = errors.New("quickadd: no date found")                 // no words name a date or recurrence
+	ErrManyDates   = errors.New("quickadd: more than one date")            // several words name a date, or a recurrence
+	ErrNoSuchDate  = errors.New("quickadd: date does not exist")           // the words name an impossible date, e.g. 31.02
+	errNotRelevant = errors.New("quickadd: words do not name a date here") // internal: a rule does not apply
+)
+
+// maxYearsAhead limits the search for the next February 29 named without a year.
+const maxYearsAhead = 8
+
+var (
+	isoDate    = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	dottedDate = regexp.MustCompile(`^(0?[1-9]|[12]\d|3[01])\.(0[1-9]|1[0-2])(?:\.(\d{4}))?$`)
+	ordinal    = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+)
+
+// weekdays maps weekday names, in every form the rules accept, to time.Weekday.
+// Abbreviations are left out on purpose: "sun", "sat" or "wed" are ordinary words too.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+
+	"воскресенье": time.Sunday, "понедельник": time.Monday, "вторник": time.Tuesday,
+	"среда": time.Wednesday, "среду": time.Wednesday, "четверг": time.Thursday,
+	"пятница": time.Friday, "пятницу": time.Friday, "суббота": time.Saturday, "субботу": time.Saturday,
+}
+
+// pluralWeekdays maps "on mondays" and "по понедельникам" style names to time.Weekday.
+var pluralWeekdays = map[string]time.Weekday{
+	"sundays": time.Sunday, "mondays": time.Monday, "tuesdays": time.Tuesday, "wednesdays": time.Wednesday,
+	"thursdays": time.Thursday, "fridays": time.Friday, "saturdays": time.Saturday,
+
+	"воскресеньям": time.Sunday, "понедельникам": time.Monday, "вторникам": time.Tuesday, "средам": time.Wednesday,
+	"четвергам": time.Thursday, "пятницам": time.Friday, "субботам": time.Saturday,
+}
+
+// months maps month names and abbreviations to time.Month. Russian names are accepted in
+// the nominative and genitive ("4 декабря").
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January, "february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March, "april": time.April, "apr": time.April, "may": time.May,
+	"june": time.June, "jun": time.June, "july": time.July, "jul": time.July, "august": time.August,
+	"aug": time.August, "september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October, "november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+
+	"январь": time.January, "января": time.January, "февраль": time.February, "февраля": time.February,
+	"март": time.March, "марта": time.March, "апрель": time.April, "апреля": time.April,
+	"май": time.May, "мая": time.May, "июнь": time.June, "июня": time.June, "июль": time.July,
+	"июля": time.July, "август": time.August, "августа": time.August, "сентябрь": time.September,
+	"сентября": time.September, "октябрь": time.October, "октября": time.October,
+	"ноябрь": time.November, "ноября": time.November, "декабрь": time.December, "декабря": time.December,
+}
+
+// unit is a length of time named in relative dates and recurrences.
+type unit int
+
+const (
+	day unit = iota
+	week
+	month
+	year
+)
+
+// units maps the names of lengths of time to units.
+var units = map[string]unit{
+	"day": day, "days": day, "week": week, "weeks": week, "month": month, "months": month, "year": year, "years": year,
+
+	"день": day, "дня": day, "дней": day, "неделя": week, "неделю": week, "недели": week, "недель": week,
+	"месяц": month, "месяца": month, "месяцев": month, "год": year, "года": year, "лет": year,
+}
+
+// numbers maps number words accepted in relative dates to their values.
+var numbers = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+
+	"один": 1, "одну": 1, "одна": 1, "два": 2, "две": 2, "три": 3, "четыре": 4, "пять": 5,
+	"шесть": 6, "семь": 7, "восемь": 8, "девять": 9, "десять": 10,
+}
+
+// frequencies maps single words naming a recurrence to its frequency.
+var frequencies = map[string]Frequency{
+	"daily": Daily, "weekly": Weekly, "monthly": Monthly, "yearly": Yearly, "annually": Yearly,
+	"ежедневно": Daily, "еженедельно": Weekly, "ежемесячно": Monthly, "ежегодно": Yearly,
+}
+
+// unitFrequencies maps the unit after "every" or "каждый" to the frequency it names.
+var unitFrequencies = map[unit]Frequency{day: Daily, week: Weekly, month: Monthly, year: Yearly}
+
+var (
+	nextWords  = set("next", "следующий", "следующую", "следующее", "следующая")
+	thisWords  = set("this", "этот", "эту", "это", "эта")
+	everyWords = set("every", "each", "каждый", "каждую", "каждое", "каждая")
+
+	// prepositions may directly precede a date and are removed along with it.
+	prepositions = set("on", "from", "starting", "в", "во", "на", "с", "со", "начиная")
+)
+
+// reading is what a rule read from the words at the start of a slice.
+type reading struct {
+	n          int          // number of words read
+	date       time.Time    // date named by the words, zero for recurrences
+	frequency  Frequency    // recurrence named by the words, empty for dates
+	weekday    time.Weekday // day of the week of a weekly recurrence
+	anyWeekday bool         // whether a weekly recurrence leaves the day of the week open
+}
+
+// rule tries to read a date or recurrence from the words at the start of a slice.
+// It returns errNotRelevant if the words do not start with one.
+type rule func(p parser, words []string) (reading, error)
+
+// rules are tried in order at every word of a phrase; the first one that applies wins.
+var rules = []rule{
+	parser.absolute,
+	parser.named,
+	parser.relative,
+	parser.weekday,
+	parser.nextPeriod,
+	parser.dayMonth,
+	parser.recurrence,
+}
+
+// parser resolves dates against a reference day.
+type parser struct {
+	today time.Time // reference day at midnight UTC
+}
+
+// Parse reads a phrase as an event, resolving relative dates against now.
+//
+// A phrase must name a date, a recurrence, or both; a recurrence without a date starts
+// on its nearest occurrence. It returns ErrEmpty, ErrNoDate, ErrManyDates or ErrNoSuchDate
+// if the phrase cannot be read.
+func Parse(phrase string, now time.Time) (Interpretation, error) {
+
+	raw := strings.Fields(phrase)
+	if len(raw) == 0 {
+		return Interpretation{}, ErrEmpty
+	}
+
+	words := make([]string, len(raw))
+	for i, w := range raw {
+		words[i] = strings.ToLower(strings.Trim(w, `,.;:!?"'()«»`))
+	}
+
+	p := parser{today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)}
+
+	used := make([]bool, len(words))
+	var dates, recurrences []reading
+
+	for i := 0; i < len(words); {
+
+		r, err := p.read(words[i:])
+		if errors.Is(err, errNotRelevant) {
+			i++
+			continue
+		}
+		if err != nil {
+			return Interpretation{}, err
+		}
+
+		start := i
+		for start > 0 && start > i-2 && prepositions[words[start-1]] && !used[start-1] {
+			start--
+		}
+
+		for j := start; j < i+r.n; j++ {
+			used[j] = true
+		}
+
+		if r.frequency != "" {
+			recurrences = append(recurrences, r)
+		} else {
+			dates = append(dates, r)
+		}
+
+		i += r.n
+
+	}
+
+	if len(dates) == 0 && len(recurrences) == 0 {
+		return Interpretation{}, ErrNoDate
+	}
+
+	if len(dates) > 1 || len(recurrences) > 1 {
+		return Interpretation{}, ErrManyDates
+	}
+
+	var res Interpretation
+	var text, matched []string
+
+	for i, w := range raw {
+		if used[i] {
+			matched = append(matched, w)
+		} else {
+			text = append(text, w)
+		}
+	}
+
+	res.Text = strings.Trim(strings.Join(text, " "), " ,;:-—")
+	res.DatePhrase = strings.Join(matched, " ")
+	res.Language = language(res.DatePhrase, phrase)
+
+	if len(dates) == 1 {
+		res.Date = dates[0].date
+	}
+
+	if len(recurrences) == 0 {
+		return res, nil
+	}
+
+	r := recurrences[0]
+	res.Recurrence = &Recurrence{Frequency: r.frequency}
+
+	switch {
+	case res.Date.IsZero() && r.frequency == Weekly && !r.anyWeekday:
+		res.Date = p.upcoming(r.weekday)
+	case res.Date.IsZero():
+		res.Date = p.today
+	case r.frequency == Weekly && !r.anyWeekday:
+		res.Date = res.Date.AddDate(0, 0, (int(r.weekday)-int(res.Date.Weekday())+7)%7)
+	}
+
+	if r.frequency == Weekly {
+		res.Recurrence.Weekday = res.Date.Weekday()
+	}
+
+	return res, nil
+
+}
+
+// Dates returns the first n dates of an event recurring with r whose first occurrence is on start.
+func (r Recurrence) Dates(start time.Time, n int) []time.Time {
+
+	dates := make([]time.Time, n)
+
+	for i := range dates {
+		switch r.Frequency {
+		case Daily:
+			dates[i] = start.AddDate(0, 0, i)
+		case Weekly:
+			dates[i] = start.AddDate(0, 0, 7*i)
+		case Monthly:
+			dates[i] = addMonths(start, i)
+		case Yearly:
+			dates[i] = addMonths(start, 12*i)
+		default:
+			return dates[:i]
+		}
+	}
+
+	return dates
+
+}
+
+// read applies the first rule that matches the words at the start of a slice.
+func (p parser) read(words []string) (reading, error) {
+	for _, rule := range rules {
+		if r, err := rule(p, words); !errors.Is(err, errNotRelevant) {
+			return r, err
+		}
+	}
+	return reading{}, errNotRelevant
+}
+
+// absolute reads numeric dates: 2028-12-04, 04.12.2028 and 04.12.
+func (p parser) absolute(words []string) (reading, error) {
+
+	if m := isoDate.FindStringSubmatch(words[0]); m != nil {
+		date, ok := makeDate(atoi(m[1]), time.Month(atoi(m[2])), atoi(m[3]))
+		if !ok {
+			return reading{}, ErrNoSuchDate
+		}
+		return reading{n: 1, date: date}, nil
+	}
+
+	if m := dottedDate.FindStringSubmatch(words[0]); m != nil {
+		date, err := p.dayOfMonth(atoi(m[1]), time.Month(atoi(m[2])), m[3])
+		return reading{n: 1, date: date}, err
+	}
+
+	return reading{}, errNotRelevant
+
+}
+
+// named reads today, tomorrow, the day after tomorrow and their Russian equivalents.
+func (p parser) named(words []string) (reading, error) {
+
+	switch words[0] {
+	case "today", "сегодня":
+		return reading{n: 1, date: p.today}, nil
+	case "tomorrow", "завтра":
+		return reading{n: 1, date: p.today.AddDate(0, 0, 1)}, nil
+	case "послезавтра":
+		return reading{n: 1, date: p.today.AddDate(0, 0, 2)}, nil
+	}
+
+	if hasPrefix(words, "day", "after", "tomorrow") {
+		return reading{n: 3, date: p.today.AddDate(0, 0, 2)}, nil
+	}
+
+	if hasPrefix(words, "the", "day", "after", "tomorrow") {
+		return reading{n: 4, date: p.today.AddDate(0, 0, 2)}, nil
+	}
+
+	return reading{}, errNotRelevant
+
+}
+
+// relative reads "in 3 days", "in a week", "через 2 месяца", "через неделю".
+func (p parser) relative(words []string) (reading, error) {
+
+	if len(words) < 2 || (words[0] != "in" && words[0] != "через") {
+		return reading{}, errNotRelevant
+	}
+
+	count, n := 1, 1
+	if value, ok := number(words[1]); ok {
+		count, n = value, 2
+	}
+
+	if len(words) <= n {
+		return reading{}, errNotRelevant
+	}
+
+	u, ok := units[words[n]]
+	if !ok {
+		return reading{}, errNotRelevant
+	}
+
+	return reading{n: n + 1, date: p.add(u, count)}, nil
+
+}
+
+// weekday reads "friday", "next friday", "this friday", "в следующую пятницу".
+func (p parser) weekday(words []string) (reading, error) {
+
+	if wd, ok := weekdays[words[0]]; ok {
+		return reading{n: 1, date: p.upcoming(wd)}, nil
+	}
+
+	if len(words) < 2 {
+		return reading{}, errNotRelevant
+	}
+
+	wd, ok := weekdays[words[1]]
+	if !ok {
+		return reading{}, errNotRelevant
+	}
+
+	switch {
+	case nextWords[words[0]]:
+		return reading{n: 2, date: p.thisWeek(wd).AddDate(0, 0, 7)}, nil
+	case thisWords[words[0]]:
+		return reading{n: 2, date: p.thisWeek(wd)}, nil
+	}
+
+	return reading{}, errNotRelevant
+
+}
+
+// nextPeriod reads "next week", "next month", "next year" and "на следующей неделе",
+// "в следующем месяце", "в следующем году": the first day of that period.
+func (p parser) nextPeriod(words []string) (reading, error) {
+
+	if len(words) < 2 {
+		return reading{}, errNotRelevant
+	}
+
+	var u unit
+
+	switch {
+	case words[0] == "next" && words[1] == "week", words[0] == "следующей" && words[1] == "неделе":
+		u = week
+	case words[0] == "next" && words[1] == "month", words[0] == "следующем" && words[1] == "месяце":
+		u = month
+	case words[0] == "next" && words[1] == "year", words[0] == "следующем" && words[1] == "году":
+		u = year
+	default:
+		return reading{}, errNotRelevant
+	}
+
+	switch u {
+	case week:
+		return reading{n: 2, date: p.thisWeek(time.Monday).AddDate(0, 0, 7)}, nil
+	case month:
+		return reading{n: 2, date: time.Date(p.today.Year(), p.today.Month()+1, 1, 0, 0, 0, 0, time.UTC)}, nil
+	default:
+		return reading{n: 2, date: time.Date(p.today.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)}, nil
+	}
+
+}
+
+// dayMonth reads "4 december", "december 4th", "dec 4 2028", "4 декабря 2028 года".
+func (p parser) dayMonth(words []string) (reading, error) {
+
+	if len(words) < 2 {
+		return reading{}, errNotRelevant
+	}
+
+	var dayWord string
+	m, ok := months[words[1]]
+	if ok {
+		dayWord = words[0]
+	} else if m, ok = months[words[0]]; ok {
+		dayWord = words[1]
+	} else {
+		return reading{}, errNotRelevant
+	}
+
+	match := ordinal.FindStringSubmatch(dayWord)
+	if match == nil {
+		return reading{}, errNotRelevant
+	}
+
+	dom := atoi(match[1])
+	if dom < 1 || dom > 31 {
+		return reading{}, errNotRelevant
+	}
+
+	n, yearWord := 2, ""
+	if len(words) > 2 && len(words[2]) == 4 && isDigits(words[2]) {
+		n, yearWord = 3, words[2]
+		if len(words) > 3 && (words[3] == "года" || words[3] == "г") {
+			n++
+		}
+	}
+
+	date, err := p.dayOfMonth(dom, m, yearWord)
+
+	return reading{n: n, date: date}, err
+
+}
+
+// recurrence reads "every monday", "every day", "weekly", "on mondays", "каждый понедельник",
+// "каждую неделю", "ежедневно", "по понедельникам".
+func (p parser) recurrence(words []string) (reading, error) {
+
+	if f, ok := frequencies[words[0]]; ok {
+		return reading{n: 1, frequency: f, anyWeekday: true}, nil
+	}
+
+	if wd, ok := pluralWeekdays[words[0]]; ok {
+		return reading{n: 1, frequency: Weekly, weekday: wd}, nil
+	}
+
+	if len(words) < 2 {
+		return reading{}, errNotRelevant
+	}
+
+	if words[0] == "по" {
+		if wd, ok := pluralWeekdays[words[1]]; ok {
+			return reading{n: 2, frequency: Weekly, weekday: wd}, nil
+		}
+	}
+
+	if !everyWords[words[0]] {
+		return reading{}, errNotRelevant
+	}
+
+	if wd, ok := weekdays[words[1]]; ok {
+		return reading{n: 2, frequency: Weekly, weekday: wd}, nil
+	}
+
+	if u, ok := units[words[1]]; ok {
+		return reading{n: 2, frequency: unitFrequencies[u], anyWeekday: true}, nil
+	}
+
+	return reading{}, errNotRelevant
+
+}
+
+// upcoming returns the nearest day falling on wd, today included.
+func (p parser) upcoming(wd time.Weekday) time.Time {
+	return p.today.AddDate(0, 0, (int(wd)-int(p.today.Weekday())+7)%7)
+}
+
+// thisWeek returns the day falling on wd in the Monday-to-Sunday week of today.
+func (p parser) thisWeek(wd time.Weekday) time.Time {
+	monday := p.today.AddDate(0, 0, -mondayIndex(p.today.Weekday()))
+	return monday.AddDate(0, 0, mondayIndex(wd))
+}
+
+// add returns the day count units after today.
+func (p parser) add(u unit, count int) time.Time {
+	switch u {
+	case day:
+		return p.today.AddDate(0, 0, count)
+	case week:
+		return p.today.AddDate(0, 0, 7*count)
+	case month:
+		return addMonths(p.today, count)
+	default:
+		return addMonths(p.today, 12*count)
+	}
+}
+
+// dayOfMonth returns the given day of a month. If yearWord is empty, it returns the nearest
+// such day that is not in the past, looking a few years ahead for February 29.
+func (p parser) dayOfMonth(dom int, m time.Month, yearWord string) (time.Time, error) {
+
+	if yearWord != "" {
+		date, ok := makeDate(atoi(yearWord), m, dom)
+		if !ok {
+			return time.Time{}, ErrNoSuchDate
+		}
+		return date, nil
+	}
+
+	for y := p.today.Year(); y <= p.today.Year()+maxYearsAhead; y++ {
+		if date, ok := makeDate(y, m, dom); ok && !date.Before(p.today) {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, ErrNoSuchDate
+
+}
+
+// makeDate returns the date at midnight UTC and whether it exists.
+func makeDate(y int, m time.Month, d int) (time.Time, bool) {
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return date, date.Year() == y && date.Month() == m && date.Day() == d
+}
+
+// addMonths adds n months to t, moving to the last day of the resulting month if it is
+// shorter than the day of t, so that January 31 plus one month is the end of February.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(t.Day(), last), 0, 0, 0, 0, time.UTC)
+}
+
+// mondayIndex returns the position of wd in a week starting on Monday.
+func mondayIndex(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+// number reads a count written in digits or as a word.
+func number(word string) (int, bool) {
+	if isDigits(word) && len(word) <= 3 {
+		return atoi(word), true
+	}
+	n, ok := numbers[word]
+	return n, ok
+}
+
+// language returns "ru" if the date words contain Cyrillic letters, "en" if they contain
+// other letters. Numeric dates such as 05.12 carry no language, so the whole phrase decides.
+func language(datePhrase, phrase string) string {
+
+	text := datePhrase
+	if strings.IndexFunc(datePhrase, unicode.IsLetter) < 0 {
+		text = phrase
+	}
+
+	if strings.IndexFunc(text, func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }) >= 0 {
+		return "ru"
+	}
+
+	return "en"
+
+}
+
+// hasPrefix reports whether words start with prefix.
+func hasPrefix(words []string, prefix ...string) bool {
+	if len(words) < len(prefix) {
+		return false
+	}
+	for i, w := range prefix {
+		if words[i] != w {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// atoi converts a string already known to hold digits.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// set builds a lookup set of words.
+func set(words ...string) map[string]bool {
+	res := make(map[string]bool, len(words))
+	for _, w := range words {
+		res[w] = true
+	}
+	return res
+}
@@ -0,0 +1,123 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// now is the reference time of the tests, a Wednesday.
+var now = time.Date(2028, 11, 29, 10, 0, 0, 0, time.UTC)
+
+// date returns a day at midnight UTC.
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		phrase     string
+		text       string
+		date       time.Time
+		recurrence *Recurrence
+		language   string
+		matched    string
+	}{
+		{"lunch with Ann next friday", "lunch with Ann", date(2028, 12, 8), nil, "en", "next friday"},
+		{"lunch with Ann on Friday", "lunch with Ann", date(2028, 12, 1), nil, "en", "on Friday"},
+		{"dentist 2028-12-04", "dentist", date(2028, 12, 4), nil, "en", "2028-12-04"},
+		{"каждый понедельник standup", "standup", date(2028, 12, 4), &Recurrence{Weekly, time.Monday}, "ru", "каждый понедельник"},
+		{"встреча с Анной в следующую пятницу", "встреча с Анной", date(2028, 12, 8), nil, "ru", "в следующую пятницу"},
+		{"созвон завтра", "созвон", date(2028, 11, 30), nil, "ru", "завтра"},
+		{"ревью послезавтра", "ревью", date(2028, 12, 1), nil, "ru", "послезавтра"},
+		{"review the day after tomorrow", "review", date(2028, 12, 1), nil, "en", "the day after tomorrow"},
+		{"today: retro", "retro", date(2028, 11, 29), nil, "en", "today:"},
+		{"report in 2 weeks", "report", date(2028, 12, 13), nil, "en", "in 2 weeks"},
+		{"report in three days", "report", date(2028, 12, 2), nil, "en", "in three days"},
+		{"отпуск через месяц", "отпуск", date(2028, 12, 29), nil, "ru", "через месяц"},
+		{"party 4 december", "party", date(2028, 12, 4), nil, "en", "4 december"},
+		{"party on Dec 4th, 2029", "party", date(2029, 12, 4), nil, "en", "on Dec 4th, 2029"},
+		{"праздник 4 декабря 2029 года", "праздник", date(2029, 12, 4), nil, "ru", "4 декабря 2029 года"},
+		{"anniversary november 1st", "anniversary", date(2029, 11, 1), nil, "en", "november 1st"},
+		{"дедлайн 05.12", "дедлайн", date(2028, 12, 5), nil, "ru", "05.12"},
+		{"deadline 5.12.2029", "deadline", date(2029, 12, 5), nil, "en", "5.12.2029"},
+		{"sync this monday", "sync", date(2028, 11, 27), nil, "en", "this monday"},
+		{"planning next week", "planning", date(2028, 12, 4), nil, "en", "next week"},
+		{"планёрка на следующей неделе", "планёрка", date(2028, 12, 4), nil, "ru", "на следующей неделе"},
+		{"budget next month", "budget", date(2028, 12, 1), nil, "en", "next month"},
+		{"итоги в следующем году", "итоги", date(2029, 1, 1), nil, "ru", "в следующем году"},
+		{"pay rent monthly", "pay rent", date(2028, 11, 29), &Recurrence{Frequency: Monthly}, "en", "monthly"},
+		{"gym every week", "gym", date(2028, 11, 29), &Recurrence{Weekly, time.Wednesday}, "en", "every week"},
+		{"йога по средам", "йога", date(2028, 11, 29), &Recurrence{Weekly, time.Wednesday}, "ru", "по средам"},
+		{"поливать цветы ежедневно", "поливать цветы", date(2028, 11, 29), &Recurrence{Frequency: Daily}, "ru", "ежедневно"},
+		{"standup every monday starting 2028-12-06", "standup", date(2028, 12, 11), &Recurrence{Weekly, time.Monday}, "en", "every monday starting 2028-12-06"},
+		{"планёрка еженедельно начиная с 05.12", "планёрка", date(2028, 12, 5), &Recurrence{Weekly, time.Tuesday}, "ru", "еженедельно начиная с 05.12"},
+		{"leap day party 29 february", "leap day party", date(2032, 2, 29), nil, "en", "29 february"},
+		{"tomorrow", "", date(2028, 11, 30), nil, "en", "tomorrow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+
+			res, err := Parse(tt.phrase, now)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.text, res.Text)
+			require.Equal(t, tt.date, res.Date)
+			require.Equal(t, tt.recurrence, res.Recurrence)
+			require.Equal(t, tt.language, res.Language)
+			require.Equal(t, tt.matched, res.DatePhrase)
+
+		})
+	}
+
+}
+
+func TestParse_Errors(t *testing.T) {
+
+	tests := map[string]error{
+		"   ":                         ErrEmpty,
+		"just some text":              ErrNoDate,
+		"march on":                    ErrNoDate,
+		"dentist 2028-02-30":          ErrNoSuchDate,
+		"дедлайн 31.02":               ErrNoSuchDate,
+		"party 30 february":           ErrNoSuchDate,
+		"call tomorrow or friday":     ErrManyDates,
+		"every monday and every week": ErrManyDates,
+	}
+
+	for phrase, want := range tests {
+		_, err := Parse(phrase, now)
+		require.ErrorIs(t, err, want, phrase)
+	}
+
+}
+
+func TestParse_ReferenceLocation(t *testing.T) {
+
+	// 23:30 on November 29 in New York is already November 30 in UTC.
+	evening := time.Date(2028, 11, 29, 23, 30, 0, 0, time.FixedZone("EST", -5*3600))
+
+	res, err := Parse("call mom tomorrow", evening)
+	require.NoError(t, err)
+	require.Equal(t, date(2028, 11, 30), res.Date)
+
+}
+
+func TestRecurrence_Dates(t *testing.T) {
+
+	monthly := Recurrence{Frequency: Monthly}
+	require.Equal(t, []time.Time{date(2028, 1, 31), date(2028, 2, 29), date(2028, 3, 31), date(2028, 4, 30)}, monthly.Dates(date(2028, 1, 31), 4))
+
+	yearly := Recurrence{Frequency: Yearly}
+	require.Equal(t, []time.Time{date(2028, 2, 29), date(2029, 2, 28), date(2030, 2, 28), date(2031, 2, 28), date(2032, 2, 29)}, yearly.Dates(date(2028, 2, 29), 5))
+
+	weekly := Recurrence{Frequency: Weekly, Weekday: time.Monday}
+	require.Equal(t, []time.Time{date(2028, 12, 25), date(2029, 1, 1)}, weekly.Dates(date(2028, 12, 25), 2))
+
+	daily := Recurrence{Frequency: Daily}
+	require.Equal(t, []time.Time{date(2028, 12, 31), date(2029, 1, 1)}, daily.Dates(date(2028, 12, 31), 2))
+
+}
@@ -0,0 +1,224 @@
+// Package shutdown runs the shutdown of an application as an ordered list of phases,
+// each bounded by its own timeout, and reports what did not finish in time.
+//
+// Components register hooks in the phase they belong to, e.g. draining the HTTP server,
+// stopping background workers, flushing buffers and finally closing stores. Phases run
+// one after another in the order they were declared; the hooks of a phase run
+// concurrently and share the phase's deadline, which their context carries. A hook that
+// is still running when the deadline and a short grace period have passed is abandoned,
+// so a stuck component cannot hold up the rest of the shutdown, and is reported as
+// force-terminated.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Hook stops a single component. It should return once ctx is done at the latest.
+type Hook func(ctx context.Context) error
+
+// Func adapts a function that cannot fail and takes no context, such as a Close method, to a Hook.
+func Func(f func()) Hook {
+	return func(context.Context) error {
+		f()
+		return nil
+	}
+}
+
+// Manager collects the phases and hooks of a shutdown and runs them once.
+// It is safe for concurrent use.
+type Manager struct {
+	phases []*phase      // phases in the order they run
+	grace  time.Duration // how long hooks still running at the deadline are waited for
+	done   bool          // set by Shutdown; later calls return an empty report
+	mu     sync.Mutex    // protects phases and done
+}
+
+// phase is a named group of hooks sharing a timeout.
+type phase struct {
+	name    string        // name the hooks are registered under
+	timeout time.Duration // time the hooks of the phase get, together
+	hooks   []hook        // hooks in registration order
+}
+
+// hook is a registered Hook with the name it is reported under.
+type hook struct {
+	name string
+	run  Hook
+}
+
+// New creates a Manager without any phases. Hooks that are still running when the
+// deadline of their phase passes are given grace more to return before they are abandoned.
+func New(grace time.Duration) *Manager {
+	return &Manager{grace: grace}
+}
+
+// Phase declares a phase running after all phases declared before it, whose hooks get
+// timeout to finish. Declaring the same phase twice panics.
+func (m *Manager) Phase(name string, timeout time.Duration) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.find(name) != nil {
+		panic(fmt.Sprintf("shutdown: phase %q declared twice", name))
+	}
+
+	m.phases = append(m.phases, &phase{name: name, timeout: timeout})
+
+}
+
+// Register adds a hook to a declared phase; it is reported under name. Registering
+// with an unknown phase panics.
+func (m *Manager) Register(phaseName, name string, run Hook) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.find(phaseName)
+	if p == nil {
+		panic(fmt.Sprintf("shutdown: unknown phase %q", phaseName))
+	}
+
+	p.hooks = append(p.hooks, hook{name: name, run: run})
+
+}
+
+// find returns the phase called name, or nil. The caller must hold the lock.
+func (m *Manager) find(name string) *phase {
+	for _, p := range m.phases {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Shutdown runs every phase in order and returns a report of all hooks. Only the first
+// call runs the hooks; later calls return an empty report.
+func (m *Manager) Shutdown() Report {
+
+	m.mu.Lock()
+	if m.done {
+		m.mu.Unlock()
+		return Report{}
+	}
+	m.done = true
+	phases := m.phases
+	m.mu.Unlock()
+
+	start := time.Now()
+	var report Report
+
+	for _, p := range phases {
+		report.Hooks = append(report.Hooks, m.run(p)...)
+	}
+
+	report.Duration = time.Since(start)
+
+	return report
+
+}
+
+// outcome is what a hook goroutine reports once the hook returns.
+type outcome struct {
+	index    int           // position of the hook in its phase
+	err      error         // error returned by the hook
+	duration time.Duration // time from the start of the phase until the hook returned
+}
+
+// run runs the hooks of a phase concurrently and waits for them until the phase deadline
+// and the grace period have passed. Hooks are reported as abandoned until they return.
+func (m *Manager) run(p *phase) []HookResult {
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	start := time.Now()
+	finished := make(chan outcome, len(p.hooks))
+
+	results := make([]HookResult, len(p.hooks))
+	for i, h := range p.hooks {
+		results[i] = HookResult{Phase: p.name, Hook: h.name, Err: context.DeadlineExceeded, TimedOut: true, Abandoned: true}
+		go func() {
+			err := h.run(ctx)
+			finished <- outcome{index: i, err: err, duration: time.Since(start)}
+		}()
+	}
+
+	deadline := ctx.Done()
+	var abandon <-chan time.Time
+
+	for pending := len(p.hooks); pending > 0; {
+		select {
+
+		case o := <-finished:
+			pending--
+			results[o.index].Duration = o.duration
+			results[o.index].Err = o.err
+			results[o.index].TimedOut = o.duration > p.timeout || errors.Is(o.err, context.DeadlineExceeded)
+			results[o.index].Abandoned = false
+
+		case <-deadline:
+			deadline = nil // fires once; wait out the grace period from here
+			grace := time.NewTimer(m.grace)
+			defer grace.Stop()
+			abandon = grace.C
+
+		case <-abandon:
+			for i := range results {
+				if results[i].Abandoned {
+					results[i].Duration = time.Since(start)
+				}
+			}
+			return results
+
+		}
+	}
+
+	return results
+
+}
+
+// Report describes a completed shutdown.
+type Report struct {
+	Duration time.Duration // time the whole shutdown took
+	Hooks    []HookResult  // result of every hook, phase by phase in registration order
+}
+
+// HookResult describes how a single hook ended.
+type HookResult struct {
+	Phase     string        // phase the hook was registered in
+	Hook      string        // name the hook was registered under
+	Duration  time.Duration // time from the start of the phase until the hook returned or was abandoned
+	Err       error         // error returned by the hook; context.DeadlineExceeded if abandoned
+	TimedOut  bool          // the hook did not finish before the deadline of its phase
+	Abandoned bool          // the hook was still running after the grace period and was left behind
+}
+
+// ForceTerminated returns the hooks that did not finish before the deadline of their
+// phase, whether they gave up on their own or were abandoned.
+func (r Report) ForceTerminated() []HookResult {
+	var res []HookResult
+	for _, h := range r.Hooks {
+		if h.TimedOut {
+			res = append(res, h)
+		}
+	}
+	return res
+}
+
+// Failed returns the hooks that finished in time but returned an error.
+func (r Report) Failed() []HookResult {
+	var res []HookResult
+	for _, h := range r.Hooks {
+		if !h.TimedOut && h.Err != nil {
+			res = append(res, h)
+		}
+	}
+	return res
+}
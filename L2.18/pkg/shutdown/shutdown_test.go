@@ -0,0 +1,110 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_PhaseOrder(t *testing.T) {
+
+	m := New(10 * time.Millisecond)
+	m.Phase("server", time.Second)
+	m.Phase("storage", time.Second)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) Hook {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	m.Register("storage", "store", record("store"))
+	m.Register("server", "http", record("http"))
+
+	report := m.Shutdown()
+
+	require.Equal(t, []string{"http", "store"}, order)
+	require.Len(t, report.Hooks, 2)
+	require.Equal(t, "server", report.Hooks[0].Phase)
+	require.Equal(t, "http", report.Hooks[0].Hook)
+	require.Equal(t, "storage", report.Hooks[1].Phase)
+	require.Empty(t, report.ForceTerminated())
+	require.Empty(t, report.Failed())
+
+	require.Empty(t, m.Shutdown().Hooks, "a second shutdown runs nothing")
+
+}
+
+func TestManager_Timeouts(t *testing.T) {
+
+	m := New(50 * time.Millisecond)
+	m.Phase("background", 50*time.Millisecond)
+	m.Phase("storage", time.Second)
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+
+	m.Register("background", "fast", func(context.Context) error { return nil })
+	m.Register("background", "honours deadline", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	m.Register("background", "stuck", func(context.Context) error {
+		<-stuck
+		return nil
+	})
+	m.Register("background", "failing", func(context.Context) error { return errors.New("boom") })
+
+	closed := false
+	m.Register("storage", "store", Func(func() { closed = true }))
+
+	report := m.Shutdown()
+
+	require.True(t, closed, "later phases run after a phase timed out")
+	require.Len(t, report.Hooks, 5)
+
+	byName := make(map[string]HookResult)
+	for _, h := range report.Hooks {
+		byName[h.Hook] = h
+	}
+
+	require.False(t, byName["fast"].TimedOut)
+	require.NoError(t, byName["fast"].Err)
+
+	require.True(t, byName["honours deadline"].TimedOut)
+	require.False(t, byName["honours deadline"].Abandoned)
+
+	require.True(t, byName["stuck"].TimedOut)
+	require.True(t, byName["stuck"].Abandoned)
+	require.ErrorIs(t, byName["stuck"].Err, context.DeadlineExceeded)
+	require.GreaterOrEqual(t, byName["stuck"].Duration, 100*time.Millisecond)
+
+	forced := report.ForceTerminated()
+	require.Len(t, forced, 2)
+	require.Equal(t, "honours deadline", forced[0].Hook)
+	require.Equal(t, "stuck", forced[1].Hook)
+
+	failed := report.Failed()
+	require.Len(t, failed, 1)
+	require.Equal(t, "failing", failed[0].Hook)
+
+}
+
+func TestManager_RegisterPanics(t *testing.T) {
+
+	m := New(0)
+	m.Phase("server", time.Second)
+
+	require.Panics(t, func() { m.Phase("server", time.Second) })
+	require.Panics(t, func() { m.Register("unknown", "hook", Func(func() {})) })
+
+}
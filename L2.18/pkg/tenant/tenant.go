@@ -0,0 +1,63 @@
+// Package tenant carries the tenant a request belongs to through the request context.
+//
+// A tenant is an isolated group of users, such as a team sharing one calendar instance.
+// The handler layer resolves the tenant of every request and stores it in the context;
+// the service and storage layers read it back to apply per-tenant limits and to keep
+// the data of different tenants apart. Requests without a tenant belong to Default.
+package tenant
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Default is the tenant of requests that do not name one, and of every request
+// when tenancy is disabled.
+const Default = "default"
+
+// maxLength is the maximum length of a tenant ID.
+const maxLength = 64
+
+// idKey is the context key under which the tenant ID is stored.
+type idKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant ID and tags the current span with it.
+func WithID(ctx context.Context, id string) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant.id", id))
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// ID returns the tenant ID stored in ctx, or Default if there is none.
+func ID(ctx context.Context) string {
+	if id, ok := ctx.Value(idKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// Normalize trims surrounding spaces from a tenant ID and lowercases it.
+// Tenant IDs are case-insensitive, matching the configuration keys they are listed under.
+func Normalize(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// Valid reports whether a normalized tenant ID is well-formed: 1 to 64 characters,
+// each a lowercase letter, a digit, a dash or an underscore.
+func Valid(id string) bool {
+
+	if len(id) == 0 || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+
+}
@@ -0,0 +1,29 @@
+package tenant
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestID(t *testing.T) {
+	require.Equal(t, Default, ID(context.Background()))
+	require.Equal(t, Default, ID(WithID(context.Background(), "")))
+	require.Equal(t, "team-a", ID(WithID(context.Background(), "team-a")))
+}
+
+func TestValid(t *testing.T) {
+
+	require.Equal(t, "team-a", Normalize("  Team-A "))
+
+	for _, id := range []string{"team-a", "team_b", "42", strings.Repeat("x", 64)} {
+		require.True(t, Valid(id), id)
+	}
+
+	for _, id := range []string{"", "Team-A", "team a", "team/a", "тим", strings.Repeat("x", 65)} {
+		require.False(t, Valid(id), id)
+	}
+
+}
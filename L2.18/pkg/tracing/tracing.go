@@ -0,0 +1,109 @@
+// Package tracing configures OpenTelemetry distributed tracing for the service and
+// provides small helpers shared by the layers that create spans.
+//
+// Trace context is always propagated using W3C traceparent headers. When tracing is
+// enabled, spans are exported to stdout or to an OTLP/HTTP collector; otherwise the
+// global no-op tracer provider is kept and spans cost next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"L2.18/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDKey is the context key under which the request ID is stored.
+type requestIDKey struct{}
+
+// Shutdown flushes pending spans and releases exporter resources.
+type Shutdown func(ctx context.Context) error
+
+// Init installs the global W3C trace context propagator and, if tracing is enabled,
+// a tracer provider exporting spans through the configured exporter.
+//
+// The returned Shutdown must be called on application exit to flush buffered spans;
+// it is a no-op when tracing is disabled.
+func Init(config config.Tracing) (Shutdown, error) {
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !config.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s span exporter: %w", config.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+
+}
+
+// newExporter creates the span exporter selected by the configuration.
+func newExporter(config config.Tracing) (sdktrace.SpanExporter, error) {
+
+	if config.Exporter == "otlp" {
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+		if config.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), options...)
+	}
+
+	return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+
+}
+
+// Tracer returns a named tracer from the global tracer provider.
+// Each layer uses its import path as the tracer name.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// End records err on the span, if any, and ends the span.
+// It is intended to be deferred with a named error result.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID and tags the current
+// span with it, so logs and traces of the same request can be correlated.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request_id", requestID))
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or an empty string if there is none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}